@@ -0,0 +1,787 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/migrations"
+	"github.com/Peltoche/zapette/internal/server"
+	"github.com/Peltoche/zapette/internal/service/backups"
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/service/masterkey"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/service/websessions"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/spf13/afero"
+)
+
+var (
+	ErrUnknownCommand   = errors.New("unknown command")
+	ErrUsernameRequired = errors.New("--username is required")
+	ErrSessionsTarget   = errors.New("one of --username or --all is required")
+	ErrInvalidRange     = errors.New("--range: invalid duration")
+	ErrInvalidTime      = errors.New("invalid time, expected YYYY-MM-DD, YYYY-MM-DD HH:MM:SS or RFC3339")
+	ErrEmptyPassword    = errors.New("empty password on the standard input")
+	ErrDatabaseCorrupt  = errors.New("the database check failed")
+	ErrInputRequired    = errors.New("--input is required")
+	ErrEmptyPassphrase  = errors.New("empty passphrase on the standard input")
+)
+
+// passwordLength is the length of the generated passwords, in bytes before
+// the base64 encoding.
+const passwordLength = 12
+
+// command is an admin subcommand. It works directly on the data folder, the
+// server doesn't need to run.
+type command struct {
+	name        string
+	description string
+	run         func(ctx context.Context, env *commandEnv) error
+	flags       func(fs *flag.FlagSet, env *commandEnv)
+	// serverFlags commands take the server flags instead of --folder.
+	serverFlags bool
+}
+
+// commandEnv is shared by a command and its flags.
+type commandEnv struct {
+	folder      string
+	configFile  string
+	passphrase  string
+	postgresURL string
+	stdin       io.Reader
+	output      io.Writer
+
+	// server is set for the serverFlags commands.
+	server *flags
+
+	username      string
+	admin         bool
+	passwordStdin bool
+	all           bool
+
+	format  string
+	span    string
+	from    string
+	to      string
+	outFile string
+	inFile  string
+
+	newPassphraseStdin bool
+}
+
+var commands = []command{
+	{
+		name:        "user create",
+		description: "Create a user, the first one is always an admin",
+		flags: func(fs *flag.FlagSet, env *commandEnv) {
+			fs.StringVar(&env.username, "username", "", "Username of the new user")
+			fs.BoolVar(&env.admin, "admin", false, "Give the admin rights")
+			fs.BoolVar(&env.passwordStdin, "password-stdin", false, "Read the password from the standard input instead of generating one")
+		},
+		run: runUserCreate,
+	},
+	{
+		name:        "user list",
+		description: "List the users",
+		run:         runUserList,
+	},
+	{
+		name:        "user delete",
+		description: "Delete a user, the last admin can't be deleted",
+		flags: func(fs *flag.FlagSet, env *commandEnv) {
+			fs.StringVar(&env.username, "username", "", "Username of the user to delete")
+		},
+		run: runUserDelete,
+	},
+	{
+		name:        "user reset-password",
+		description: "Set a new password and close the user sessions",
+		flags: func(fs *flag.FlagSet, env *commandEnv) {
+			fs.StringVar(&env.username, "username", "", "Username of the user")
+			fs.BoolVar(&env.passwordStdin, "password-stdin", false, "Read the password from the standard input instead of generating one")
+		},
+		run: runUserResetPassword,
+	},
+	{
+		name:        "sessions revoke",
+		description: "Close the web sessions of a user, or of everyone",
+		flags: func(fs *flag.FlagSet, env *commandEnv) {
+			fs.StringVar(&env.username, "username", "", "Username of the user")
+			fs.BoolVar(&env.all, "all", false, "Close the sessions of all the users")
+		},
+		run: runSessionsRevoke,
+	},
+	{
+		name:        "stats export",
+		description: "Export the memory stats",
+		flags: func(fs *flag.FlagSet, env *commandEnv) {
+			fs.StringVar(&env.format, "format", string(sysstats.CSV), "File format (csv, json)")
+			fs.StringVar(&env.span, "range", "24h", "Export the last DURATION (5m, 6h, 7d...)")
+			fs.StringVar(&env.from, "from", "", "Start of the export, replace --range")
+			fs.StringVar(&env.to, "to", "", "End of the export (default now)")
+			fs.StringVar(&env.outFile, "output", "", "Write into FILE instead of the standard output")
+		},
+		run: runStatsExport,
+	},
+	{
+		name:        "config print",
+		description: "Print the effective configuration, the secrets are redacted",
+		serverFlags: true,
+		run:         runConfigPrint,
+	},
+	{
+		name:        "db check",
+		description: "Check the database integrity",
+		run:         runDBCheck,
+	},
+	{
+		name:        "db backup",
+		description: "Save a backup of the database, the server can keep running",
+		flags: func(fs *flag.FlagSet, env *commandEnv) {
+			fs.StringVar(&env.outFile, "output", "", "Write into FILE instead of the backups folder")
+		},
+		run: runDBBackup,
+	},
+	{
+		name:        "db restore",
+		description: "Replace the database with a backup, the server must be stopped",
+		flags: func(fs *flag.FlagSet, env *commandEnv) {
+			fs.StringVar(&env.inFile, "input", "", "Backup FILE to restore")
+		},
+		run: runDBRestore,
+	},
+	{
+		name:        "masterkey rotate",
+		description: "Seal the master key with a new keyfile or passphrase",
+		flags: func(fs *flag.FlagSet, env *commandEnv) {
+			fs.BoolVar(&env.newPassphraseStdin, "new-passphrase-stdin", false, "Seal with the passphrase read from the standard input instead of a new keyfile")
+		},
+		run: runMasterKeyRotate,
+	},
+}
+
+// isCommand returns true if the arguments start with a subcommand instead
+// of the server flags.
+func isCommand(args []string) bool {
+	return len(args) > 1 && !strings.HasPrefix(args[1], "-")
+}
+
+func runCommand(ctx context.Context, args []string, stdin io.Reader, output io.Writer) exitCode {
+	var cmd *command
+	for i := range commands {
+		name := strings.Fields(commands[i].name)
+		if len(args) > len(name) && slices.Equal(args[1:len(name)+1], name) {
+			cmd = &commands[i]
+			break
+		}
+	}
+
+	if cmd == nil {
+		fmt.Fprintf(output, "%s: %q\n\n", ErrUnknownCommand, strings.Join(args[1:min(len(args), 3)], " "))
+		io.WriteString(output, commandsDescription())
+		return exitInitError
+	}
+
+	env := commandEnv{stdin: stdin, output: output}
+	cmdArgs := args[len(strings.Fields(cmd.name)):]
+
+	if cmd.serverFlags {
+		var err error
+		env.server, err = parseFlags(cmdArgs, getDefaultFolder(), output)
+		if err != nil {
+			return exitInitError
+		}
+
+		if env.server == nil {
+			return exitOK
+		}
+	} else {
+		code, ok := env.parseFlags(cmd, cmdArgs)
+		if !ok {
+			return code
+		}
+	}
+
+	err := cmd.run(ctx, &env)
+	if err != nil {
+		fmt.Fprintf(output, "Error: %s\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// parseFlags parses the command flags. The folder, the passphrase and the
+// database url are also read from the env variables and the config file, like
+// the server ones.
+func (env *commandEnv) parseFlags(cmd *command, args []string) (exitCode, bool) {
+	fs := flag.NewFlagSet(binaryName+" "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(env.output)
+	fs.StringVar(&env.folder, "folder", getDefaultFolder(), "Specify you data directory location")
+	fs.StringVar(&env.configFile, "config", "", "Read the folder from the config FILE")
+	fs.StringVar(&env.passphrase, "passphrase", "", "Passphrase of the master key, if sealed with one")
+	fs.StringVar(&env.postgresURL, "postgres-url", "", "Use the PostgreSQL database at URL instead of SQLite")
+	if cmd.flags != nil {
+		cmd.flags(fs, env)
+	}
+
+	err := fs.Parse(args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK, false
+	}
+
+	if err != nil {
+		return exitInitError, false
+	}
+
+	_, _, err = loadSettings(fs, env.configFile, []string{"folder", "passphrase", "postgres-url"})
+	if err != nil {
+		fmt.Fprintln(env.output, err)
+		return exitInitError, false
+	}
+
+	return exitOK, true
+}
+
+func commandsDescription() string {
+	w := strings.Builder{}
+	w.WriteString("Commands:\n")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(&w, "  %-22s%s\n", cmd.name, cmd.description)
+	}
+
+	fmt.Fprintf(&w, "\nRun '%s <command> --help' for the command flags.\n", binaryName)
+
+	return w.String()
+}
+
+// exec runs fn with the services of the data folder.
+func (env *commandEnv) exec(ctx context.Context, fn any) error {
+	cfg, err := NewConfigFromFlags(&flags{
+		Folder:      env.folder,
+		Passphrase:  env.passphrase,
+		PostgresURL: env.postgresURL,
+		LogLevel:    "error",
+	})
+	if err != nil {
+		return err
+	}
+
+	return server.Exec(ctx, cfg, fn)
+}
+
+// password returns the password read from the standard input with
+// --password-stdin, or a generated one.
+func (env *commandEnv) password() (secret.Text, bool, error) {
+	if !env.passwordStdin {
+		raw := make([]byte, passwordLength)
+		_, err := rand.Read(raw)
+		if err != nil {
+			return secret.Text{}, false, fmt.Errorf("failed to generate the password: %w", err)
+		}
+
+		return secret.NewText(base64.RawURLEncoding.EncodeToString(raw)), true, nil
+	}
+
+	line, err := env.readLine()
+	if err != nil {
+		return secret.Text{}, false, err
+	}
+
+	if line == "" {
+		return secret.Text{}, false, ErrEmptyPassword
+	}
+
+	return secret.NewText(line), false, nil
+}
+
+// readLine reads the first line of the standard input.
+func (env *commandEnv) readLine() (string, error) {
+	line, err := bufio.NewReader(env.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read the standard input: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func runUserCreate(ctx context.Context, env *commandEnv) error {
+	if env.username == "" {
+		return ErrUsernameRequired
+	}
+
+	password, generated, err := env.password()
+	if err != nil {
+		return err
+	}
+
+	return env.exec(ctx, func(usersSvc users.Service) error {
+		allUsers, err := usersSvc.GetAll(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to list the users: %w", err)
+		}
+
+		var user *users.User
+		if len(allUsers) == 0 {
+			user, err = usersSvc.Bootstrap(ctx, &users.BootstrapCmd{
+				Username: env.username,
+				Password: password,
+			})
+		} else {
+			user, err = usersSvc.Create(ctx, &users.CreateCmd{
+				CreatedBy: firstAdmin(allUsers),
+				Username:  env.username,
+				Password:  password,
+				IsAdmin:   env.admin,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create the user: %w", err)
+		}
+
+		fmt.Fprintf(env.output, "User %q created (admin: %t)\n", user.Username(), user.IsAdmin())
+		if generated {
+			fmt.Fprintf(env.output, "Password: %s\n", password.Raw())
+		}
+
+		return nil
+	})
+}
+
+// firstAdmin returns the oldest admin, used as creator of the users created
+// from the command line.
+func firstAdmin(allUsers []users.User) *users.User {
+	var res *users.User
+	for i, user := range allUsers {
+		if user.IsAdmin() && (res == nil || user.CreatedAt().Before(res.CreatedAt())) {
+			res = &allUsers[i]
+		}
+	}
+
+	return res
+}
+
+func runUserList(ctx context.Context, env *commandEnv) error {
+	return env.exec(ctx, func(usersSvc users.Service) error {
+		allUsers, err := usersSvc.GetAll(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to list the users: %w", err)
+		}
+
+		w := tabwriter.NewWriter(env.output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "USERNAME\tADMIN\tSTATUS\tCREATED")
+		for _, user := range allUsers {
+			fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", user.Username(), user.IsAdmin(), user.Status(), user.CreatedAt().Local().Format(time.DateTime))
+		}
+
+		return w.Flush()
+	})
+}
+
+func runUserDelete(ctx context.Context, env *commandEnv) error {
+	if env.username == "" {
+		return ErrUsernameRequired
+	}
+
+	return env.exec(ctx, func(usersSvc users.Service) error {
+		user, err := usersSvc.GetByUsername(ctx, env.username)
+		if err != nil {
+			return fmt.Errorf("failed to get the user %q: %w", env.username, err)
+		}
+
+		err = usersSvc.AddToDeletion(ctx, user.ID())
+		if err != nil {
+			return fmt.Errorf("failed to delete the user: %w", err)
+		}
+
+		fmt.Fprintf(env.output, "User %q deleted\n", user.Username())
+
+		return nil
+	})
+}
+
+func runUserResetPassword(ctx context.Context, env *commandEnv) error {
+	if env.username == "" {
+		return ErrUsernameRequired
+	}
+
+	password, generated, err := env.password()
+	if err != nil {
+		return err
+	}
+
+	return env.exec(ctx, func(usersSvc users.Service, sessionsSvc websessions.Service) error {
+		user, err := usersSvc.GetByUsername(ctx, env.username)
+		if err != nil {
+			return fmt.Errorf("failed to get the user %q: %w", env.username, err)
+		}
+
+		cmd := users.UpdatePasswordCmd{UserID: user.ID(), NewPassword: password}
+		err = cmd.Validate()
+		if err != nil {
+			return err
+		}
+
+		err = usersSvc.UpdateUserPassword(ctx, &cmd)
+		if err != nil {
+			return fmt.Errorf("failed to update the password: %w", err)
+		}
+
+		// The old password may be compromised.
+		err = sessionsSvc.DeleteAll(ctx, user.ID())
+		if err != nil {
+			return fmt.Errorf("failed to revoke the sessions: %w", err)
+		}
+
+		fmt.Fprintf(env.output, "Password of %q updated\n", user.Username())
+		if generated {
+			fmt.Fprintf(env.output, "Password: %s\n", password.Raw())
+		}
+
+		return nil
+	})
+}
+
+func runSessionsRevoke(ctx context.Context, env *commandEnv) error {
+	if (env.username == "") == !env.all {
+		return ErrSessionsTarget
+	}
+
+	return env.exec(ctx, func(usersSvc users.Service, sessionsSvc websessions.Service) error {
+		var targets []users.User
+		if env.all {
+			allUsers, err := usersSvc.GetAll(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("failed to list the users: %w", err)
+			}
+
+			targets = allUsers
+		} else {
+			user, err := usersSvc.GetByUsername(ctx, env.username)
+			if err != nil {
+				return fmt.Errorf("failed to get the user %q: %w", env.username, err)
+			}
+
+			targets = []users.User{*user}
+		}
+
+		for _, user := range targets {
+			err := sessionsSvc.DeleteAll(ctx, user.ID())
+			if err != nil {
+				return fmt.Errorf("failed to revoke the sessions of %q: %w", user.Username(), err)
+			}
+
+			fmt.Fprintf(env.output, "Sessions of %q revoked\n", user.Username())
+		}
+
+		return nil
+	})
+}
+
+func runStatsExport(ctx context.Context, env *commandEnv) error {
+	format, err := sysstats.ParseFormat(env.format)
+	if err != nil {
+		return fmt.Errorf("--format: %w", err)
+	}
+
+	start, end, err := parseExportRange(env, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return env.exec(ctx, func(sysstatsSvc sysstats.Service) error {
+		stats, err := sysstatsSvc.GetRange(ctx, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to get the stats: %w", err)
+		}
+
+		w := env.output
+		if env.outFile != "" {
+			file, err := os.Create(env.outFile)
+			if err != nil {
+				return fmt.Errorf("failed to create %q: %w", env.outFile, err)
+			}
+			defer file.Close()
+
+			w = file
+		}
+
+		err = sysstats.Encode(w, format, stats)
+		if err != nil {
+			return fmt.Errorf("failed to write the stats: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// parseExportRange returns the period set with --from and --to, or with
+// --range if --from is missing.
+func parseExportRange(env *commandEnv, now time.Time) (time.Time, time.Time, error) {
+	end := now
+	if env.to != "" {
+		var err error
+		end, err = parseTime(env.to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("--to: %w", err)
+		}
+	}
+
+	if env.from != "" {
+		start, err := parseTime(env.from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("--from: %w", err)
+		}
+
+		return start, end, nil
+	}
+
+	span, err := parseSpan(env.span)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return end.Add(-span), end, nil
+}
+
+// parseSpan parses a duration, with the days unit in addition to the
+// time.ParseDuration ones.
+func parseSpan(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, ErrInvalidRange
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	span, err := time.ParseDuration(s)
+	if err != nil || span <= 0 {
+		return 0, ErrInvalidRange
+	}
+
+	return span, nil
+}
+
+// parseTime parses a time, in the local timezone if none is given.
+func parseTime(s string) (time.Time, error) {
+	res, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return res, nil
+	}
+
+	for _, layout := range []string{time.DateTime, time.DateOnly} {
+		res, err = time.ParseInLocation(layout, s, time.Local)
+		if err == nil {
+			return res, nil
+		}
+	}
+
+	return time.Time{}, ErrInvalidTime
+}
+
+func runConfigPrint(_ context.Context, env *commandEnv) error {
+	configFile := env.server.ConfigFile
+	if configFile == "" {
+		configFile = "none"
+	}
+
+	fmt.Fprintf(env.output, "# Config file: %s\n", configFile)
+	fmt.Fprintf(env.output, "# Precedence: flag > env > file > default\n")
+
+	w := tabwriter.NewWriter(env.output, 0, 0, 2, ' ', 0)
+	for _, s := range env.server.Settings {
+		fmt.Fprintf(w, "%s: %s\t# %s\n", s.Name, formatSetting(s), s.Source)
+	}
+
+	return w.Flush()
+}
+
+// formatSetting formats the value as YAML, to be copied in a config file.
+func formatSetting(s setting) string {
+	value := s.Value.(flag.Getter).Get()
+
+	if slices.Contains(secretSettings, s.Name) && value != "" {
+		return `"<redacted>"`
+	}
+
+	if str, ok := value.(string); ok {
+		return strconv.Quote(str)
+	}
+
+	return fmt.Sprint(value)
+}
+
+func runDBCheck(ctx context.Context, env *commandEnv) error {
+	return env.exec(ctx, func(db *sql.DB) error {
+		problems, err := sqlstorage.Check(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			fmt.Fprintln(env.output, "ok")
+			return nil
+		}
+
+		for _, problem := range problems {
+			fmt.Fprintln(env.output, problem)
+		}
+
+		return fmt.Errorf("%w: %d problems found", ErrDatabaseCorrupt, len(problems))
+	})
+}
+
+func runDBBackup(ctx context.Context, env *commandEnv) error {
+	return env.exec(ctx, func(backupsSvc backups.Service) error {
+		if env.outFile == "" {
+			backup, err := backupsSvc.Create(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to create the backup: %w", err)
+			}
+
+			fmt.Fprintf(env.output, "Backup saved in %s (%s)\n", path.Join(env.folder, backups.Dir, backup.Name()), backup.Size().HR())
+
+			return nil
+		}
+
+		// The file is created first in order to never overwrite an existing
+		// one, "VACUUM INTO" accepts the empty files.
+		file, err := os.OpenFile(env.outFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create %q: %w", env.outFile, err)
+		}
+		file.Close()
+
+		err = backupsSvc.WriteFile(ctx, env.outFile)
+		if err != nil {
+			os.Remove(env.outFile)
+			return fmt.Errorf("failed to write the backup: %w", err)
+		}
+
+		fmt.Fprintf(env.output, "Backup saved in %s\n", env.outFile)
+
+		return nil
+	})
+}
+
+// runDBRestore checks the backup before replacing the database with it. The
+// older schemas are accepted, they are migrated during the next start.
+func runDBRestore(ctx context.Context, env *commandEnv) error {
+	if env.inFile == "" {
+		return ErrInputRequired
+	}
+
+	if env.postgresURL != "" {
+		return fmt.Errorf("db restore: %w, use pg_restore with PostgreSQL", sqlstorage.ErrSQLiteOnly)
+	}
+
+	dbPath := path.Join(env.folder, dbFileName)
+
+	lock, err := sqlstorage.LockExclusive(dbPath)
+	if errors.Is(err, sqlstorage.ErrDatabaseInUse) {
+		return fmt.Errorf("db restore: %w, stop the server first", err)
+	}
+
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	backup, err := sqlstorage.OpenReadOnly(env.inFile)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", env.inFile, err)
+	}
+	defer backup.Close()
+
+	problems, err := sqlstorage.Check(ctx, backup)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d problems found in the backup, run \"db check\" on it", ErrDatabaseCorrupt, len(problems))
+	}
+
+	version, err := migrations.CheckVersion(ctx, backup)
+	if err != nil {
+		return fmt.Errorf("invalid backup: %w", err)
+	}
+
+	// The server must be able to open the master key of the backup, or all
+	// its secrets would be lost.
+	configSvc := config.Init(backup, tools.NewToolbox(tools.Config{})).Service
+
+	err = masterkey.Check(ctx, masterkey.Config{Passphrase: secret.NewText(env.passphrase)}, env.folder, afero.NewOsFs(), configSvc)
+	if err != nil {
+		return fmt.Errorf("the master key of the backup can't be opened: %w", err)
+	}
+
+	backup.Close()
+
+	oldSuffix := ".before-restore-" + time.Now().UTC().Format("20060102T150405Z")
+
+	err = sqlstorage.Replace(afero.NewOsFs(), dbPath, env.inFile, oldSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to replace the database: %w", err)
+	}
+
+	fmt.Fprintf(env.output, "Database restored from %s (schema version %d)\n", env.inFile, version)
+	fmt.Fprintf(env.output, "The previous database is kept in %s\n", dbPath+oldSuffix)
+
+	return nil
+}
+
+// runMasterKeyRotate seals the master key again. The data doesn't need to be
+// encrypted again: the master key is the same, only its seal changes.
+func runMasterKeyRotate(ctx context.Context, env *commandEnv) error {
+	passphrase := secret.Empty
+	if env.newPassphraseStdin {
+		line, err := env.readLine()
+		if err != nil {
+			return err
+		}
+
+		if line == "" {
+			return ErrEmptyPassphrase
+		}
+
+		passphrase = secret.NewText(line)
+	}
+
+	return env.exec(ctx, func(masterkeySvc masterkey.Service) error {
+		err := masterkeySvc.Rotate(ctx, passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to rotate the master key: %w", err)
+		}
+
+		if env.newPassphraseStdin {
+			fmt.Fprintf(env.output, "Master key sealed with the new passphrase, start the server with --passphrase or $%s\n", envName("passphrase"))
+		} else {
+			fmt.Fprintf(env.output, "Master key sealed with a new keyfile in %s\n", env.folder)
+		}
+
+		fmt.Fprintln(env.output, "The previous keyfile is kept in the data folder, the backups made before the rotation need it to be restored")
+
+		return nil
+	})
+}
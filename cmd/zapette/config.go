@@ -12,6 +12,7 @@ import (
 	"log/slog"
 	"math/big"
 	"net"
+	"net/url"
 	"os"
 	"path"
 	"strconv"
@@ -19,21 +20,33 @@ import (
 	"time"
 
 	"github.com/Peltoche/zapette/assets"
+	"github.com/Peltoche/zapette/internal/agent"
 	"github.com/Peltoche/zapette/internal/server"
+	"github.com/Peltoche/zapette/internal/service/masterkey"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/logger"
 	"github.com/Peltoche/zapette/internal/tools/response"
 	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/secret"
 	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
 	"github.com/Peltoche/zapette/internal/web/html"
 	"github.com/spf13/afero"
 )
 
 var (
-	ErrConflictTLSConfig = errors.New("can't use --self-signed-cert and --tls-key at the same time")
-	ErrDevFlagRequire    = errors.New("this flag require the --dev flag setup")
+	ErrConflictTLSConfig    = errors.New("can't use --self-signed-cert and --tls-key at the same time")
+	ErrDevFlagRequire       = errors.New("this flag require the --dev flag setup")
+	ErrInvalidAgentServer   = errors.New("--agent-server: must be an http(s) url")
+	ErrAgentTokenRequired   = errors.New("--agent-token or $" + agentTokenEnv + " is required with --agent-server")
+	ErrInvalidAgentInterval = errors.New("--agent-interval: must be at least 1s")
 )
 
+// dbFileName is the SQLite database, inside the data folder.
+const dbFileName = "db.sqlite"
+
+// agentTokenEnv keeps the token out of the process list, see envName.
+const agentTokenEnv = "ZAPETTE_AGENT_TOKEN"
+
 type flags struct {
 	LogLevel       string
 	Folder         string
@@ -49,6 +62,16 @@ type flags struct {
 	HotReload      bool
 	PrintVersion   bool
 	PrintHelp      bool
+	AgentServer    string
+	AgentToken     string
+	AgentInterval  time.Duration
+	Passphrase     string
+	PostgresURL    string
+	// ConfigFile is the config file given with --config, replaced by the
+	// file actually loaded, if any.
+	ConfigFile string
+	// Settings are the values used, filled by loadSettings.
+	Settings []setting
 }
 
 func NewConfigFromFlags(flags *flags) (server.Config, error) {
@@ -60,20 +83,9 @@ func NewConfigFromFlags(flags *flags) (server.Config, error) {
 		return server.Config{}, fmt.Errorf("--memory-fs: %w", ErrDevFlagRequire)
 	}
 
-	var logLevel slog.Level
-	switch strings.ToLower(flags.LogLevel) {
-	case "info":
-		logLevel = slog.LevelInfo
-	case "warn", "warning":
-		logLevel = slog.LevelWarn
-	case "err", "error":
-		logLevel = slog.LevelError
-	default:
-		return server.Config{}, errors.New("invalid log level")
-	}
-
-	if flags.Debug {
-		logLevel = slog.LevelDebug
+	logLevel, err := parseLogLevel(flags)
+	if err != nil {
+		return server.Config{}, err
 	}
 
 	var fs afero.Fs
@@ -84,10 +96,10 @@ func NewConfigFromFlags(flags *flags) (server.Config, error) {
 		storagePath = ":memory:"
 	} else {
 		fs = afero.NewOsFs()
-		storagePath = path.Join(flags.Folder, "db.sqlite")
+		storagePath = path.Join(flags.Folder, dbFileName)
 	}
 
-	err := fs.MkdirAll(flags.Folder, 0o755)
+	err = fs.MkdirAll(flags.Folder, 0o755)
 	if err != nil && !errors.Is(err, os.ErrExist) {
 		return server.Config{}, fmt.Errorf("failed to create %q: %w", flags.Folder, err)
 	}
@@ -116,7 +128,8 @@ func NewConfigFromFlags(flags *flags) (server.Config, error) {
 			HostNames: flags.HTTPHostnames,
 		},
 		Storage: sqlstorage.Config{
-			Path: storagePath,
+			Path:        storagePath,
+			PostgresURL: flags.PostgresURL,
 		},
 		Assets: assets.Config{
 			HotReload: flags.HotReload,
@@ -131,6 +144,9 @@ func NewConfigFromFlags(flags *flags) (server.Config, error) {
 			},
 		},
 		Folder: server.Folder(flags.Folder),
+		MasterKey: masterkey.Config{
+			Passphrase: secret.NewText(flags.Passphrase),
+		},
 		HTML: html.Config{
 			PrettyRender: flags.Dev,
 			HotReload:    flags.HotReload,
@@ -138,6 +154,58 @@ func NewConfigFromFlags(flags *flags) (server.Config, error) {
 	}, nil
 }
 
+// NewAgentConfigFromFlags returns the config of the agent mode, enabled with
+// the --agent-server flag.
+func NewAgentConfigFromFlags(flags *flags) (agent.Config, error) {
+	logLevel, err := parseLogLevel(flags)
+	if err != nil {
+		return agent.Config{}, err
+	}
+
+	serverURL, err := url.Parse(flags.AgentServer)
+	if err != nil || (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		return agent.Config{}, ErrInvalidAgentServer
+	}
+
+	if flags.AgentToken == "" {
+		return agent.Config{}, ErrAgentTokenRequired
+	}
+
+	if flags.AgentInterval < time.Second {
+		return agent.Config{}, ErrInvalidAgentInterval
+	}
+
+	return agent.Config{
+		Tools: tools.Config{
+			Log: logger.Config{
+				Level:  logLevel,
+				Output: os.Stderr,
+			},
+		},
+		FS:       afero.NewOsFs(),
+		Server:   flags.AgentServer,
+		Token:    secret.NewText(flags.AgentToken),
+		Interval: flags.AgentInterval,
+	}, nil
+}
+
+func parseLogLevel(flags *flags) (slog.Level, error) {
+	if flags.Debug {
+		return slog.LevelDebug, nil
+	}
+
+	switch strings.ToLower(flags.LogLevel) {
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "err", "error":
+		return slog.LevelError, nil
+	default:
+		return 0, errors.New("invalid log level")
+	}
+}
+
 func generateSelfSignedCertificate(hostnames []string, folderPath string, fs afero.Fs) (string, string, error) {
 	sslfolder := path.Join(folderPath, "ssl")
 	certificatePath := path.Join(sslfolder, "cert.pem")
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 
+	"github.com/Peltoche/zapette/internal/agent"
 	"github.com/Peltoche/zapette/internal/server"
 	"github.com/Peltoche/zapette/internal/tools/buildinfos"
 	"github.com/adrg/xdg"
@@ -19,6 +20,15 @@ const (
 
 Usage:
   ` + binaryName + ` [flags...]
+  ` + binaryName + ` --agent-server <url> --agent-token <token>
+  ` + binaryName + ` <command> [flags...]
+
+Configuration:
+  Each flag, except --config, --help and --version, can also be set with an
+  environment variable or in a YAML config file: --http-port is set with
+  $ZAPETTE_HTTP_PORT or with "http-port: 8080". The first value found wins:
+  flag > environment > config file > default. Run '` + binaryName + ` config print'
+  to show the effective configuration.
 
 Flags:
 `
@@ -37,13 +47,17 @@ const (
 func main() {
 	output := os.Stdout
 
-	code := mainRun(os.Args, output)
+	code := mainRun(os.Args, os.Stdin, output)
 	os.Exit(int(code))
 }
 
-func mainRun(args []string, output io.Writer) exitCode {
+func mainRun(args []string, stdin io.Reader, output io.Writer) exitCode {
 	ctx := context.Background()
 
+	if isCommand(args) {
+		return runCommand(ctx, args, stdin, output)
+	}
+
 	defaultFolder := getDefaultFolder()
 	flags, err := parseFlags(args, defaultFolder, output)
 	if err != nil {
@@ -59,6 +73,10 @@ func mainRun(args []string, output io.Writer) exitCode {
 		return exitOK
 	}
 
+	if flags.AgentServer != "" {
+		return runAgent(ctx, flags, output)
+	}
+
 	cfg, err := NewConfigFromFlags(flags)
 	if err != nil {
 		io.WriteString(output, err.Error())
@@ -73,6 +91,23 @@ func mainRun(args []string, output io.Writer) exitCode {
 	return exitOK
 }
 
+// runAgent runs the agent mode: the stats are pushed to a central server
+// instead of being served locally.
+func runAgent(ctx context.Context, flags *flags, output io.Writer) exitCode {
+	cfg, err := NewAgentConfigFromFlags(flags)
+	if err != nil {
+		io.WriteString(output, err.Error())
+		return exitInitError
+	}
+
+	err = agent.Run(ctx, cfg)
+	if err != nil {
+		return exitError
+	}
+
+	return exitOK
+}
+
 func getDefaultFolder() string {
 	var defaultFolder string
 
@@ -94,6 +129,30 @@ func getDefaultFolder() string {
 func parseFlags(args []string, defaultFolder string, output io.Writer) (*flags, error) {
 	flags := flags{}
 
+	fs := newServerFlagSet(&flags, defaultFolder, output)
+
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	if flags.PrintHelp {
+		io.WriteString(output, binaryDescription)
+		fs.PrintDefaults()
+		io.WriteString(output, "\n"+commandsDescription())
+		return nil, nil
+	}
+
+	flags.ConfigFile, flags.Settings, err = loadSettings(fs, flags.ConfigFile, settingNames())
+	if err != nil {
+		fmt.Fprintln(output, err)
+		return nil, err
+	}
+
+	return &flags, nil
+}
+
+func newServerFlagSet(flags *flags, defaultFolder string, output io.Writer) *flag.FlagSet {
 	fs := flag.NewFlagSet("flags", flag.ContinueOnError)
 	fs.SetOutput(output)
 
@@ -107,6 +166,8 @@ func parseFlags(args []string, defaultFolder string, output io.Writer) (*flags,
 	fs.StringVar(&flags.LogLevel, "log-level", "info", "Log message verbosity LEVEL (debug, info, warning, error)")
 
 	fs.StringVar(&flags.Folder, "folder", defaultFolder, "Specify you data directory location")
+	fs.StringVar(&flags.Passphrase, "passphrase", "", "Seal the master key with PASSPHRASE instead of the keyfile, better given with $"+envName("passphrase"))
+	fs.StringVar(&flags.PostgresURL, "postgres-url", "", "Store the data in the PostgreSQL database at URL instead of SQLite")
 	fs.BoolVar(&flags.MemoryFS, "memory-fs", false, "Replace the OS filesystem by a in-memory stub. *Every data will disapear after each restart*.")
 
 	fs.StringVar(&flags.TLSCert, "tls-cert", "", "Public HTTPS certificate file (.crt)")
@@ -116,19 +177,14 @@ func parseFlags(args []string, defaultFolder string, output io.Writer) (*flags,
 	fs.IntVar(&flags.HTTPPort, "http-port", 5764, "Web server port number.")
 	fs.StringVar(&flags.HTTPHost, "http-host", "0.0.0.0", "Web server IP address")
 
-	fs.BoolVar(&flags.PrintVersion, "version", false, "version for zapette")
-	fs.BoolVar(&flags.PrintHelp, "help", false, "help for zapette")
+	fs.StringVar(&flags.AgentServer, "agent-server", "", "Run as an agent pushing the stats to the zapette at this URL")
+	fs.StringVar(&flags.AgentToken, "agent-token", "", "Token of the host, given by the central zapette")
+	fs.DurationVar(&flags.AgentInterval, "agent-interval", agent.DefaultInterval, "Interval between two pushes of the agent")
 
-	err := fs.Parse(args[1:])
-	if err != nil {
-		return nil, err
-	}
+	fs.StringVar(&flags.ConfigFile, "config", "", "Read the settings from FILE (default $"+configEnv+" or "+binaryName+"/"+configFileName+" in the data directories)")
 
-	if flags.PrintHelp {
-		io.WriteString(output, binaryDescription)
-		fs.PrintDefaults()
-		return nil, nil
-	}
+	fs.BoolVar(&flags.PrintVersion, "version", false, "version for zapette")
+	fs.BoolVar(&flags.PrintHelp, "help", false, "help for zapette")
 
-	return &flags, nil
+	return fs
 }
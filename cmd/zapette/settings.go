@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// configEnv gives the config file path, replaced by --config.
+	configEnv      = "ZAPETTE_CONFIG"
+	configFileName = "config.yaml"
+	envPrefix      = "ZAPETTE_"
+)
+
+var (
+	ErrUnknownSetting    = errors.New("unknown setting")
+	ErrInvalidConfigFile = errors.New("invalid config file")
+)
+
+// source is where the value of a setting comes from. The first one found
+// wins: flag > env > file > default.
+type source string
+
+const (
+	sourceFlag    source = "flag"
+	sourceEnv     source = "env"
+	sourceFile    source = "file"
+	sourceDefault source = "default"
+)
+
+// notSettings are the flags which can only be given on the command line.
+var notSettings = []string{"config", "help", "version"}
+
+// secretSettings are redacted by `config print`.
+var secretSettings = []string{"agent-token", "passphrase", "postgres-url"}
+
+// setting is the effective value of a flag.
+type setting struct {
+	Name   string
+	Value  flag.Value
+	Source source
+}
+
+// settingNames returns the name of the server flags which can also be set
+// with the env variables and the config file.
+func settingNames() []string {
+	fs := newServerFlagSet(&flags{}, "", io.Discard)
+
+	res := []string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		if !slices.Contains(notSettings, f.Name) {
+			res = append(res, f.Name)
+		}
+	})
+
+	return res
+}
+
+// envName returns the env variable of a setting: "http-port" is set with
+// $ZAPETTE_HTTP_PORT.
+func envName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// loadSettings sets the flags in names which are not given on the command
+// line, from the env variables then from the config file. It returns the
+// config file used, empty if none, and the effective settings.
+func loadSettings(fs *flag.FlagSet, configPath string, names []string) (string, []setting, error) {
+	configPath = findConfigFile(configPath)
+
+	file, err := readConfigFile(configPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fromFlags := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { fromFlags[f.Name] = true })
+
+	res := []setting{}
+	for _, name := range names {
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+
+		src := sourceDefault
+		envValue, inEnv := os.LookupEnv(envName(name))
+		fileValue, inFile := file[name]
+
+		switch {
+		case fromFlags[name]:
+			src = sourceFlag
+		case inEnv:
+			src = sourceEnv
+			err = fs.Set(name, envValue)
+			if err != nil {
+				return "", nil, fmt.Errorf("$%s: %w", envName(name), err)
+			}
+		case inFile:
+			src = sourceFile
+			err = fs.Set(name, fileValue)
+			if err != nil {
+				return "", nil, fmt.Errorf("%s: %s: %w", configPath, name, err)
+			}
+		}
+
+		res = append(res, setting{Name: name, Value: f.Value, Source: src})
+	}
+
+	return configPath, res, nil
+}
+
+// findConfigFile returns the config file given with --config or
+// $ZAPETTE_CONFIG, or else the first config.yaml found in the folders
+// scanned by getDefaultFolder. It returns an empty path if there is none.
+func findConfigFile(configPath string) string {
+	if configPath != "" {
+		return configPath
+	}
+
+	if configPath = os.Getenv(configEnv); configPath != "" {
+		return configPath
+	}
+
+	for _, dir := range configDirs {
+		filePath := path.Join(dir, binaryName, configFileName)
+
+		_, err := os.Stat(filePath)
+		if err == nil {
+			return filePath
+		}
+	}
+
+	return ""
+}
+
+// readConfigFile reads a YAML file with a key per setting, named like the
+// flags:
+//
+//	folder: /var/lib/zapette
+//	http-port: 8080
+func readConfigFile(configPath string) (map[string]string, error) {
+	res := map[string]string{}
+
+	if configPath == "" {
+		return res, nil
+	}
+
+	rawFile, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %w", err)
+	}
+
+	var values map[string]any
+	err = yaml.Unmarshal(rawFile, &values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfigFile, configPath, err)
+	}
+
+	names := settingNames()
+	for name, value := range values {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("%w: %s: %q", ErrUnknownSetting, configPath, name)
+		}
+
+		switch value.(type) {
+		case string, bool, int, float64:
+			res[name] = fmt.Sprint(value)
+		case nil:
+			res[name] = ""
+		default:
+			return nil, fmt.Errorf("%w: %s: %s: must be a single value", ErrInvalidConfigFile, configPath, name)
+		}
+	}
+
+	return res, nil
+}
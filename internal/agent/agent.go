@@ -0,0 +1,156 @@
+// Package agent runs zapette as a lightweight agent: the stats of the local
+// host are collected and pushed to a central zapette instead of being stored
+// and served locally.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/sysinfos"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/spf13/afero"
+)
+
+const (
+	// DefaultInterval is the interval between two pushes. It matches the
+	// default collection interval of the server.
+	DefaultInterval = 5 * time.Second
+
+	requestTimeout = 10 * time.Second
+)
+
+// ErrRejected is returned when the server refuses the token. Retrying is
+// useless until the agent is configured with a new token.
+var ErrRejected = errors.New("token rejected by the server")
+
+type Config struct {
+	Tools tools.Config
+	FS    afero.Fs
+	// Server is the base url of the central zapette.
+	Server   string
+	Token    secret.Text
+	Interval time.Duration
+}
+
+type agent struct {
+	infos     sysinfos.Service
+	collector sysstats.Collector
+	client    *http.Client
+	logger    *slog.Logger
+	url       string
+	token     secret.Text
+}
+
+// Run pushes the stats at the start of each interval, based on the computer
+// clock, until the context is canceled or a termination signal is received.
+func Run(ctx context.Context, cfg Config) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	tools := tools.NewToolbox(cfg.Tools)
+
+	infos, err := sysinfos.Init(cfg.FS, nil, tools)
+	if err != nil {
+		return err
+	}
+
+	a := newAgent(cfg, infos, sysstats.NewCollector(cfg.FS, tools), tools)
+
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	a.logger.Info("agent started", slog.String("server", cfg.Server), slog.Duration("interval", interval))
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			a.logger.Info("agent stopped")
+			return nil
+		}
+
+		// Align the pushes on the interval, like the server collections.
+		now := time.Now()
+		timer.Reset(now.Truncate(interval).Add(interval).Sub(now))
+
+		err = a.push(ctx)
+		switch {
+		case errors.Is(err, ErrRejected):
+			a.logger.Error("failed to push the stats", slog.String("error", err.Error()))
+			return err
+		case err != nil && ctx.Err() == nil:
+			// The server is probably unreachable, the next push will
+			// retry.
+			a.logger.Warn("failed to push the stats", slog.String("error", err.Error()))
+		}
+	}
+}
+
+func newAgent(cfg Config, infos sysinfos.Service, collector sysstats.Collector, tools tools.Tools) *agent {
+	return &agent{
+		infos:     infos,
+		collector: collector,
+		client:    &http.Client{Timeout: requestTimeout},
+		logger:    tools.Logger().With(slog.String("source", "agent")),
+		url:       strings.TrimSuffix(cfg.Server, "/") + hosts.PushPath,
+		token:     cfg.Token,
+	}
+}
+
+func (a *agent) push(ctx context.Context) error {
+	stats, err := a.collector.Collect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to collect the stats: %w", err)
+	}
+
+	infos := a.infos.GetInfos(ctx)
+
+	body, err := json.Marshal(hosts.NewReport(infos.Hostname(), infos.Uptime(), stats))
+	if err != nil {
+		return fmt.Errorf("failed to marshal the report: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+a.token.Raw())
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized:
+		return ErrRejected
+	case res.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
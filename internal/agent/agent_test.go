@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/sysinfos"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/startutils"
+	"github.com/go-chi/chi/v5"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAgent(t *testing.T, hostsSvc hosts.Service) *agent {
+	t.Helper()
+
+	tools := tools.NewToolboxForTest(t)
+	afs := afero.NewMemMapFs()
+	startutils.LoadFileinFS(t, afs, "../service/sysinfos/testdata/uptime.txt", "/proc/uptime")
+	startutils.LoadFileinFS(t, afs, "../service/sysinfos/testdata/hostname.txt", "/etc/hostname")
+	startutils.LoadFileinFS(t, afs, "../service/sysstats/testdata/meminfo.txt", "/proc/meminfo")
+
+	infos, err := sysinfos.Init(afs, nil, tools)
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	hosts.NewHTTPHandler(hostsSvc).Register(router, nil)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return newAgent(Config{
+		Server: server.URL + "/",
+		Token:  secret.NewText("some-token"),
+	}, infos, sysstats.NewCollector(afs, tools), tools)
+}
+
+func TestAgent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("push success", func(t *testing.T) {
+		hostsMock := hosts.NewMockService(t)
+		agent := newTestAgent(t, hostsMock)
+
+		hostsMock.On("Push", mock.Anything, mock.MatchedBy(func(cmd *hosts.PushCmd) bool {
+			return cmd.Token.Raw() == "some-token" &&
+				cmd.Report.Hostname() == "zapettePC" &&
+				cmd.Report.Stats().Memory() != nil
+		})).Return(nil).Once()
+
+		err := agent.push(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("push with a rejected token", func(t *testing.T) {
+		hostsMock := hosts.NewMockService(t)
+		agent := newTestAgent(t, hostsMock)
+
+		hostsMock.On("Push", mock.Anything, mock.Anything).Return(errs.Unauthorized(hosts.ErrInvalidToken)).Once()
+
+		err := agent.push(ctx)
+		require.ErrorIs(t, err, ErrRejected)
+	})
+
+	t.Run("push with a server error", func(t *testing.T) {
+		hostsMock := hosts.NewMockService(t)
+		agent := newTestAgent(t, hostsMock)
+
+		hostsMock.On("Push", mock.Anything, mock.Anything).Return(errs.Internal(context.DeadlineExceeded)).Once()
+
+		err := agent.push(ctx)
+		require.ErrorContains(t, err, "unexpected status 500")
+	})
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notification_channels;
+
+DROP INDEX IF EXISTS idx_notification_channels_id;
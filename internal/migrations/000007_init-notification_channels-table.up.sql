@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS notification_channels (
+  "id" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "kind" TEXT NOT NULL,
+  "settings" TEXT NOT NULL,
+  "sealed_key" BLOB NOT NULL,
+  "secret" BLOB,
+  "created_at" TEXT NOT NULL,
+  "created_by" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_channels_id ON notification_channels(id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS silences (
+  "id" TEXT NOT NULL,
+  "matchers" TEXT NOT NULL,
+  "starts_at" TEXT NOT NULL,
+  "ends_at" TEXT NOT NULL,
+  "comment" TEXT NOT NULL,
+  "created_at" TEXT NOT NULL,
+  "created_by" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_silences_id ON silences(id);
+CREATE INDEX IF NOT EXISTS idx_silences_ends_at ON silences(ends_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+  "id" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "matchers" TEXT NOT NULL,
+  "weekdays" TEXT NOT NULL,
+  "start" INTEGER NOT NULL,
+  "duration" INTEGER NOT NULL,
+  "created_at" TEXT NOT NULL,
+  "created_by" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_maintenance_windows_id ON maintenance_windows(id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS alerts;
+
+DROP INDEX IF EXISTS idx_alerts_fingerprint;
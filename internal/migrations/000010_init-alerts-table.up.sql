@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS alerts (
+  "fingerprint" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "labels" TEXT NOT NULL,
+  "severity" TEXT NOT NULL,
+  "summary" TEXT NOT NULL,
+  "started_at" TEXT NOT NULL,
+  "updated_at" TEXT NOT NULL,
+  "resolved_at" TEXT,
+  "notified" INTEGER NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_fingerprint ON alerts(fingerprint);
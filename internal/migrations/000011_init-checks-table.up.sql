@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS checks (
+  "id" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "kind" TEXT NOT NULL,
+  "target" TEXT NOT NULL,
+  "settings" TEXT NOT NULL,
+  "interval" INTEGER NOT NULL,
+  "timeout" INTEGER NOT NULL,
+  "created_at" TEXT NOT NULL,
+  "created_by" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_checks_id ON checks(id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS check_probes (
+  "check_id" TEXT NOT NULL,
+  "time" INTEGER NOT NULL,
+  "up" INTEGER NOT NULL,
+  "latency" INTEGER NOT NULL,
+  "error" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_check_probes_check_id_time ON check_probes(check_id, time);
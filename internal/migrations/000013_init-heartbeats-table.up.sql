@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS heartbeats (
+  "id" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "status" TEXT NOT NULL,
+  "period" INTEGER NOT NULL,
+  "grace" INTEGER NOT NULL,
+  "last_ping_at" TEXT,
+  "started_at" TEXT,
+  "created_at" TEXT NOT NULL,
+  "created_by" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_heartbeats_id ON heartbeats(id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS heartbeat_pings (
+  "id" TEXT NOT NULL,
+  "heartbeat_id" TEXT NOT NULL,
+  "at" TEXT NOT NULL,
+  "kind" TEXT NOT NULL,
+  "exit_code" INTEGER,
+  "duration" INTEGER,
+  "body" TEXT NOT NULL,
+  "remote_addr" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_heartbeat_pings_id ON heartbeat_pings(id);
+CREATE INDEX IF NOT EXISTS idx_heartbeat_pings_heartbeat_id_at ON heartbeat_pings(heartbeat_id, at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS hosts (
+  "id" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "token_hash" TEXT NOT NULL,
+  "hostname" TEXT NOT NULL,
+  "uptime" INTEGER NOT NULL,
+  "last_seen_at" TEXT,
+  "created_at" TEXT NOT NULL,
+  "created_by" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_hosts_id ON hosts(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_hosts_token_hash ON hosts(token_hash);
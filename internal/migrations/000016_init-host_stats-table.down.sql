@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS host_stats;
+
+DROP INDEX IF EXISTS idx_host_stats_host_id_time;
+DROP INDEX IF EXISTS idx_host_stats_time;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS host_stats (
+  "host_id" TEXT NOT NULL,
+  "time" INTEGER NOT NULL,
+  "content" BLOB NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_host_stats_host_id_time ON host_stats(host_id, time);
+CREATE INDEX IF NOT EXISTS idx_host_stats_time ON host_stats(time);
@@ -0,0 +1 @@
+ALTER TABLE hosts ADD COLUMN "tags" TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS dashboards (
+  "id" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "owner_id" TEXT NOT NULL,
+  "share_token_hash" TEXT,
+  "panels" TEXT NOT NULL,
+  "created_at" TEXT NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dashboards_id ON dashboards(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dashboards_share_token_hash ON dashboards(share_token_hash);
+CREATE INDEX IF NOT EXISTS idx_dashboards_owner_id ON dashboards(owner_id);
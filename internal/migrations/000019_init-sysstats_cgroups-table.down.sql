@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sysstats_cgroups;
+
+DROP INDEX IF EXISTS idx_sysstats_cgroups_time_path;
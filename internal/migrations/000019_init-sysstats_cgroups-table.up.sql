@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS sysstats_cgroups (
+  "time" INTEGER NOT NULL,
+  "path" TEXT NOT NULL,
+  "memory" INTEGER NOT NULL,
+  "anon" INTEGER NOT NULL,
+  "file" INTEGER NOT NULL,
+  "cpu_usage" INTEGER NOT NULL,
+  "io_read" INTEGER NOT NULL,
+  "io_write" INTEGER NOT NULL,
+  "pids" INTEGER NOT NULL
+) STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sysstats_cgroups_time_path ON sysstats_cgroups(time, path);
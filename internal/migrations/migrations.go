@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"errors"
@@ -9,15 +10,36 @@ import (
 
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lib/pq"
 )
 
 //go:embed *.sql
 var fs embed.FS
 
+// postgresFS contains the same migrations written for PostgreSQL.
+//
+//go:embed postgres/*.sql
+var postgresFS embed.FS
+
+var (
+	ErrNoSchema    = errors.New("not a zapette database")
+	ErrDirtySchema = errors.New("a migration failed on this database")
+	ErrNewerSchema = errors.New("the database comes from a newer version")
+)
+
+// Run applies the migrations matching the database engine, SQLite or
+// PostgreSQL.
 func Run(db *sql.DB, tools tools.Tools) error {
+	if _, ok := db.Driver().(*pq.Driver); ok {
+		return runPostgres(db, tools)
+	}
+
 	// Error not possible
 	d, _ := iofs.New(fs, ".")
 
@@ -25,7 +47,34 @@ func Run(db *sql.DB, tools tools.Tools) error {
 	if err != nil {
 		return fmt.Errorf("failed to setup the sqlite3 instance: %w", err)
 	}
-	m, err := migrate.NewWithInstance("iofs", d, "sqlite3", driver)
+
+	return up(d, "sqlite3", driver, tools)
+}
+
+func runPostgres(db *sql.DB, tools tools.Tools) error {
+	ctx := context.Background()
+
+	// Error not possible
+	d, _ := iofs.New(postgresFS, "postgres")
+
+	// The driver is built on a dedicated connection, released at the end:
+	// closing the driver would close the whole db.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a connection: %w", err)
+	}
+	defer conn.Close()
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to setup the postgres instance: %w", err)
+	}
+
+	return up(d, "postgres", driver, tools)
+}
+
+func up(d source.Driver, driverName string, driver database.Driver, tools tools.Tools) error {
+	m, err := migrate.NewWithInstance("iofs", d, driverName, driver)
 	if err != nil {
 		return fmt.Errorf("failed to create a migrate manager: %w", err)
 	}
@@ -42,6 +91,58 @@ func Run(db *sql.DB, tools tools.Tools) error {
 	return nil
 }
 
+// Latest returns the version of the last migration.
+func Latest() uint {
+	// Error not possible
+	d, _ := iofs.New(fs, ".")
+
+	version, err := d.First()
+	if err != nil {
+		return 0
+	}
+
+	for {
+		next, err := d.Next(version)
+		if err != nil {
+			return version
+		}
+
+		version = next
+	}
+}
+
+// Version returns the schema version of the database and if the last
+// migration failed.
+func Version(ctx context.Context, db *sql.DB) (uint, bool, error) {
+	var version uint
+	var dirty bool
+
+	err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, fmt.Errorf("%w: %w", ErrNoSchema, err)
+	}
+
+	return version, dirty, nil
+}
+
+// CheckVersion checks that the database can be used by this version: the
+// older schemas are migrated by Run but the newer ones are unknown.
+func CheckVersion(ctx context.Context, db *sql.DB) (uint, error) {
+	version, dirty, err := Version(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case dirty:
+		return version, fmt.Errorf("%w: version %d", ErrDirtySchema, version)
+	case version > Latest():
+		return version, fmt.Errorf("%w: version %d, expected at most %d", ErrNewerSchema, version, Latest())
+	}
+
+	return version, nil
+}
+
 type migrateLogger struct {
 	Logger *slog.Logger
 }
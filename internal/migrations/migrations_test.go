@@ -1,7 +1,9 @@
 package migrations
 
 import (
+	"context"
 	"database/sql"
+	iofs "io/fs"
 	"testing"
 
 	"github.com/Peltoche/zapette/internal/tools"
@@ -47,3 +49,68 @@ func TestRunMigrationTwice(t *testing.T) {
 	err = Run(db, tools)
 	require.NoError(t, err)
 }
+
+func TestVersion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("CheckVersion success", func(t *testing.T) {
+		db := newTestStorage(t)
+		require.NoError(t, Run(db, nil))
+
+		version, err := CheckVersion(ctx, db)
+		require.NoError(t, err)
+		assert.Equal(t, Latest(), version)
+	})
+
+	t.Run("CheckVersion without schema", func(t *testing.T) {
+		db := newTestStorage(t)
+
+		_, err := CheckVersion(ctx, db)
+		require.ErrorIs(t, err, ErrNoSchema)
+	})
+
+	t.Run("CheckVersion with a newer schema", func(t *testing.T) {
+		db := newTestStorage(t)
+		require.NoError(t, Run(db, nil))
+
+		_, err := db.Exec("UPDATE schema_migrations SET version = ?", Latest()+1)
+		require.NoError(t, err)
+
+		_, err = CheckVersion(ctx, db)
+		require.ErrorIs(t, err, ErrNewerSchema)
+	})
+
+	t.Run("CheckVersion with a dirty schema", func(t *testing.T) {
+		db := newTestStorage(t)
+		require.NoError(t, Run(db, nil))
+
+		_, err := db.Exec("UPDATE schema_migrations SET dirty = true")
+		require.NoError(t, err)
+
+		_, err = CheckVersion(ctx, db)
+		require.ErrorIs(t, err, ErrDirtySchema)
+	})
+}
+
+func TestPostgresMigrations(t *testing.T) {
+	t.Run("mirror the SQLite migrations", func(t *testing.T) {
+		sqliteFiles, err := fs.ReadDir(".")
+		require.NoError(t, err)
+
+		postgresFiles, err := postgresFS.ReadDir("postgres")
+		require.NoError(t, err)
+
+		names := func(entries []iofs.DirEntry) []string {
+			res := []string{}
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					res = append(res, entry.Name())
+				}
+			}
+
+			return res
+		}
+
+		assert.Equal(t, names(sqliteFiles), names(postgresFiles))
+	})
+}
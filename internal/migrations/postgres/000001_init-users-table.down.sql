@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS users;
+
+DROP INDEX IF EXISTS idx_users_id;
+DROP INDEX IF EXISTS idx_users_username;
+
+DROP FUNCTION IF EXISTS notify_changes;
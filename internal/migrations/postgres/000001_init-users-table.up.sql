@@ -0,0 +1,28 @@
+-- The PostgreSQL migrations mirror the SQLite ones. The TEXT columns use the
+-- "C" collation in order to be sorted byte per byte, like with SQLite.
+
+-- notify_changes sends the name of the modified table on the
+-- "zapette_changes" channel. All the tables have a trigger calling it.
+CREATE OR REPLACE FUNCTION notify_changes() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('zapette_changes', TG_TABLE_NAME);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE IF NOT EXISTS users (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "username" TEXT COLLATE "C" NOT NULL,
+  "admin" BOOLEAN NOT NULL,
+  "password" TEXT COLLATE "C" NOT NULL,
+  "status" TEXT COLLATE "C" NOT NULL,
+  "password_changed_at" TEXT COLLATE "C" NOT NULL,
+  "created_at" TEXT COLLATE "C" NOT NULL,
+  "created_by" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_id ON users(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users(username);
+
+CREATE TRIGGER notify_users_changes AFTER INSERT OR UPDATE OR DELETE ON users
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
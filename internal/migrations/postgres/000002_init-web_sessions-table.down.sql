@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS web_sessions;
+
+DROP INDEX IF EXISTS idx_web_sessions_token;
+DROP INDEX IF EXISTS idx_web_sessions_user_id;
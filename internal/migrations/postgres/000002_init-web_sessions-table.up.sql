@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS web_sessions (
+  "token" TEXT COLLATE "C" NOT NULL,
+  "user_id" TEXT COLLATE "C" NOT NULL,
+  "ip" TEXT COLLATE "C" NOT NULL,
+  "device" TEXT COLLATE "C" NOT NULL,
+  "created_at" TEXT COLLATE "C" NOT NULL,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON UPDATE RESTRICT ON DELETE RESTRICT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_web_sessions_token ON web_sessions(token);
+CREATE INDEX IF NOT EXISTS idx_web_sessions_user_id ON web_sessions(user_id);
+
+CREATE TRIGGER notify_web_sessions_changes AFTER INSERT OR UPDATE OR DELETE ON web_sessions
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sysstats;
+
+DROP INDEX IF EXISTS idx_sysstats_time;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sysstats (
+  "time" BIGINT NOT NULL,
+  "namespace" BIGINT NOT NULL,
+  "content" BYTEA NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sysstats_time ON sysstats(time);
+
+CREATE TRIGGER notify_sysstats_changes AFTER INSERT OR UPDATE OR DELETE ON sysstats
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS config;
+
+DROP INDEX IF EXISTS idx_config_key;
+
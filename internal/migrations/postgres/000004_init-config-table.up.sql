@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS config (
+  "key" TEXT COLLATE "C" NOT NULL,
+  "value" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_config_key ON config(key);
+
+CREATE TRIGGER notify_config_changes AFTER INSERT OR UPDATE OR DELETE ON config
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS timeseries_config;
+
+DROP INDEX IF EXISTS idx_timeseries_config_tsid ;
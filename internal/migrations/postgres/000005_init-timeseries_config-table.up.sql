@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS timeseries_config (
+  "ts_id" TEXT COLLATE "C" NOT NULL,
+  "graph_span_s" BIGINT NOT NULL,
+  "tick_span_s" BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_timeseries_config_tsid ON timeseries_config(ts_id);
+
+CREATE TRIGGER notify_timeseries_config_changes AFTER INSERT OR UPDATE OR DELETE ON timeseries_config
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS timeseries_data;
+
+DROP INDEX IF EXISTS idx_timeseries_data_tsid_time;
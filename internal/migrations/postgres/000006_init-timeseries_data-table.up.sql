@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS timeseries_data (
+  "ts_id" TEXT COLLATE "C" NOT NULL,
+  "time_unix_sec" BIGINT NOT NULL,
+  "content" BYTEA NOT NULL,
+  FOREIGN KEY(ts_id) REFERENCES timeseries_config(ts_id) ON UPDATE RESTRICT ON DELETE RESTRICT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_timeseries_tsid_time ON timeseries_data(ts_id, time_unix_sec);
+
+CREATE TRIGGER notify_timeseries_data_changes AFTER INSERT OR UPDATE OR DELETE ON timeseries_data
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS notification_channels (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "name" TEXT COLLATE "C" NOT NULL,
+  "kind" TEXT COLLATE "C" NOT NULL,
+  "settings" TEXT COLLATE "C" NOT NULL,
+  "sealed_key" BYTEA NOT NULL,
+  "secret" BYTEA,
+  "created_at" TEXT COLLATE "C" NOT NULL,
+  "created_by" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_channels_id ON notification_channels(id);
+
+CREATE TRIGGER notify_notification_channels_changes AFTER INSERT OR UPDATE OR DELETE ON notification_channels
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS silences;
+
+DROP INDEX IF EXISTS idx_silences_id;
+DROP INDEX IF EXISTS idx_silences_ends_at;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS silences (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "matchers" TEXT COLLATE "C" NOT NULL,
+  "starts_at" TEXT COLLATE "C" NOT NULL,
+  "ends_at" TEXT COLLATE "C" NOT NULL,
+  "comment" TEXT COLLATE "C" NOT NULL,
+  "created_at" TEXT COLLATE "C" NOT NULL,
+  "created_by" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_silences_id ON silences(id);
+CREATE INDEX IF NOT EXISTS idx_silences_ends_at ON silences(ends_at);
+
+CREATE TRIGGER notify_silences_changes AFTER INSERT OR UPDATE OR DELETE ON silences
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS maintenance_windows;
+
+DROP INDEX IF EXISTS idx_maintenance_windows_id;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "name" TEXT COLLATE "C" NOT NULL,
+  "matchers" TEXT COLLATE "C" NOT NULL,
+  "weekdays" TEXT COLLATE "C" NOT NULL,
+  "start" BIGINT NOT NULL,
+  "duration" BIGINT NOT NULL,
+  "created_at" TEXT COLLATE "C" NOT NULL,
+  "created_by" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_maintenance_windows_id ON maintenance_windows(id);
+
+CREATE TRIGGER notify_maintenance_windows_changes AFTER INSERT OR UPDATE OR DELETE ON maintenance_windows
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
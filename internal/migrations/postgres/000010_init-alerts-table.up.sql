@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS alerts (
+  "fingerprint" TEXT COLLATE "C" NOT NULL,
+  "name" TEXT COLLATE "C" NOT NULL,
+  "labels" TEXT COLLATE "C" NOT NULL,
+  "severity" TEXT COLLATE "C" NOT NULL,
+  "summary" TEXT COLLATE "C" NOT NULL,
+  "started_at" TEXT COLLATE "C" NOT NULL,
+  "updated_at" TEXT COLLATE "C" NOT NULL,
+  "resolved_at" TEXT COLLATE "C",
+  "notified" BOOLEAN NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_fingerprint ON alerts(fingerprint);
+
+CREATE TRIGGER notify_alerts_changes AFTER INSERT OR UPDATE OR DELETE ON alerts
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS checks;
+
+DROP INDEX IF EXISTS idx_checks_id;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS checks (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "name" TEXT COLLATE "C" NOT NULL,
+  "kind" TEXT COLLATE "C" NOT NULL,
+  "target" TEXT COLLATE "C" NOT NULL,
+  "settings" TEXT COLLATE "C" NOT NULL,
+  "interval" BIGINT NOT NULL,
+  "timeout" BIGINT NOT NULL,
+  "created_at" TEXT COLLATE "C" NOT NULL,
+  "created_by" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_checks_id ON checks(id);
+
+CREATE TRIGGER notify_checks_changes AFTER INSERT OR UPDATE OR DELETE ON checks
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
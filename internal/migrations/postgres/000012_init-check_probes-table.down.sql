@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS check_probes;
+
+DROP INDEX IF EXISTS idx_check_probes_check_id_time;
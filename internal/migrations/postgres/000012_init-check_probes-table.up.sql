@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS check_probes (
+  "check_id" TEXT COLLATE "C" NOT NULL,
+  "time" BIGINT NOT NULL,
+  "up" BOOLEAN NOT NULL,
+  "latency" BIGINT NOT NULL,
+  "error" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_check_probes_check_id_time ON check_probes(check_id, time);
+
+CREATE TRIGGER notify_check_probes_changes AFTER INSERT OR UPDATE OR DELETE ON check_probes
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
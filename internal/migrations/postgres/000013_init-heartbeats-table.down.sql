@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS heartbeats;
+
+DROP INDEX IF EXISTS idx_heartbeats_id;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS heartbeats (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "name" TEXT COLLATE "C" NOT NULL,
+  "status" TEXT COLLATE "C" NOT NULL,
+  "period" BIGINT NOT NULL,
+  "grace" BIGINT NOT NULL,
+  "last_ping_at" TEXT COLLATE "C",
+  "started_at" TEXT COLLATE "C",
+  "created_at" TEXT COLLATE "C" NOT NULL,
+  "created_by" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_heartbeats_id ON heartbeats(id);
+
+CREATE TRIGGER notify_heartbeats_changes AFTER INSERT OR UPDATE OR DELETE ON heartbeats
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS heartbeat_pings;
+
+DROP INDEX IF EXISTS idx_heartbeat_pings_id;
+DROP INDEX IF EXISTS idx_heartbeat_pings_heartbeat_id_at;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS heartbeat_pings (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "heartbeat_id" TEXT COLLATE "C" NOT NULL,
+  "at" TEXT COLLATE "C" NOT NULL,
+  "kind" TEXT COLLATE "C" NOT NULL,
+  "exit_code" BIGINT,
+  "duration" BIGINT,
+  "body" TEXT COLLATE "C" NOT NULL,
+  "remote_addr" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_heartbeat_pings_id ON heartbeat_pings(id);
+CREATE INDEX IF NOT EXISTS idx_heartbeat_pings_heartbeat_id_at ON heartbeat_pings(heartbeat_id, at);
+
+CREATE TRIGGER notify_heartbeat_pings_changes AFTER INSERT OR UPDATE OR DELETE ON heartbeat_pings
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
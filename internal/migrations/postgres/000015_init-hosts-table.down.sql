@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS hosts;
+
+DROP INDEX IF EXISTS idx_hosts_id;
+DROP INDEX IF EXISTS idx_hosts_token_hash;
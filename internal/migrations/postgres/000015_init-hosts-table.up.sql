@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS hosts (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "name" TEXT COLLATE "C" NOT NULL,
+  "token_hash" TEXT COLLATE "C" NOT NULL,
+  "hostname" TEXT COLLATE "C" NOT NULL,
+  "uptime" BIGINT NOT NULL,
+  "last_seen_at" TEXT COLLATE "C",
+  "created_at" TEXT COLLATE "C" NOT NULL,
+  "created_by" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_hosts_id ON hosts(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_hosts_token_hash ON hosts(token_hash);
+
+CREATE TRIGGER notify_hosts_changes AFTER INSERT OR UPDATE OR DELETE ON hosts
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
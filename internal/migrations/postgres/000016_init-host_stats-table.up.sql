@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS host_stats (
+  "host_id" TEXT COLLATE "C" NOT NULL,
+  "time" BIGINT NOT NULL,
+  "content" BYTEA NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_host_stats_host_id_time ON host_stats(host_id, time);
+CREATE INDEX IF NOT EXISTS idx_host_stats_time ON host_stats(time);
+
+CREATE TRIGGER notify_host_stats_changes AFTER INSERT OR UPDATE OR DELETE ON host_stats
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS dashboards;
+
+DROP INDEX IF EXISTS idx_dashboards_id;
+DROP INDEX IF EXISTS idx_dashboards_share_token_hash;
+DROP INDEX IF EXISTS idx_dashboards_owner_id;
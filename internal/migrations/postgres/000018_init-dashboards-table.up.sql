@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS dashboards (
+  "id" TEXT COLLATE "C" NOT NULL,
+  "name" TEXT COLLATE "C" NOT NULL,
+  "owner_id" TEXT COLLATE "C" NOT NULL,
+  "share_token_hash" TEXT COLLATE "C",
+  "panels" TEXT COLLATE "C" NOT NULL,
+  "created_at" TEXT COLLATE "C" NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dashboards_id ON dashboards(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dashboards_share_token_hash ON dashboards(share_token_hash);
+CREATE INDEX IF NOT EXISTS idx_dashboards_owner_id ON dashboards(owner_id);
+
+CREATE TRIGGER notify_dashboards_changes AFTER INSERT OR UPDATE OR DELETE ON dashboards
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
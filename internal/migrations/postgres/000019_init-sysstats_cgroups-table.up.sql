@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS sysstats_cgroups (
+  "time" BIGINT NOT NULL,
+  "path" TEXT COLLATE "C" NOT NULL,
+  "memory" BIGINT NOT NULL,
+  "anon" BIGINT NOT NULL,
+  "file" BIGINT NOT NULL,
+  "cpu_usage" BIGINT NOT NULL,
+  "io_read" BIGINT NOT NULL,
+  "io_write" BIGINT NOT NULL,
+  "pids" BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sysstats_cgroups_time_path ON sysstats_cgroups(time, path);
+
+CREATE TRIGGER notify_sysstats_cgroups_changes AFTER INSERT OR UPDATE OR DELETE ON sysstats_cgroups
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
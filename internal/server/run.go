@@ -44,3 +44,13 @@ func Run(ctx context.Context, cfg Config) (os.Signal, error) {
 
 	return signal, nil
 }
+
+// Exec builds the services and calls fn with the ones it asks for, like
+// fx.Invoke. The migrations are applied but the app isn't started: neither
+// the HTTP server nor the crons are running. It's used by the admin
+// commands, fn can return an error.
+func Exec(ctx context.Context, cfg Config, fn any) error {
+	app := start(ctx, cfg, fx.Invoke(fn))
+
+	return app.Err()
+}
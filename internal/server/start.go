@@ -9,20 +9,46 @@ import (
 
 	"github.com/Peltoche/zapette/assets"
 	"github.com/Peltoche/zapette/internal/migrations"
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/anomalies"
+	"github.com/Peltoche/zapette/internal/service/backups"
+	"github.com/Peltoche/zapette/internal/service/checks"
 	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/service/containers"
+	"github.com/Peltoche/zapette/internal/service/dashboards"
+	"github.com/Peltoche/zapette/internal/service/forecasts"
+	"github.com/Peltoche/zapette/internal/service/heartbeats"
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/logs"
+	"github.com/Peltoche/zapette/internal/service/masterkey"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/silences"
 	"github.com/Peltoche/zapette/internal/service/sysinfos"
 	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/service/systemd"
 	"github.com/Peltoche/zapette/internal/service/timeseries"
 	"github.com/Peltoche/zapette/internal/service/users"
 	"github.com/Peltoche/zapette/internal/service/utilities"
 	"github.com/Peltoche/zapette/internal/service/websessions"
 	"github.com/Peltoche/zapette/internal/tools"
-	"github.com/Peltoche/zapette/internal/tools/cron"
 	"github.com/Peltoche/zapette/internal/tools/logger"
 	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
 	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	alertspages "github.com/Peltoche/zapette/internal/web/handlers/alerts"
 	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	backupspages "github.com/Peltoche/zapette/internal/web/handlers/backups"
+	checkspages "github.com/Peltoche/zapette/internal/web/handlers/checks"
+	containerspages "github.com/Peltoche/zapette/internal/web/handlers/containers"
+	dashboardspages "github.com/Peltoche/zapette/internal/web/handlers/dashboards"
+	heartbeatspages "github.com/Peltoche/zapette/internal/web/handlers/heartbeats"
+	hostspages "github.com/Peltoche/zapette/internal/web/handlers/hosts"
+	jobspages "github.com/Peltoche/zapette/internal/web/handlers/jobs"
+	logspages "github.com/Peltoche/zapette/internal/web/handlers/logs"
+	notificationspages "github.com/Peltoche/zapette/internal/web/handlers/notifications"
 	"github.com/Peltoche/zapette/internal/web/handlers/server"
+	settingspages "github.com/Peltoche/zapette/internal/web/handlers/settings"
+	systemdpages "github.com/Peltoche/zapette/internal/web/handlers/systemd"
 	"github.com/Peltoche/zapette/internal/web/html"
 	"github.com/Peltoche/zapette/internal/web/middlewares"
 	"github.com/spf13/afero"
@@ -34,13 +60,14 @@ type Folder string
 
 type Config struct {
 	fx.Out
-	Tools    tools.Config
-	FS       afero.Fs
-	Storage  sqlstorage.Config
-	Folder   Folder
-	Listener router.Config
-	HTML     html.Config
-	Assets   assets.Config
+	Tools     tools.Config
+	FS        afero.Fs
+	Storage   sqlstorage.Config
+	Folder    Folder
+	MasterKey masterkey.Config
+	Listener  router.Config
+	HTML      html.Config
+	Assets    assets.Config
 }
 
 // AsRoute annotates the given constructor to state that
@@ -86,14 +113,29 @@ func start(ctx context.Context, cfg Config, invoke fx.Option) *fx.App {
 			sqlstorage.NewSQLChangeHookList,
 			sqlstorage.Init,
 			auth.NewAuthenticator,
+			scheduler.New,
 
 			// Services
 			fx.Annotate(users.Init, fx.As(new(users.Service))),
 			fx.Annotate(websessions.Init, fx.As(new(websessions.Service))),
 			fx.Annotate(sysinfos.Init, fx.As(new(sysinfos.Service))),
-			fx.Annotate(config.Init, fx.As(new(config.Service))),
+			config.Init,
 			fx.Annotate(timeseries.Init, fx.As(new(timeseries.Service))),
 			sysstats.Init,
+			fx.Annotate(masterkey.Init, fx.As(new(masterkey.Service))),
+			fx.Annotate(notifications.Init, fx.As(new(notifications.Service))),
+			fx.Annotate(silences.Init, fx.As(new(silences.Service))),
+			alerts.Init,
+			forecasts.Init,
+			anomalies.Init,
+			checks.Init,
+			heartbeats.Init,
+			fx.Annotate(systemd.Init, fx.As(new(systemd.Service))),
+			fx.Annotate(logs.Init, fx.As(new(logs.Service))),
+			fx.Annotate(containers.Init, fx.As(new(containers.Service))),
+			hosts.Init,
+			fx.Annotate(dashboards.Init, fx.As(new(dashboards.Service))),
+			backups.Init,
 
 			// Middlewares
 			middlewares.NewBootstrapMiddleware,
@@ -101,12 +143,26 @@ func start(ctx context.Context, cfg Config, invoke fx.Option) *fx.App {
 			// HTTP handlers
 			AsRoute(assets.NewHTTPHandler),
 			AsRoute(utilities.NewHTTPHandler),
+			AsRoute(heartbeats.NewHTTPHandler),
+			AsRoute(hosts.NewHTTPHandler),
 
 			// Web Pages
 			AsRoute(auth.NewLoginPage),
 			AsRoute(auth.NewBootstrapPage),
 			AsRoute(server.NewDetailsPage),
 			AsRoute(server.NewMemoryGraphPage),
+			AsRoute(notificationspages.NewChannelsPage),
+			AsRoute(alertspages.NewAlertsPage),
+			AsRoute(checkspages.NewChecksPage),
+			AsRoute(heartbeatspages.NewHeartbeatsPage),
+			AsRoute(systemdpages.NewUnitsPage),
+			AsRoute(logspages.NewLogsPage),
+			AsRoute(containerspages.NewContainersPage),
+			AsRoute(hostspages.NewHostsPage),
+			AsRoute(dashboardspages.NewDashboardsPage),
+			AsRoute(settingspages.NewSettingsPage),
+			AsRoute(jobspages.NewJobsPage),
+			AsRoute(backupspages.NewBackupsPage),
 
 			// HTTP Router / HTTP Server
 			router.InitMiddlewares,
@@ -121,10 +177,33 @@ func start(ctx context.Context, cfg Config, invoke fx.Option) *fx.App {
 			}, fx.ParamTags(`group:"hooks"`),
 		)),
 
-		// Start the tasks-runner
-		fx.Invoke(func(svc *sysstats.SystatsCron, lc fx.Lifecycle, tools tools.Tools) {
-			cronSvc := cron.New(svc.Name(), svc.Duration(), tools, svc)
-			cronSvc.FXRegister(lc)
+		// Start the jobs
+		fx.Invoke(func(
+			s *scheduler.Scheduler,
+			lc fx.Lifecycle,
+			sysstatsCron *sysstats.SystatsCron,
+			dispatchCron *alerts.DispatchCron,
+			forecastCron *forecasts.ForecastCron,
+			anomalyCron *anomalies.AnomalyCron,
+			checkCron *checks.CheckCron,
+			lateCron *heartbeats.LateCron,
+			staleCron *hosts.StaleCron,
+			hostsPurgeCron *hosts.PurgeCron,
+			backupCron *backups.BackupCron,
+		) error {
+			s.FXRegister(lc)
+
+			return s.Add(
+				sysstatsCron.Job(),
+				dispatchCron.Job(),
+				forecastCron.Job(),
+				anomalyCron.Job(),
+				checkCron.Job(),
+				lateCron.Job(),
+				staleCron.Job(),
+				hostsPurgeCron.Job(),
+				backupCron.Job(),
+			)
 		}),
 
 		invoke,
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/Peltoche/zapette/assets"
+	"github.com/Peltoche/zapette/internal/service/users"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/logger"
 	"github.com/Peltoche/zapette/internal/tools/router"
@@ -79,3 +81,25 @@ func TestServerRun(t *testing.T) {
 
 	require.NoError(t, runErr)
 }
+
+func TestServerExec(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		var called bool
+		err := Exec(ctx, testConfig, func(usersSvc users.Service) {
+			called = usersSvc != nil
+		})
+
+		require.NoError(t, err)
+		require.True(t, called)
+	})
+
+	t.Run("with an error", func(t *testing.T) {
+		err := Exec(ctx, testConfig, func(users.Service) error {
+			return errors.New("some-error")
+		})
+
+		require.ErrorContains(t, err, "some-error")
+	})
+}
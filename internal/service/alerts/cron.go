@@ -0,0 +1,34 @@
+package alerts
+
+import (
+	"context"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+)
+
+// DispatchCron sends the notifications for the firing alerts not notified
+// yet: the new ones and the ones muted until now. This way an alert still
+// firing at the end of a silence or a maintenance window is notified, and
+// a slow channel never delays the jobs firing the alerts.
+type DispatchCron struct {
+	service Service
+}
+
+func newDispatchCron(service Service) *DispatchCron {
+	return &DispatchCron{service: service}
+}
+
+func (c *DispatchCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "alerts-dispatch",
+		Schedule: scheduler.Every(30 * time.Second),
+		Jitter:   5 * time.Second,
+		Timeout:  10 * time.Minute,
+		Runner:   c,
+	}
+}
+
+func (c *DispatchCron) Run(ctx context.Context) error {
+	return c.service.dispatchPending(ctx)
+}
@@ -0,0 +1,38 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/silences"
+	"github.com/Peltoche/zapette/internal/tools"
+	"go.uber.org/fx"
+)
+
+type Result struct {
+	fx.Out
+	Service Service
+	Cron    *DispatchCron
+}
+
+type Service interface {
+	// Fire raises an alert or refreshes it if already firing. The
+	// notifications are sent in the background by the DispatchCron, only
+	// once per firing period and unless muted.
+	Fire(ctx context.Context, cmd *FireCmd) (*Alert, error)
+	Resolve(ctx context.Context, cmd *ResolveCmd) error
+	GetFiring(ctx context.Context) ([]Alert, error)
+	dispatchPending(ctx context.Context) error
+}
+
+func Init(db *sql.DB, notifications notifications.Service, silences silences.Service, tools tools.Tools) Result {
+	storage := newSqlStorage(db)
+
+	svc := newService(storage, notifications, silences, tools)
+
+	return Result{
+		Service: svc,
+		Cron:    newDispatchCron(svc),
+	}
+}
@@ -0,0 +1,124 @@
+package alerts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/silences"
+	v "github.com/go-ozzo/ozzo-validation"
+)
+
+// NameLabel is the label containing the alert name. It is added to the
+// alert labels when matching them against the silences.
+const NameLabel = "alertname"
+
+// Alert is raised by a rule (a forecast, a failing check, etc.) and stays
+// firing until the rule resolves it.
+//
+// An alert is identified by its fingerprint, computed from its name and labels.
+type Alert struct {
+	startedAt   time.Time
+	updatedAt   time.Time
+	resolvedAt  *time.Time
+	mute        *silences.MuteReason
+	labels      map[string]string
+	fingerprint string
+	name        string
+	severity    notifications.Severity
+	summary     string
+	notified    bool
+}
+
+func (a Alert) Fingerprint() string              { return a.fingerprint }
+func (a Alert) Name() string                     { return a.name }
+func (a Alert) Labels() map[string]string        { return a.labels }
+func (a Alert) Severity() notifications.Severity { return a.severity }
+func (a Alert) Summary() string                  { return a.summary }
+func (a Alert) StartedAt() time.Time             { return a.startedAt }
+func (a Alert) UpdatedAt() time.Time             { return a.updatedAt }
+func (a Alert) ResolvedAt() *time.Time           { return a.resolvedAt }
+func (a Alert) IsFiring() bool                   { return a.resolvedAt == nil }
+
+// MutedBy returns the reason why the alert notifications are muted or nil
+// if they are not. It is only set by the Service.GetFiring method.
+func (a Alert) MutedBy() *silences.MuteReason { return a.mute }
+
+// SortedLabelNames returns the label names in a stable order, used for the display.
+func (a Alert) SortedLabelNames() []string {
+	return sortedKeys(a.labels)
+}
+
+func (a *Alert) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"fingerprint": a.fingerprint,
+		"name":        a.name,
+		"labels":      a.labels,
+		"severity":    a.severity,
+		"summary":     a.summary,
+		"startedAt":   a.startedAt,
+		"updatedAt":   a.updatedAt,
+		"resolvedAt":  a.resolvedAt,
+	})
+}
+
+// matchLabels returns the labels used to match the silences.
+func (a *Alert) matchLabels() map[string]string {
+	res := maps.Clone(a.labels)
+	if res == nil {
+		res = map[string]string{}
+	}
+
+	res[NameLabel] = a.name
+
+	return res
+}
+
+func fingerprint(name string, labels map[string]string) string {
+	h := sha256.New()
+	h.Write([]byte(name))
+
+	for _, key := range sortedKeys(labels) {
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(labels[key]))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func sortedKeys(labels map[string]string) []string {
+	res := make([]string, 0, len(labels))
+	for key := range labels {
+		res = append(res, key)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+type FireCmd struct {
+	Labels   map[string]string
+	Name     string
+	Severity notifications.Severity
+	Summary  string
+}
+
+func (t FireCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Name, v.Required, v.Length(1, 100)),
+		v.Field(&t.Severity, v.Required, v.In(notifications.Info, notifications.Warning, notifications.Critical)),
+		v.Field(&t.Summary, v.Length(0, 1000)),
+	)
+}
+
+type ResolveCmd struct {
+	Labels map[string]string
+	Name   string
+}
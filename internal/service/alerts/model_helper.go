@@ -0,0 +1,75 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+type FakeAlertBuilder struct {
+	t     testing.TB
+	alert *Alert
+}
+
+func NewFakeAlert(t testing.TB) *FakeAlertBuilder {
+	t.Helper()
+
+	startedAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now()).UTC()
+	name := gofakeit.AppName()
+	labels := map[string]string{"host": gofakeit.DomainName()}
+
+	return &FakeAlertBuilder{
+		t: t,
+		alert: &Alert{
+			fingerprint: fingerprint(name, labels),
+			name:        name,
+			labels:      labels,
+			severity:    notifications.Warning,
+			summary:     gofakeit.Sentence(5),
+			startedAt:   startedAt,
+			updatedAt:   startedAt,
+			resolvedAt:  nil,
+			notified:    true,
+		},
+	}
+}
+
+func (f *FakeAlertBuilder) WithLabels(labels map[string]string) *FakeAlertBuilder {
+	f.alert.labels = labels
+	f.alert.fingerprint = fingerprint(f.alert.name, labels)
+
+	return f
+}
+
+func (f *FakeAlertBuilder) WithNotified(notified bool) *FakeAlertBuilder {
+	f.alert.notified = notified
+
+	return f
+}
+
+func (f *FakeAlertBuilder) WithResolvedAt(t time.Time) *FakeAlertBuilder {
+	t = t.UTC()
+	f.alert.resolvedAt = &t
+
+	return f
+}
+
+func (f *FakeAlertBuilder) Build() *Alert {
+	return f.alert
+}
+
+func (f *FakeAlertBuilder) BuildAndStore(ctx context.Context, db *sql.DB) *Alert {
+	f.t.Helper()
+
+	storage := newSqlStorage(db)
+
+	err := storage.Save(ctx, f.alert)
+	require.NoError(f.t, err)
+
+	return f.alert
+}
@@ -0,0 +1,217 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/silences"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+)
+
+// notifyTimeout bounds the notification of a single alert, all the channels
+// and the retries included.
+const notifyTimeout = time.Minute
+
+type storage interface {
+	Save(ctx context.Context, alert *Alert) error
+	GetByFingerprint(ctx context.Context, fingerprint string) (*Alert, error)
+	GetFiring(ctx context.Context) ([]Alert, error)
+}
+
+type service struct {
+	storage       storage
+	notifications notifications.Service
+	silences      silences.Service
+	clock         clock.Clock
+	log           *slog.Logger
+}
+
+func newService(storage storage, notifications notifications.Service, silences silences.Service, tools tools.Tools) *service {
+	return &service{
+		storage:       storage,
+		notifications: notifications,
+		silences:      silences,
+		clock:         tools.Clock(),
+		log:           tools.Logger().With(slog.String("source", "alerts")),
+	}
+}
+
+func (s *service) Fire(ctx context.Context, cmd *FireCmd) (*Alert, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	fp := fingerprint(cmd.Name, cmd.Labels)
+	now := s.clock.Now()
+
+	alert, err := s.storage.GetByFingerprint(ctx, fp)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return nil, errs.Internal(fmt.Errorf("failed to GetByFingerprint: %w", err))
+	}
+
+	if alert == nil || !alert.IsFiring() {
+		// A resolved alert firing again starts a new firing period.
+		alert = &Alert{
+			fingerprint: fp,
+			name:        cmd.Name,
+			labels:      cmd.Labels,
+			startedAt:   now,
+			resolvedAt:  nil,
+			notified:    false,
+		}
+	}
+
+	alert.severity = cmd.Severity
+	alert.summary = cmd.Summary
+	alert.updatedAt = now
+
+	err = s.storage.Save(ctx, alert)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to save the alert: %w", err))
+	}
+
+	return alert, nil
+}
+
+func (s *service) Resolve(ctx context.Context, cmd *ResolveCmd) error {
+	alert, err := s.storage.GetByFingerprint(ctx, fingerprint(cmd.Name, cmd.Labels))
+	if errors.Is(err, errNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to GetByFingerprint: %w", err))
+	}
+
+	if !alert.IsFiring() {
+		return nil
+	}
+
+	now := s.clock.Now()
+	alert.resolvedAt = ptr.To(now)
+	alert.updatedAt = now
+
+	err = s.storage.Save(ctx, alert)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to save the alert: %w", err))
+	}
+
+	if !alert.notified {
+		// Nobody have been told about this alert, no need to tell it's over.
+		return nil
+	}
+
+	mute, err := s.silences.GetMuteReason(ctx, alert.matchLabels())
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to GetMuteReason: %w", err))
+	}
+
+	if mute != nil {
+		return nil
+	}
+
+	err = s.notifications.Notify(ctx, &notifications.Message{
+		At:       now,
+		Labels:   alert.labels,
+		Title:    "[RESOLVED] " + alert.name,
+		Body:     alert.summary,
+		Severity: notifications.Info,
+	})
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Notify: %w", err))
+	}
+
+	return nil
+}
+
+// GetFiring returns all the firing alerts with their mute reason.
+func (s *service) GetFiring(ctx context.Context) ([]Alert, error) {
+	res, err := s.storage.GetFiring(ctx)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to GetFiring: %w", err))
+	}
+
+	for i := range res {
+		res[i].mute, err = s.silences.GetMuteReason(ctx, res[i].matchLabels())
+		if err != nil {
+			return nil, errs.Internal(fmt.Errorf("failed to GetMuteReason: %w", err))
+		}
+	}
+
+	return res, nil
+}
+
+func (s *service) dispatchPending(ctx context.Context) error {
+	alerts, err := s.storage.GetFiring(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to GetFiring: %w", err)
+	}
+
+	for i := range alerts {
+		if alerts[i].notified {
+			continue
+		}
+
+		err = s.dispatch(ctx, &alerts[i])
+		if err != nil {
+			return fmt.Errorf("failed to dispatch the alert %q: %w", alerts[i].name, err)
+		}
+	}
+
+	return nil
+}
+
+// dispatch notifies the alert unless it is muted. The rule evaluation is not
+// impacted by the mute: the alert stays firing and is notified by the
+// DispatchCron once the mute ends.
+//
+// The notification is limited to notifyTimeout in order to not let a slow
+// channel delay the other alerts.
+func (s *service) dispatch(ctx context.Context, alert *Alert) error {
+	mute, err := s.silences.GetMuteReason(ctx, alert.matchLabels())
+	if err != nil {
+		return fmt.Errorf("failed to GetMuteReason: %w", err)
+	}
+
+	if mute != nil {
+		s.log.DebugContext(ctx, "alert muted",
+			slog.String("alert", alert.name),
+			slog.String("reason", mute.String()))
+		return nil
+	}
+
+	// A failing channel is reported but the alert is flagged as notified
+	// anyway in order to avoid spamming the working channels.
+	notifyCtx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	notifyErr := s.notifications.Notify(notifyCtx, &notifications.Message{
+		At:       alert.updatedAt,
+		Labels:   alert.labels,
+		Title:    "[FIRING] " + alert.name,
+		Body:     alert.summary,
+		Severity: alert.severity,
+	})
+	cancel()
+
+	alert.notified = true
+
+	err = s.storage.Save(ctx, alert)
+	if err != nil {
+		return fmt.Errorf("failed to save the alert: %w", err)
+	}
+
+	if notifyErr != nil {
+		s.log.ErrorContext(ctx, "failed to notify an alert",
+			slog.String("alert", alert.name),
+			slog.String("error", notifyErr.Error()))
+	}
+
+	return nil
+}
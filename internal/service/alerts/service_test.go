@@ -0,0 +1,290 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/silences"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertsService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Fire a new alert", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		notificationsMock := notifications.NewMockService(t)
+		silencesMock := silences.NewMockService(t)
+		svc := newService(storageMock, notificationsMock, silencesMock, tools)
+
+		// Data
+		now := time.Now()
+		labels := map[string]string{"mount": "/"}
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("GetByFingerprint", mock.Anything, fingerprint("disk-full", labels)).Return(nil, errNotFound).Once()
+		storageMock.On("Save", mock.Anything, mock.AnythingOfType("*alerts.Alert")).Return(nil).Once()
+
+		// Run
+		res, err := svc.Fire(ctx, &FireCmd{
+			Name:     "disk-full",
+			Labels:   labels,
+			Severity: notifications.Critical,
+			Summary:  "/ is full in 3 days",
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, "disk-full", res.Name())
+		assert.Equal(t, now, res.StartedAt())
+		assert.True(t, res.IsFiring())
+		assert.False(t, res.notified)
+	})
+
+	t.Run("Fire an already notified alert", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		notificationsMock := notifications.NewMockService(t)
+		silencesMock := silences.NewMockService(t)
+		svc := newService(storageMock, notificationsMock, silencesMock, tools)
+
+		// Data
+		now := time.Now()
+		alert := NewFakeAlert(t).Build()
+		startedAt := alert.StartedAt()
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("GetByFingerprint", mock.Anything, alert.Fingerprint()).Return(alert, nil).Once()
+		storageMock.On("Save", mock.Anything, alert).Return(nil).Once()
+
+		// Run
+		res, err := svc.Fire(ctx, &FireCmd{
+			Name:     alert.Name(),
+			Labels:   alert.Labels(),
+			Severity: notifications.Critical,
+			Summary:  "some new summary",
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, startedAt, res.StartedAt())
+		assert.Equal(t, now, res.UpdatedAt())
+		assert.Equal(t, "some new summary", res.Summary())
+	})
+
+	t.Run("Fire with an invalid cmd", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		svc := newService(newMockStorage(t), notifications.NewMockService(t), silences.NewMockService(t), tools)
+
+		res, err := svc.Fire(ctx, &FireCmd{Name: "foo", Severity: "unknown"})
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Resolve a notified alert", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		notificationsMock := notifications.NewMockService(t)
+		silencesMock := silences.NewMockService(t)
+		svc := newService(storageMock, notificationsMock, silencesMock, tools)
+
+		// Data
+		now := time.Now()
+		alert := NewFakeAlert(t).Build()
+
+		// Mocks
+		storageMock.On("GetByFingerprint", mock.Anything, alert.Fingerprint()).Return(alert, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("Save", mock.Anything, alert).Return(nil).Once()
+		silencesMock.On("GetMuteReason", mock.Anything, alert.matchLabels()).Return(nil, nil).Once()
+		notificationsMock.On("Notify", mock.Anything, &notifications.Message{
+			At:       now,
+			Labels:   alert.Labels(),
+			Title:    "[RESOLVED] " + alert.Name(),
+			Body:     alert.Summary(),
+			Severity: notifications.Info,
+		}).Return(nil).Once()
+
+		// Run
+		err := svc.Resolve(ctx, &ResolveCmd{Name: alert.Name(), Labels: alert.Labels()})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.False(t, alert.IsFiring())
+		assert.Equal(t, &now, alert.ResolvedAt())
+	})
+
+	t.Run("Resolve a never notified alert", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, notifications.NewMockService(t), silences.NewMockService(t), tools)
+
+		// Data
+		now := time.Now()
+		alert := NewFakeAlert(t).WithNotified(false).Build()
+
+		// Mocks
+		storageMock.On("GetByFingerprint", mock.Anything, alert.Fingerprint()).Return(alert, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("Save", mock.Anything, alert).Return(nil).Once()
+
+		// Run
+		err := svc.Resolve(ctx, &ResolveCmd{Name: alert.Name(), Labels: alert.Labels()})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.False(t, alert.IsFiring())
+	})
+
+	t.Run("Resolve an unknown alert", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, notifications.NewMockService(t), silences.NewMockService(t), tools)
+
+		storageMock.On("GetByFingerprint", mock.Anything, fingerprint("foo", nil)).Return(nil, errNotFound).Once()
+
+		err := svc.Resolve(ctx, &ResolveCmd{Name: "foo"})
+		require.NoError(t, err)
+	})
+
+	t.Run("GetFiring success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		silencesMock := silences.NewMockService(t)
+		svc := newService(storageMock, notifications.NewMockService(t), silencesMock, tools)
+
+		// Data
+		alert := NewFakeAlert(t).Build()
+		window := silences.NewFakeMaintenanceWindow(t).Build()
+
+		// Mocks
+		storageMock.On("GetFiring", mock.Anything).Return([]Alert{*alert}, nil).Once()
+		silencesMock.On("GetMuteReason", mock.Anything, alert.matchLabels()).
+			Return(&silences.MuteReason{Window: window}, nil).Once()
+
+		// Run
+		res, err := svc.GetFiring(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, &silences.MuteReason{Window: window}, res[0].MutedBy())
+	})
+
+	t.Run("dispatchPending notifies the alerts once unmuted", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		notificationsMock := notifications.NewMockService(t)
+		silencesMock := silences.NewMockService(t)
+		svc := newService(storageMock, notificationsMock, silencesMock, tools)
+
+		// Data
+		alreadyNotified := NewFakeAlert(t).Build()
+		pending := NewFakeAlert(t).WithNotified(false).Build()
+
+		// Mocks
+		storageMock.On("GetFiring", mock.Anything).Return([]Alert{*alreadyNotified, *pending}, nil).Once()
+		silencesMock.On("GetMuteReason", mock.Anything, pending.matchLabels()).Return(nil, nil).Once()
+		notificationsMock.On("Notify", mock.Anything, mock.AnythingOfType("*notifications.Message")).Return(nil).Once()
+		storageMock.On("Save", mock.Anything, mock.MatchedBy(func(a *Alert) bool {
+			return a.Fingerprint() == pending.Fingerprint() && a.notified
+		})).Return(nil).Once()
+
+		// Run
+		err := svc.dispatchPending(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("dispatchPending sends the notification with a timeout", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		notificationsMock := notifications.NewMockService(t)
+		silencesMock := silences.NewMockService(t)
+		svc := newService(storageMock, notificationsMock, silencesMock, tools)
+
+		// Data
+		pending := NewFakeAlert(t).WithNotified(false).Build()
+
+		// Mocks
+		storageMock.On("GetFiring", mock.Anything).Return([]Alert{*pending}, nil).Once()
+		silencesMock.On("GetMuteReason", mock.Anything, pending.matchLabels()).Return(nil, nil).Once()
+		notificationsMock.On("Notify", mock.MatchedBy(func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return ok
+		}), &notifications.Message{
+			At:       pending.UpdatedAt(),
+			Labels:   pending.Labels(),
+			Title:    "[FIRING] " + pending.Name(),
+			Body:     pending.Summary(),
+			Severity: pending.Severity(),
+		}).Return(context.DeadlineExceeded).Once()
+		// The alert is flagged as notified even if a channel failed.
+		storageMock.On("Save", mock.Anything, mock.MatchedBy(func(a *Alert) bool {
+			return a.Fingerprint() == pending.Fingerprint() && a.notified
+		})).Return(nil).Once()
+
+		// Run
+		err := svc.dispatchPending(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("dispatchPending skips the muted alerts", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		notificationsMock := notifications.NewMockService(t)
+		silencesMock := silences.NewMockService(t)
+		svc := newService(storageMock, notificationsMock, silencesMock, tools)
+
+		// Data
+		pending := NewFakeAlert(t).WithNotified(false).Build()
+		silence := silences.NewFakeSilence(t).Build()
+
+		// Mocks
+		storageMock.On("GetFiring", mock.Anything).Return([]Alert{*pending}, nil).Once()
+		silencesMock.On("GetMuteReason", mock.Anything, pending.matchLabels()).
+			Return(&silences.MuteReason{Silence: silence}, nil).Once()
+
+		// Run
+		err := svc.dispatchPending(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+}
+
+func Test_fingerprint(t *testing.T) {
+	assert.Equal(t,
+		fingerprint("foo", map[string]string{"a": "1", "b": "2"}),
+		fingerprint("foo", map[string]string{"b": "2", "a": "1"}))
+
+	assert.NotEqual(t,
+		fingerprint("foo", map[string]string{"a": "1"}),
+		fingerprint("bar", map[string]string{"a": "1"}))
+
+	assert.NotEqual(t,
+		fingerprint("foo", map[string]string{"ab": ""}),
+		fingerprint("foo", map[string]string{"a": "b"}))
+}
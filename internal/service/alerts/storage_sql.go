@@ -0,0 +1,164 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+)
+
+const tableName = "alerts"
+
+var errNotFound = errors.New("not found")
+
+var allFields = []string{"fingerprint", "name", "labels", "severity", "summary", "started_at", "updated_at", "resolved_at", "notified"}
+
+// upsertSuffix replaces the alert with the same fingerprint.
+var upsertSuffix = "ON CONFLICT(fingerprint) DO UPDATE SET " + excludedValues(allFields[1:])
+
+type sqlStorage struct {
+	db *sql.DB
+}
+
+func newSqlStorage(db *sql.DB) *sqlStorage {
+	return &sqlStorage{db}
+}
+
+// Save inserts the alert or replaces the one with the same fingerprint.
+func (s *sqlStorage) Save(ctx context.Context, a *Alert) error {
+	rawLabels, err := json.Marshal(a.labels)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the labels: %w", err)
+	}
+
+	var resolvedAt *sqlstorage.SQLTime
+	if a.resolvedAt != nil {
+		resolvedAt = ptr.To(sqlstorage.SQLTime(*a.resolvedAt))
+	}
+
+	_, err = sq.
+		Insert(tableName).
+		Columns(allFields...).
+		Values(
+			a.fingerprint,
+			a.name,
+			string(rawLabels),
+			a.severity,
+			a.summary,
+			ptr.To(sqlstorage.SQLTime(a.startedAt)),
+			ptr.To(sqlstorage.SQLTime(a.updatedAt)),
+			resolvedAt,
+			a.notified,
+		).
+		Suffix(upsertSuffix).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetByFingerprint(ctx context.Context, fingerprint string) (*Alert, error) {
+	row := sq.
+		Select(allFields...).
+		From(tableName).
+		Where(sq.Eq{"fingerprint": fingerprint}).
+		RunWith(s.db).
+		QueryRowContext(ctx)
+
+	res, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) GetFiring(ctx context.Context) ([]Alert, error) {
+	rows, err := sq.
+		Select(allFields...).
+		From(tableName).
+		Where(sq.Eq{"resolved_at": nil}).
+		OrderBy("started_at").
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	res := []Alert{}
+
+	for rows.Next() {
+		alert, err := s.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		res = append(res, *alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) scan(row sqlstorage.RowScanner) (*Alert, error) {
+	var res Alert
+	var rawLabels string
+	var sqlStartedAt, sqlUpdatedAt sqlstorage.SQLTime
+	var sqlResolvedAt *sqlstorage.SQLTime
+
+	err := row.Scan(
+		&res.fingerprint,
+		&res.name,
+		&rawLabels,
+		&res.severity,
+		&res.summary,
+		&sqlStartedAt,
+		&sqlUpdatedAt,
+		&sqlResolvedAt,
+		&res.notified,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(rawLabels), &res.labels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid labels: %w", err)
+	}
+
+	res.startedAt = sqlStartedAt.Time()
+	res.updatedAt = sqlUpdatedAt.Time()
+	if sqlResolvedAt != nil {
+		res.resolvedAt = ptr.To(sqlResolvedAt.Time())
+	}
+
+	return &res, nil
+}
+
+// excludedValues returns the SET clause of an upsert replacing the fields
+// with the values of the rejected insert.
+func excludedValues(fields []string) string {
+	res := make([]string, len(fields))
+	for i, field := range fields {
+		res[i] = field + " = excluded." + field
+	}
+
+	return strings.Join(res, ", ")
+}
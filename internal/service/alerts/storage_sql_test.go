@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertsSqlStorage(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqlstorage.NewTestStorage(t)
+	store := newSqlStorage(db)
+
+	alert := NewFakeAlert(t).Build()
+	resolvedAlert := NewFakeAlert(t).WithResolvedAt(time.Now()).Build()
+
+	t.Run("GetByFingerprint not found", func(t *testing.T) {
+		res, err := store.GetByFingerprint(ctx, alert.Fingerprint())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("Save success", func(t *testing.T) {
+		err := store.Save(ctx, alert)
+		require.NoError(t, err)
+
+		err = store.Save(ctx, resolvedAlert)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetByFingerprint success", func(t *testing.T) {
+		res, err := store.GetByFingerprint(ctx, alert.Fingerprint())
+		require.NoError(t, err)
+		assert.Equal(t, alert, res)
+
+		res, err = store.GetByFingerprint(ctx, resolvedAlert.Fingerprint())
+		require.NoError(t, err)
+		assert.Equal(t, resolvedAlert, res)
+	})
+
+	t.Run("GetFiring success", func(t *testing.T) {
+		res, err := store.GetFiring(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []Alert{*alert}, res)
+	})
+
+	t.Run("Save replaces an existing alert", func(t *testing.T) {
+		alert.summary = "some new summary"
+
+		err := store.Save(ctx, alert)
+		require.NoError(t, err)
+
+		res, err := store.GetByFingerprint(ctx, alert.Fingerprint())
+		require.NoError(t, err)
+		assert.Equal(t, "some new summary", res.Summary())
+	})
+}
@@ -0,0 +1,31 @@
+package anomalies
+
+import (
+	"context"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+)
+
+// AnomalyCron fires an alert when the latest memory points are anomalies.
+type AnomalyCron struct {
+	service Service
+}
+
+func newAnomalyCron(service Service) *AnomalyCron {
+	return &AnomalyCron{service: service}
+}
+
+func (c *AnomalyCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "anomalies",
+		Schedule: scheduler.Every(30 * time.Second),
+		Jitter:   5 * time.Second,
+		Timeout:  30 * time.Second,
+		Runner:   c,
+	}
+}
+
+func (c *AnomalyCron) Run(ctx context.Context) error {
+	return c.service.evaluate(ctx)
+}
@@ -0,0 +1,36 @@
+package anomalies
+
+import (
+	"context"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"go.uber.org/fx"
+)
+
+type Result struct {
+	fx.Out
+	Service Service
+	Cron    *AnomalyCron
+}
+
+type Service interface {
+	// GetMemoryScores returns the memory usage recorded between start and
+	// end with their anomaly score.
+	GetMemoryScores(ctx context.Context, start, end time.Time) ([]ScoredPoint, error)
+	IsAlertingEnabled(ctx context.Context) (bool, error)
+	SetAlertingEnabled(ctx context.Context, enabled bool) error
+	evaluate(ctx context.Context) error
+}
+
+func Init(sysstats sysstats.Service, alerts alerts.Service, config config.Service, tools tools.Tools) Result {
+	svc := newService(sysstats, alerts, config, tools)
+
+	return Result{
+		Service: svc,
+		Cron:    newAnomalyCron(svc),
+	}
+}
@@ -0,0 +1,35 @@
+package anomalies
+
+import (
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/anomaly"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+)
+
+// ScoredPoint is a memory usage point with its anomaly score.
+type ScoredPoint struct {
+	at    time.Time
+	total datasize.ByteSize
+	// value is the used memory percentage.
+	value float64
+	score anomaly.Score
+}
+
+func (p ScoredPoint) At() time.Time            { return p.at }
+func (p ScoredPoint) Total() datasize.ByteSize { return p.total }
+func (p ScoredPoint) Value() float64           { return p.value }
+func (p ScoredPoint) Score() anomaly.Score     { return p.score }
+func (p ScoredPoint) IsAnomaly() bool          { return p.score.IsAnomaly }
+
+// Baseline returns the expected used memory or nil if there is not enough
+// history to compute it.
+func (p ScoredPoint) Baseline() *datasize.ByteSize {
+	if !p.score.HasBaseline {
+		return nil
+	}
+
+	res := datasize.ByteSize(p.score.Mean / 100 * float64(p.total))
+
+	return &res
+}
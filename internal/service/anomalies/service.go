@@ -0,0 +1,160 @@
+package anomalies
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/anomaly"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+)
+
+const MemoryAnomalyAlert = "memory-anomaly"
+
+const (
+	// baselineWindow is the history used to compute the baseline of a point.
+	baselineWindow = 30 * time.Minute
+	// consecutiveAnomalies is the number of anomalies in a row required to
+	// fire an alert. A single spike is not worth a notification.
+	consecutiveAnomalies = 3
+	// threshold is the number of standard deviations from the baseline
+	// making a point anomalous.
+	threshold = 4
+)
+
+// newDetector returns the detector for points spaced by the given
+// collection interval.
+func newDetector(interval time.Duration) anomaly.Detector {
+	return anomaly.Detector{
+		Window:    int(baselineWindow / interval),
+		MinPoints: int(5 * time.Minute / interval),
+		Threshold: threshold,
+		// The values are percentages: ignore the variations under 1%.
+		MinStdDev: 1,
+	}
+}
+
+type service struct {
+	sysstats sysstats.Service
+	alerts   alerts.Service
+	config   config.Service
+	clock    clock.Clock
+}
+
+func newService(sysstats sysstats.Service, alerts alerts.Service, config config.Service, tools tools.Tools) *service {
+	return &service{
+		sysstats: sysstats,
+		alerts:   alerts,
+		config:   config,
+		clock:    tools.Clock(),
+	}
+}
+
+func (s *service) GetMemoryScores(ctx context.Context, start, end time.Time) ([]ScoredPoint, error) {
+	interval, err := s.config.GetDuration(ctx, config.CollectionInterval)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to get the collection interval: %w", err))
+	}
+
+	stats, err := s.sysstats.GetRange(ctx, start.Add(-baselineWindow), end)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to GetRange: %w", err))
+	}
+
+	values := make([]float64, len(stats))
+	for i, stat := range stats {
+		mem := stat.Memory()
+		values[i] = float64(mem.UsedMemory()) / float64(mem.TotalMemory()) * 100
+	}
+
+	scores := newDetector(interval).Detect(values)
+
+	res := []ScoredPoint{}
+	for i, stat := range stats {
+		if !stat.Time().After(start) {
+			continue
+		}
+
+		res = append(res, ScoredPoint{
+			at:    stat.Time(),
+			total: stat.Memory().TotalMemory(),
+			value: values[i],
+			score: scores[i],
+		})
+	}
+
+	return res, nil
+}
+
+func (s *service) IsAlertingEnabled(ctx context.Context) (bool, error) {
+	res, err := s.config.GetAnomalyAlerts(ctx)
+	if err != nil {
+		return false, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) SetAlertingEnabled(ctx context.Context, enabled bool) error {
+	err := s.config.SetAnomalyAlerts(ctx, enabled)
+	if err != nil {
+		return errs.Internal(err)
+	}
+
+	return nil
+}
+
+func (s *service) evaluate(ctx context.Context) error {
+	enabled, err := s.config.GetAnomalyAlerts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to GetAnomalyAlerts: %w", err)
+	}
+
+	resolveCmd := alerts.ResolveCmd{Name: MemoryAnomalyAlert}
+
+	if !enabled {
+		return s.alerts.Resolve(ctx, &resolveCmd)
+	}
+
+	interval, err := s.config.GetDuration(ctx, config.CollectionInterval)
+	if err != nil {
+		return fmt.Errorf("failed to get the collection interval: %w", err)
+	}
+
+	now := s.clock.Now()
+
+	points, err := s.GetMemoryScores(ctx, now.Add(-consecutiveAnomalies*interval*2), now)
+	if err != nil {
+		return fmt.Errorf("failed to GetMemoryScores: %w", err)
+	}
+
+	if len(points) < consecutiveAnomalies {
+		return s.alerts.Resolve(ctx, &resolveCmd)
+	}
+
+	for _, p := range points[len(points)-consecutiveAnomalies:] {
+		if !p.IsAnomaly() {
+			return s.alerts.Resolve(ctx, &resolveCmd)
+		}
+	}
+
+	latest := points[len(points)-1]
+
+	_, err = s.alerts.Fire(ctx, &alerts.FireCmd{
+		Name:     MemoryAnomalyAlert,
+		Severity: notifications.Warning,
+		Summary: fmt.Sprintf("Memory usage at %.0f%% while the usual usage is %.0f%% ± %.0f%%",
+			latest.value, latest.score.Mean, threshold*latest.score.StdDev),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Fire: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package anomalies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// buildHistory returns an hour of stats, every 5s, with the memory used at
+// ~50%. The last spikeLen points use 90% of the memory.
+func buildHistory(t *testing.T, now time.Time, spikeLen int) []sysstats.Stats {
+	res := []sysstats.Stats{}
+
+	total := 16 * datasize.GB
+
+	for at := now.Add(-time.Hour); !at.After(now); at = at.Add(5 * time.Second) {
+		available := total / 2
+		if len(res)%2 == 0 {
+			available += 100 * datasize.MB
+		}
+
+		res = append(res, *sysstats.NewFakeStats(t).
+			WithTime(at).
+			WithMemory(total, available).
+			Build())
+	}
+
+	for i := len(res) - spikeLen; i < len(res); i++ {
+		res[i] = *sysstats.NewFakeStats(t).
+			WithTime(res[i].Time()).
+			WithMemory(total, total/10).
+			Build()
+	}
+
+	return res
+}
+
+func TestAnomaliesService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetMemoryScores success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		sysstatsMock := sysstats.NewMockService(t)
+		configMock := config.NewMockService(t)
+		svc := newService(sysstatsMock, alerts.NewMockService(t), configMock, tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		start := now.Add(-5 * time.Minute)
+		history := buildHistory(t, now, 1)
+
+		// Mocks
+		configMock.On("GetDuration", mock.Anything, config.CollectionInterval).Return(5*time.Second, nil).Once()
+		sysstatsMock.On("GetRange", mock.Anything, start.Add(-baselineWindow), now).Return(history, nil).Once()
+
+		// Run
+		res, err := svc.GetMemoryScores(ctx, start, now)
+
+		// Asserts
+		require.NoError(t, err)
+		require.Len(t, res, 60)
+
+		assert.Equal(t, start.Add(5*time.Second), res[0].At())
+		assert.False(t, res[0].IsAnomaly())
+		require.NotNil(t, res[0].Baseline())
+		assert.InDelta(t, float64(8*datasize.GB), float64(*res[0].Baseline()), float64(100*datasize.MB))
+
+		latest := res[len(res)-1]
+		assert.Equal(t, now, latest.At())
+		assert.InDelta(t, 90, latest.Value(), 0.1)
+		assert.True(t, latest.IsAnomaly())
+	})
+
+	t.Run("GetMemoryScores without baseline", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		sysstatsMock := sysstats.NewMockService(t)
+		configMock := config.NewMockService(t)
+		svc := newService(sysstatsMock, alerts.NewMockService(t), configMock, tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		start := now.Add(-5 * time.Minute)
+		history := buildHistory(t, now, 1)[700:]
+
+		// Mocks
+		configMock.On("GetDuration", mock.Anything, config.CollectionInterval).Return(5*time.Second, nil).Once()
+		sysstatsMock.On("GetRange", mock.Anything, start.Add(-baselineWindow), now).Return(history, nil).Once()
+
+		// Run
+		res, err := svc.GetMemoryScores(ctx, start, now)
+
+		// Asserts
+		require.NoError(t, err)
+		require.NotEmpty(t, res)
+		for _, p := range res {
+			assert.Nil(t, p.Baseline())
+			assert.False(t, p.IsAnomaly())
+		}
+	})
+
+	t.Run("evaluate fires an alert on a lasting anomaly", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		sysstatsMock := sysstats.NewMockService(t)
+		alertsMock := alerts.NewMockService(t)
+		configMock := config.NewMockService(t)
+		svc := newService(sysstatsMock, alertsMock, configMock, tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		start := now.Add(-30 * time.Second)
+		history := buildHistory(t, now, 3)
+
+		// Mocks
+		configMock.On("GetAnomalyAlerts", mock.Anything).Return(true, nil).Once()
+		configMock.On("GetDuration", mock.Anything, config.CollectionInterval).Return(5*time.Second, nil).Twice()
+		tools.ClockMock.On("Now").Return(now).Once()
+		sysstatsMock.On("GetRange", mock.Anything, start.Add(-baselineWindow), now).Return(history, nil).Once()
+		alertsMock.On("Fire", mock.Anything, &alerts.FireCmd{
+			Name:     MemoryAnomalyAlert,
+			Severity: notifications.Warning,
+			Summary:  "Memory usage at 90% while the usual usage is 50% ± 4%",
+		}).Return(&alerts.Alert{}, nil).Once()
+
+		// Run
+		err := svc.evaluate(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("evaluate ignores a single spike", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		sysstatsMock := sysstats.NewMockService(t)
+		alertsMock := alerts.NewMockService(t)
+		configMock := config.NewMockService(t)
+		svc := newService(sysstatsMock, alertsMock, configMock, tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		start := now.Add(-30 * time.Second)
+		history := buildHistory(t, now, 1)
+
+		// Mocks
+		configMock.On("GetAnomalyAlerts", mock.Anything).Return(true, nil).Once()
+		configMock.On("GetDuration", mock.Anything, config.CollectionInterval).Return(5*time.Second, nil).Twice()
+		tools.ClockMock.On("Now").Return(now).Once()
+		sysstatsMock.On("GetRange", mock.Anything, start.Add(-baselineWindow), now).Return(history, nil).Once()
+		alertsMock.On("Resolve", mock.Anything, &alerts.ResolveCmd{Name: MemoryAnomalyAlert}).Return(nil).Once()
+
+		// Run
+		err := svc.evaluate(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("evaluate resolves the alert when disabled", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		alertsMock := alerts.NewMockService(t)
+		configMock := config.NewMockService(t)
+		svc := newService(sysstats.NewMockService(t), alertsMock, configMock, tools)
+
+		// Mocks
+		configMock.On("GetAnomalyAlerts", mock.Anything).Return(false, nil).Once()
+		alertsMock.On("Resolve", mock.Anything, &alerts.ResolveCmd{Name: MemoryAnomalyAlert}).Return(nil).Once()
+
+		// Run
+		err := svc.evaluate(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,32 @@
+package backups
+
+import (
+	"context"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+)
+
+// BackupCron saves a backup once the config.BackupInterval setting is
+// elapsed since the latest one, and deletes the oldest ones.
+type BackupCron struct {
+	service Service
+}
+
+func newBackupCron(service Service) *BackupCron {
+	return &BackupCron{service: service}
+}
+
+func (c *BackupCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "backups",
+		Schedule: scheduler.Every(10 * time.Minute),
+		Jitter:   time.Minute,
+		Timeout:  10 * time.Minute,
+		Runner:   c,
+	}
+}
+
+func (c *BackupCron) Run(ctx context.Context) error {
+	return c.service.backupIfDue(ctx)
+}
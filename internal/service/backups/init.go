@@ -0,0 +1,45 @@
+package backups
+
+import (
+	"context"
+	"database/sql"
+	"io"
+
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/spf13/afero"
+	"go.uber.org/fx"
+)
+
+type Result struct {
+	fx.Out
+	Service Service
+	Cron    *BackupCron
+}
+
+type Service interface {
+	// Create saves a new manual backup in the backups folder. The manual
+	// backups aren't deleted by the rotation.
+	Create(ctx context.Context) (*Backup, error)
+	// GetAll returns the backups of the backups folder, the most recent
+	// first.
+	GetAll(ctx context.Context) ([]Backup, error)
+	// Open returns the content of a backup returned by GetAll.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// WriteFile writes a new backup at filePath, outside of the backups
+	// folder. The file must not exist or be empty.
+	WriteFile(ctx context.Context, filePath string) error
+	// Snapshot returns a new backup without saving it. Its temporary file is
+	// deleted once closed.
+	Snapshot(ctx context.Context) (io.ReadCloser, error)
+	backupIfDue(ctx context.Context) error
+}
+
+func Init(db *sql.DB, folderPath string, fs afero.Fs, config config.Service, tools tools.Tools) Result {
+	svc := newService(db, folderPath, fs, config, tools)
+
+	return Result{
+		Service: svc,
+		Cron:    newBackupCron(svc),
+	}
+}
@@ -0,0 +1,70 @@
+package backups
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+)
+
+const (
+	// Dir is the backups folder, inside the data folder.
+	Dir = "backups"
+
+	filePrefix = "zapette-"
+	fileExt    = ".sqlite"
+	// manualSuffix marks the backups created on demand, they are never
+	// deleted by the rotation.
+	manualSuffix = "-manual"
+	// fileTimeFormat is the creation time format in the file names. They
+	// are sorted by date with the alphabetical order.
+	fileTimeFormat = "20060102T150405Z"
+)
+
+// Backup is a copy of the database saved in the backups folder.
+type Backup struct {
+	createdAt time.Time
+	name      string
+	size      datasize.ByteSize
+	manual    bool
+}
+
+func (b Backup) Name() string            { return b.name }
+func (b Backup) Size() datasize.ByteSize { return b.size }
+func (b Backup) CreatedAt() time.Time    { return b.createdAt }
+func (b Backup) Manual() bool            { return b.manual }
+
+// FileName returns the name of an automatic backup created at the given
+// time.
+func FileName(createdAt time.Time) string {
+	return filePrefix + createdAt.UTC().Format(fileTimeFormat) + fileExt
+}
+
+// ManualFileName returns the name of a manual backup created at the given
+// time.
+func ManualFileName(createdAt time.Time) string {
+	return filePrefix + createdAt.UTC().Format(fileTimeFormat) + manualSuffix + fileExt
+}
+
+// parseFileName returns the creation time of a backup file and whether it's
+// a manual one, false for the other files.
+func parseFileName(name string) (time.Time, bool, bool) {
+	raw, ok := strings.CutPrefix(name, filePrefix)
+	if !ok {
+		return time.Time{}, false, false
+	}
+
+	raw, ok = strings.CutSuffix(raw, fileExt)
+	if !ok {
+		return time.Time{}, false, false
+	}
+
+	raw, manual := strings.CutSuffix(raw, manualSuffix)
+
+	res, err := time.Parse(fileTimeFormat, raw)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+
+	return res, manual, true
+}
@@ -0,0 +1,40 @@
+package backups
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type FakeBackupBuilder struct {
+	t      testing.TB
+	backup *Backup
+}
+
+func NewFakeBackup(t testing.TB) *FakeBackupBuilder {
+	t.Helper()
+
+	createdAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now()).UTC().Truncate(time.Second)
+
+	return &FakeBackupBuilder{
+		t: t,
+		backup: &Backup{
+			createdAt: createdAt,
+			name:      FileName(createdAt),
+			size:      datasize.ByteSize(gofakeit.IntRange(1, 100)) * datasize.MB,
+		},
+	}
+}
+
+func (f *FakeBackupBuilder) Manual() *FakeBackupBuilder {
+	f.backup.name = ManualFileName(f.backup.createdAt)
+	f.backup.manual = true
+
+	return f
+}
+
+func (f *FakeBackupBuilder) Build() *Backup {
+	return f.backup
+}
@@ -0,0 +1,254 @@
+package backups
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/spf13/afero"
+)
+
+var (
+	ErrUnknownBackup = errors.New("unknown backup")
+	ErrBackupExists  = errors.New("a backup already exists for this time")
+	ErrNotSQLite     = errors.New("the backups are made from the SQLite database, use pg_dump with PostgreSQL")
+)
+
+type service struct {
+	db     *sql.DB
+	fs     afero.Fs
+	dir    string
+	config config.Service
+	clock  clock.Clock
+}
+
+func newService(db *sql.DB, folderPath string, fs afero.Fs, config config.Service, tools tools.Tools) *service {
+	return &service{
+		db:     db,
+		fs:     fs,
+		dir:    path.Join(folderPath, Dir),
+		config: config,
+		clock:  tools.Clock(),
+	}
+}
+
+func (s *service) Create(ctx context.Context) (*Backup, error) {
+	return s.create(ctx, true)
+}
+
+func (s *service) create(ctx context.Context, manual bool) (*Backup, error) {
+	if sqlstorage.DialectOf(s.db) != sqlstorage.SQLite {
+		return nil, errs.BadRequest(ErrNotSQLite)
+	}
+
+	createdAt := s.clock.Now().UTC().Truncate(time.Second)
+	name := FileName(createdAt)
+	if manual {
+		name = ManualFileName(createdAt)
+	}
+	filePath := path.Join(s.dir, name)
+
+	exists, err := afero.Exists(s.fs, filePath)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to check %q: %w", filePath, err))
+	}
+
+	if exists {
+		return nil, errs.BadRequest(ErrBackupExists)
+	}
+
+	err = s.fs.MkdirAll(s.dir, 0o700)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to create %q: %w", s.dir, err))
+	}
+
+	// The file is written with a temporary name, a partial backup must
+	// never be listed.
+	tmpPath := filePath + ".tmp"
+	defer s.fs.Remove(tmpPath)
+
+	err = s.WriteFile(ctx, tmpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.fs.Rename(tmpPath, filePath)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to rename %q: %w", tmpPath, err))
+	}
+
+	info, err := s.fs.Stat(filePath)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to stat %q: %w", filePath, err))
+	}
+
+	return &Backup{
+		createdAt: createdAt,
+		name:      name,
+		size:      datasize.ByteSize(info.Size()),
+		manual:    manual,
+	}, nil
+}
+
+func (s *service) GetAll(_ context.Context) ([]Backup, error) {
+	entries, err := afero.ReadDir(s.fs, s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Backup{}, nil
+	}
+
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to read %q: %w", s.dir, err))
+	}
+
+	res := []Backup{}
+	for _, entry := range entries {
+		createdAt, manual, ok := parseFileName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		res = append(res, Backup{
+			createdAt: createdAt,
+			name:      entry.Name(),
+			size:      datasize.ByteSize(entry.Size()),
+			manual:    manual,
+		})
+	}
+
+	slices.SortFunc(res, func(a, b Backup) int { return b.createdAt.Compare(a.createdAt) })
+
+	return res, nil
+}
+
+func (s *service) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	backups, err := s.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the listed names are accepted, the name comes from the url.
+	if !slices.ContainsFunc(backups, func(b Backup) bool { return b.name == name }) {
+		return nil, errs.NotFound(ErrUnknownBackup)
+	}
+
+	file, err := s.fs.Open(path.Join(s.dir, name))
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to open %q: %w", name, err))
+	}
+
+	return file, nil
+}
+
+func (s *service) WriteFile(ctx context.Context, filePath string) error {
+	if sqlstorage.DialectOf(s.db) != sqlstorage.SQLite {
+		return errs.BadRequest(ErrNotSQLite)
+	}
+
+	// "VACUUM INTO" writes on the disk, whatever the afero.Fs.
+	err := sqlstorage.Backup(ctx, s.db, filePath)
+	if err != nil {
+		return errs.Internal(err)
+	}
+
+	return nil
+}
+
+func (s *service) Snapshot(ctx context.Context) (io.ReadCloser, error) {
+	err := s.fs.MkdirAll(s.dir, 0o700)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to create %q: %w", s.dir, err))
+	}
+
+	// The snapshot is written next to the backups rather than in the
+	// temporary folder, which is often too small for a copy of the database.
+	tmp, err := afero.TempFile(s.fs, s.dir, ".snapshot-*.tmp")
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to create a temporary file: %w", err))
+	}
+
+	tmpPath := tmp.Name()
+	tmp.Close()
+
+	err = s.WriteFile(ctx, tmpPath)
+	if err != nil {
+		_ = s.fs.Remove(tmpPath)
+		return nil, err
+	}
+
+	file, err := s.fs.Open(tmpPath)
+	if err != nil {
+		_ = s.fs.Remove(tmpPath)
+		return nil, errs.Internal(fmt.Errorf("failed to open %q: %w", tmpPath, err))
+	}
+
+	return &snapshotFile{File: file, fs: s.fs}, nil
+}
+
+// snapshotFile deletes the snapshot once closed.
+type snapshotFile struct {
+	afero.File
+	fs afero.Fs
+}
+
+func (f *snapshotFile) Close() error {
+	err := f.File.Close()
+
+	return errors.Join(err, f.fs.Remove(f.Name()))
+}
+
+// backupIfDue creates a backup if the latest automatic one is older than the
+// config.BackupInterval setting, then deletes the automatic backups exceeding
+// the config.BackupKeep setting. The manual backups are never deleted.
+// Nothing is done with PostgreSQL.
+func (s *service) backupIfDue(ctx context.Context) error {
+	interval, err := s.config.GetDuration(ctx, config.BackupInterval)
+	if err != nil {
+		return fmt.Errorf("failed to get the backup interval: %w", err)
+	}
+
+	if interval == 0 || sqlstorage.DialectOf(s.db) != sqlstorage.SQLite {
+		return nil
+	}
+
+	backups, err := s.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the backups: %w", err)
+	}
+
+	backups = slices.DeleteFunc(backups, func(b Backup) bool { return b.manual })
+
+	if len(backups) == 0 || s.clock.Now().Sub(backups[0].createdAt) >= interval {
+		backup, err := s.create(ctx, false)
+		if err != nil {
+			return fmt.Errorf("failed to create the backup: %w", err)
+		}
+
+		backups = slices.Insert(backups, 0, *backup)
+	}
+
+	keep, err := s.config.GetInt(ctx, config.BackupKeep)
+	if err != nil {
+		return fmt.Errorf("failed to get the number of backups kept: %w", err)
+	}
+
+	for _, backup := range backups[min(keep, len(backups)):] {
+		err = s.fs.Remove(path.Join(s.dir, backup.name))
+		if err != nil {
+			return fmt.Errorf("failed to delete %q: %w", backup.name, err)
+		}
+	}
+
+	return nil
+}
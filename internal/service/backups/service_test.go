@@ -0,0 +1,234 @@
+package backups
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// sqliteHeader starts all the SQLite files.
+const sqliteHeader = "SQLite format 3\x00"
+
+func TestBackupsService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.June, 7, 10, 7, 3, 0, time.UTC)
+
+	// "VACUUM INTO" writes on the disk, the backups folder must be a real
+	// one.
+	newTestService := func(t *testing.T) (*service, *tools.Mock, *config.MockService, string) {
+		t.Helper()
+
+		tools := tools.NewMock(t)
+		configMock := config.NewMockService(t)
+		folder := t.TempDir()
+
+		return newService(sqlstorage.NewSQLiteTestStorage(t), folder, afero.NewOsFs(), configMock, tools), tools, configMock, folder
+	}
+
+	writeFile := func(t *testing.T, folder, name string) {
+		t.Helper()
+
+		require.NoError(t, os.MkdirAll(path.Join(folder, Dir), 0o700))
+		require.NoError(t, os.WriteFile(path.Join(folder, Dir, name), []byte("foo"), 0o600))
+	}
+
+	t.Run("Create success", func(t *testing.T) {
+		t.Parallel()
+		svc, tools, _, folder := newTestService(t)
+
+		tools.ClockMock.On("Now").Return(now).Once()
+
+		res, err := svc.Create(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "zapette-20240607T100703Z-manual.sqlite", res.Name())
+		assert.Equal(t, now, res.CreatedAt())
+		assert.True(t, res.Manual())
+		assert.Positive(t, res.Size())
+
+		content, err := os.ReadFile(path.Join(folder, Dir, "zapette-20240607T100703Z-manual.sqlite"))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(content, []byte(sqliteHeader)))
+	})
+
+	t.Run("Create twice in the same second", func(t *testing.T) {
+		t.Parallel()
+		svc, tools, _, _ := newTestService(t)
+
+		tools.ClockMock.On("Now").Return(now).Twice()
+
+		_, err := svc.Create(ctx)
+		require.NoError(t, err)
+
+		res, err := svc.Create(ctx)
+		require.ErrorIs(t, err, ErrBackupExists)
+		assert.Nil(t, res)
+	})
+
+	t.Run("GetAll success", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _, folder := newTestService(t)
+
+		for _, name := range []string{
+			FileName(now.Add(-time.Hour)),
+			ManualFileName(now.Add(-time.Minute)),
+			FileName(now),
+			"zapette-invalid.sqlite",
+			"zapette-20240607T100703Z.sqlite.tmp",
+			"notes.txt",
+		} {
+			writeFile(t, folder, name)
+		}
+
+		res, err := svc.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+		assert.Equal(t, FileName(now), res[0].Name())
+		assert.Equal(t, now, res[0].CreatedAt())
+		assert.False(t, res[0].Manual())
+		assert.Equal(t, ManualFileName(now.Add(-time.Minute)), res[1].Name())
+		assert.Equal(t, now.Add(-time.Minute), res[1].CreatedAt())
+		assert.True(t, res[1].Manual())
+		assert.Equal(t, FileName(now.Add(-time.Hour)), res[2].Name())
+	})
+
+	t.Run("GetAll without backups folder", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _, _ := newTestService(t)
+
+		res, err := svc.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Open success", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _, folder := newTestService(t)
+
+		writeFile(t, folder, FileName(now))
+
+		file, err := svc.Open(ctx, FileName(now))
+		require.NoError(t, err)
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, "foo", string(content))
+	})
+
+	t.Run("Open with an unknown backup", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _, folder := newTestService(t)
+
+		require.NoError(t, os.WriteFile(path.Join(folder, "master.key"), []byte("foo"), 0o600))
+
+		file, err := svc.Open(ctx, "../master.key")
+		require.ErrorIs(t, err, errs.ErrNotFound)
+		require.ErrorIs(t, err, ErrUnknownBackup)
+		assert.Nil(t, file)
+	})
+
+	t.Run("WriteFile success", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _, folder := newTestService(t)
+
+		filePath := path.Join(folder, "backup.sqlite")
+		err := svc.WriteFile(ctx, filePath)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(content, []byte(sqliteHeader)))
+	})
+
+	t.Run("Snapshot success", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _, folder := newTestService(t)
+
+		file, err := svc.Snapshot(ctx)
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(content, []byte(sqliteHeader)))
+
+		require.NoError(t, file.Close())
+
+		// The temporary file is deleted once closed.
+		entries, err := os.ReadDir(path.Join(folder, Dir))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("backupIfDue creates a backup and deletes the oldest ones", func(t *testing.T) {
+		t.Parallel()
+		svc, tools, configMock, folder := newTestService(t)
+
+		for i := 1; i <= 3; i++ {
+			writeFile(t, folder, FileName(now.Add(-time.Duration(i)*24*time.Hour)))
+		}
+
+		writeFile(t, folder, ManualFileName(now.Add(-time.Hour)))
+		writeFile(t, folder, ManualFileName(now.Add(-100*24*time.Hour)))
+
+		configMock.On("GetDuration", mock.Anything, config.BackupInterval).Return(24*time.Hour, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Twice()
+		configMock.On("GetInt", mock.Anything, config.BackupKeep).Return(2, nil).Once()
+
+		err := svc.backupIfDue(ctx)
+		require.NoError(t, err)
+
+		// The manual backups are neither counted nor deleted.
+		res, err := svc.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, res, 4)
+		assert.Equal(t, FileName(now), res[0].Name())
+		assert.Equal(t, ManualFileName(now.Add(-time.Hour)), res[1].Name())
+		assert.Equal(t, FileName(now.Add(-24*time.Hour)), res[2].Name())
+		assert.Equal(t, ManualFileName(now.Add(-100*24*time.Hour)), res[3].Name())
+	})
+
+	t.Run("backupIfDue with a recent backup", func(t *testing.T) {
+		t.Parallel()
+		svc, tools, configMock, folder := newTestService(t)
+
+		writeFile(t, folder, FileName(now.Add(-time.Hour)))
+
+		configMock.On("GetDuration", mock.Anything, config.BackupInterval).Return(24*time.Hour, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		configMock.On("GetInt", mock.Anything, config.BackupKeep).Return(2, nil).Once()
+
+		err := svc.backupIfDue(ctx)
+		require.NoError(t, err)
+
+		res, err := svc.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("backupIfDue disabled", func(t *testing.T) {
+		t.Parallel()
+		svc, _, configMock, _ := newTestService(t)
+
+		configMock.On("GetDuration", mock.Anything, config.BackupInterval).Return(time.Duration(0), nil).Once()
+
+		err := svc.backupIfDue(ctx)
+		require.NoError(t, err)
+
+		res, err := svc.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+}
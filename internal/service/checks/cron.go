@@ -0,0 +1,30 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+)
+
+// CheckCron runs the checks when their interval is elapsed.
+type CheckCron struct {
+	service Service
+}
+
+func newCheckCron(service Service) *CheckCron {
+	return &CheckCron{service: service}
+}
+
+func (c *CheckCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "checks",
+		Schedule: scheduler.Every(MinInterval),
+		Timeout:  time.Minute,
+		Runner:   c,
+	}
+}
+
+func (c *CheckCron) Run(ctx context.Context) error {
+	return c.service.runDue(ctx)
+}
@@ -0,0 +1,40 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"go.uber.org/fx"
+)
+
+type Result struct {
+	fx.Out
+	Service Service
+	Cron    *CheckCron
+}
+
+type Service interface {
+	Create(ctx context.Context, cmd *CreateCmd) (*Check, error)
+	GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Check, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Check, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// GetProbes returns the probes of the check run since the given time,
+	// sorted by time.
+	GetProbes(ctx context.Context, check *Check, since time.Time) ([]Probe, error)
+	runDue(ctx context.Context) error
+}
+
+func Init(db *sql.DB, alerts alerts.Service, tools tools.Tools) Result {
+	storage := newSqlStorage(db)
+	svc := newService(storage, alerts, tools)
+
+	return Result{
+		Service: svc,
+		Cron:    newCheckCron(svc),
+	}
+}
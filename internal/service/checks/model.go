@@ -0,0 +1,193 @@
+package checks
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	v "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+type Kind string
+
+const (
+	// HTTP does a GET on the target URL and checks the response status and
+	// optionally the body.
+	HTTP Kind = "http"
+	// TCP opens a connection to the target "host:port".
+	TCP Kind = "tcp"
+	// DNS resolves the target hostname, optionally against a given resolver.
+	DNS Kind = "dns"
+)
+
+var AllKinds = []Kind{HTTP, TCP, DNS}
+
+const (
+	MinInterval    = 10 * time.Second
+	DefaultTimeout = 10 * time.Second
+)
+
+var errInvalidHostPort = errors.New("must be a valid host:port")
+
+// Settings contains the settings specific to a check kind.
+type Settings struct {
+	// ExpectedStatus is the HTTP status expected, 200 if not set.
+	ExpectedStatus int `json:"expectedStatus,omitempty"`
+	// BodyRegex is an optional regex the HTTP body must match.
+	BodyRegex string `json:"bodyRegex,omitempty"`
+	// Resolver is the "host:port" of the DNS server used. The system resolver
+	// is used if not set.
+	Resolver string `json:"resolver,omitempty"`
+}
+
+func (s Settings) validateFor(kind Kind) error {
+	switch kind {
+	case HTTP:
+		return v.ValidateStruct(&s,
+			v.Field(&s.ExpectedStatus, v.Min(100), v.Max(599)),
+			v.Field(&s.BodyRegex, v.By(isRegex)),
+		)
+	case DNS:
+		return v.ValidateStruct(&s,
+			v.Field(&s.Resolver, v.By(isHostPort)),
+		)
+	default:
+		return nil
+	}
+}
+
+// Check is a synthetic check run on a regular interval.
+type Check struct {
+	createdAt time.Time
+	id        uuid.UUID
+	name      string
+	kind      Kind
+	target    string
+	createdBy uuid.UUID
+	settings  Settings
+	interval  time.Duration
+	timeout   time.Duration
+}
+
+func (c Check) ID() uuid.UUID           { return c.id }
+func (c Check) Name() string            { return c.name }
+func (c Check) Kind() Kind              { return c.kind }
+func (c Check) Target() string          { return c.target }
+func (c Check) Settings() Settings      { return c.settings }
+func (c Check) Interval() time.Duration { return c.interval }
+func (c Check) Timeout() time.Duration  { return c.timeout }
+func (c Check) CreatedAt() time.Time    { return c.createdAt }
+func (c Check) CreatedBy() uuid.UUID    { return c.createdBy }
+
+// Probe is the result of a single run of a check.
+type Probe struct {
+	at      time.Time
+	checkID uuid.UUID
+	err     string
+	latency time.Duration
+	up      bool
+}
+
+func (p Probe) At() time.Time          { return p.at }
+func (p Probe) CheckID() uuid.UUID     { return p.checkID }
+func (p Probe) IsUp() bool             { return p.up }
+func (p Probe) Latency() time.Duration { return p.latency }
+func (p Probe) Error() string          { return p.err }
+
+// Summary aggregates the probes of a check over a period.
+type Summary struct {
+	Latest *Probe
+	// Uptime is the percentage of successful probes.
+	Uptime float64
+	// AvgLatency is the mean latency of the successful probes.
+	AvgLatency time.Duration
+	Count      int
+}
+
+// Summarize aggregates the given probes, sorted by time.
+func Summarize(probes []Probe) Summary {
+	res := Summary{Count: len(probes)}
+	if len(probes) == 0 {
+		return res
+	}
+
+	res.Latest = &probes[len(probes)-1]
+
+	var up int
+	var latency time.Duration
+	for _, p := range probes {
+		if p.up {
+			up++
+			latency += p.latency
+		}
+	}
+
+	res.Uptime = float64(up) / float64(len(probes)) * 100
+	if up > 0 {
+		res.AvgLatency = latency / time.Duration(up)
+	}
+
+	return res
+}
+
+type CreateCmd struct {
+	CreatedBy *users.User
+	Name      string
+	Kind      Kind
+	Target    string
+	Settings  Settings
+	Interval  time.Duration
+	// Timeout is DefaultTimeout if not set.
+	Timeout time.Duration
+}
+
+func (t CreateCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.CreatedBy, v.Required),
+		v.Field(&t.Name, v.Required, v.Length(1, 50)),
+		v.Field(&t.Kind, v.Required, v.In(HTTP, TCP, DNS)),
+		v.Field(&t.Target, v.Required, v.By(func(_ any) error { return t.validateTarget() })),
+		v.Field(&t.Settings, v.By(func(_ any) error { return t.Settings.validateFor(t.Kind) })),
+		v.Field(&t.Interval, v.Required, v.Min(MinInterval)),
+		v.Field(&t.Timeout, v.Min(time.Second), v.Max(t.Interval)),
+	)
+}
+
+func (t CreateCmd) validateTarget() error {
+	switch t.Kind {
+	case HTTP:
+		return is.URL.Validate(t.Target)
+	case TCP:
+		return isHostPort(t.Target)
+	case DNS:
+		return is.Host.Validate(t.Target)
+	default:
+		return nil
+	}
+}
+
+func isHostPort(value any) error {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil || host == "" || port == "" {
+		return errInvalidHostPort
+	}
+
+	return nil
+}
+
+func isRegex(value any) error {
+	s, _ := value.(string)
+
+	_, err := regexp.Compile(s)
+
+	return err
+}
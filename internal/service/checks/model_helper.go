@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+type FakeCheckBuilder struct {
+	t     testing.TB
+	check *Check
+}
+
+func NewFakeCheck(t testing.TB) *FakeCheckBuilder {
+	t.Helper()
+
+	uuidProvider := uuid.NewProvider()
+	createdAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+
+	return &FakeCheckBuilder{
+		t: t,
+		check: &Check{
+			id:        uuidProvider.New(),
+			name:      gofakeit.AppName(),
+			kind:      HTTP,
+			target:    gofakeit.URL(),
+			settings:  Settings{ExpectedStatus: 200},
+			interval:  time.Minute,
+			timeout:   DefaultTimeout,
+			createdAt: createdAt.UTC(),
+			createdBy: uuidProvider.New(),
+		},
+	}
+}
+
+func (f *FakeCheckBuilder) WithKind(kind Kind, target string) *FakeCheckBuilder {
+	f.check.kind = kind
+	f.check.target = target
+
+	return f
+}
+
+func (f *FakeCheckBuilder) WithSettings(settings Settings) *FakeCheckBuilder {
+	f.check.settings = settings
+
+	return f
+}
+
+func (f *FakeCheckBuilder) WithInterval(interval time.Duration) *FakeCheckBuilder {
+	f.check.interval = interval
+
+	return f
+}
+
+func (f *FakeCheckBuilder) Build() *Check {
+	return f.check
+}
+
+func (f *FakeCheckBuilder) BuildAndStore(ctx context.Context, db *sql.DB) *Check {
+	f.t.Helper()
+
+	storage := newSqlStorage(db)
+
+	err := storage.Save(ctx, f.check)
+	require.NoError(f.t, err)
+
+	return f.check
+}
+
+// NewFakeProbe returns a probe of the given check.
+func NewFakeProbe(check *Check, at time.Time, up bool, latency time.Duration) Probe {
+	res := Probe{
+		at:      at.Truncate(time.Second).UTC(),
+		checkID: check.id,
+		up:      up,
+		latency: latency.Truncate(time.Microsecond),
+	}
+
+	if !up {
+		res.err = "some-error"
+	}
+
+	return res
+}
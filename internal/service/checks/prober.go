@@ -0,0 +1,18 @@
+package checks
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected status code")
+	ErrBodyMismatch     = errors.New("the body doesn't match the regex")
+	ErrNoAddress        = errors.New("no address found")
+)
+
+// prober runs a check of a given kind. It returns an error if the check
+// fails.
+type prober interface {
+	Probe(ctx context.Context, check *Check) error
+}
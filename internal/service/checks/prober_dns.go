@@ -0,0 +1,38 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+type dnsProber struct{}
+
+func newDNSProber() *dnsProber {
+	return &dnsProber{}
+}
+
+func (p *dnsProber) Probe(ctx context.Context, check *Check) error {
+	resolver := net.DefaultResolver
+
+	if addr := check.settings.Resolver; addr != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		}
+	}
+
+	addrs, err := resolver.LookupHost(ctx, check.target)
+	if err != nil {
+		return fmt.Errorf("failed to resolve: %w", err)
+	}
+
+	if len(addrs) == 0 {
+		return ErrNoAddress
+	}
+
+	return nil
+}
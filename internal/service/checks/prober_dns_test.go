@@ -0,0 +1,40 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSProber(t *testing.T) {
+	ctx := context.Background()
+	prober := newDNSProber()
+
+	t.Run("Probe with the system resolver", func(t *testing.T) {
+		check := NewFakeCheck(t).WithKind(DNS, "localhost").Build()
+
+		err := prober.Probe(ctx, check)
+		require.NoError(t, err)
+	})
+
+	t.Run("Probe with an unreachable resolver", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := conn.LocalAddr().String()
+		conn.Close()
+
+		check := NewFakeCheck(t).
+			WithKind(DNS, "example.com").
+			WithSettings(Settings{Resolver: addr}).
+			Build()
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		err = prober.Probe(ctx, check)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,69 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// httpBodyLimit is the max body size read to match the body regex.
+const httpBodyLimit = 1024 * 1024
+
+type httpProber struct {
+	client *http.Client
+}
+
+func newHTTPProber(client *http.Client) *httpProber {
+	// The redirects are not followed so a 3xx status can be expected.
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &httpProber{client: client}
+}
+
+func (p *httpProber) Probe(ctx context.Context, check *Check) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "zapette")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer res.Body.Close()
+
+	expected := check.settings.ExpectedStatus
+	if expected == 0 {
+		expected = http.StatusOK
+	}
+
+	if res.StatusCode != expected {
+		return fmt.Errorf("%w: expected %d, have %d", ErrUnexpectedStatus, expected, res.StatusCode)
+	}
+
+	if check.settings.BodyRegex == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(check.settings.BodyRegex)
+	if err != nil {
+		return fmt.Errorf("invalid body regex: %w", err)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, httpBodyLimit))
+	if err != nil {
+		return fmt.Errorf("failed to read the body: %w", err)
+	}
+
+	if !re.Match(body) {
+		return fmt.Errorf("%w: %q", ErrBodyMismatch, check.settings.BodyRegex)
+	}
+
+	return nil
+}
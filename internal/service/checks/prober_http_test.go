@@ -0,0 +1,85 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProber(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/", http.StatusMovedPermanently)
+			return
+		}
+
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer srv.Close()
+
+	prober := newHTTPProber(srv.Client())
+
+	t.Run("Probe success", func(t *testing.T) {
+		check := NewFakeCheck(t).WithKind(HTTP, srv.URL).Build()
+
+		err := prober.Probe(ctx, check)
+		require.NoError(t, err)
+	})
+
+	t.Run("Probe with a matching body", func(t *testing.T) {
+		check := NewFakeCheck(t).
+			WithKind(HTTP, srv.URL).
+			WithSettings(Settings{BodyRegex: `"status":\s*"ok"`}).
+			Build()
+
+		err := prober.Probe(ctx, check)
+		require.NoError(t, err)
+	})
+
+	t.Run("Probe with a body mismatch", func(t *testing.T) {
+		check := NewFakeCheck(t).
+			WithKind(HTTP, srv.URL).
+			WithSettings(Settings{BodyRegex: `"status":\s*"degraded"`}).
+			Build()
+
+		err := prober.Probe(ctx, check)
+		require.ErrorIs(t, err, ErrBodyMismatch)
+	})
+
+	t.Run("Probe with an unexpected status", func(t *testing.T) {
+		check := NewFakeCheck(t).WithKind(HTTP, srv.URL+"/missing").Build()
+
+		err := prober.Probe(ctx, check)
+		require.ErrorIs(t, err, ErrUnexpectedStatus)
+	})
+
+	t.Run("Probe with an expected status", func(t *testing.T) {
+		check := NewFakeCheck(t).
+			WithKind(HTTP, srv.URL+"/missing").
+			WithSettings(Settings{ExpectedStatus: http.StatusNotFound}).
+			Build()
+
+		err := prober.Probe(ctx, check)
+		require.NoError(t, err)
+	})
+
+	t.Run("Probe with an expected redirect", func(t *testing.T) {
+		check := NewFakeCheck(t).
+			WithKind(HTTP, srv.URL+"/old").
+			WithSettings(Settings{ExpectedStatus: http.StatusMovedPermanently}).
+			Build()
+
+		err := prober.Probe(ctx, check)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,24 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+type tcpProber struct {
+	dialer *net.Dialer
+}
+
+func newTCPProber() *tcpProber {
+	return &tcpProber{dialer: &net.Dialer{}}
+}
+
+func (p *tcpProber) Probe(ctx context.Context, check *Check) error {
+	conn, err := p.dialer.DialContext(ctx, "tcp", check.target)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	return conn.Close()
+}
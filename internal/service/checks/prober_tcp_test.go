@@ -0,0 +1,37 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTCPProber(t *testing.T) {
+	ctx := context.Background()
+	prober := newTCPProber()
+
+	t.Run("Probe success", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		check := NewFakeCheck(t).WithKind(TCP, listener.Addr().String()).Build()
+
+		err = prober.Probe(ctx, check)
+		require.NoError(t, err)
+	})
+
+	t.Run("Probe with a closed port", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+
+		check := NewFakeCheck(t).WithKind(TCP, addr).Build()
+
+		err = prober.Probe(ctx, check)
+		require.Error(t, err)
+	})
+}
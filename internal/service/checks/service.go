@@ -0,0 +1,270 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const (
+	CheckDownAlert = "check-down"
+	CheckLabel     = "check"
+	// CheckIDLabel identifies the check inside the alerts, the names aren't
+	// unique.
+	CheckIDLabel = "check_id"
+
+	// probesRetention is the duration the probes are kept.
+	probesRetention = 7 * 24 * time.Hour
+)
+
+var ErrUnsupportedKind = errors.New("unsupported check kind")
+
+type storage interface {
+	Save(ctx context.Context, check *Check) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Check, error)
+	GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Check, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	SaveProbe(ctx context.Context, probe *Probe) error
+	GetProbes(ctx context.Context, checkID uuid.UUID, since time.Time) ([]Probe, error)
+	DeleteProbesBefore(ctx context.Context, before time.Time) error
+}
+
+type service struct {
+	storage storage
+	alerts  alerts.Service
+	clock   clock.Clock
+	uuid    uuid.Service
+	log     *slog.Logger
+	probers map[Kind]prober
+
+	// lastRuns contains the last run of each check. It's kept in memory so
+	// all the checks run at startup.
+	lastRuns map[uuid.UUID]time.Time
+	lock     sync.Mutex
+}
+
+func newService(storage storage, alerts alerts.Service, tools tools.Tools) *service {
+	return &service{
+		storage: storage,
+		alerts:  alerts,
+		clock:   tools.Clock(),
+		uuid:    tools.UUID(),
+		log:     tools.Logger().With(slog.String("source", "checks")),
+		probers: map[Kind]prober{
+			HTTP: newHTTPProber(&http.Client{}),
+			TCP:  newTCPProber(),
+			DNS:  newDNSProber(),
+		},
+		lastRuns: map[uuid.UUID]time.Time{},
+		lock:     sync.Mutex{},
+	}
+}
+
+func (s *service) Create(ctx context.Context, cmd *CreateCmd) (*Check, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	timeout := cmd.Timeout
+	if timeout == 0 {
+		timeout = min(DefaultTimeout, cmd.Interval)
+	}
+
+	check := Check{
+		id:        s.uuid.New(),
+		name:      cmd.Name,
+		kind:      cmd.Kind,
+		target:    cmd.Target,
+		settings:  cmd.Settings,
+		interval:  cmd.Interval.Round(time.Second),
+		timeout:   timeout.Round(time.Second),
+		createdAt: s.clock.Now(),
+		createdBy: cmd.CreatedBy.ID(),
+	}
+
+	err = s.storage.Save(ctx, &check)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to save the check: %w", err))
+	}
+
+	return &check, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*Check, error) {
+	res, err := s.storage.GetByID(ctx, id)
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Check, error) {
+	res, err := s.storage.GetAll(ctx, cmd)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+	check, err := s.GetByID(ctx, id)
+	if errors.Is(err, errs.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Delete(ctx, id)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Delete: %w", err))
+	}
+
+	err = s.alerts.Resolve(ctx, downResolveCmd(check))
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to resolve the alert: %w", err))
+	}
+
+	s.lock.Lock()
+	delete(s.lastRuns, id)
+	s.lock.Unlock()
+
+	return nil
+}
+
+func (s *service) GetProbes(ctx context.Context, check *Check, since time.Time) ([]Probe, error) {
+	res, err := s.storage.GetProbes(ctx, check.id, since)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+// runDue runs all the checks with an elapsed interval, records the probes and
+// fires or resolves their alerts.
+func (s *service) runDue(ctx context.Context) error {
+	checks, err := s.storage.GetAll(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to GetAll: %w", err)
+	}
+
+	now := s.clock.Now()
+
+	due := []Check{}
+	s.lock.Lock()
+	for _, check := range checks {
+		// The cron isn't perfectly regular, a small margin avoids to skip a tick.
+		if last, ok := s.lastRuns[check.id]; ok && now.Sub(last) < check.interval-time.Second {
+			continue
+		}
+
+		s.lastRuns[check.id] = now
+		due = append(due, check)
+	}
+	s.lock.Unlock()
+
+	wg := sync.WaitGroup{}
+	for i := range due {
+		wg.Add(1)
+		go func(check *Check) {
+			defer wg.Done()
+
+			err := s.run(ctx, check)
+			if err != nil {
+				s.log.Error("failed to run a check",
+					slog.String("check", check.name),
+					slog.String("error", err.Error()))
+			}
+		}(&due[i])
+	}
+	wg.Wait()
+
+	err = s.storage.DeleteProbesBefore(ctx, now.Add(-probesRetention))
+	if err != nil {
+		return fmt.Errorf("failed to DeleteProbesBefore: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) run(ctx context.Context, check *Check) error {
+	prober, ok := s.probers[check.kind]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnsupportedKind, check.kind)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, check.timeout)
+	defer cancel()
+
+	start := s.clock.Now()
+	probeErr := prober.Probe(probeCtx, check)
+	end := s.clock.Now()
+
+	probe := Probe{
+		at:      start.Truncate(time.Second),
+		checkID: check.id,
+		up:      probeErr == nil,
+		latency: end.Sub(start),
+	}
+
+	if probeErr != nil {
+		probe.err = probeErr.Error()
+	}
+
+	err := s.storage.SaveProbe(ctx, &probe)
+	if err != nil {
+		return fmt.Errorf("failed to SaveProbe: %w", err)
+	}
+
+	if probe.up {
+		return s.alerts.Resolve(ctx, downResolveCmd(check))
+	}
+
+	_, err = s.alerts.Fire(ctx, &alerts.FireCmd{
+		Name:     CheckDownAlert,
+		Labels:   downLabels(check),
+		Severity: notifications.Critical,
+		Summary:  fmt.Sprintf("%s is down: %s", check.name, probe.err),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Fire: %w", err)
+	}
+
+	return nil
+}
+
+func downResolveCmd(check *Check) *alerts.ResolveCmd {
+	return &alerts.ResolveCmd{
+		Name:   CheckDownAlert,
+		Labels: downLabels(check),
+	}
+}
+
+func downLabels(check *Check) map[string]string {
+	return map[string]string{
+		CheckLabel:   check.name,
+		CheckIDLabel: string(check.id),
+	}
+}
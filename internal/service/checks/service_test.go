@@ -0,0 +1,225 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		// Data
+		user := users.NewFakeUser(t).Build()
+		now := time.Now()
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-check-id")).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("Save", mock.Anything, mock.AnythingOfType("*checks.Check")).Return(nil).Once()
+
+		// Run
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: user,
+			Name:      "My app",
+			Kind:      HTTP,
+			Target:    "http://localhost:8080/health",
+			Settings:  Settings{BodyRegex: "ok"},
+			Interval:  time.Minute,
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, uuid.UUID("some-check-id"), res.ID())
+		assert.Equal(t, "My app", res.Name())
+		assert.Equal(t, HTTP, res.Kind())
+		assert.Equal(t, time.Minute, res.Interval())
+		assert.Equal(t, DefaultTimeout, res.Timeout())
+		assert.Equal(t, user.ID(), res.CreatedBy())
+		assert.Equal(t, now, res.CreatedAt())
+	})
+
+	t.Run("Create with an invalid target", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		svc := newService(newMockStorage(t), alerts.NewMockService(t), tools)
+
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: users.NewFakeUser(t).Build(),
+			Name:      "My db",
+			Kind:      TCP,
+			Target:    "localhost",
+			Interval:  time.Minute,
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Create with a too short interval", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		svc := newService(newMockStorage(t), alerts.NewMockService(t), tools)
+
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: users.NewFakeUser(t).Build(),
+			Name:      "My dns",
+			Kind:      DNS,
+			Target:    "example.com",
+			Interval:  time.Second,
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		storageMock.On("GetByID", mock.Anything, uuid.UUID("some-id")).Return(nil, errNotFound).Once()
+
+		res, err := svc.GetByID(ctx, uuid.UUID("some-id"))
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("Delete resolves the alert", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(storageMock, alertsMock, tools)
+
+		// Data
+		check := NewFakeCheck(t).Build()
+
+		// Mocks
+		storageMock.On("GetByID", mock.Anything, check.ID()).Return(check, nil).Once()
+		storageMock.On("Delete", mock.Anything, check.ID()).Return(nil).Once()
+		alertsMock.On("Resolve", mock.Anything, &alerts.ResolveCmd{
+			Name:   CheckDownAlert,
+			Labels: map[string]string{CheckLabel: check.Name(), CheckIDLabel: string(check.ID())},
+		}).Return(nil).Once()
+
+		// Run
+		err := svc.Delete(ctx, check.ID())
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("runDue records the probes and fires the alerts", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		httpMock := newMockProber(t)
+		tcpMock := newMockProber(t)
+		svc := newService(storageMock, alertsMock, tools)
+		svc.probers[HTTP] = httpMock
+		svc.probers[TCP] = tcpMock
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		upCheck := NewFakeCheck(t).Build()
+		downCheck := NewFakeCheck(t).WithKind(TCP, "localhost:5432").Build()
+
+		// Mocks
+		storageMock.On("GetAll", mock.Anything, (*sqlstorage.PaginateCmd)(nil)).
+			Return([]Check{*upCheck, *downCheck}, nil).Once()
+		tools.ClockMock.On("Now").Return(now)
+
+		httpMock.On("Probe", mock.Anything, upCheck).Return(nil).Once()
+		storageMock.On("SaveProbe", mock.Anything, &Probe{at: now, checkID: upCheck.ID(), up: true}).Return(nil).Once()
+		alertsMock.On("Resolve", mock.Anything, &alerts.ResolveCmd{
+			Name:   CheckDownAlert,
+			Labels: map[string]string{CheckLabel: upCheck.Name(), CheckIDLabel: string(upCheck.ID())},
+		}).Return(nil).Once()
+
+		tcpMock.On("Probe", mock.Anything, downCheck).Return(errors.New("connection refused")).Once()
+		storageMock.On("SaveProbe", mock.Anything, &Probe{at: now, checkID: downCheck.ID(), up: false, err: "connection refused"}).Return(nil).Once()
+		alertsMock.On("Fire", mock.Anything, &alerts.FireCmd{
+			Name:     CheckDownAlert,
+			Labels:   map[string]string{CheckLabel: downCheck.Name(), CheckIDLabel: string(downCheck.ID())},
+			Severity: notifications.Critical,
+			Summary:  downCheck.Name() + " is down: connection refused",
+		}).Return(&alerts.Alert{}, nil).Once()
+
+		storageMock.On("DeleteProbesBefore", mock.Anything, now.Add(-probesRetention)).Return(nil).Once()
+
+		// Run
+		err := svc.runDue(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("runDue skips the checks run recently", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		check := NewFakeCheck(t).WithInterval(time.Minute).Build()
+		svc.lastRuns[check.ID()] = now.Add(-30 * time.Second)
+
+		// Mocks
+		storageMock.On("GetAll", mock.Anything, (*sqlstorage.PaginateCmd)(nil)).Return([]Check{*check}, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("DeleteProbesBefore", mock.Anything, now.Add(-probesRetention)).Return(nil).Once()
+
+		// Run
+		err := svc.runDue(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	check := NewFakeCheck(t).Build()
+	now := time.Now()
+
+	t.Run("with no probes", func(t *testing.T) {
+		res := Summarize([]Probe{})
+		assert.Equal(t, Summary{}, res)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		probes := []Probe{
+			NewFakeProbe(check, now.Add(-3*time.Minute), true, 10*time.Millisecond),
+			NewFakeProbe(check, now.Add(-2*time.Minute), false, 10*time.Second),
+			NewFakeProbe(check, now.Add(-time.Minute), true, 20*time.Millisecond),
+			NewFakeProbe(check, now, true, 30*time.Millisecond),
+		}
+
+		res := Summarize(probes)
+		assert.Equal(t, &probes[3], res.Latest)
+		assert.Equal(t, 75.0, res.Uptime)
+		assert.Equal(t, 20*time.Millisecond, res.AvgLatency)
+		assert.Equal(t, 4, res.Count)
+	})
+}
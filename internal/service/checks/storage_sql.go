@@ -0,0 +1,238 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const (
+	checksTableName = "checks"
+	probesTableName = "check_probes"
+)
+
+var errNotFound = errors.New("not found")
+
+var (
+	allCheckFields = []string{"id", "name", "kind", "target", "settings", "interval", "timeout", "created_at", "created_by"}
+	allProbeFields = []string{"check_id", "time", "up", "latency", "error"}
+)
+
+type sqlStorage struct {
+	db *sql.DB
+}
+
+func newSqlStorage(db *sql.DB) *sqlStorage {
+	return &sqlStorage{db}
+}
+
+func (s *sqlStorage) Save(ctx context.Context, c *Check) error {
+	rawSettings, err := json.Marshal(c.settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the settings: %w", err)
+	}
+
+	_, err = sq.
+		Insert(checksTableName).
+		Columns(allCheckFields...).
+		Values(
+			c.id,
+			c.name,
+			c.kind,
+			c.target,
+			string(rawSettings),
+			int64(c.interval.Seconds()),
+			int64(c.timeout.Seconds()),
+			ptr.To(sqlstorage.SQLTime(c.createdAt)),
+			c.createdBy,
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetByID(ctx context.Context, id uuid.UUID) (*Check, error) {
+	row := sq.
+		Select(allCheckFields...).
+		From(checksTableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		QueryRowContext(ctx)
+
+	res, err := s.scanCheck(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Check, error) {
+	rows, err := sqlstorage.PaginateSelection(sq.
+		Select(allCheckFields...).
+		From(checksTableName), cmd).
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	checks := []Check{}
+
+	for rows.Next() {
+		res, err := s.scanCheck(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		checks = append(checks, *res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return checks, nil
+}
+
+func (s *sqlStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := sq.
+		Delete(probesTableName).
+		Where(sq.Eq{"check_id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete the probes: %w", err)
+	}
+
+	_, err = sq.
+		Delete(checksTableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) SaveProbe(ctx context.Context, p *Probe) error {
+	_, err := sq.
+		Insert(probesTableName).
+		Columns(allProbeFields...).
+		Values(
+			p.checkID,
+			p.at.Unix(),
+			p.up,
+			p.latency.Microseconds(),
+			p.err,
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetProbes(ctx context.Context, checkID uuid.UUID, since time.Time) ([]Probe, error) {
+	rows, err := sq.
+		Select(allProbeFields...).
+		From(probesTableName).
+		Where(sq.And{sq.Eq{"check_id": checkID}, sq.GtOrEq{"time": since.Unix()}}).
+		OrderBy("time ASC").
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	probes := []Probe{}
+
+	for rows.Next() {
+		var res Probe
+		var unixTime, latency int64
+
+		err := rows.Scan(&res.checkID, &unixTime, &res.up, &latency, &res.err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		res.at = time.Unix(unixTime, 0).UTC()
+		res.latency = time.Duration(latency) * time.Microsecond
+
+		probes = append(probes, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return probes, nil
+}
+
+func (s *sqlStorage) DeleteProbesBefore(ctx context.Context, before time.Time) error {
+	_, err := sq.
+		Delete(probesTableName).
+		Where(sq.Lt{"time": before.Unix()}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) scanCheck(row sqlstorage.RowScanner) (*Check, error) {
+	var res Check
+	var rawSettings string
+	var interval, timeout int64
+	var sqlCreatedAt sqlstorage.SQLTime
+
+	err := row.Scan(
+		&res.id,
+		&res.name,
+		&res.kind,
+		&res.target,
+		&rawSettings,
+		&interval,
+		&timeout,
+		&sqlCreatedAt,
+		&res.createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(rawSettings), &res.settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+
+	res.interval = time.Duration(interval) * time.Second
+	res.timeout = time.Duration(timeout) * time.Second
+	res.createdAt = sqlCreatedAt.Time()
+
+	return &res, nil
+}
@@ -0,0 +1,91 @@
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksSqlStorage(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqlstorage.NewTestStorage(t)
+	store := newSqlStorage(db)
+
+	now := time.Now().UTC()
+	check := NewFakeCheck(t).
+		WithKind(DNS, "example.com").
+		WithSettings(Settings{Resolver: "1.1.1.1:53"}).
+		Build()
+	probe1 := NewFakeProbe(check, now.Add(-2*time.Minute), true, 12*time.Millisecond)
+	probe2 := NewFakeProbe(check, now.Add(-time.Minute), false, time.Second)
+
+	t.Run("GetAll with nothing", func(t *testing.T) {
+		res, err := store.GetAll(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Save success", func(t *testing.T) {
+		err := store.Save(ctx, check)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetByID success", func(t *testing.T) {
+		res, err := store.GetByID(ctx, check.ID())
+		require.NoError(t, err)
+		assert.Equal(t, check, res)
+	})
+
+	t.Run("GetAll success", func(t *testing.T) {
+		res, err := store.GetAll(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []Check{*check}, res)
+	})
+
+	t.Run("SaveProbe success", func(t *testing.T) {
+		err := store.SaveProbe(ctx, &probe1)
+		require.NoError(t, err)
+
+		err = store.SaveProbe(ctx, &probe2)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetProbes success", func(t *testing.T) {
+		res, err := store.GetProbes(ctx, check.ID(), now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []Probe{probe1, probe2}, res)
+	})
+
+	t.Run("GetProbes since a given time", func(t *testing.T) {
+		res, err := store.GetProbes(ctx, check.ID(), probe2.At())
+		require.NoError(t, err)
+		assert.Equal(t, []Probe{probe2}, res)
+	})
+
+	t.Run("DeleteProbesBefore success", func(t *testing.T) {
+		err := store.DeleteProbesBefore(ctx, probe2.At())
+		require.NoError(t, err)
+
+		res, err := store.GetProbes(ctx, check.ID(), now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []Probe{probe2}, res)
+	})
+
+	t.Run("Delete success", func(t *testing.T) {
+		err := store.Delete(ctx, check.ID())
+		require.NoError(t, err)
+
+		res, err := store.GetByID(ctx, check.ID())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+
+		probes, err := store.GetProbes(ctx, check.ID(), now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, probes)
+	})
+}
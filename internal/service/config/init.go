@@ -3,18 +3,47 @@ package config
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
 	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"go.uber.org/fx"
 )
 
 type Service interface {
 	SetSysstatInputNamespace(ctx context.Context, id uuid.UUID) error
 	GetSysstatInputNamespace(ctx context.Context) (*uuid.UUID, error)
+	SetMasterKey(ctx context.Context, key *secret.SealedKey, salt []byte) error
+	GetMasterKey(ctx context.Context) (*secret.SealedKey, []byte, error)
+	SetAnomalyAlerts(ctx context.Context, enabled bool) error
+	GetAnomalyAlerts(ctx context.Context) (bool, error)
+
+	GetSettings(ctx context.Context) ([]SettingValue, error)
+	GetDuration(ctx context.Context, setting *Setting) (time.Duration, error)
+	GetString(ctx context.Context, setting *Setting) (string, error)
+	GetInt(ctx context.Context, setting *Setting) (int, error)
+	UpdateSetting(ctx context.Context, key ConfigKey, value string) error
+	ResetSetting(ctx context.Context, key ConfigKey) error
+	// Watch notifies the changes, the watchers must read again the settings
+	// they use.
+	Watch(ctx context.Context) chan struct{}
+}
+
+type Result struct {
+	fx.Out
+	Service Service
+	Watcher sqlstorage.SQLChangeHook `group:"hooks"`
 }
 
-func Init(db *sql.DB, tools tools.Tools) Service {
+func Init(db *sql.DB, tools tools.Tools) Result {
 	storage := newSqlStorage(db)
 
-	return newService(storage, tools)
+	svc := newService(storage, tools)
+
+	return Result{
+		Service: svc,
+		Watcher: svc,
+	}
 }
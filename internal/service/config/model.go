@@ -1,7 +1,184 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
 type ConfigKey string
 
 const (
 	sysstatsInputNamespace ConfigKey = "sysstats.input-namespace"
+	masterKey              ConfigKey = "masterkey.sealed"
+	anomalyAlerts          ConfigKey = "anomalies.alerts-enabled"
+)
+
+// masterKeySaltSep separates the sealed master key from its salt.
+const masterKeySaltSep = ":"
+
+// SettingKind is the type of a setting value.
+type SettingKind string
+
+const (
+	DurationSetting SettingKind = "duration"
+	StringSetting   SettingKind = "string"
+	IntSetting      SettingKind = "int"
 )
+
+// Setting is a value editable at runtime from the settings page. It's saved
+// in the config table, the default value is used until it's set.
+type Setting struct {
+	key          ConfigKey
+	label        string
+	description  string
+	kind         SettingKind
+	defaultValue string
+	rules        []v.Rule
+}
+
+func (s Setting) Key() ConfigKey      { return s.key }
+func (s Setting) Label() string       { return s.label }
+func (s Setting) Description() string { return s.description }
+func (s Setting) Kind() SettingKind   { return s.kind }
+func (s Setting) Default() string     { return s.defaultValue }
+
+// Validate checks a new value of the setting.
+func (s Setting) Validate(value string) error {
+	return v.Validate(value, s.rules...)
+}
+
+var (
+	CollectionInterval = &Setting{
+		key:          "sysstats.collection-interval",
+		label:        "Collection interval",
+		description:  "Period between two collections of the memory, disks and CPU stats.",
+		kind:         DurationSetting,
+		defaultValue: "5s",
+		rules:        []v.Rule{v.Required, v.By(durationBetween(time.Second, time.Hour))},
+	}
+	CGroupsInterval = &Setting{
+		key:          "sysstats.cgroups-interval",
+		label:        "CGroups collection interval",
+		description:  "Period between two collections of the slices, services and scopes accounting.",
+		kind:         DurationSetting,
+		defaultValue: "5s",
+		rules:        []v.Rule{v.Required, v.By(durationBetween(time.Second, time.Hour))},
+	}
+	StatsRetention = &Setting{
+		key:          "sysstats.retention",
+		label:        "Stats retention",
+		description:  "Duration the stats are kept, 0s keeps them forever.",
+		kind:         DurationSetting,
+		defaultValue: "0s",
+		rules:        []v.Rule{v.Required, v.By(durationBetween(0, 10*365*24*time.Hour))},
+	}
+	SessionLifetime = &Setting{
+		key:          "websessions.lifetime",
+		label:        "Session lifetime",
+		description:  "Duration a web session stays valid after the login.",
+		kind:         DurationSetting,
+		defaultValue: "8760h",
+		rules:        []v.Rule{v.Required, v.By(durationBetween(5*time.Minute, 10*365*24*time.Hour))},
+	}
+	HostnameOverride = &Setting{
+		key:          "sysinfos.hostname",
+		label:        "Hostname override",
+		description:  "Hostname displayed instead of the /etc/hostname one. Leave empty to use it.",
+		kind:         StringSetting,
+		defaultValue: "",
+		rules:        []v.Rule{v.Length(0, 253), is.DNSName},
+	}
+	BackupInterval = &Setting{
+		key:          "backups.interval",
+		label:        "Backup interval",
+		description:  "Period between two automatic database backups, 0s disables them.",
+		kind:         DurationSetting,
+		defaultValue: "24h",
+		rules:        []v.Rule{v.Required, v.By(durationBetween(0, 30*24*time.Hour))},
+	}
+	BackupKeep = &Setting{
+		key:          "backups.keep",
+		label:        "Backups kept",
+		description:  "Number of automatic backups kept, the oldest ones are deleted.",
+		kind:         IntSetting,
+		defaultValue: "7",
+		rules:        []v.Rule{v.Required, v.By(intBetween(1, 1000))},
+	}
+)
+
+// Settings are all the runtime settings, in the settings page order.
+var Settings = []*Setting{
+	CollectionInterval,
+	CGroupsInterval,
+	StatsRetention,
+	SessionLifetime,
+	HostnameOverride,
+	BackupInterval,
+	BackupKeep,
+}
+
+// SettingValue is a setting with its current value.
+type SettingValue struct {
+	setting *Setting
+	value   string
+	isSet   bool
+}
+
+func (s SettingValue) Setting() *Setting { return s.setting }
+func (s SettingValue) Value() string     { return s.value }
+
+// IsDefault returns true if the setting has never been set, or has been
+// reset.
+func (s SettingValue) IsDefault() bool { return !s.isSet }
+
+func durationBetween(minDuration, maxDuration time.Duration) v.RuleFunc {
+	return func(value any) error {
+		d, err := time.ParseDuration(value.(string))
+		if err != nil {
+			return errors.New("must be a duration like 30s, 5m or 12h")
+		}
+
+		if d < minDuration || d > maxDuration {
+			return fmt.Errorf("must be between %s and %s", formatDuration(minDuration), formatDuration(maxDuration))
+		}
+
+		return nil
+	}
+}
+
+func intBetween(minValue, maxValue int) v.RuleFunc {
+	return func(value any) error {
+		n, err := strconv.Atoi(value.(string))
+		if err != nil {
+			return errors.New("must be a number")
+		}
+
+		if n < minValue || n > maxValue {
+			return fmt.Errorf("must be between %d and %d", minValue, maxValue)
+		}
+
+		return nil
+	}
+}
+
+// formatDuration formats a duration without the zero units: "1h" instead of
+// "1h0m0s".
+func formatDuration(d time.Duration) string {
+	res := d.String()
+
+	if strings.HasSuffix(res, "m0s") {
+		res = strings.TrimSuffix(res, "0s")
+	}
+
+	if strings.HasSuffix(res, "h0m") {
+		res = strings.TrimSuffix(res, "0m")
+	}
+
+	return res
+}
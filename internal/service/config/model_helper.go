@@ -0,0 +1,15 @@
+package config
+
+import "testing"
+
+// NewFakeSettingValue returns the given setting set with the value, or with
+// its default value if the value is empty.
+func NewFakeSettingValue(t testing.TB, setting *Setting, value string) SettingValue {
+	t.Helper()
+
+	if value == "" {
+		return SettingValue{setting: setting, value: setting.defaultValue, isSet: false}
+	}
+
+	return SettingValue{setting: setting, value: value, isSet: true}
+}
@@ -2,22 +2,35 @@ package config
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
 	"github.com/Peltoche/zapette/internal/tools/uuid"
 )
 
+var ErrUnknownSetting = errors.New("unknown setting")
+
 type storage interface {
 	Save(ctx context.Context, key ConfigKey, value string) error
 	Get(ctx context.Context, key ConfigKey) (string, error)
+	Delete(ctx context.Context, key ConfigKey) error
 }
 
 type service struct {
 	storage storage
 	uuid    uuid.Service
+
+	watcherLock sync.Mutex
+	watchers    []chan struct{}
 }
 
 func newService(storage storage, tools tools.Tools) *service {
@@ -53,3 +66,238 @@ func (s *service) GetSysstatInputNamespace(ctx context.Context) (*uuid.UUID, err
 
 	return &res, nil
 }
+
+// SetMasterKey saves the sealed master key. The salt is given when the key is
+// sealed with a passphrase, it's saved with the key so a rotation is done
+// with a single write.
+func (s *service) SetMasterKey(ctx context.Context, key *secret.SealedKey, salt []byte) error {
+	value := key.Base64()
+	if salt != nil {
+		value += masterKeySaltSep + base64.RawStdEncoding.EncodeToString(salt)
+	}
+
+	err := s.storage.Save(ctx, masterKey, value)
+	if err != nil {
+		return fmt.Errorf("failed to Save: %w", err)
+	}
+
+	return nil
+}
+
+// GetMasterKey returns the sealed master key and its salt, nil if the key is
+// sealed with the keyfile.
+func (s *service) GetMasterKey(ctx context.Context) (*secret.SealedKey, []byte, error) {
+	value, err := s.storage.Get(ctx, masterKey)
+	if errors.Is(err, errNotfound) {
+		return nil, nil, errs.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to Get: %w", err)
+	}
+
+	keyStr, saltStr, hasSalt := strings.Cut(value, masterKeySaltSep)
+
+	res, err := secret.SealedKeyFromBase64(keyStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid key format: %w", err)
+	}
+
+	var salt []byte
+	if hasSalt {
+		salt, err = base64.RawStdEncoding.Strict().DecodeString(saltStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid salt format: %w", err)
+		}
+	}
+
+	return res, salt, nil
+}
+
+func (s *service) SetAnomalyAlerts(ctx context.Context, enabled bool) error {
+	err := s.storage.Save(ctx, anomalyAlerts, strconv.FormatBool(enabled))
+	if err != nil {
+		return fmt.Errorf("failed to Save: %w", err)
+	}
+
+	return nil
+}
+
+// GetAnomalyAlerts returns false if the value have never been set.
+func (s *service) GetAnomalyAlerts(ctx context.Context) (bool, error) {
+	raw, err := s.storage.Get(ctx, anomalyAlerts)
+	if errors.Is(err, errNotfound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to Get: %w", err)
+	}
+
+	res, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid bool format: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetSettings returns all the runtime settings with their current value.
+func (s *service) GetSettings(ctx context.Context) ([]SettingValue, error) {
+	res := make([]SettingValue, len(Settings))
+
+	for i, setting := range Settings {
+		value, isSet, err := s.getSetting(ctx, setting)
+		if err != nil {
+			return nil, errs.Internal(err)
+		}
+
+		res[i] = SettingValue{setting: setting, value: value, isSet: isSet}
+	}
+
+	return res, nil
+}
+
+// GetDuration returns the value of a DurationSetting.
+func (s *service) GetDuration(ctx context.Context, setting *Setting) (time.Duration, error) {
+	value, _, err := s.getSetting(ctx, setting)
+	if err != nil {
+		return 0, errs.Internal(err)
+	}
+
+	res, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errs.Internal(fmt.Errorf("invalid duration format for %q: %w", setting.key, err))
+	}
+
+	return res, nil
+}
+
+// GetString returns the value of a StringSetting.
+func (s *service) GetString(ctx context.Context, setting *Setting) (string, error) {
+	value, _, err := s.getSetting(ctx, setting)
+	if err != nil {
+		return "", errs.Internal(err)
+	}
+
+	return value, nil
+}
+
+// GetInt returns the value of an IntSetting.
+func (s *service) GetInt(ctx context.Context, setting *Setting) (int, error) {
+	value, _, err := s.getSetting(ctx, setting)
+	if err != nil {
+		return 0, errs.Internal(err)
+	}
+
+	res, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errs.Internal(fmt.Errorf("invalid int format for %q: %w", setting.key, err))
+	}
+
+	return res, nil
+}
+
+func (s *service) UpdateSetting(ctx context.Context, key ConfigKey, value string) error {
+	setting, err := getSettingByKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = setting.Validate(value)
+	if err != nil {
+		return errs.Validation(fmt.Errorf("%s: %w", setting.label, err))
+	}
+
+	err = s.storage.Save(ctx, key, value)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Save: %w", err))
+	}
+
+	return nil
+}
+
+// ResetSetting sets back the default value.
+func (s *service) ResetSetting(ctx context.Context, key ConfigKey) error {
+	_, err := getSettingByKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Delete(ctx, key)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Delete: %w", err))
+	}
+
+	return nil
+}
+
+func (s *service) getSetting(ctx context.Context, setting *Setting) (string, bool, error) {
+	res, err := s.storage.Get(ctx, setting.key)
+	if errors.Is(err, errNotfound) {
+		return setting.defaultValue, false, nil
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("failed to Get %q: %w", setting.key, err)
+	}
+
+	return res, true, nil
+}
+
+func getSettingByKey(key ConfigKey) (*Setting, error) {
+	idx := slices.IndexFunc(Settings, func(s *Setting) bool { return s.key == key })
+	if idx < 0 {
+		return nil, errs.NotFound(fmt.Errorf("%w: %q", ErrUnknownSetting, key))
+	}
+
+	return Settings[idx], nil
+}
+
+func (s *service) SQLHookName() string {
+	return "config-svc"
+}
+
+// RunSQLHook notifies the watchers of any change in the config table.
+func (s *service) RunSQLHook(ctx context.Context, table string) error {
+	if table != tableName {
+		return nil
+	}
+
+	s.watcherLock.Lock()
+	defer s.watcherLock.Unlock()
+
+	// Skip the event if one is already waiting, the watchers read the
+	// settings they need anyway.
+	for _, watcher := range s.watchers {
+		select {
+		case watcher <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Watch returns a channel notified after each settings change. It's closed
+// with the context.
+func (s *service) Watch(ctx context.Context) chan struct{} {
+	c := make(chan struct{}, 1)
+
+	go func() {
+		<-ctx.Done()
+
+		s.watcherLock.Lock()
+		defer s.watcherLock.Unlock()
+		s.watchers = slices.DeleteFunc(s.watchers, func(n chan struct{}) bool {
+			return n == c
+		})
+		close(c)
+	}()
+
+	s.watcherLock.Lock()
+	defer s.watcherLock.Unlock()
+	s.watchers = append(s.watchers, c)
+
+	return c
+}
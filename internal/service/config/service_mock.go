@@ -7,6 +7,10 @@ import (
 
 	secret "github.com/Peltoche/zapette/internal/tools/secret"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/Peltoche/zapette/internal/tools/uuid"
 )
 
 // MockService is an autogenerated mock type for the Service type
@@ -14,8 +18,92 @@ type MockService struct {
 	mock.Mock
 }
 
+// GetAnomalyAlerts provides a mock function with given fields: ctx
+func (_m *MockService) GetAnomalyAlerts(ctx context.Context) (bool, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAnomalyAlerts")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (bool, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDuration provides a mock function with given fields: ctx, setting
+func (_m *MockService) GetDuration(ctx context.Context, setting *Setting) (time.Duration, error) {
+	ret := _m.Called(ctx, setting)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDuration")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *Setting) (time.Duration, error)); ok {
+		return rf(ctx, setting)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *Setting) time.Duration); ok {
+		r0 = rf(ctx, setting)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *Setting) error); ok {
+		r1 = rf(ctx, setting)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInt provides a mock function with given fields: ctx, setting
+func (_m *MockService) GetInt(ctx context.Context, setting *Setting) (int, error) {
+	ret := _m.Called(ctx, setting)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInt")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *Setting) (int, error)); ok {
+		return rf(ctx, setting)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *Setting) int); ok {
+		r0 = rf(ctx, setting)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *Setting) error); ok {
+		r1 = rf(ctx, setting)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMasterKey provides a mock function with given fields: ctx
-func (_m *MockService) GetMasterKey(ctx context.Context) (*secret.SealedKey, error) {
+func (_m *MockService) GetMasterKey(ctx context.Context) (*secret.SealedKey, []byte, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
@@ -23,8 +111,9 @@ func (_m *MockService) GetMasterKey(ctx context.Context) (*secret.SealedKey, err
 	}
 
 	var r0 *secret.SealedKey
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*secret.SealedKey, error)); ok {
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*secret.SealedKey, []byte, error)); ok {
 		return rf(ctx)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context) *secret.SealedKey); ok {
@@ -35,6 +124,44 @@ func (_m *MockService) GetMasterKey(ctx context.Context) (*secret.SealedKey, err
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context) []byte); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSettings provides a mock function with given fields: ctx
+func (_m *MockService) GetSettings(ctx context.Context) ([]SettingValue, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSettings")
+	}
+
+	var r0 []SettingValue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]SettingValue, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []SettingValue); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]SettingValue)
+		}
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
 		r1 = rf(ctx)
 	} else {
@@ -44,16 +171,74 @@ func (_m *MockService) GetMasterKey(ctx context.Context) (*secret.SealedKey, err
 	return r0, r1
 }
 
-// SetMasterKey provides a mock function with given fields: ctx, key
-func (_m *MockService) SetMasterKey(ctx context.Context, key *secret.SealedKey) error {
+// GetString provides a mock function with given fields: ctx, setting
+func (_m *MockService) GetString(ctx context.Context, setting *Setting) (string, error) {
+	ret := _m.Called(ctx, setting)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetString")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *Setting) (string, error)); ok {
+		return rf(ctx, setting)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *Setting) string); ok {
+		r0 = rf(ctx, setting)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *Setting) error); ok {
+		r1 = rf(ctx, setting)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSysstatInputNamespace provides a mock function with given fields: ctx
+func (_m *MockService) GetSysstatInputNamespace(ctx context.Context) (*uuid.UUID, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSysstatInputNamespace")
+	}
+
+	var r0 *uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*uuid.UUID, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *uuid.UUID); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetSetting provides a mock function with given fields: ctx, key
+func (_m *MockService) ResetSetting(ctx context.Context, key ConfigKey) error {
 	ret := _m.Called(ctx, key)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SetMasterKey")
+		panic("no return value specified for ResetSetting")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *secret.SealedKey) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, ConfigKey) error); ok {
 		r0 = rf(ctx, key)
 	} else {
 		r0 = ret.Error(0)
@@ -62,6 +247,98 @@ func (_m *MockService) SetMasterKey(ctx context.Context, key *secret.SealedKey)
 	return r0
 }
 
+// SetAnomalyAlerts provides a mock function with given fields: ctx, enabled
+func (_m *MockService) SetAnomalyAlerts(ctx context.Context, enabled bool) error {
+	ret := _m.Called(ctx, enabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAnomalyAlerts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) error); ok {
+		r0 = rf(ctx, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetMasterKey provides a mock function with given fields: ctx, key, salt
+func (_m *MockService) SetMasterKey(ctx context.Context, key *secret.SealedKey, salt []byte) error {
+	ret := _m.Called(ctx, key, salt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMasterKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *secret.SealedKey, []byte) error); ok {
+		r0 = rf(ctx, key, salt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSysstatInputNamespace provides a mock function with given fields: ctx, id
+func (_m *MockService) SetSysstatInputNamespace(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSysstatInputNamespace")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSetting provides a mock function with given fields: ctx, key, value
+func (_m *MockService) UpdateSetting(ctx context.Context, key ConfigKey, value string) error {
+	ret := _m.Called(ctx, key, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSetting")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ConfigKey, string) error); ok {
+		r0 = rf(ctx, key, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Watch provides a mock function with given fields: ctx
+func (_m *MockService) Watch(ctx context.Context) chan struct{} {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 chan struct{}
+	if rf, ok := ret.Get(0).(func(context.Context) chan struct{}); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(chan struct{})
+		}
+	}
+
+	return r0
+}
+
 // NewMockService creates a new instance of MockService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockService(t interface {
@@ -3,8 +3,11 @@ package config
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
 	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +23,11 @@ func TestConfig(t *testing.T) {
 
 	someID := tools.UUID().New()
 
+	someKey, err := secret.NewKey()
+	require.NoError(t, err)
+	someSealedKey, err := secret.SealKey(someKey, someKey)
+	require.NoError(t, err)
+
 	t.Run("SetSysstatsInputNamespace success", func(t *testing.T) {
 		err := svc.SetSysstatInputNamespace(ctx, someID)
 		require.NoError(t, err)
@@ -31,4 +39,178 @@ func TestConfig(t *testing.T) {
 
 		assert.Equal(t, &someID, res)
 	})
+
+	t.Run("GetMasterKey not found", func(t *testing.T) {
+		res, salt, err := svc.GetMasterKey(ctx)
+		assert.Nil(t, res)
+		assert.Nil(t, salt)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("SetMasterKey success", func(t *testing.T) {
+		err := svc.SetMasterKey(ctx, someSealedKey, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetMasterKey success", func(t *testing.T) {
+		res, salt, err := svc.GetMasterKey(ctx)
+		require.NoError(t, err)
+
+		assert.True(t, someSealedKey.Equals(res))
+		assert.Nil(t, salt)
+	})
+
+	t.Run("SetMasterKey with a salt", func(t *testing.T) {
+		someSalt, err := secret.NewSalt()
+		require.NoError(t, err)
+
+		err = svc.SetMasterKey(ctx, someSealedKey, someSalt)
+		require.NoError(t, err)
+
+		res, salt, err := svc.GetMasterKey(ctx)
+		require.NoError(t, err)
+
+		assert.True(t, someSealedKey.Equals(res))
+		assert.Equal(t, someSalt, salt)
+	})
+
+	t.Run("GetAnomalyAlerts default value", func(t *testing.T) {
+		res, err := svc.GetAnomalyAlerts(ctx)
+		require.NoError(t, err)
+		assert.False(t, res)
+	})
+
+	t.Run("SetAnomalyAlerts success", func(t *testing.T) {
+		err := svc.SetAnomalyAlerts(ctx, true)
+		require.NoError(t, err)
+
+		res, err := svc.GetAnomalyAlerts(ctx)
+		require.NoError(t, err)
+		assert.True(t, res)
+	})
+}
+
+func TestSettings(t *testing.T) {
+	ctx := context.Background()
+
+	tools := tools.NewToolboxForTest(t)
+	db := sqlstorage.NewTestStorage(t)
+	store := newSqlStorage(db)
+	svc := newService(store, tools)
+
+	t.Run("GetSettings with the default values", func(t *testing.T) {
+		res, err := svc.GetSettings(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, res, len(Settings))
+		for i, setting := range res {
+			assert.Equal(t, Settings[i], setting.Setting())
+			assert.Equal(t, Settings[i].Default(), setting.Value())
+			assert.True(t, setting.IsDefault())
+		}
+	})
+
+	t.Run("GetDuration with the default value", func(t *testing.T) {
+		res, err := svc.GetDuration(ctx, CollectionInterval)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, res)
+	})
+
+	t.Run("UpdateSetting success", func(t *testing.T) {
+		err := svc.UpdateSetting(ctx, CollectionInterval.Key(), "1m")
+		require.NoError(t, err)
+
+		res, err := svc.GetDuration(ctx, CollectionInterval)
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, res)
+
+		settings, err := svc.GetSettings(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "1m", settings[0].Value())
+		assert.False(t, settings[0].IsDefault())
+	})
+
+	t.Run("UpdateSetting with an out of range duration", func(t *testing.T) {
+		err := svc.UpdateSetting(ctx, CollectionInterval.Key(), "2h")
+		require.ErrorIs(t, err, errs.ErrValidation)
+		require.ErrorContains(t, err, "Collection interval: must be between 1s and 1h")
+	})
+
+	t.Run("UpdateSetting with an invalid duration", func(t *testing.T) {
+		err := svc.UpdateSetting(ctx, SessionLifetime.Key(), "foo")
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("UpdateSetting with an invalid hostname", func(t *testing.T) {
+		err := svc.UpdateSetting(ctx, HostnameOverride.Key(), "not a hostname")
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("UpdateSetting with an unknown key", func(t *testing.T) {
+		err := svc.UpdateSetting(ctx, "unknown", "foo")
+		require.ErrorIs(t, err, errs.ErrNotFound)
+		require.ErrorIs(t, err, ErrUnknownSetting)
+	})
+
+	t.Run("GetString success", func(t *testing.T) {
+		err := svc.UpdateSetting(ctx, HostnameOverride.Key(), "example.com")
+		require.NoError(t, err)
+
+		res, err := svc.GetString(ctx, HostnameOverride)
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", res)
+	})
+
+	t.Run("GetInt success", func(t *testing.T) {
+		res, err := svc.GetInt(ctx, BackupKeep)
+		require.NoError(t, err)
+		assert.Equal(t, 7, res)
+
+		err = svc.UpdateSetting(ctx, BackupKeep.Key(), "3")
+		require.NoError(t, err)
+
+		res, err = svc.GetInt(ctx, BackupKeep)
+		require.NoError(t, err)
+		assert.Equal(t, 3, res)
+	})
+
+	t.Run("UpdateSetting with an invalid number", func(t *testing.T) {
+		err := svc.UpdateSetting(ctx, BackupKeep.Key(), "0")
+		require.ErrorIs(t, err, errs.ErrValidation)
+		require.ErrorContains(t, err, "Backups kept: must be between 1 and 1000")
+
+		err = svc.UpdateSetting(ctx, BackupKeep.Key(), "foo")
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("ResetSetting success", func(t *testing.T) {
+		err := svc.ResetSetting(ctx, CollectionInterval.Key())
+		require.NoError(t, err)
+
+		res, err := svc.GetDuration(ctx, CollectionInterval)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, res)
+	})
+
+	t.Run("ResetSetting with an unknown key", func(t *testing.T) {
+		err := svc.ResetSetting(ctx, "unknown")
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("Watch is notified of the changes", func(t *testing.T) {
+		watchCtx, cancel := context.WithCancel(ctx)
+		c := svc.Watch(watchCtx)
+
+		err := svc.RunSQLHook(ctx, "users")
+		require.NoError(t, err)
+		assert.Empty(t, c)
+
+		err = svc.RunSQLHook(ctx, tableName)
+		require.NoError(t, err)
+		assert.Len(t, c, 1)
+
+		cancel()
+		for range c {
+		}
+	})
 }
@@ -13,6 +13,24 @@ type mockStorage struct {
 	mock.Mock
 }
 
+// Delete provides a mock function with given fields: ctx, key
+func (_m *mockStorage) Delete(ctx context.Context, key ConfigKey) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ConfigKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, key
 func (_m *mockStorage) Get(ctx context.Context, key ConfigKey) (string, error) {
 	ret := _m.Called(ctx, key)
@@ -26,7 +26,7 @@ func (s *sqlStorage) Save(ctx context.Context, key ConfigKey, value string) erro
 		Insert(tableName).
 		Columns("key", "value").
 		Values(key, value).
-		Suffix("ON CONFLICT DO UPDATE SET value = ?", value).
+		Suffix("ON CONFLICT(key) DO UPDATE SET value = excluded.value").
 		RunWith(s.db).
 		ExecContext(ctx)
 	if err != nil {
@@ -55,3 +55,16 @@ func (s *sqlStorage) Get(ctx context.Context, key ConfigKey) (string, error) {
 
 	return res, nil
 }
+
+func (s *sqlStorage) Delete(ctx context.Context, key ConfigKey) error {
+	_, err := sq.
+		Delete(tableName).
+		Where(sq.Eq{"key": key}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
@@ -25,4 +25,13 @@ func TestSQLStorage(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "some-content", res)
 	})
+
+	t.Run("Delete success", func(t *testing.T) {
+		err := store.Delete(ctx, sysstatsInputNamespace)
+		require.NoError(t, err)
+
+		res, err := store.Get(ctx, sysstatsInputNamespace)
+		require.ErrorIs(t, err, errNotfound)
+		assert.Empty(t, res)
+	})
 }
@@ -0,0 +1,399 @@
+package containers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+)
+
+// maxLineSize is the maximum size of a log line. The longer lines are
+// truncated.
+const maxLineSize = 1024 * 1024
+
+// apiEngine talks to the Docker compatible API exposed by Docker and Podman
+// over a unix socket. The versionless paths are used so any API version
+// works.
+type apiEngine struct {
+	client *http.Client
+}
+
+// defaultSockets returns the sockets to try, in order: $DOCKER_HOST, Docker,
+// then the rootless and the rootful Podman.
+func defaultSockets() []string {
+	res := []string{}
+
+	if host, ok := strings.CutPrefix(os.Getenv("DOCKER_HOST"), "unix://"); ok {
+		res = append(res, host)
+	}
+
+	res = append(res, "/var/run/docker.sock")
+
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		res = append(res, path.Join(runtimeDir, "podman", "podman.sock"))
+	}
+
+	return append(res, "/run/podman/podman.sock")
+}
+
+func newAPIEngine(sockets []string) *apiEngine {
+	dialer := net.Dialer{Timeout: 2 * time.Second}
+
+	return &apiEngine{
+		client: &http.Client{
+			Transport: &http.Transport{
+				// The first socket accepting the connection is used.
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					for _, socket := range sockets {
+						conn, err := dialer.DialContext(ctx, "unix", socket)
+						if err == nil {
+							return conn, nil
+						}
+					}
+
+					return nil, ErrUnavailable
+				},
+			},
+		},
+	}
+}
+
+type apiContainer struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	RestartCount int    `json:"RestartCount"`
+	Config       struct {
+		Image string `json:"Image"`
+		Tty   bool   `json:"Tty"`
+	} `json:"Config"`
+	State struct {
+		Status    string    `json:"Status"`
+		StartedAt time.Time `json:"StartedAt"`
+	} `json:"State"`
+}
+
+func (e *apiEngine) ListContainers(ctx context.Context) ([]Container, error) {
+	var list []struct {
+		ID     string `json:"Id"`
+		Status string `json:"Status"`
+	}
+
+	err := e.getJSON(ctx, "/containers/json?all=true", &list)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Container, 0, len(list))
+	for _, item := range list {
+		container, err := e.GetContainer(ctx, item.ID)
+		if errors.Is(err, errNotFound) {
+			// Removed in the meantime.
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		container.status = item.Status
+		res = append(res, *container)
+	}
+
+	return res, nil
+}
+
+func (e *apiEngine) GetContainer(ctx context.Context, id string) (*Container, error) {
+	var raw apiContainer
+
+	err := e.getJSON(ctx, "/containers/"+url.PathEscape(id)+"/json", &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Container{
+		id:           raw.ID,
+		name:         strings.TrimPrefix(raw.Name, "/"),
+		image:        raw.Config.Image,
+		state:        raw.State.Status,
+		restartCount: raw.RestartCount,
+		tty:          raw.Config.Tty,
+	}
+
+	// The containers never started have the "0001-01-01T00:00:00Z" date.
+	if raw.State.StartedAt.Year() > 1 {
+		res.startedAt = raw.State.StartedAt
+	}
+
+	return &res, nil
+}
+
+type apiCPUStats struct {
+	CPUUsage struct {
+		TotalUsage  uint64   `json:"total_usage"`
+		PercpuUsage []uint64 `json:"percpu_usage"`
+	} `json:"cpu_usage"`
+	SystemUsage uint64 `json:"system_cpu_usage"`
+	OnlineCPUs  uint64 `json:"online_cpus"`
+}
+
+func (e *apiEngine) GetUsage(ctx context.Context, id string) (*Usage, error) {
+	var raw struct {
+		CPUStats    apiCPUStats `json:"cpu_stats"`
+		PreCPUStats apiCPUStats `json:"precpu_stats"`
+		MemoryStats struct {
+			Stats map[string]uint64 `json:"stats"`
+			Usage uint64            `json:"usage"`
+			Limit uint64            `json:"limit"`
+		} `json:"memory_stats"`
+	}
+
+	err := e.getJSON(ctx, "/containers/"+url.PathEscape(id)+"/stats?stream=false", &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same computation as the docker CLI: the page cache is excluded. It's
+	// "inactive_file" with cgroup v2 and "total_inactive_file" with v1.
+	memory := raw.MemoryStats.Usage
+	cache := raw.MemoryStats.Stats["inactive_file"]
+	if v1Cache, ok := raw.MemoryStats.Stats["total_inactive_file"]; ok {
+		cache = v1Cache
+	}
+	if cache < memory {
+		memory -= cache
+	}
+
+	res := Usage{
+		memory:      datasize.ByteSize(memory),
+		memoryLimit: datasize.ByteSize(raw.MemoryStats.Limit),
+	}
+
+	cpus := raw.CPUStats.OnlineCPUs
+	if cpus == 0 {
+		cpus = uint64(len(raw.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	cpuDelta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+	if raw.PreCPUStats.SystemUsage > 0 && systemDelta > 0 && cpuDelta >= 0 {
+		res.cpuPercent = ptr.To(cpuDelta / systemDelta * float64(cpus) * 100)
+	}
+
+	return &res, nil
+}
+
+func (e *apiEngine) RunAction(ctx context.Context, id string, action Action) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://engine/containers/"+url.PathEscape(id)+"/"+string(action), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	res, err := e.do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	return nil
+}
+
+func (e *apiEngine) Logs(ctx context.Context, container *Container, tail int, follow bool) (<-chan LogLine, error) {
+	query := url.Values{
+		"stdout":     {"true"},
+		"stderr":     {"true"},
+		"timestamps": {"true"},
+		"tail":       {strconv.Itoa(tail)},
+		"follow":     {strconv.FormatBool(follow)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://engine/containers/"+url.PathEscape(container.id)+"/logs?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	res, err := e.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	lineCh := make(chan LogLine)
+	go func() {
+		defer close(lineCh)
+		defer res.Body.Close()
+
+		send := func(line LogLine) bool {
+			select {
+			case lineCh <- line:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Without a TTY the stdout and stderr streams are multiplexed.
+		if container.tty {
+			readRawLogs(res.Body, send)
+		} else {
+			readMultiplexedLogs(res.Body, send)
+		}
+	}()
+
+	return lineCh, nil
+}
+
+func (e *apiEngine) getJSON(ctx context.Context, path string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://engine"+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	res, err := e.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	err = json.NewDecoder(res.Body).Decode(dst)
+	if err != nil {
+		return fmt.Errorf("failed to decode the response: %w", err)
+	}
+
+	return nil
+}
+
+// do runs the request and converts the error responses. The 304 returned
+// when starting a started container or stopping a stopped one is a success.
+func (e *apiEngine) do(req *http.Request) (*http.Response, error) {
+	res, err := e.client.Do(req)
+	if errors.Is(err, ErrUnavailable) {
+		return nil, ErrUnavailable
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", req.URL.Path, err)
+	}
+
+	if res.StatusCode < 400 {
+		return res, nil
+	}
+	defer res.Body.Close()
+
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(io.LimitReader(res.Body, 64*1024)).Decode(&apiErr)
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", errNotFound, apiErr.Message)
+	}
+
+	return nil, fmt.Errorf("%s %s: %d: %s", req.Method, req.URL.Path, res.StatusCode, apiErr.Message)
+}
+
+// readRawLogs reads the logs of a container with a tty. The lines longer
+// than maxLineSize are truncated, the remaining bytes are dropped.
+func readRawLogs(r io.Reader, send func(LogLine) bool) {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	line := []byte{}
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(line) < maxLineSize {
+			line = append(line, chunk[:min(len(chunk), maxLineSize-len(line))]...)
+		}
+
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case err != nil:
+			if len(line) > 0 {
+				send(parseLogLine("stdout", string(line)))
+			}
+			return
+		}
+
+		if !send(parseLogLine("stdout", strings.TrimRight(string(line), "\r\n"))) {
+			return
+		}
+
+		line = line[:0]
+	}
+}
+
+// readMultiplexedLogs decodes the frames: a header with the stream (1 for
+// stdout, 2 for stderr) and the payload size, then the payload. A line can be
+// split across several frames. Like with readRawLogs, the lines are
+// truncated to maxLineSize.
+func readMultiplexedLogs(r io.Reader, send func(LogLine) bool) {
+	header := make([]byte, 8)
+	pending := map[string]*bytes.Buffer{"stdout": {}, "stderr": {}}
+
+	for {
+		_, err := io.ReadFull(r, header)
+		if err != nil {
+			break
+		}
+
+		stream := "stdout"
+		if header[0] == 2 {
+			stream = "stderr"
+		}
+
+		buf := pending[stream]
+		_, err = io.CopyN(buf, r, int64(binary.BigEndian.Uint32(header[4:])))
+		if err != nil {
+			break
+		}
+
+		for {
+			line, err := buf.ReadString('\n')
+			if err != nil {
+				// Incomplete line, wait for the next frame.
+				buf.Reset()
+				buf.WriteString(line[:min(len(line), maxLineSize)])
+				break
+			}
+
+			line = strings.TrimSuffix(line, "\n")
+			if !send(parseLogLine(stream, line[:min(len(line), maxLineSize)])) {
+				return
+			}
+		}
+	}
+
+	for _, stream := range []string{"stdout", "stderr"} {
+		if pending[stream].Len() > 0 {
+			send(parseLogLine(stream, pending[stream].String()))
+		}
+	}
+}
+
+// parseLogLine splits the timestamp added with "timestamps=true".
+func parseLogLine(stream, line string) LogLine {
+	line = strings.TrimSuffix(line, "\r")
+
+	rawTime, message, ok := strings.Cut(line, " ")
+	if ok {
+		at, err := time.Parse(time.RFC3339Nano, rawTime)
+		if err == nil {
+			return LogLine{at: at, stream: stream, message: message}
+		}
+	}
+
+	return LogLine{stream: stream, message: line}
+}
@@ -0,0 +1,221 @@
+package containers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeEngineServer serves the handler on a unix socket, the same way
+// Docker and Podman do.
+func newFakeEngineServer(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	socket := path.Join(t.TempDir(), "docker.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return socket
+}
+
+func writeFrame(w http.ResponseWriter, stream byte, payload string) {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	w.Write(header)
+	w.Write([]byte(payload))
+}
+
+func TestAPIEngine(t *testing.T) {
+	ctx := context.Background()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("all"))
+		fmt.Fprint(w, `[
+			{"Id": "aaa111", "Names": ["/web"], "Status": "Up 2 hours"},
+			{"Id": "bbb222", "Names": ["/db"], "Status": "Exited (0) 3 days ago"},
+			{"Id": "removed", "Names": ["/tmp"], "Status": "Removal In Progress"}
+		]`)
+	})
+	mux.HandleFunc("GET /containers/aaa111/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"Id": "aaa111", "Name": "/web", "RestartCount": 3,
+			"Config": {"Image": "nginx:1.27", "Tty": false},
+			"State": {"Status": "running", "StartedAt": "2024-03-10T10:00:00.123Z"}
+		}`)
+	})
+	mux.HandleFunc("GET /containers/bbb222/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"Id": "bbb222", "Name": "/db", "RestartCount": 0,
+			"Config": {"Image": "postgres:16", "Tty": true},
+			"State": {"Status": "created", "StartedAt": "0001-01-01T00:00:00Z"}
+		}`)
+	})
+	mux.HandleFunc("GET /containers/{id}/json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "No such container"}`)
+	})
+	mux.HandleFunc("GET /containers/aaa111/stats", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "false", r.URL.Query().Get("stream"))
+		fmt.Fprint(w, `{
+			"cpu_stats": {"cpu_usage": {"total_usage": 3000}, "system_cpu_usage": 20000, "online_cpus": 4},
+			"precpu_stats": {"cpu_usage": {"total_usage": 1000}, "system_cpu_usage": 10000, "online_cpus": 4},
+			"memory_stats": {"usage": 3000, "limit": 10000, "stats": {"inactive_file": 1000}}
+		}`)
+	})
+	mux.HandleFunc("POST /containers/aaa111/restart", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /containers/aaa111/start", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+	mux.HandleFunc("POST /containers/aaa111/stop", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"message": "cannot stop container"}`)
+	})
+	mux.HandleFunc("GET /containers/aaa111/logs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10", r.URL.Query().Get("tail"))
+		assert.Equal(t, "true", r.URL.Query().Get("timestamps"))
+		writeFrame(w, 1, "2024-03-10T10:00:00Z started\n2024-03-10T10:00:01Z listen")
+		writeFrame(w, 1, "ing on :80\n")
+		writeFrame(w, 2, "2024-03-10T10:00:02Z warning: no config\n")
+	})
+	mux.HandleFunc("GET /containers/bbb222/logs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "2024-03-10T10:00:00Z tty line\r\n")
+	})
+
+	engine := newAPIEngine([]string{"/does/not/exist.sock", newFakeEngineServer(t, mux)})
+
+	t.Run("ListContainers success", func(t *testing.T) {
+		res, err := engine.ListContainers(ctx)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		assert.Equal(t, Container{
+			id:           "aaa111",
+			name:         "web",
+			image:        "nginx:1.27",
+			state:        "running",
+			status:       "Up 2 hours",
+			startedAt:    time.Date(2024, time.March, 10, 10, 0, 0, 123000000, time.UTC),
+			restartCount: 3,
+		}, res[0])
+
+		assert.Equal(t, "db", res[1].Name())
+		assert.True(t, res[1].StartedAt().IsZero())
+		assert.True(t, res[1].tty)
+	})
+
+	t.Run("GetContainer not found", func(t *testing.T) {
+		res, err := engine.GetContainer(ctx, "unknown")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("GetUsage success", func(t *testing.T) {
+		res, err := engine.GetUsage(ctx, "aaa111")
+		require.NoError(t, err)
+		require.NotNil(t, res.CPUPercent())
+		assert.InDelta(t, 80.0, *res.CPUPercent(), 0.001)
+		assert.Equal(t, datasize.ByteSize(2000), res.Memory())
+		assert.Equal(t, datasize.ByteSize(10000), res.MemoryLimit())
+	})
+
+	t.Run("RunAction success", func(t *testing.T) {
+		require.NoError(t, engine.RunAction(ctx, "aaa111", RestartAction))
+		require.NoError(t, engine.RunAction(ctx, "aaa111", StartAction))
+	})
+
+	t.Run("RunAction with an error", func(t *testing.T) {
+		err := engine.RunAction(ctx, "aaa111", StopAction)
+		require.ErrorContains(t, err, "cannot stop container")
+	})
+
+	t.Run("Logs multiplexed", func(t *testing.T) {
+		lineCh, err := engine.Logs(ctx, &Container{id: "aaa111"}, 10, false)
+		require.NoError(t, err)
+
+		res := []LogLine{}
+		for line := range lineCh {
+			res = append(res, line)
+		}
+
+		assert.Equal(t, []LogLine{
+			{at: time.Date(2024, time.March, 10, 10, 0, 0, 0, time.UTC), stream: "stdout", message: "started"},
+			{at: time.Date(2024, time.March, 10, 10, 0, 1, 0, time.UTC), stream: "stdout", message: "listening on :80"},
+			{at: time.Date(2024, time.March, 10, 10, 0, 2, 0, time.UTC), stream: "stderr", message: "warning: no config"},
+		}, res)
+	})
+
+	t.Run("Logs with a tty", func(t *testing.T) {
+		lineCh, err := engine.Logs(ctx, &Container{id: "bbb222", tty: true}, 10, false)
+		require.NoError(t, err)
+
+		line := <-lineCh
+		assert.Equal(t, "tty line", line.Message())
+		_, ok := <-lineCh
+		assert.False(t, ok)
+	})
+
+	t.Run("readRawLogs with a long line", func(t *testing.T) {
+		input := strings.Repeat("a", maxLineSize+10) + "\nnext line\n"
+
+		res := []LogLine{}
+		readRawLogs(strings.NewReader(input), func(line LogLine) bool {
+			res = append(res, line)
+			return true
+		})
+
+		require.Len(t, res, 2)
+		assert.Len(t, res[0].Message(), maxLineSize)
+		assert.Equal(t, "next line", res[1].Message())
+	})
+
+	t.Run("readMultiplexedLogs with a long line", func(t *testing.T) {
+		input := &bytes.Buffer{}
+		for _, payload := range []string{strings.Repeat("a", maxLineSize), strings.Repeat("a", 10) + "\nnext line\n"} {
+			header := make([]byte, 8)
+			header[0] = 1
+			binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+			input.Write(header)
+			input.WriteString(payload)
+		}
+
+		res := []LogLine{}
+		readMultiplexedLogs(input, func(line LogLine) bool {
+			res = append(res, line)
+			return true
+		})
+
+		require.Len(t, res, 2)
+		assert.Len(t, res[0].Message(), maxLineSize)
+		assert.Equal(t, "next line", res[1].Message())
+	})
+
+	t.Run("Without any socket", func(t *testing.T) {
+		engine := newAPIEngine([]string{"/does/not/exist.sock"})
+
+		res, err := engine.ListContainers(ctx)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrUnavailable)
+	})
+}
@@ -0,0 +1,23 @@
+package containers
+
+import (
+	"context"
+)
+
+type Service interface {
+	// GetAll returns all the containers, stopped ones included, sorted by
+	// name. The running ones have their usage.
+	GetAll(ctx context.Context) ([]Container, error)
+	GetByID(ctx context.Context, id string) (*Container, error)
+	// Control starts, stops or restarts a container.
+	Control(ctx context.Context, cmd *ControlCmd) error
+	// GetLogs returns the last lines written by the container.
+	GetLogs(ctx context.Context, container *Container, tail int) ([]LogLine, error)
+	// TailLogs streams the new lines written by the container until the
+	// context is canceled.
+	TailLogs(ctx context.Context, container *Container) (<-chan LogLine, error)
+}
+
+func Init() Service {
+	return newService(newAPIEngine(defaultSockets()))
+}
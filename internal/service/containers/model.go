@@ -0,0 +1,120 @@
+package containers
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	v "github.com/go-ozzo/ozzo-validation"
+)
+
+type Action string
+
+const (
+	StartAction   Action = "start"
+	StopAction    Action = "stop"
+	RestartAction Action = "restart"
+)
+
+var AllActions = []Action{StartAction, StopAction, RestartAction}
+
+// containerID matches the container ids and names accepted by the API.
+var containerID = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Container is the state of a Docker or Podman container.
+type Container struct {
+	startedAt    time.Time
+	usage        *Usage
+	id           string
+	name         string
+	image        string
+	state        string
+	status       string
+	restartCount int
+	tty          bool
+}
+
+func (c Container) ID() string { return c.id }
+
+// ShortID returns the 12 first characters of the id, as displayed by the
+// docker CLI.
+func (c Container) ShortID() string {
+	if len(c.id) < 12 {
+		return c.id
+	}
+
+	return c.id[:12]
+}
+
+func (c Container) Name() string  { return c.name }
+func (c Container) Image() string { return c.image }
+
+// State is "created", "running", "paused", "restarting", "removing",
+// "exited" or "dead".
+func (c Container) State() string { return c.state }
+
+// Status is the human readable status, for example "Up 2 hours".
+func (c Container) Status() string { return c.status }
+
+// StartedAt returns zero if the container has never been started.
+func (c Container) StartedAt() time.Time { return c.startedAt }
+func (c Container) RestartCount() int    { return c.restartCount }
+
+// Usage returns the resources consumed by the container. It's nil if the
+// container isn't running.
+func (c Container) Usage() *Usage { return c.usage }
+
+func (c Container) IsRunning() bool { return c.state == "running" }
+
+// Uptime returns the time since the container start. It's zero if the
+// container isn't running.
+func (c Container) Uptime(now time.Time) time.Duration {
+	if !c.IsRunning() || c.startedAt.IsZero() {
+		return 0
+	}
+
+	return now.Sub(c.startedAt).Truncate(time.Second)
+}
+
+// Usage is the resources consumption of a running container.
+type Usage struct {
+	// cpuPercent is nil if the engine didn't return two samples to compare.
+	cpuPercent  *float64
+	memory      datasize.ByteSize
+	memoryLimit datasize.ByteSize
+}
+
+// CPUPercent is relative to a single CPU: 200% is two CPUs fully used.
+func (u Usage) CPUPercent() *float64 { return u.cpuPercent }
+
+// Memory is the memory used, the page cache excluded.
+func (u Usage) Memory() datasize.ByteSize { return u.memory }
+
+// MemoryLimit is the limit of the container, or the host memory if there
+// isn't any.
+func (u Usage) MemoryLimit() datasize.ByteSize { return u.memoryLimit }
+
+// LogLine is a line written by a container on stdout or stderr.
+type LogLine struct {
+	at      time.Time
+	stream  string
+	message string
+}
+
+func (l LogLine) At() time.Time { return l.at }
+
+// Stream is "stdout" or "stderr".
+func (l LogLine) Stream() string  { return l.stream }
+func (l LogLine) Message() string { return l.message }
+
+type ControlCmd struct {
+	ID     string
+	Action Action
+}
+
+func (t ControlCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.ID, v.Required, v.Match(containerID)),
+		v.Field(&t.Action, v.Required, v.In(StartAction, StopAction, RestartAction)),
+	)
+}
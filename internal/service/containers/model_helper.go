@@ -0,0 +1,71 @@
+package containers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type FakeContainerBuilder struct {
+	t         testing.TB
+	container *Container
+}
+
+func NewFakeContainer(t testing.TB) *FakeContainerBuilder {
+	t.Helper()
+
+	startedAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+
+	return &FakeContainerBuilder{
+		t: t,
+		container: &Container{
+			id:           strings.ReplaceAll(gofakeit.UUID()+gofakeit.UUID(), "-", ""),
+			name:         strings.ToLower(gofakeit.Username()),
+			image:        strings.ToLower(gofakeit.AppName()) + ":latest",
+			state:        "running",
+			status:       "Up 2 hours",
+			startedAt:    startedAt.UTC().Truncate(time.Second),
+			restartCount: gofakeit.Number(0, 5),
+		},
+	}
+}
+
+func (f *FakeContainerBuilder) WithName(name string) *FakeContainerBuilder {
+	f.container.name = name
+
+	return f
+}
+
+func (f *FakeContainerBuilder) WithState(state string) *FakeContainerBuilder {
+	f.container.state = state
+
+	return f
+}
+
+func (f *FakeContainerBuilder) WithUsage(cpuPercent float64, memory, limit datasize.ByteSize) *FakeContainerBuilder {
+	f.container.usage = NewFakeUsage(cpuPercent, memory, limit)
+
+	return f
+}
+
+func (f *FakeContainerBuilder) Build() *Container {
+	return f.container
+}
+
+// NewFakeUsage returns a Usage, used with the engine mocks.
+func NewFakeUsage(cpuPercent float64, memory, limit datasize.ByteSize) *Usage {
+	return &Usage{
+		cpuPercent:  ptr.To(cpuPercent),
+		memory:      memory,
+		memoryLimit: limit,
+	}
+}
+
+// NewFakeLogLine returns a LogLine written on stdout.
+func NewFakeLogLine(at time.Time, message string) LogLine {
+	return LogLine{at: at, stream: "stdout", message: message}
+}
@@ -0,0 +1,139 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+)
+
+// MaxLogLines is the maximum number of log lines returned by GetLogs.
+const MaxLogLines = 1000
+
+var (
+	// ErrUnavailable is returned when no Docker or Podman socket can be
+	// reached.
+	ErrUnavailable = errors.New("no container engine available")
+
+	errInvalidID = errors.New("invalid container id")
+	errNotFound  = errors.New("not found")
+)
+
+// engine is a small abstraction over the Docker compatible API.
+type engine interface {
+	ListContainers(ctx context.Context) ([]Container, error)
+	GetContainer(ctx context.Context, id string) (*Container, error)
+	// GetUsage returns the resources consumed by a running container.
+	GetUsage(ctx context.Context, id string) (*Usage, error)
+	RunAction(ctx context.Context, id string, action Action) error
+	// Logs returns the last tail lines of the container. With follow, the
+	// new lines are streamed until the context is canceled. The channel is
+	// closed when the streaming stops.
+	Logs(ctx context.Context, container *Container, tail int, follow bool) (<-chan LogLine, error)
+}
+
+type service struct {
+	engine engine
+}
+
+func newService(engine engine) *service {
+	return &service{engine: engine}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]Container, error) {
+	res, err := s.engine.ListContainers(ctx)
+	if err != nil {
+		return nil, s.wrapErr(fmt.Errorf("failed to ListContainers: %w", err))
+	}
+
+	// Each stats call waits for a second sample to compute the CPU usage,
+	// they are made concurrently.
+	wg := sync.WaitGroup{}
+	for i := range res {
+		if !res[i].IsRunning() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(container *Container) {
+			defer wg.Done()
+
+			// A container stopped in the meantime has no usage.
+			container.usage, _ = s.engine.GetUsage(ctx, container.id)
+		}(&res[i])
+	}
+	wg.Wait()
+
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+
+	return res, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id string) (*Container, error) {
+	if !containerID.MatchString(id) {
+		return nil, errs.Validation(errInvalidID)
+	}
+
+	res, err := s.engine.GetContainer(ctx, id)
+	if err != nil {
+		return nil, s.wrapErr(fmt.Errorf("failed to GetContainer: %w", err))
+	}
+
+	if res.IsRunning() {
+		res.usage, _ = s.engine.GetUsage(ctx, res.id)
+	}
+
+	return res, nil
+}
+
+func (s *service) Control(ctx context.Context, cmd *ControlCmd) error {
+	err := cmd.Validate()
+	if err != nil {
+		return errs.Validation(err)
+	}
+
+	err = s.engine.RunAction(ctx, cmd.ID, cmd.Action)
+	if err != nil {
+		return s.wrapErr(fmt.Errorf("failed to %s %q: %w", cmd.Action, cmd.ID, err))
+	}
+
+	return nil
+}
+
+func (s *service) GetLogs(ctx context.Context, container *Container, tail int) ([]LogLine, error) {
+	if tail <= 0 || tail > MaxLogLines {
+		tail = MaxLogLines
+	}
+
+	lineCh, err := s.engine.Logs(ctx, container, tail, false)
+	if err != nil {
+		return nil, s.wrapErr(fmt.Errorf("failed to get the logs: %w", err))
+	}
+
+	res := []LogLine{}
+	for line := range lineCh {
+		res = append(res, line)
+	}
+
+	return res, nil
+}
+
+func (s *service) TailLogs(ctx context.Context, container *Container) (<-chan LogLine, error) {
+	res, err := s.engine.Logs(ctx, container, 0, true)
+	if err != nil {
+		return nil, s.wrapErr(fmt.Errorf("failed to follow the logs: %w", err))
+	}
+
+	return res, nil
+}
+
+func (s *service) wrapErr(err error) error {
+	if errors.Is(err, errNotFound) {
+		return errs.NotFound(err)
+	}
+
+	return errs.Internal(err)
+}
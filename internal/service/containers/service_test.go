@@ -0,0 +1,136 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainersService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetAll success", func(t *testing.T) {
+		t.Parallel()
+		engineMock := newMockEngine(t)
+		svc := newService(engineMock)
+
+		// Data
+		web := NewFakeContainer(t).WithName("web").Build()
+		db := NewFakeContainer(t).WithName("db").WithState("exited").Build()
+		usage := NewFakeUsage(12.5, 200*datasize.MB, 2*datasize.GB)
+
+		// Mocks
+		engineMock.On("ListContainers", mock.Anything).Return([]Container{*web, *db}, nil).Once()
+		engineMock.On("GetUsage", mock.Anything, web.ID()).Return(usage, nil).Once()
+
+		// Run
+		res, err := svc.GetAll(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Equal(t, "db", res[0].Name())
+		assert.Nil(t, res[0].Usage())
+		assert.Equal(t, "web", res[1].Name())
+		assert.Equal(t, usage, res[1].Usage())
+	})
+
+	t.Run("GetAll with the engine unavailable", func(t *testing.T) {
+		t.Parallel()
+		engineMock := newMockEngine(t)
+		svc := newService(engineMock)
+
+		engineMock.On("ListContainers", mock.Anything).Return(nil, ErrUnavailable).Once()
+
+		res, err := svc.GetAll(ctx)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrUnavailable)
+		require.ErrorIs(t, err, errs.ErrInternal)
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+		t.Parallel()
+		engineMock := newMockEngine(t)
+		svc := newService(engineMock)
+
+		engineMock.On("GetContainer", mock.Anything, "unknown").Return(nil, errNotFound).Once()
+
+		res, err := svc.GetByID(ctx, "unknown")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("GetByID with an invalid id", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockEngine(t))
+
+		res, err := svc.GetByID(ctx, "../foo")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Control success", func(t *testing.T) {
+		t.Parallel()
+		engineMock := newMockEngine(t)
+		svc := newService(engineMock)
+
+		engineMock.On("RunAction", mock.Anything, "web", RestartAction).Return(nil).Once()
+
+		err := svc.Control(ctx, &ControlCmd{ID: "web", Action: RestartAction})
+		require.NoError(t, err)
+	})
+
+	t.Run("Control with an invalid action", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockEngine(t))
+
+		err := svc.Control(ctx, &ControlCmd{ID: "web", Action: Action("kill")})
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Control with an engine error", func(t *testing.T) {
+		t.Parallel()
+		engineMock := newMockEngine(t)
+		svc := newService(engineMock)
+
+		engineMock.On("RunAction", mock.Anything, "web", StopAction).Return(fmt.Errorf("some-error")).Once()
+
+		err := svc.Control(ctx, &ControlCmd{ID: "web", Action: StopAction})
+		require.ErrorIs(t, err, errs.ErrInternal)
+		require.ErrorContains(t, err, "some-error")
+	})
+
+	t.Run("GetLogs success", func(t *testing.T) {
+		t.Parallel()
+		engineMock := newMockEngine(t)
+		svc := newService(engineMock)
+
+		// Data
+		container := NewFakeContainer(t).Build()
+		now := time.Now()
+		lines := []LogLine{NewFakeLogLine(now, "line 1"), NewFakeLogLine(now, "line 2")}
+
+		lineCh := make(chan LogLine, len(lines))
+		for _, line := range lines {
+			lineCh <- line
+		}
+		close(lineCh)
+
+		// Mocks
+		engineMock.On("Logs", mock.Anything, container, MaxLogLines, false).Return((<-chan LogLine)(lineCh), nil).Once()
+
+		// Run
+		res, err := svc.GetLogs(ctx, container, MaxLogLines+1)
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, lines, res)
+	})
+}
@@ -0,0 +1,35 @@
+package dashboards
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+type Service interface {
+	Create(ctx context.Context, cmd *CreateCmd) (*Dashboard, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Dashboard, error)
+	// GetByShareToken returns the dashboard shared with the given token.
+	GetByShareToken(ctx context.Context, token secret.Text) (*Dashboard, error)
+	GetAllForUser(ctx context.Context, user *users.User) ([]Dashboard, error)
+	Delete(ctx context.Context, dashboard *Dashboard) error
+	AddPanel(ctx context.Context, cmd *AddPanelCmd) (*Dashboard, error)
+	MovePanel(ctx context.Context, cmd *MovePanelCmd) (*Dashboard, error)
+	DeletePanel(ctx context.Context, dashboard *Dashboard, panelID uuid.UUID) (*Dashboard, error)
+	Share(ctx context.Context, dashboard *Dashboard) (*Dashboard, error)
+	Unshare(ctx context.Context, dashboard *Dashboard) (*Dashboard, error)
+	GetAllSeries(ctx context.Context) ([]Series, error)
+	GetPanelData(ctx context.Context, panel *Panel) (*PanelData, error)
+}
+
+func Init(db *sql.DB, sysstats sysstats.Service, hosts hosts.Service, tools tools.Tools) Service {
+	storage := newSqlStorage(db)
+
+	return newService(storage, sysstats, hosts, tools)
+}
@@ -0,0 +1,205 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	v "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+const (
+	MaxPanels = 30
+
+	// MaxPoints is the max number of points displayed by a line panel. The
+	// stats are downsampled to fit.
+	MaxPoints = 120
+)
+
+var (
+	ErrTooManyPanels = errors.New("too many panels")
+	ErrUnknownSeries = errors.New("unknown series")
+	ErrUnknownPanel  = errors.New("unknown panel")
+)
+
+type PanelKind string
+
+const (
+	LinePanel  PanelKind = "line"
+	GaugePanel PanelKind = "gauge"
+	StatPanel  PanelKind = "stat"
+	TablePanel PanelKind = "table"
+)
+
+var PanelKinds = []PanelKind{LinePanel, GaugePanel, StatPanel, TablePanel}
+
+// Spans are the time ranges a panel can display.
+var Spans = []time.Duration{
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+// Widths are the widths a panel can have, in columns of a 12 columns grid.
+var Widths = []int{3, 4, 6, 12}
+
+// Dashboard is a list of panels composed by a user. It can be shared
+// read-only with anyone having its share link, only the hash of the share
+// token is saved.
+type Dashboard struct {
+	createdAt      time.Time
+	shareToken     *secret.Text
+	shareTokenHash *string
+	id             uuid.UUID
+	name           string
+	ownerID        uuid.UUID
+	panels         []Panel
+}
+
+func (d Dashboard) ID() uuid.UUID        { return d.id }
+func (d Dashboard) Name() string         { return d.name }
+func (d Dashboard) OwnerID() uuid.UUID   { return d.ownerID }
+func (d Dashboard) Panels() []Panel      { return d.panels }
+func (d Dashboard) CreatedAt() time.Time { return d.createdAt }
+
+// ShareToken is only set on the dashboards returned by Share and
+// GetByShareToken, it can't be read again.
+func (d Dashboard) ShareToken() *secret.Text { return d.shareToken }
+func (d Dashboard) IsShared() bool           { return d.shareTokenHash != nil }
+
+func (d Dashboard) IsOwnedBy(user *users.User) bool {
+	return user != nil && d.ownerID == user.ID()
+}
+
+// Panel returns the panel with the given id or nil.
+func (d Dashboard) Panel(id uuid.UUID) *Panel {
+	for i := range d.panels {
+		if d.panels[i].id == id {
+			return &d.panels[i]
+		}
+	}
+
+	return nil
+}
+
+// Panel displays a series over a time span.
+type Panel struct {
+	id     uuid.UUID
+	title  string
+	kind   PanelKind
+	series string
+	span   time.Duration
+	width  int
+}
+
+func (p Panel) ID() uuid.UUID       { return p.id }
+func (p Panel) Title() string       { return p.title }
+func (p Panel) Kind() PanelKind     { return p.kind }
+func (p Panel) Series() string      { return p.series }
+func (p Panel) Span() time.Duration { return p.span }
+func (p Panel) Width() int          { return p.width }
+
+type panelJSON struct {
+	ID     uuid.UUID `json:"id"`
+	Title  string    `json:"title"`
+	Kind   PanelKind `json:"kind"`
+	Series string    `json:"series"`
+	// Span is in seconds.
+	Span  int64 `json:"span"`
+	Width int   `json:"width"`
+}
+
+func (p *Panel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(panelJSON{
+		ID:     p.id,
+		Title:  p.title,
+		Kind:   p.kind,
+		Series: p.series,
+		Span:   int64(p.span.Seconds()),
+		Width:  p.width,
+	})
+}
+
+func (p *Panel) UnmarshalJSON(b []byte) error {
+	var res panelJSON
+
+	err := json.Unmarshal(b, &res)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal the panel: %w", err)
+	}
+
+	p.id = res.ID
+	p.title = res.Title
+	p.kind = res.Kind
+	p.series = res.Series
+	p.span = time.Duration(res.Span) * time.Second
+	p.width = res.Width
+
+	return nil
+}
+
+type CreateCmd struct {
+	Owner *users.User
+	Name  string
+}
+
+func (t CreateCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Owner, v.Required),
+		v.Field(&t.Name, v.Required, v.Length(1, 50)),
+	)
+}
+
+type AddPanelCmd struct {
+	Dashboard *Dashboard
+	Title     string
+	Kind      PanelKind
+	// Series is the key of the displayed series.
+	Series string
+	Span   time.Duration
+	Width  int
+}
+
+func (t AddPanelCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Dashboard, v.Required),
+		v.Field(&t.Title, v.Length(0, 50)),
+		v.Field(&t.Kind, v.Required, v.In(toAny(PanelKinds)...)),
+		v.Field(&t.Series, v.Required),
+		v.Field(&t.Span, v.Required, v.In(toAny(Spans)...)),
+		v.Field(&t.Width, v.Required, v.In(toAny(Widths)...)),
+	)
+}
+
+type MovePanelCmd struct {
+	Dashboard *Dashboard
+	PanelID   uuid.UUID
+	// Offset is -1 to move the panel before the previous one and 1 to move
+	// it after the next one.
+	Offset int
+}
+
+func (t MovePanelCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Dashboard, v.Required),
+		v.Field(&t.PanelID, v.Required, is.UUIDv4),
+		v.Field(&t.Offset, v.Required, v.In(-1, 1)),
+	)
+}
+
+func toAny[T any](values []T) []any {
+	res := make([]any, len(values))
+	for i, value := range values {
+		res[i] = value
+	}
+
+	return res
+}
@@ -0,0 +1,112 @@
+package dashboards
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+type FakeDashboardBuilder struct {
+	t         testing.TB
+	dashboard *Dashboard
+}
+
+func NewFakeDashboard(t testing.TB) *FakeDashboardBuilder {
+	t.Helper()
+
+	uuidProvider := uuid.NewProvider()
+	createdAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+
+	return &FakeDashboardBuilder{
+		t: t,
+		dashboard: &Dashboard{
+			id:             uuidProvider.New(),
+			name:           gofakeit.AppName(),
+			ownerID:        uuidProvider.New(),
+			shareToken:     nil,
+			shareTokenHash: nil,
+			panels:         []Panel{},
+			createdAt:      createdAt.UTC(),
+		},
+	}
+}
+
+func (f *FakeDashboardBuilder) WithOwner(ownerID uuid.UUID) *FakeDashboardBuilder {
+	f.dashboard.ownerID = ownerID
+
+	return f
+}
+
+func (f *FakeDashboardBuilder) WithName(name string) *FakeDashboardBuilder {
+	f.dashboard.name = name
+
+	return f
+}
+
+func (f *FakeDashboardBuilder) WithShareToken(token secret.Text) *FakeDashboardBuilder {
+	tokenHash := token.Hash()
+	f.dashboard.shareToken = &token
+	f.dashboard.shareTokenHash = &tokenHash
+
+	return f
+}
+
+func (f *FakeDashboardBuilder) WithPanels(panels ...Panel) *FakeDashboardBuilder {
+	f.dashboard.panels = panels
+
+	return f
+}
+
+func (f *FakeDashboardBuilder) Build() *Dashboard {
+	return f.dashboard
+}
+
+func (f *FakeDashboardBuilder) BuildAndStore(ctx context.Context, db *sql.DB) *Dashboard {
+	f.t.Helper()
+
+	storage := newSqlStorage(db)
+
+	err := storage.Save(ctx, f.dashboard)
+	require.NoError(f.t, err)
+
+	return f.dashboard
+}
+
+// NewFakePanel returns a panel displaying the given series, used with
+// WithPanels.
+func NewFakePanel(kind PanelKind, series string) Panel {
+	return Panel{
+		id:     uuid.NewProvider().New(),
+		title:  gofakeit.AppName(),
+		kind:   kind,
+		series: series,
+		span:   time.Hour,
+		width:  6,
+	}
+}
+
+// NewFakePanelData returns the data of a panel, used by the templates.
+func NewFakePanelData(series Series, points []Point, latest, limit *float64) *PanelData {
+	return &PanelData{
+		series: series,
+		points: points,
+		latest: latest,
+		limit:  limit,
+	}
+}
+
+// NewFakeSeries returns a series, used by the templates.
+func NewFakeSeries(key, label, unit string) Series {
+	return Series{
+		key:         key,
+		sourceLabel: "This server",
+		label:       label,
+		unit:        unit,
+	}
+}
@@ -0,0 +1,260 @@
+package dashboards
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+)
+
+// LocalSource is the source of the series recorded by this server. The
+// other sources are the ids of the hosts.
+const LocalSource = "local"
+
+const diskMetricPrefix = "disk:"
+
+// Series is a metric recorded for a source. Its key has the form
+// "<source>/<metric>".
+type Series struct {
+	key         string
+	sourceLabel string
+	label       string
+	unit        string
+}
+
+func (s Series) Key() string { return s.key }
+
+// Label is the human readable name of the series.
+func (s Series) Label() string { return s.sourceLabel + " - " + s.label }
+func (s Series) Unit() string  { return s.unit }
+
+func newSeries(source, sourceLabel string, m *metric) Series {
+	return Series{
+		key:         source + "/" + m.name,
+		sourceLabel: sourceLabel,
+		label:       m.label,
+		unit:        m.unit,
+	}
+}
+
+// splitSeriesKey returns the source and the metric of a series key.
+func splitSeriesKey(key string) (string, string, bool) {
+	return strings.Cut(key, "/")
+}
+
+type metric struct {
+	name  string
+	label string
+	unit  string
+	// value returns the metric value and its upper bound if any. The
+	// previous stats are required for the rates and can be nil.
+	value func(prev, stats *sysstats.Stats) (*float64, *float64)
+}
+
+var staticMetrics = []metric{
+	{
+		name:  "memory.used",
+		label: "Memory used",
+		unit:  "GiB",
+		value: func(_, stats *sysstats.Stats) (*float64, *float64) {
+			return ptr.To(stats.Memory().UsedMemory().GBytes()), ptr.To(stats.Memory().TotalMemory().GBytes())
+		},
+	},
+	{
+		name:  "memory.bufcache",
+		label: "Cache + Buffer",
+		unit:  "GiB",
+		value: func(_, stats *sysstats.Stats) (*float64, *float64) {
+			return ptr.To(stats.Memory().BufCache().GBytes()), ptr.To(stats.Memory().TotalMemory().GBytes())
+		},
+	},
+	{
+		name:  "memory.swap",
+		label: "Swap used",
+		unit:  "GiB",
+		value: func(_, stats *sysstats.Stats) (*float64, *float64) {
+			return ptr.To(stats.Memory().UsedSwap().GBytes()), ptr.To(stats.Memory().TotalSwap().GBytes())
+		},
+	},
+	{
+		name:  "memory.percent",
+		label: "Memory used",
+		unit:  "%",
+		value: func(_, stats *sysstats.Stats) (*float64, *float64) {
+			return ptr.To(float64(stats.Memory().PercentageUsedMemory())), ptr.To(100.0)
+		},
+	},
+	{
+		name:  "cpu.percent",
+		label: "CPU",
+		unit:  "%",
+		value: func(prev, stats *sysstats.Stats) (*float64, *float64) {
+			if prev == nil {
+				return nil, nil
+			}
+
+			return stats.CPUPercent(prev), ptr.To(100.0)
+		},
+	},
+}
+
+func diskMetric(mountPoint string) *metric {
+	return &metric{
+		name:  diskMetricPrefix + mountPoint,
+		label: "Disk " + mountPoint,
+		unit:  "%",
+		value: func(_, stats *sysstats.Stats) (*float64, *float64) {
+			for _, disk := range stats.Disks() {
+				if disk.MountPoint() == mountPoint {
+					return ptr.To(float64(disk.PercentageUsed())), ptr.To(100.0)
+				}
+			}
+
+			return nil, nil
+		},
+	}
+}
+
+func findMetric(name string) (*metric, bool) {
+	mountPoint, isDisk := strings.CutPrefix(name, diskMetricPrefix)
+	if isDisk && mountPoint != "" {
+		return diskMetric(mountPoint), true
+	}
+
+	for i := range staticMetrics {
+		if staticMetrics[i].name == name {
+			return &staticMetrics[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// sourceSeries returns all the series of a source. The disks are the ones of
+// the latest stats, which can be nil.
+func sourceSeries(source, sourceLabel string, latest *sysstats.Stats) []Series {
+	res := []Series{}
+
+	for i := range staticMetrics {
+		res = append(res, newSeries(source, sourceLabel, &staticMetrics[i]))
+	}
+
+	if latest != nil {
+		for _, disk := range latest.Disks() {
+			res = append(res, newSeries(source, sourceLabel, diskMetric(disk.MountPoint())))
+		}
+	}
+
+	return res
+}
+
+// Point is a value of a series at a given time.
+type Point struct {
+	At    time.Time
+	Value float64
+}
+
+// PanelData are the values of a series over the span of a panel.
+type PanelData struct {
+	series Series
+	points []Point
+	latest *float64
+	limit  *float64
+}
+
+func (d PanelData) Series() Series  { return d.series }
+func (d PanelData) Points() []Point { return d.points }
+
+// Latest is the most recent value, nil if there is no value in the span.
+func (d PanelData) Latest() *float64 { return d.latest }
+
+// Limit is the upper bound of the latest value, nil if unbounded.
+func (d PanelData) Limit() *float64 { return d.limit }
+
+// Percent returns the latest value relative to its limit.
+func (d PanelData) Percent() *int {
+	if d.latest == nil || d.limit == nil || *d.limit == 0 {
+		return nil
+	}
+
+	return ptr.To(min(int(*d.latest / *d.limit * 100), 100))
+}
+
+func (d PanelData) Min() *float64 {
+	var res *float64
+	for _, point := range d.points {
+		if res == nil || point.Value < *res {
+			res = ptr.To(point.Value)
+		}
+	}
+
+	return res
+}
+
+func (d PanelData) Max() *float64 {
+	var res *float64
+	for _, point := range d.points {
+		if res == nil || point.Value > *res {
+			res = ptr.To(point.Value)
+		}
+	}
+
+	return res
+}
+
+func (d PanelData) Avg() *float64 {
+	if len(d.points) == 0 {
+		return nil
+	}
+
+	var sum float64
+	for _, point := range d.points {
+		sum += point.Value
+	}
+
+	return ptr.To(sum / float64(len(d.points)))
+}
+
+// newPanelData computes the values of the metric for the stats, sorted by
+// time, and downsamples them to MaxPoints. The last value of each bucket is
+// kept.
+func newPanelData(series Series, m *metric, stats []sysstats.Stats, start time.Time, span time.Duration) *PanelData {
+	res := PanelData{
+		series: series,
+		points: []Point{},
+		latest: nil,
+		limit:  nil,
+	}
+
+	bucketSpan := span / MaxPoints
+	lastBucket := time.Duration(-1)
+
+	var prev *sysstats.Stats
+	for i := range stats {
+		stat := &stats[i]
+		if stat.IsEmpty() {
+			continue
+		}
+
+		value, limit := m.value(prev, stat)
+		prev = stat
+
+		if value == nil {
+			continue
+		}
+
+		res.latest, res.limit = value, limit
+
+		bucket := stat.Time().Sub(start) / bucketSpan
+		if bucket == lastBucket {
+			res.points[len(res.points)-1] = Point{At: stat.Time(), Value: *value}
+			continue
+		}
+
+		lastBucket = bucket
+		res.points = append(res.points, Point{At: stat.Time(), Value: *value})
+	}
+
+	return &res
+}
@@ -0,0 +1,83 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanelData(t *testing.T) {
+	t.Run("newPanelData downsamples the points", func(t *testing.T) {
+		start := time.Now().Truncate(time.Second)
+		span := MaxPoints * time.Minute
+
+		m, ok := findMetric("cpu.percent")
+		require.True(t, ok)
+
+		stats := []sysstats.Stats{
+			*sysstats.NewFakeStats(t).WithTime(start.Add(10 * time.Second)).WithCPU(sysstats.NewFakeCPU(0, 0)).Build(),
+			// Same bucket, only the last one is kept.
+			*sysstats.NewFakeStats(t).WithTime(start.Add(20 * time.Second)).WithCPU(sysstats.NewFakeCPU(10, 100)).Build(),
+			*sysstats.NewFakeStats(t).WithTime(start.Add(30 * time.Second)).WithCPU(sysstats.NewFakeCPU(30, 200)).Build(),
+			*sysstats.NewFakeStats(t).WithTime(start.Add(90 * time.Second)).WithCPU(sysstats.NewFakeCPU(130, 300)).Build(),
+		}
+
+		res := newPanelData(Series{}, m, stats, start, span)
+
+		// The first stats have no previous stats for the CPU rate.
+		assert.Equal(t, []Point{
+			{At: start.Add(30 * time.Second), Value: 20},
+			{At: start.Add(90 * time.Second), Value: 100},
+		}, res.Points())
+		assert.Equal(t, 100.0, *res.Latest())
+		assert.Equal(t, 100, *res.Percent())
+		assert.Equal(t, 20.0, *res.Min())
+		assert.Equal(t, 100.0, *res.Max())
+		assert.Equal(t, 60.0, *res.Avg())
+	})
+
+	t.Run("without any stats", func(t *testing.T) {
+		m, ok := findMetric("memory.used")
+		require.True(t, ok)
+
+		res := newPanelData(Series{}, m, []sysstats.Stats{}, time.Now(), time.Hour)
+
+		assert.Empty(t, res.Points())
+		assert.Nil(t, res.Latest())
+		assert.Nil(t, res.Percent())
+		assert.Nil(t, res.Avg())
+	})
+
+	t.Run("Percent is bounded", func(t *testing.T) {
+		res := NewFakePanelData(Series{}, []Point{}, ptr.To(12.0), ptr.To(10.0))
+
+		assert.Equal(t, 100, *res.Percent())
+	})
+
+	t.Run("findMetric with a disk", func(t *testing.T) {
+		m, ok := findMetric("disk:/home")
+		require.True(t, ok)
+		assert.Equal(t, "Disk /home", m.label)
+
+		_, ok = findMetric("disk:")
+		assert.False(t, ok)
+	})
+}
+
+func TestPanelJSON(t *testing.T) {
+	panel := NewFakePanel(TablePanel, "local/memory.swap")
+
+	raw, err := json.Marshal(&panel)
+	require.NoError(t, err)
+
+	var res Panel
+	err = json.Unmarshal(raw, &res)
+	require.NoError(t, err)
+
+	assert.Equal(t, panel, res)
+}
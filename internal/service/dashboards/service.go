@@ -0,0 +1,299 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+// localSourceLabel is the label of the series recorded by this server.
+const localSourceLabel = "This server"
+
+type storage interface {
+	Save(ctx context.Context, dashboard *Dashboard) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Dashboard, error)
+	GetByShareTokenHash(ctx context.Context, tokenHash string) (*Dashboard, error)
+	GetAllByOwner(ctx context.Context, ownerID uuid.UUID) ([]Dashboard, error)
+	Patch(ctx context.Context, id uuid.UUID, fields map[string]any) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type service struct {
+	storage  storage
+	sysstats sysstats.Service
+	hosts    hosts.Service
+	clock    clock.Clock
+	uuid     uuid.Service
+}
+
+func newService(storage storage, sysstats sysstats.Service, hosts hosts.Service, tools tools.Tools) *service {
+	return &service{
+		storage:  storage,
+		sysstats: sysstats,
+		hosts:    hosts,
+		clock:    tools.Clock(),
+		uuid:     tools.UUID(),
+	}
+}
+
+func (s *service) Create(ctx context.Context, cmd *CreateCmd) (*Dashboard, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	dashboard := Dashboard{
+		id:             s.uuid.New(),
+		name:           cmd.Name,
+		ownerID:        cmd.Owner.ID(),
+		shareToken:     nil,
+		shareTokenHash: nil,
+		panels:         []Panel{},
+		createdAt:      s.clock.Now(),
+	}
+
+	err = s.storage.Save(ctx, &dashboard)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to save the dashboard: %w", err))
+	}
+
+	return &dashboard, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*Dashboard, error) {
+	res, err := s.storage.GetByID(ctx, id)
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) GetByShareToken(ctx context.Context, token secret.Text) (*Dashboard, error) {
+	res, err := s.storage.GetByShareTokenHash(ctx, token.Hash())
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	res.shareToken = &token
+
+	return res, nil
+}
+
+func (s *service) GetAllForUser(ctx context.Context, user *users.User) ([]Dashboard, error) {
+	res, err := s.storage.GetAllByOwner(ctx, user.ID())
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) Delete(ctx context.Context, dashboard *Dashboard) error {
+	err := s.storage.Delete(ctx, dashboard.id)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Delete: %w", err))
+	}
+
+	return nil
+}
+
+func (s *service) AddPanel(ctx context.Context, cmd *AddPanelCmd) (*Dashboard, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	_, metricName, ok := splitSeriesKey(cmd.Series)
+	if !ok {
+		return nil, errs.Validation(ErrUnknownSeries)
+	}
+
+	_, ok = findMetric(metricName)
+	if !ok {
+		return nil, errs.Validation(ErrUnknownSeries)
+	}
+
+	if len(cmd.Dashboard.panels) >= MaxPanels {
+		return nil, errs.Validation(ErrTooManyPanels)
+	}
+
+	panels := append(slices.Clone(cmd.Dashboard.panels), Panel{
+		id:     s.uuid.New(),
+		title:  cmd.Title,
+		kind:   cmd.Kind,
+		series: cmd.Series,
+		span:   cmd.Span,
+		width:  cmd.Width,
+	})
+
+	return s.savePanels(ctx, cmd.Dashboard, panels)
+}
+
+func (s *service) MovePanel(ctx context.Context, cmd *MovePanelCmd) (*Dashboard, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	idx := slices.IndexFunc(cmd.Dashboard.panels, func(p Panel) bool { return p.id == cmd.PanelID })
+	if idx < 0 {
+		return nil, errs.NotFound(ErrUnknownPanel)
+	}
+
+	target := idx + cmd.Offset
+	if target < 0 || target >= len(cmd.Dashboard.panels) {
+		// Already at the edge.
+		return cmd.Dashboard, nil
+	}
+
+	panels := slices.Clone(cmd.Dashboard.panels)
+	panels[idx], panels[target] = panels[target], panels[idx]
+
+	return s.savePanels(ctx, cmd.Dashboard, panels)
+}
+
+func (s *service) DeletePanel(ctx context.Context, dashboard *Dashboard, panelID uuid.UUID) (*Dashboard, error) {
+	panels := slices.DeleteFunc(slices.Clone(dashboard.panels), func(p Panel) bool { return p.id == panelID })
+	if len(panels) == len(dashboard.panels) {
+		return nil, errs.NotFound(ErrUnknownPanel)
+	}
+
+	return s.savePanels(ctx, dashboard, panels)
+}
+
+func (s *service) savePanels(ctx context.Context, dashboard *Dashboard, panels []Panel) (*Dashboard, error) {
+	rawPanels, err := marshalPanels(panels)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	err = s.storage.Patch(ctx, dashboard.id, map[string]any{"panels": rawPanels})
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to Patch: %w", err))
+	}
+
+	res := *dashboard
+	res.panels = panels
+
+	return &res, nil
+}
+
+// Share generates a new share token. The previous share link, if any,
+// stops working. The token is only returned by this call.
+func (s *service) Share(ctx context.Context, dashboard *Dashboard) (*Dashboard, error) {
+	token := secret.NewText(string(s.uuid.New()))
+	tokenHash := token.Hash()
+
+	err := s.storage.Patch(ctx, dashboard.id, map[string]any{"share_token_hash": tokenHash})
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to Patch: %w", err))
+	}
+
+	res := *dashboard
+	res.shareToken = &token
+	res.shareTokenHash = &tokenHash
+
+	return &res, nil
+}
+
+func (s *service) Unshare(ctx context.Context, dashboard *Dashboard) (*Dashboard, error) {
+	err := s.storage.Patch(ctx, dashboard.id, map[string]any{"share_token_hash": nil})
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to Patch: %w", err))
+	}
+
+	res := *dashboard
+	res.shareToken = nil
+	res.shareTokenHash = nil
+
+	return &res, nil
+}
+
+// GetAllSeries returns the series which can be displayed: the ones of this
+// server then the ones of each host.
+func (s *service) GetAllSeries(ctx context.Context) ([]Series, error) {
+	latest, err := s.sysstats.GetLatest(ctx)
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		return nil, errs.Internal(fmt.Errorf("failed to get the latest stats: %w", err))
+	}
+
+	res := sourceSeries(LocalSource, localSourceLabel, latest)
+
+	hostList, err := s.hosts.GetAll(ctx, nil)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to get the hosts: %w", err))
+	}
+
+	for _, host := range hostList {
+		latest, err := s.hosts.GetLatestStats(ctx, &host)
+		if err != nil && !errors.Is(err, errs.ErrNotFound) {
+			return nil, errs.Internal(fmt.Errorf("failed to get the latest stats of %q: %w", host.Name(), err))
+		}
+
+		res = append(res, sourceSeries(string(host.ID()), host.Name(), latest)...)
+	}
+
+	return res, nil
+}
+
+// GetPanelData returns the values of the panel series over its span.
+func (s *service) GetPanelData(ctx context.Context, panel *Panel) (*PanelData, error) {
+	source, metricName, _ := splitSeriesKey(panel.series)
+
+	m, ok := findMetric(metricName)
+	if !ok {
+		return nil, errs.NotFound(ErrUnknownSeries)
+	}
+
+	end := s.clock.Now()
+	start := end.Add(-panel.span)
+
+	var stats []sysstats.Stats
+	var sourceLabel string
+	var err error
+
+	switch source {
+	case LocalSource:
+		sourceLabel = localSourceLabel
+		stats, err = s.sysstats.GetRange(ctx, start, end)
+	default:
+		var host *hosts.Host
+
+		host, err = s.hosts.GetByID(ctx, uuid.UUID(source))
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil, errs.NotFound(ErrUnknownSeries)
+		}
+
+		if err != nil {
+			return nil, errs.Internal(fmt.Errorf("failed to get the host: %w", err))
+		}
+
+		sourceLabel = host.Name()
+		stats, err = s.hosts.GetStatsRange(ctx, host, start, end)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to get the stats: %w", err))
+	}
+
+	return newPanelData(newSeries(source, sourceLabel, m), m, stats, start, panel.span), nil
+}
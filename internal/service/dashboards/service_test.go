@@ -0,0 +1,404 @@
+package dashboards
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardsService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools)
+
+		// Data
+		user := users.NewFakeUser(t).Build()
+		now := time.Now()
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-dashboard-id")).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("Save", mock.Anything, mock.AnythingOfType("*dashboards.Dashboard")).Return(nil).Once()
+
+		// Run
+		res, err := svc.Create(ctx, &CreateCmd{
+			Owner: user,
+			Name:  "Wall TV",
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, uuid.UUID("some-dashboard-id"), res.ID())
+		assert.Equal(t, "Wall TV", res.Name())
+		assert.True(t, res.IsOwnedBy(user))
+		assert.False(t, res.IsShared())
+		assert.Empty(t, res.Panels())
+		assert.Equal(t, now, res.CreatedAt())
+	})
+
+	t.Run("Create with an empty name", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		res, err := svc.Create(ctx, &CreateCmd{
+			Owner: users.NewFakeUser(t).Build(),
+			Name:  "",
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		storageMock.On("GetByID", mock.Anything, uuid.UUID("some-id")).Return(nil, errNotFound).Once()
+
+		res, err := svc.GetByID(ctx, "some-id")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("GetByShareToken success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		dashboard := NewFakeDashboard(t).WithShareToken(secret.NewText("some-token")).Build()
+		stored := *dashboard
+		stored.shareToken = nil
+
+		storageMock.On("GetByShareTokenHash", mock.Anything, secret.NewText("some-token").Hash()).Return(&stored, nil).Once()
+
+		res, err := svc.GetByShareToken(ctx, secret.NewText("some-token"))
+		require.NoError(t, err)
+		assert.Equal(t, dashboard, res)
+	})
+
+	t.Run("GetAllForUser success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		user := users.NewFakeUser(t).Build()
+		dashboard := NewFakeDashboard(t).WithOwner(user.ID()).Build()
+
+		storageMock.On("GetAllByOwner", mock.Anything, user.ID()).Return([]Dashboard{*dashboard}, nil).Once()
+
+		res, err := svc.GetAllForUser(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, []Dashboard{*dashboard}, res)
+	})
+
+	t.Run("AddPanel success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools)
+
+		// Data
+		existing := NewFakePanel(StatPanel, "local/cpu.percent")
+		dashboard := NewFakeDashboard(t).WithPanels(existing).Build()
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-panel-id")).Once()
+		storageMock.On("Patch", mock.Anything, dashboard.ID(), mock.AnythingOfType("map[string]interface {}")).Return(nil).Once()
+
+		// Run
+		res, err := svc.AddPanel(ctx, &AddPanelCmd{
+			Dashboard: dashboard,
+			Title:     "Root disk",
+			Kind:      GaugePanel,
+			Series:    "local/disk:/",
+			Span:      time.Hour,
+			Width:     4,
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		require.Len(t, res.Panels(), 2)
+		assert.Equal(t, existing, res.Panels()[0])
+		assert.Equal(t, Panel{
+			id:     "some-panel-id",
+			title:  "Root disk",
+			kind:   GaugePanel,
+			series: "local/disk:/",
+			span:   time.Hour,
+			width:  4,
+		}, res.Panels()[1])
+
+		// The given dashboard is not modified.
+		assert.Len(t, dashboard.Panels(), 1)
+	})
+
+	t.Run("AddPanel with an unknown series", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		res, err := svc.AddPanel(ctx, &AddPanelCmd{
+			Dashboard: NewFakeDashboard(t).Build(),
+			Kind:      LinePanel,
+			Series:    "local/unknown",
+			Span:      time.Hour,
+			Width:     4,
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+		require.ErrorIs(t, err, ErrUnknownSeries)
+	})
+
+	t.Run("AddPanel with an invalid span", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		res, err := svc.AddPanel(ctx, &AddPanelCmd{
+			Dashboard: NewFakeDashboard(t).Build(),
+			Kind:      LinePanel,
+			Series:    "local/memory.used",
+			Span:      42 * time.Minute,
+			Width:     4,
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("AddPanel with too many panels", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		panels := make([]Panel, MaxPanels)
+		for i := range panels {
+			panels[i] = NewFakePanel(StatPanel, "local/cpu.percent")
+		}
+
+		res, err := svc.AddPanel(ctx, &AddPanelCmd{
+			Dashboard: NewFakeDashboard(t).WithPanels(panels...).Build(),
+			Kind:      LinePanel,
+			Series:    "local/memory.used",
+			Span:      time.Hour,
+			Width:     4,
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrTooManyPanels)
+	})
+
+	t.Run("MovePanel success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		// Data
+		panel1 := NewFakePanel(StatPanel, "local/cpu.percent")
+		panel2 := NewFakePanel(LinePanel, "local/memory.used")
+		dashboard := NewFakeDashboard(t).WithPanels(panel1, panel2).Build()
+
+		// Mocks
+		rawPanels, err := marshalPanels([]Panel{panel2, panel1})
+		require.NoError(t, err)
+		storageMock.On("Patch", mock.Anything, dashboard.ID(), map[string]any{"panels": rawPanels}).Return(nil).Once()
+
+		// Run
+		res, err := svc.MovePanel(ctx, &MovePanelCmd{
+			Dashboard: dashboard,
+			PanelID:   panel2.ID(),
+			Offset:    -1,
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, []Panel{panel2, panel1}, res.Panels())
+	})
+
+	t.Run("MovePanel already at the edge", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		panel := NewFakePanel(StatPanel, "local/cpu.percent")
+		dashboard := NewFakeDashboard(t).WithPanels(panel).Build()
+
+		res, err := svc.MovePanel(ctx, &MovePanelCmd{
+			Dashboard: dashboard,
+			PanelID:   panel.ID(),
+			Offset:    1,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, dashboard, res)
+	})
+
+	t.Run("DeletePanel success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		panel1 := NewFakePanel(StatPanel, "local/cpu.percent")
+		panel2 := NewFakePanel(LinePanel, "local/memory.used")
+		dashboard := NewFakeDashboard(t).WithPanels(panel1, panel2).Build()
+
+		storageMock.On("Patch", mock.Anything, dashboard.ID(), mock.AnythingOfType("map[string]interface {}")).Return(nil).Once()
+
+		res, err := svc.DeletePanel(ctx, dashboard, panel1.ID())
+		require.NoError(t, err)
+		assert.Equal(t, []Panel{panel2}, res.Panels())
+	})
+
+	t.Run("DeletePanel with an unknown panel", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		res, err := svc.DeletePanel(ctx, NewFakeDashboard(t).Build(), "some-id")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("Share success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools)
+
+		dashboard := NewFakeDashboard(t).Build()
+
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-token")).Once()
+		storageMock.On("Patch", mock.Anything, dashboard.ID(), map[string]any{"share_token_hash": secret.NewText("some-token").Hash()}).Return(nil).Once()
+
+		res, err := svc.Share(ctx, dashboard)
+		require.NoError(t, err)
+		assert.True(t, res.IsShared())
+		assert.Equal(t, "some-token", res.ShareToken().Raw())
+	})
+
+	t.Run("Unshare success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		dashboard := NewFakeDashboard(t).WithShareToken(secret.NewText("some-token")).Build()
+
+		storageMock.On("Patch", mock.Anything, dashboard.ID(), map[string]any{"share_token_hash": nil}).Return(nil).Once()
+
+		res, err := svc.Unshare(ctx, dashboard)
+		require.NoError(t, err)
+		assert.False(t, res.IsShared())
+	})
+
+	t.Run("Delete success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, sysstats.NewMockService(t), hosts.NewMockService(t), tools.NewMock(t))
+
+		dashboard := NewFakeDashboard(t).Build()
+
+		storageMock.On("Delete", mock.Anything, dashboard.ID()).Return(nil).Once()
+
+		err := svc.Delete(ctx, dashboard)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetAllSeries success", func(t *testing.T) {
+		t.Parallel()
+		sysstatsMock := sysstats.NewMockService(t)
+		hostsMock := hosts.NewMockService(t)
+		svc := newService(newMockStorage(t), sysstatsMock, hostsMock, tools.NewMock(t))
+
+		// Data
+		latest := sysstats.NewFakeStats(t).WithDisks(sysstats.NewFakeDisk("/home", datasize.GB, datasize.MB)).Build()
+		host := hosts.NewFakeHost(t).WithName("web-1").Build()
+		neverSeen := hosts.NewFakeHost(t).Build()
+
+		// Mocks
+		sysstatsMock.On("GetLatest", mock.Anything).Return(latest, nil).Once()
+		hostsMock.On("GetAll", mock.Anything, (*sqlstorage.PaginateCmd)(nil)).Return([]hosts.Host{*host, *neverSeen}, nil).Once()
+		hostsMock.On("GetLatestStats", mock.Anything, host).Return(sysstats.NewFakeStats(t).Build(), nil).Once()
+		hostsMock.On("GetLatestStats", mock.Anything, neverSeen).Return(nil, errs.NotFound(fmt.Errorf("not found"))).Once()
+
+		// Run
+		res, err := svc.GetAllSeries(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+
+		keys := []string{}
+		for _, series := range res {
+			keys = append(keys, series.Key())
+		}
+
+		assert.Contains(t, keys, "local/memory.used")
+		assert.Contains(t, keys, "local/disk:/home")
+		assert.Contains(t, keys, string(host.ID())+"/cpu.percent")
+		assert.Contains(t, keys, string(host.ID())+"/disk:/")
+		assert.Contains(t, keys, string(neverSeen.ID())+"/memory.percent")
+		assert.NotContains(t, keys, string(neverSeen.ID())+"/disk:/")
+		assert.Len(t, res, 3*len(staticMetrics)+2)
+	})
+
+	t.Run("GetPanelData for the local server", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		sysstatsMock := sysstats.NewMockService(t)
+		svc := newService(newMockStorage(t), sysstatsMock, hosts.NewMockService(t), tools)
+
+		// Data
+		now := time.Now().Truncate(time.Second)
+		panel := NewFakePanel(LinePanel, "local/memory.percent")
+		stats := []sysstats.Stats{
+			*sysstats.NewFakeStats(t).WithTime(now.Add(-time.Minute)).WithMemory(10*datasize.GB, 6*datasize.GB).Build(),
+			*sysstats.NewFakeStats(t).WithTime(now).WithMemory(10*datasize.GB, 5*datasize.GB).Build(),
+		}
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		sysstatsMock.On("GetRange", mock.Anything, now.Add(-time.Hour), now).Return(stats, nil).Once()
+
+		// Run
+		res, err := svc.GetPanelData(ctx, &panel)
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, "This server - Memory used", res.Series().Label())
+		assert.Equal(t, "%", res.Series().Unit())
+		assert.Equal(t, []Point{{At: now.Add(-time.Minute), Value: 40}, {At: now, Value: 50}}, res.Points())
+		assert.Equal(t, 50.0, *res.Latest())
+		assert.Equal(t, 50, *res.Percent())
+	})
+
+	t.Run("GetPanelData for a deleted host", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		hostsMock := hosts.NewMockService(t)
+		svc := newService(newMockStorage(t), sysstats.NewMockService(t), hostsMock, tools)
+
+		panel := NewFakePanel(LinePanel, "some-host-id/memory.percent")
+
+		tools.ClockMock.On("Now").Return(time.Now()).Once()
+		hostsMock.On("GetByID", mock.Anything, uuid.UUID("some-host-id")).Return(nil, errs.NotFound(fmt.Errorf("not found"))).Once()
+
+		res, err := svc.GetPanelData(ctx, &panel)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+		require.ErrorIs(t, err, ErrUnknownSeries)
+	})
+}
@@ -0,0 +1,182 @@
+package dashboards
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const tableName = "dashboards"
+
+var errNotFound = errors.New("not found")
+
+var allFields = []string{"id", "name", "owner_id", "share_token_hash", "panels", "created_at"}
+
+type sqlStorage struct {
+	db *sql.DB
+}
+
+func newSqlStorage(db *sql.DB) *sqlStorage {
+	return &sqlStorage{db}
+}
+
+func (s *sqlStorage) Save(ctx context.Context, d *Dashboard) error {
+	rawPanels, err := marshalPanels(d.panels)
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.
+		Insert(tableName).
+		Columns(allFields...).
+		Values(
+			d.id,
+			d.name,
+			d.ownerID,
+			d.shareTokenHash,
+			rawPanels,
+			ptr.To(sqlstorage.SQLTime(d.createdAt)),
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetByID(ctx context.Context, id uuid.UUID) (*Dashboard, error) {
+	return s.getByKeys(ctx, sq.Eq{"id": id})
+}
+
+func (s *sqlStorage) GetByShareTokenHash(ctx context.Context, tokenHash string) (*Dashboard, error) {
+	return s.getByKeys(ctx, sq.Eq{"share_token_hash": tokenHash})
+}
+
+func (s *sqlStorage) getByKeys(ctx context.Context, wheres ...any) (*Dashboard, error) {
+	query := sq.
+		Select(allFields...).
+		From(tableName)
+
+	for _, where := range wheres {
+		query = query.Where(where)
+	}
+
+	res, err := s.scan(query.RunWith(s.db).QueryRowContext(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetAllByOwner returns the dashboards of a user sorted by name.
+func (s *sqlStorage) GetAllByOwner(ctx context.Context, ownerID uuid.UUID) ([]Dashboard, error) {
+	rows, err := sq.
+		Select(allFields...).
+		From(tableName).
+		Where(sq.Eq{"owner_id": ownerID}).
+		OrderBy("name", "created_at").
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	res := []Dashboard{}
+
+	for rows.Next() {
+		dashboard, err := s.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		res = append(res, *dashboard)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) Patch(ctx context.Context, id uuid.UUID, fields map[string]any) error {
+	_, err := sq.Update(tableName).
+		SetMap(fields).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := sq.
+		Delete(tableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) scan(row sqlstorage.RowScanner) (*Dashboard, error) {
+	var res Dashboard
+	var sqlCreatedAt sqlstorage.SQLTime
+	var rawPanels string
+
+	err := row.Scan(
+		&res.id,
+		&res.name,
+		&res.ownerID,
+		&res.shareTokenHash,
+		&rawPanels,
+		&sqlCreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(rawPanels), &res.panels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid panels: %w", err)
+	}
+	res.createdAt = sqlCreatedAt.Time()
+
+	return &res, nil
+}
+
+// marshalPanels returns the panels as a JSON array, the format of the panels
+// column.
+func marshalPanels(panels []Panel) (string, error) {
+	if panels == nil {
+		panels = []Panel{}
+	}
+
+	raw, err := json.Marshal(panels)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the panels: %w", err)
+	}
+
+	return string(raw), nil
+}
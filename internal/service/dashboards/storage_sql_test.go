@@ -0,0 +1,85 @@
+package dashboards
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardSqlStorage(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqlstorage.NewTestStorage(t)
+	storage := newSqlStorage(db)
+
+	ownerID := uuid.UUID("1cbd9a25-5b8f-4b36-a1c2-3d8c4a3de0d6")
+	dashboard := NewFakeDashboard(t).
+		WithOwner(ownerID).
+		WithName("b-dashboard").
+		WithPanels(NewFakePanel(LinePanel, "local/memory.used"), NewFakePanel(GaugePanel, "local/disk:/")).
+		Build()
+	shared := NewFakeDashboard(t).
+		WithOwner(ownerID).
+		WithName("a-dashboard").
+		WithShareToken(secret.NewText("some-token")).
+		Build()
+	// Only the share token hash is saved.
+	storedShared := *shared
+	storedShared.shareToken = nil
+
+	t.Run("Save success", func(t *testing.T) {
+		err := storage.Save(ctx, dashboard)
+		require.NoError(t, err)
+
+		err = storage.Save(ctx, shared)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetByID success", func(t *testing.T) {
+		res, err := storage.GetByID(ctx, dashboard.ID())
+		require.NoError(t, err)
+		assert.Equal(t, dashboard, res)
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+		res, err := storage.GetByID(ctx, "some-invalid-id")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("GetByShareTokenHash success", func(t *testing.T) {
+		res, err := storage.GetByShareTokenHash(ctx, secret.NewText("some-token").Hash())
+		require.NoError(t, err)
+		assert.Equal(t, &storedShared, res)
+	})
+
+	t.Run("GetAllByOwner success", func(t *testing.T) {
+		res, err := storage.GetAllByOwner(ctx, ownerID)
+		require.NoError(t, err)
+		assert.Equal(t, []Dashboard{storedShared, *dashboard}, res)
+	})
+
+	t.Run("Patch success", func(t *testing.T) {
+		err := storage.Patch(ctx, dashboard.ID(), map[string]any{"panels": "[]", "share_token_hash": nil})
+		require.NoError(t, err)
+
+		res, err := storage.GetByID(ctx, dashboard.ID())
+		require.NoError(t, err)
+		assert.Empty(t, res.Panels())
+		assert.False(t, res.IsShared())
+	})
+
+	t.Run("Delete success", func(t *testing.T) {
+		err := storage.Delete(ctx, dashboard.ID())
+		require.NoError(t, err)
+
+		res, err := storage.GetByID(ctx, dashboard.ID())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+}
@@ -0,0 +1,31 @@
+package forecasts
+
+import (
+	"context"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+)
+
+// ForecastCron refreshes the forecasts and fires the related alerts.
+type ForecastCron struct {
+	service Service
+}
+
+func newForecastCron(service Service) *ForecastCron {
+	return &ForecastCron{service: service}
+}
+
+func (c *ForecastCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "forecasts",
+		Schedule: scheduler.MustParseCron("*/5 * * * *"),
+		Jitter:   30 * time.Second,
+		Timeout:  time.Minute,
+		Runner:   c,
+	}
+}
+
+func (c *ForecastCron) Run(ctx context.Context) error {
+	return c.service.refresh(ctx)
+}
@@ -0,0 +1,31 @@
+package forecasts
+
+import (
+	"context"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"go.uber.org/fx"
+)
+
+type Result struct {
+	fx.Out
+	Service Service
+	Cron    *ForecastCron
+}
+
+type Service interface {
+	// GetAll returns the latest forecasts for the memory and the disks.
+	GetAll(ctx context.Context) ([]Forecast, error)
+	refresh(ctx context.Context) error
+}
+
+func Init(sysstats sysstats.Service, alerts alerts.Service, tools tools.Tools) Result {
+	svc := newService(sysstats, alerts, tools)
+
+	return Result{
+		Service: svc,
+		Cron:    newForecastCron(svc),
+	}
+}
@@ -0,0 +1,94 @@
+package forecasts
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+)
+
+type Resource string
+
+const (
+	Memory Resource = "memory"
+	Disk   Resource = "disk"
+)
+
+// Forecast estimates when a resource will be exhausted based on its recent
+// history.
+type Forecast struct {
+	computedAt time.Time
+	fullAt     *time.Time
+	resource   Resource
+	name       string
+	used       datasize.ByteSize
+	total      datasize.ByteSize
+	// rate is the usage growth in bytes per second.
+	rate float64
+}
+
+func (f Forecast) Resource() Resource            { return f.resource }
+func (f Forecast) Used() datasize.ByteSize       { return f.used }
+func (f Forecast) Total() datasize.ByteSize      { return f.total }
+func (f Forecast) ComputedAt() time.Time         { return f.computedAt }
+func (f Forecast) FullAt() *time.Time            { return f.fullAt }
+func (f Forecast) RatePerDay() datasize.ByteSize { return datasize.ByteSize(f.rate * 86400) }
+
+// Name is the mount point for a disk or "memory".
+func (f Forecast) Name() string { return f.name }
+
+// IsFilling is true if the resource will be exhausted within the forecast
+// horizon.
+func (f Forecast) IsFilling() bool { return f.fullAt != nil }
+
+// FullIn returns the remaining time before the resource exhaustion.
+func (f Forecast) FullIn() time.Duration {
+	if f.fullAt == nil {
+		return 0
+	}
+
+	return f.fullAt.Sub(f.computedAt)
+}
+
+// Summary returns an human readable version of the forecast, for example:
+// "At this rate, / is full in 3 days".
+func (f Forecast) Summary() string {
+	if f.fullAt == nil {
+		return fmt.Sprintf("%s is not filling up", f.name)
+	}
+
+	return fmt.Sprintf("At this rate, %s is full in %s", f.name, humanizeDuration(f.FullIn()))
+}
+
+func (f *Forecast) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"resource":   f.resource,
+		"name":       f.name,
+		"used":       f.used,
+		"total":      f.total,
+		"fullAt":     f.fullAt,
+		"computedAt": f.computedAt,
+	})
+}
+
+func humanizeDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "less than a minute"
+	case d < 2*time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < 48*time.Hour:
+		return plural(int(d.Hours()), "hour")
+	default:
+		return plural(int(d.Hours()/24), "day")
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+
+	return fmt.Sprintf("%d %ss", n, unit)
+}
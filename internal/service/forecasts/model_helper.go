@@ -0,0 +1,44 @@
+package forecasts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+)
+
+type FakeForecastBuilder struct {
+	t        testing.TB
+	forecast *Forecast
+}
+
+// NewFakeForecast returns a forecast for a disk mounted at name and full in 3 days.
+func NewFakeForecast(t testing.TB, name string) *FakeForecastBuilder {
+	t.Helper()
+
+	now := time.Now()
+
+	return &FakeForecastBuilder{
+		t: t,
+		forecast: &Forecast{
+			computedAt: now,
+			fullAt:     ptr.To(now.Add(72 * time.Hour)),
+			resource:   Disk,
+			name:       name,
+			used:       70 * datasize.GB,
+			total:      100 * datasize.GB,
+			rate:       float64(10*datasize.GB) / 86400,
+		},
+	}
+}
+
+func (f *FakeForecastBuilder) WithResource(resource Resource) *FakeForecastBuilder {
+	f.forecast.resource = resource
+
+	return f
+}
+
+func (f *FakeForecastBuilder) Build() *Forecast {
+	return f.forecast
+}
@@ -0,0 +1,223 @@
+package forecasts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/forecast"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+)
+
+const (
+	DiskFullAlert         = "disk-full-forecast"
+	MemoryExhaustionAlert = "memory-exhaustion-forecast"
+)
+
+const (
+	// historyWindow is the history used to compute the trend.
+	historyWindow = 24 * time.Hour
+	// minHistory is the minimal history span required to compute a trend.
+	minHistory = 30 * time.Minute
+	// resampleBucket smooths the points before the trend computation. The
+	// empty buckets, a downtime or a longer collection interval, are
+	// interpolated.
+	resampleBucket = time.Minute
+	// horizon is the maximum forecast. An exhaustion further than that is
+	// too uncertain to be displayed.
+	horizon = 90 * 24 * time.Hour
+
+	warningHorizon  = 72 * time.Hour
+	criticalHorizon = 24 * time.Hour
+
+	holtAlpha = 0.3
+	holtBeta  = 0.05
+)
+
+type service struct {
+	sysstats sysstats.Service
+	alerts   alerts.Service
+	clock    clock.Clock
+	lock     *sync.RWMutex
+	latest   []Forecast
+}
+
+func newService(sysstats sysstats.Service, alerts alerts.Service, tools tools.Tools) *service {
+	return &service{
+		sysstats: sysstats,
+		alerts:   alerts,
+		clock:    tools.Clock(),
+		lock:     new(sync.RWMutex),
+		latest:   nil,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]Forecast, error) {
+	s.lock.RLock()
+	latest := s.latest
+	s.lock.RUnlock()
+
+	if latest != nil {
+		return latest, nil
+	}
+
+	// The cron didn't run yet.
+	res, err := s.compute(ctx)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	s.lock.Lock()
+	s.latest = res
+	s.lock.Unlock()
+
+	return res, nil
+}
+
+func (s *service) refresh(ctx context.Context) error {
+	res, err := s.compute(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	s.latest = res
+	s.lock.Unlock()
+
+	var alertErrs []error
+	for _, f := range res {
+		err = s.updateAlert(ctx, &f)
+		if err != nil {
+			alertErrs = append(alertErrs, fmt.Errorf("%s: %w", f.name, err))
+		}
+	}
+
+	return errors.Join(alertErrs...)
+}
+
+func (s *service) updateAlert(ctx context.Context, f *Forecast) error {
+	name := MemoryExhaustionAlert
+	var labels map[string]string
+	if f.resource == Disk {
+		name = DiskFullAlert
+		labels = map[string]string{"mount": f.name}
+	}
+
+	if !f.IsFilling() || f.FullIn() > warningHorizon {
+		return s.alerts.Resolve(ctx, &alerts.ResolveCmd{Name: name, Labels: labels})
+	}
+
+	severity := notifications.Warning
+	if f.FullIn() <= criticalHorizon {
+		severity = notifications.Critical
+	}
+
+	_, err := s.alerts.Fire(ctx, &alerts.FireCmd{
+		Name:     name,
+		Labels:   labels,
+		Severity: severity,
+		Summary:  f.Summary(),
+	})
+
+	return err
+}
+
+type serie struct {
+	resource Resource
+	name     string
+	total    datasize.ByteSize
+	used     datasize.ByteSize
+	points   []forecast.Point
+}
+
+func (s *service) compute(ctx context.Context) ([]Forecast, error) {
+	now := s.clock.Now()
+
+	stats, err := s.sysstats.GetRange(ctx, now.Add(-historyWindow), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetRange: %w", err)
+	}
+
+	memory := &serie{resource: Memory, name: "memory"}
+	disks := map[string]*serie{}
+	diskOrder := []string{}
+
+	for _, stat := range stats {
+		mem := stat.Memory()
+		memory.total = mem.TotalMemory()
+		memory.used = mem.UsedMemory()
+		memory.points = append(memory.points, forecast.Point{At: stat.Time(), Value: float64(mem.UsedMemory())})
+
+		for _, disk := range stat.Disks() {
+			d, ok := disks[disk.MountPoint()]
+			if !ok {
+				d = &serie{resource: Disk, name: disk.MountPoint()}
+				disks[disk.MountPoint()] = d
+				diskOrder = append(diskOrder, disk.MountPoint())
+			}
+
+			d.total = disk.Total()
+			d.used = disk.Used()
+			d.points = append(d.points, forecast.Point{At: stat.Time(), Value: float64(disk.Used())})
+		}
+	}
+
+	res := []Forecast{}
+	if len(memory.points) > 0 {
+		res = append(res, s.forecast(now, memory))
+	}
+
+	for _, name := range diskOrder {
+		res = append(res, s.forecast(now, disks[name]))
+	}
+
+	return res, nil
+}
+
+func (s *service) forecast(now time.Time, serie *serie) Forecast {
+	res := Forecast{
+		computedAt: now,
+		resource:   serie.resource,
+		name:       serie.name,
+		used:       serie.used,
+		total:      serie.total,
+		fullAt:     nil,
+	}
+
+	points := serie.points
+	if len(points) < 2 || points[len(points)-1].At.Sub(points[0].At) < minHistory {
+		return res
+	}
+
+	trend, err := forecast.Holt(forecast.Resample(points, resampleBucket), holtAlpha, holtBeta)
+	if err != nil {
+		return res
+	}
+
+	res.rate = trend.Slope
+
+	eta, ok := trend.TimeToReach(float64(serie.total))
+	if !ok {
+		return res
+	}
+
+	fullAt := trend.At.Add(eta)
+	if fullAt.Before(now) {
+		fullAt = now
+	}
+
+	if fullAt.Sub(now) <= horizon {
+		res.fullAt = ptr.To(fullAt)
+	}
+
+	return res
+}
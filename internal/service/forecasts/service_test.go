@@ -0,0 +1,133 @@
+package forecasts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// buildHistory returns an hour of stats, every 5s. The root disk fills by
+// 1GB per hour and the memory is stable.
+func buildHistory(t *testing.T, now time.Time) []sysstats.Stats {
+	res := []sysstats.Stats{}
+
+	for at := now.Add(-time.Hour); !at.After(now); at = at.Add(5 * time.Second) {
+		elapsed := at.Sub(now.Add(-time.Hour))
+		used := 50*datasize.GB + datasize.ByteSize(float64(datasize.GB)*elapsed.Hours())
+
+		res = append(res, *sysstats.NewFakeStats(t).
+			WithTime(at).
+			WithMemory(16*datasize.GB, 8*datasize.GB).
+			WithDisks(sysstats.NewFakeDisk("/", 100*datasize.GB, 100*datasize.GB-used)).
+			Build())
+	}
+
+	return res
+}
+
+func TestForecastsService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetAll computes the forecasts", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		sysstatsMock := sysstats.NewMockService(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(sysstatsMock, alertsMock, tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		history := buildHistory(t, now)
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		sysstatsMock.On("GetRange", mock.Anything, now.Add(-historyWindow), now).Return(history, nil).Once()
+
+		// Run
+		res, err := svc.GetAll(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		assert.Equal(t, Memory, res[0].Resource())
+		assert.False(t, res[0].IsFilling())
+		assert.Equal(t, "memory is not filling up", res[0].Summary())
+
+		assert.Equal(t, Disk, res[1].Resource())
+		assert.Equal(t, "/", res[1].Name())
+		require.True(t, res[1].IsFilling())
+		// 49GB left at 1GB per hour.
+		assert.InDelta(t, 49*time.Hour, res[1].FullIn(), float64(30*time.Minute))
+		assert.Equal(t, "At this rate, / is full in 2 days", res[1].Summary())
+
+		// The second call uses the cached value.
+		res2, err := svc.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, res, res2)
+	})
+
+	t.Run("GetAll with a too short history", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		sysstatsMock := sysstats.NewMockService(t)
+		svc := newService(sysstatsMock, alerts.NewMockService(t), tools)
+
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		history := buildHistory(t, now)[700:]
+
+		tools.ClockMock.On("Now").Return(now).Once()
+		sysstatsMock.On("GetRange", mock.Anything, now.Add(-historyWindow), now).Return(history, nil).Once()
+
+		res, err := svc.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.False(t, res[1].IsFilling())
+	})
+
+	t.Run("refresh fires and resolves the alerts", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		sysstatsMock := sysstats.NewMockService(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(sysstatsMock, alertsMock, tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		history := buildHistory(t, now)
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		sysstatsMock.On("GetRange", mock.Anything, now.Add(-historyWindow), now).Return(history, nil).Once()
+		alertsMock.On("Resolve", mock.Anything, &alerts.ResolveCmd{Name: MemoryExhaustionAlert}).Return(nil).Once()
+		alertsMock.On("Fire", mock.Anything, &alerts.FireCmd{
+			Name:     DiskFullAlert,
+			Labels:   map[string]string{"mount": "/"},
+			Severity: notifications.Warning,
+			Summary:  "At this rate, / is full in 2 days",
+		}).Return(nil, nil).Once()
+
+		// Run
+		err := svc.refresh(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+}
+
+func Test_humanizeDuration(t *testing.T) {
+	assert.Equal(t, "less than a minute", humanizeDuration(10*time.Second))
+	assert.Equal(t, "1 minute", humanizeDuration(time.Minute))
+	assert.Equal(t, "90 minutes", humanizeDuration(90*time.Minute))
+	assert.Equal(t, "5 hours", humanizeDuration(5*time.Hour))
+	assert.Equal(t, "3 days", humanizeDuration(80*time.Hour))
+}
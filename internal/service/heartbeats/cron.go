@@ -0,0 +1,31 @@
+package heartbeats
+
+import (
+	"context"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+)
+
+// LateCron marks the heartbeats without a ping in time as down.
+type LateCron struct {
+	service Service
+}
+
+func newLateCron(service Service) *LateCron {
+	return &LateCron{service: service}
+}
+
+func (c *LateCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "heartbeats-late",
+		Schedule: scheduler.Every(30 * time.Second),
+		Jitter:   5 * time.Second,
+		Timeout:  30 * time.Second,
+		Runner:   c,
+	}
+}
+
+func (c *LateCron) Run(ctx context.Context) error {
+	return c.service.markLate(ctx)
+}
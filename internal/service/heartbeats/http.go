@@ -0,0 +1,86 @@
+package heartbeats
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/logger"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/go-chi/chi/v5"
+)
+
+// HTTPHandler exposes the ping endpoints called by the jobs:
+//
+//   - /ping/<id> for a success
+//   - /ping/<id>/start when the job starts
+//   - /ping/<id>/fail for a failure
+//   - /ping/<id>/<exit-code> with the exit code of the job
+//
+// The request body is kept as the output of the run. Those endpoints are not
+// authenticated: the heartbeat id is the secret.
+type HTTPHandler struct {
+	service Service
+}
+
+func NewHTTPHandler(service Service) *HTTPHandler {
+	return &HTTPHandler{service: service}
+}
+
+func (h *HTTPHandler) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Logger, mids.RealIP)
+	}
+
+	r.HandleFunc("/ping/{id}", h.ping)
+	r.HandleFunc("/ping/{id}/{action}", h.ping)
+}
+
+func (h *HTTPHandler) ping(w http.ResponseWriter, r *http.Request) {
+	cmd := PingCmd{
+		HeartbeatID: uuid.UUID(chi.URLParam(r, "id")),
+		Kind:        Success,
+		RemoteAddr:  r.RemoteAddr,
+	}
+
+	switch action := chi.URLParam(r, "action"); action {
+	case "":
+	case "start":
+		cmd.Kind = Start
+	case "fail":
+		cmd.Kind = Fail
+	default:
+		code, err := strconv.Atoi(action)
+		if err != nil {
+			http.Error(w, "unknown action", http.StatusNotFound)
+			return
+		}
+
+		cmd.ExitCode = &code
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read the body", http.StatusBadRequest)
+		return
+	}
+
+	cmd.Body = string(body)
+
+	err = h.service.Ping(r.Context(), &cmd)
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, errs.ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err != nil:
+		logger.LogEntrySetError(r.Context(), err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		w.Write([]byte("OK"))
+	}
+}
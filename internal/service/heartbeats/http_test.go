@@ -0,0 +1,84 @@
+package heartbeats
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPingHTTPHandler(t *testing.T) {
+	tests := []struct {
+		Name   string
+		URL    string
+		Body   string
+		Cmd    *PingCmd
+		Err    error
+		Status int
+	}{
+		{
+			Name:   "success",
+			URL:    "/ping/some-id",
+			Body:   "some-output",
+			Cmd:    &PingCmd{HeartbeatID: "some-id", Kind: Success, Body: "some-output", RemoteAddr: "192.0.2.1:1234"},
+			Status: http.StatusOK,
+		},
+		{
+			Name:   "start",
+			URL:    "/ping/some-id/start",
+			Cmd:    &PingCmd{HeartbeatID: "some-id", Kind: Start, RemoteAddr: "192.0.2.1:1234"},
+			Status: http.StatusOK,
+		},
+		{
+			Name:   "fail",
+			URL:    "/ping/some-id/fail",
+			Cmd:    &PingCmd{HeartbeatID: "some-id", Kind: Fail, RemoteAddr: "192.0.2.1:1234"},
+			Status: http.StatusOK,
+		},
+		{
+			Name:   "exit code",
+			URL:    "/ping/some-id/3",
+			Cmd:    &PingCmd{HeartbeatID: "some-id", Kind: Success, ExitCode: ptr.To(3), RemoteAddr: "192.0.2.1:1234"},
+			Status: http.StatusOK,
+		},
+		{
+			Name:   "unknown action",
+			URL:    "/ping/some-id/foo",
+			Cmd:    nil,
+			Status: http.StatusNotFound,
+		},
+		{
+			Name:   "unknown heartbeat",
+			URL:    "/ping/some-id",
+			Cmd:    &PingCmd{HeartbeatID: "some-id", Kind: Success, RemoteAddr: "192.0.2.1:1234"},
+			Err:    errs.NotFound(errNotFound),
+			Status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			serviceMock := NewMockService(t)
+			srv := chi.NewRouter()
+			NewHTTPHandler(serviceMock).Register(srv, nil)
+
+			if test.Cmd != nil {
+				test.Cmd.HeartbeatID = uuid.UUID("some-id")
+				serviceMock.On("Ping", mock.Anything, test.Cmd).Return(test.Err).Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, test.URL, strings.NewReader(test.Body))
+			srv.ServeHTTP(w, r)
+
+			assert.Equal(t, test.Status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package heartbeats
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"go.uber.org/fx"
+)
+
+type Result struct {
+	fx.Out
+	Service Service
+	Cron    *LateCron
+}
+
+type Service interface {
+	Create(ctx context.Context, cmd *CreateCmd) (*Heartbeat, error)
+	GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Heartbeat, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Heartbeat, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Ping(ctx context.Context, cmd *PingCmd) error
+	// GetPings returns the latest pings of the heartbeat, the most recent
+	// first.
+	GetPings(ctx context.Context, heartbeat *Heartbeat, limit int) ([]Ping, error)
+	markLate(ctx context.Context) error
+}
+
+func Init(db *sql.DB, alerts alerts.Service, tools tools.Tools) Result {
+	storage := newSqlStorage(db)
+	svc := newService(storage, alerts, tools)
+
+	return Result{
+		Service: svc,
+		Cron:    newLateCron(svc),
+	}
+}
@@ -0,0 +1,136 @@
+package heartbeats
+
+import (
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	v "github.com/go-ozzo/ozzo-validation"
+)
+
+type Status string
+
+const (
+	// New is the status of a heartbeat which have never been pinged.
+	New Status = "new"
+	// Up is the status of a heartbeat which received its last ping in time.
+	Up Status = "up"
+	// Started is the status of a heartbeat with a job running.
+	Started Status = "started"
+	// Down is the status of a late or failed heartbeat.
+	Down Status = "down"
+)
+
+type PingKind string
+
+const (
+	Success PingKind = "success"
+	Start   PingKind = "start"
+	Fail    PingKind = "fail"
+)
+
+const (
+	MinPeriod = time.Minute
+	MaxPeriod = 365 * 24 * time.Hour
+
+	// MaxBodySize is the max size of a ping body kept. The rest is
+	// truncated.
+	MaxBodySize = 10 * 1024
+)
+
+// Heartbeat expects a ping every period. It's marked as down when a ping is
+// late by more than the grace time.
+type Heartbeat struct {
+	createdAt  time.Time
+	lastPingAt *time.Time
+	startedAt  *time.Time
+	id         uuid.UUID
+	name       string
+	status     Status
+	createdBy  uuid.UUID
+	period     time.Duration
+	grace      time.Duration
+}
+
+func (h Heartbeat) ID() uuid.UUID          { return h.id }
+func (h Heartbeat) Name() string           { return h.name }
+func (h Heartbeat) Status() Status         { return h.status }
+func (h Heartbeat) Period() time.Duration  { return h.period }
+func (h Heartbeat) Grace() time.Duration   { return h.grace }
+func (h Heartbeat) LastPingAt() *time.Time { return h.lastPingAt }
+func (h Heartbeat) StartedAt() *time.Time  { return h.startedAt }
+func (h Heartbeat) CreatedAt() time.Time   { return h.createdAt }
+func (h Heartbeat) CreatedBy() uuid.UUID   { return h.createdBy }
+
+// Deadline returns the time after which the heartbeat is late. It returns
+// nil if the heartbeat doesn't expect any ping.
+func (h Heartbeat) Deadline() *time.Time {
+	switch {
+	case h.status == Started && h.startedAt != nil:
+		// A started job must finish within the grace time.
+		res := h.startedAt.Add(h.grace)
+		return &res
+	case h.status == Up && h.lastPingAt != nil:
+		res := h.lastPingAt.Add(h.period + h.grace)
+		return &res
+	default:
+		return nil
+	}
+}
+
+// Ping is a signal received from a job.
+type Ping struct {
+	at          time.Time
+	exitCode    *int
+	duration    *time.Duration
+	id          uuid.UUID
+	heartbeatID uuid.UUID
+	kind        PingKind
+	body        string
+	remoteAddr  string
+}
+
+func (p Ping) ID() uuid.UUID          { return p.id }
+func (p Ping) HeartbeatID() uuid.UUID { return p.heartbeatID }
+func (p Ping) At() time.Time          { return p.at }
+func (p Ping) Kind() PingKind         { return p.kind }
+func (p Ping) ExitCode() *int         { return p.exitCode }
+func (p Ping) Body() string           { return p.body }
+func (p Ping) RemoteAddr() string     { return p.remoteAddr }
+
+// Duration returns the time elapsed since the start ping. It's nil if the job
+// haven't sent a start ping.
+func (p Ping) Duration() *time.Duration { return p.duration }
+
+type CreateCmd struct {
+	CreatedBy *users.User
+	Name      string
+	Period    time.Duration
+	Grace     time.Duration
+}
+
+func (t CreateCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.CreatedBy, v.Required),
+		v.Field(&t.Name, v.Required, v.Length(1, 50)),
+		v.Field(&t.Period, v.Required, v.Min(MinPeriod), v.Max(MaxPeriod)),
+		v.Field(&t.Grace, v.Required, v.Min(MinPeriod), v.Max(MaxPeriod)),
+	)
+}
+
+type PingCmd struct {
+	HeartbeatID uuid.UUID
+	Kind        PingKind
+	// ExitCode is optional. A non zero exit code is a failure.
+	ExitCode   *int
+	Body       string
+	RemoteAddr string
+}
+
+func (t PingCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.HeartbeatID, v.Required),
+		v.Field(&t.Kind, v.Required, v.In(Success, Start, Fail)),
+		v.Field(&t.ExitCode, v.Min(0), v.Max(255)),
+	)
+}
@@ -0,0 +1,131 @@
+package heartbeats
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+type FakeHeartbeatBuilder struct {
+	t         testing.TB
+	heartbeat *Heartbeat
+}
+
+func NewFakeHeartbeat(t testing.TB) *FakeHeartbeatBuilder {
+	t.Helper()
+
+	uuidProvider := uuid.NewProvider()
+	createdAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+
+	return &FakeHeartbeatBuilder{
+		t: t,
+		heartbeat: &Heartbeat{
+			id:         uuidProvider.New(),
+			name:       gofakeit.AppName(),
+			status:     New,
+			period:     24 * time.Hour,
+			grace:      time.Hour,
+			lastPingAt: nil,
+			startedAt:  nil,
+			createdAt:  createdAt.UTC(),
+			createdBy:  uuidProvider.New(),
+		},
+	}
+}
+
+// WithLastPing sets the heartbeat as up with a ping at the given time.
+func (f *FakeHeartbeatBuilder) WithLastPing(at time.Time) *FakeHeartbeatBuilder {
+	f.heartbeat.status = Up
+	f.heartbeat.lastPingAt = ptr.To(at.UTC())
+
+	return f
+}
+
+// WithStart sets the heartbeat as started at the given time.
+func (f *FakeHeartbeatBuilder) WithStart(at time.Time) *FakeHeartbeatBuilder {
+	f.heartbeat.status = Started
+	f.heartbeat.startedAt = ptr.To(at.UTC())
+
+	return f
+}
+
+func (f *FakeHeartbeatBuilder) WithStatus(status Status) *FakeHeartbeatBuilder {
+	f.heartbeat.status = status
+
+	return f
+}
+
+func (f *FakeHeartbeatBuilder) Build() *Heartbeat {
+	return f.heartbeat
+}
+
+func (f *FakeHeartbeatBuilder) BuildAndStore(ctx context.Context, db *sql.DB) *Heartbeat {
+	f.t.Helper()
+
+	storage := newSqlStorage(db)
+
+	err := storage.Save(ctx, f.heartbeat)
+	require.NoError(f.t, err)
+
+	return f.heartbeat
+}
+
+type FakePingBuilder struct {
+	t    testing.TB
+	ping *Ping
+}
+
+func NewFakePing(t testing.TB, heartbeat *Heartbeat) *FakePingBuilder {
+	t.Helper()
+
+	uuidProvider := uuid.NewProvider()
+	at := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+
+	return &FakePingBuilder{
+		t: t,
+		ping: &Ping{
+			id:          uuidProvider.New(),
+			heartbeatID: heartbeat.id,
+			at:          at.UTC(),
+			kind:        Success,
+			exitCode:    nil,
+			duration:    nil,
+			body:        gofakeit.Sentence(5),
+			remoteAddr:  gofakeit.IPv4Address(),
+		},
+	}
+}
+
+func (f *FakePingBuilder) WithAt(at time.Time) *FakePingBuilder {
+	f.ping.at = at.UTC()
+
+	return f
+}
+
+func (f *FakePingBuilder) WithKind(kind PingKind) *FakePingBuilder {
+	f.ping.kind = kind
+
+	return f
+}
+
+func (f *FakePingBuilder) WithExitCode(code int) *FakePingBuilder {
+	f.ping.exitCode = &code
+
+	return f
+}
+
+func (f *FakePingBuilder) WithDuration(duration time.Duration) *FakePingBuilder {
+	f.ping.duration = &duration
+
+	return f
+}
+
+func (f *FakePingBuilder) Build() *Ping {
+	return f.ping
+}
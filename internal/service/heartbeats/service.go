@@ -0,0 +1,289 @@
+package heartbeats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const (
+	HeartbeatDownAlert = "heartbeat-down"
+	HeartbeatLabel     = "heartbeat"
+
+	// HeartbeatIDLabel identifies the heartbeat inside the alerts, the names
+	// aren't unique.
+	HeartbeatIDLabel = "heartbeat_id"
+
+	// pingsRetention is the duration the pings are kept.
+	pingsRetention = 30 * 24 * time.Hour
+)
+
+type storage interface {
+	Save(ctx context.Context, heartbeat *Heartbeat) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Heartbeat, error)
+	GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Heartbeat, error)
+	Patch(ctx context.Context, id uuid.UUID, fields map[string]any) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	SavePing(ctx context.Context, ping *Ping) error
+	GetPings(ctx context.Context, heartbeatID uuid.UUID, limit int) ([]Ping, error)
+	DeletePingsBefore(ctx context.Context, before time.Time) error
+}
+
+type service struct {
+	storage storage
+	alerts  alerts.Service
+	clock   clock.Clock
+	uuid    uuid.Service
+}
+
+func newService(storage storage, alerts alerts.Service, tools tools.Tools) *service {
+	return &service{
+		storage: storage,
+		alerts:  alerts,
+		clock:   tools.Clock(),
+		uuid:    tools.UUID(),
+	}
+}
+
+func (s *service) Create(ctx context.Context, cmd *CreateCmd) (*Heartbeat, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	heartbeat := Heartbeat{
+		id:         s.uuid.New(),
+		name:       cmd.Name,
+		status:     New,
+		period:     cmd.Period.Round(time.Second),
+		grace:      cmd.Grace.Round(time.Second),
+		lastPingAt: nil,
+		startedAt:  nil,
+		createdAt:  s.clock.Now(),
+		createdBy:  cmd.CreatedBy.ID(),
+	}
+
+	err = s.storage.Save(ctx, &heartbeat)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to save the heartbeat: %w", err))
+	}
+
+	return &heartbeat, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*Heartbeat, error) {
+	res, err := s.storage.GetByID(ctx, id)
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Heartbeat, error) {
+	res, err := s.storage.GetAll(ctx, cmd)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+	heartbeat, err := s.GetByID(ctx, id)
+	if errors.Is(err, errs.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Delete(ctx, id)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Delete: %w", err))
+	}
+
+	err = s.alerts.Resolve(ctx, downResolveCmd(heartbeat))
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to resolve the alert: %w", err))
+	}
+
+	return nil
+}
+
+func (s *service) GetPings(ctx context.Context, heartbeat *Heartbeat, limit int) ([]Ping, error) {
+	res, err := s.storage.GetPings(ctx, heartbeat.id, limit)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) Ping(ctx context.Context, cmd *PingCmd) error {
+	err := cmd.Validate()
+	if err != nil {
+		return errs.Validation(err)
+	}
+
+	heartbeat, err := s.GetByID(ctx, cmd.HeartbeatID)
+	if err != nil {
+		return err
+	}
+
+	kind := cmd.Kind
+	if kind == Success && cmd.ExitCode != nil && *cmd.ExitCode != 0 {
+		kind = Fail
+	}
+
+	body := cmd.Body
+	if len(body) > MaxBodySize {
+		body = body[:MaxBodySize]
+	}
+
+	now := s.clock.Now()
+
+	ping := Ping{
+		id:          s.uuid.New(),
+		heartbeatID: heartbeat.id,
+		at:          now,
+		kind:        kind,
+		exitCode:    cmd.ExitCode,
+		body:        body,
+		remoteAddr:  cmd.RemoteAddr,
+	}
+
+	if kind != Start && heartbeat.status == Started && heartbeat.startedAt != nil {
+		ping.duration = ptr.To(now.Sub(*heartbeat.startedAt))
+	}
+
+	err = s.storage.SavePing(ctx, &ping)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to SavePing: %w", err))
+	}
+
+	switch kind {
+	case Start:
+		err = s.storage.Patch(ctx, heartbeat.id, map[string]any{
+			"status":     Started,
+			"started_at": ptr.To(sqlstorage.SQLTime(now)),
+		})
+	case Success:
+		err = s.storage.Patch(ctx, heartbeat.id, map[string]any{
+			"status":       Up,
+			"last_ping_at": ptr.To(sqlstorage.SQLTime(now)),
+			"started_at":   nil,
+		})
+	default:
+		err = s.storage.Patch(ctx, heartbeat.id, map[string]any{
+			"status":       Down,
+			"last_ping_at": ptr.To(sqlstorage.SQLTime(now)),
+			"started_at":   nil,
+		})
+	}
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Patch: %w", err))
+	}
+
+	switch kind {
+	case Success:
+		err = s.alerts.Resolve(ctx, downResolveCmd(heartbeat))
+	case Fail:
+		summary := fmt.Sprintf("%s reported a failure", heartbeat.name)
+		if cmd.ExitCode != nil {
+			summary = fmt.Sprintf("%s (exit code %d)", summary, *cmd.ExitCode)
+		}
+
+		err = s.fireDown(ctx, heartbeat, summary)
+	}
+	if err != nil {
+		return errs.Internal(err)
+	}
+
+	return nil
+}
+
+// markLate marks the heartbeats which have passed their deadline as down.
+func (s *service) markLate(ctx context.Context) error {
+	heartbeats, err := s.storage.GetAll(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to GetAll: %w", err)
+	}
+
+	now := s.clock.Now()
+
+	for _, heartbeat := range heartbeats {
+		deadline := heartbeat.Deadline()
+		if deadline == nil || deadline.After(now) {
+			continue
+		}
+
+		var summary string
+		if heartbeat.status == Started {
+			summary = fmt.Sprintf("%s is late: the job started at %s is still running", heartbeat.name, heartbeat.startedAt.Format(time.DateTime))
+		} else {
+			summary = fmt.Sprintf("%s is late: no ping since %s", heartbeat.name, heartbeat.lastPingAt.Format(time.DateTime))
+		}
+
+		err = s.storage.Patch(ctx, heartbeat.id, map[string]any{"status": Down})
+		if err != nil {
+			return fmt.Errorf("failed to Patch %q: %w", heartbeat.name, err)
+		}
+
+		err = s.fireDown(ctx, &heartbeat, summary)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.storage.DeletePingsBefore(ctx, now.Add(-pingsRetention))
+	if err != nil {
+		return fmt.Errorf("failed to DeletePingsBefore: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) fireDown(ctx context.Context, heartbeat *Heartbeat, summary string) error {
+	_, err := s.alerts.Fire(ctx, &alerts.FireCmd{
+		Name:     HeartbeatDownAlert,
+		Labels:   downLabels(heartbeat),
+		Severity: notifications.Critical,
+		Summary:  summary,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Fire: %w", err)
+	}
+
+	return nil
+}
+
+func downResolveCmd(heartbeat *Heartbeat) *alerts.ResolveCmd {
+	return &alerts.ResolveCmd{
+		Name:   HeartbeatDownAlert,
+		Labels: downLabels(heartbeat),
+	}
+}
+
+func downLabels(heartbeat *Heartbeat) map[string]string {
+	return map[string]string{
+		HeartbeatLabel:   heartbeat.name,
+		HeartbeatIDLabel: string(heartbeat.id),
+	}
+}
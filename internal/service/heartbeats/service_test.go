@@ -0,0 +1,250 @@
+package heartbeats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatsService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		// Data
+		user := users.NewFakeUser(t).Build()
+		now := time.Now()
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-heartbeat-id")).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("Save", mock.Anything, mock.AnythingOfType("*heartbeats.Heartbeat")).Return(nil).Once()
+
+		// Run
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: user,
+			Name:      "Backups",
+			Period:    24 * time.Hour,
+			Grace:     time.Hour,
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, uuid.UUID("some-heartbeat-id"), res.ID())
+		assert.Equal(t, "Backups", res.Name())
+		assert.Equal(t, New, res.Status())
+		assert.Nil(t, res.Deadline())
+		assert.Equal(t, user.ID(), res.CreatedBy())
+		assert.Equal(t, now, res.CreatedAt())
+	})
+
+	t.Run("Create with a too short period", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		svc := newService(newMockStorage(t), alerts.NewMockService(t), tools)
+
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: users.NewFakeUser(t).Build(),
+			Name:      "Backups",
+			Period:    time.Second,
+			Grace:     time.Hour,
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Ping success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(storageMock, alertsMock, tools)
+
+		// Data
+		now := time.Now().UTC()
+		heartbeat := NewFakeHeartbeat(t).WithStart(now.Add(-time.Minute)).Build()
+
+		// Mocks
+		storageMock.On("GetByID", mock.Anything, heartbeat.ID()).Return(heartbeat, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-ping-id")).Once()
+		storageMock.On("SavePing", mock.Anything, &Ping{
+			id:          uuid.UUID("some-ping-id"),
+			heartbeatID: heartbeat.ID(),
+			at:          now,
+			kind:        Success,
+			exitCode:    ptr.To(0),
+			duration:    ptr.To(time.Minute),
+			body:        "done",
+			remoteAddr:  "1.2.3.4",
+		}).Return(nil).Once()
+		storageMock.On("Patch", mock.Anything, heartbeat.ID(), map[string]any{
+			"status":       Up,
+			"last_ping_at": ptr.To(sqlstorage.SQLTime(now)),
+			"started_at":   nil,
+		}).Return(nil).Once()
+		alertsMock.On("Resolve", mock.Anything, &alerts.ResolveCmd{
+			Name:   HeartbeatDownAlert,
+			Labels: map[string]string{HeartbeatLabel: heartbeat.Name(), HeartbeatIDLabel: string(heartbeat.ID())},
+		}).Return(nil).Once()
+
+		// Run
+		err := svc.Ping(ctx, &PingCmd{
+			HeartbeatID: heartbeat.ID(),
+			Kind:        Success,
+			ExitCode:    ptr.To(0),
+			Body:        "done",
+			RemoteAddr:  "1.2.3.4",
+		})
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("Ping with a non zero exit code fires an alert", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(storageMock, alertsMock, tools)
+
+		// Data
+		now := time.Now().UTC()
+		heartbeat := NewFakeHeartbeat(t).WithLastPing(now.Add(-24 * time.Hour)).Build()
+
+		// Mocks
+		storageMock.On("GetByID", mock.Anything, heartbeat.ID()).Return(heartbeat, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-ping-id")).Once()
+		storageMock.On("SavePing", mock.Anything, &Ping{
+			id:          uuid.UUID("some-ping-id"),
+			heartbeatID: heartbeat.ID(),
+			at:          now,
+			kind:        Fail,
+			exitCode:    ptr.To(2),
+			body:        "disk full",
+		}).Return(nil).Once()
+		storageMock.On("Patch", mock.Anything, heartbeat.ID(), map[string]any{
+			"status":       Down,
+			"last_ping_at": ptr.To(sqlstorage.SQLTime(now)),
+			"started_at":   nil,
+		}).Return(nil).Once()
+		alertsMock.On("Fire", mock.Anything, &alerts.FireCmd{
+			Name:     HeartbeatDownAlert,
+			Labels:   map[string]string{HeartbeatLabel: heartbeat.Name(), HeartbeatIDLabel: string(heartbeat.ID())},
+			Severity: notifications.Critical,
+			Summary:  heartbeat.Name() + " reported a failure (exit code 2)",
+		}).Return(&alerts.Alert{}, nil).Once()
+
+		// Run
+		err := svc.Ping(ctx, &PingCmd{
+			HeartbeatID: heartbeat.ID(),
+			Kind:        Success,
+			ExitCode:    ptr.To(2),
+			Body:        "disk full",
+		})
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("Ping start", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		// Data
+		now := time.Now().UTC()
+		heartbeat := NewFakeHeartbeat(t).Build()
+
+		// Mocks
+		storageMock.On("GetByID", mock.Anything, heartbeat.ID()).Return(heartbeat, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-ping-id")).Once()
+		storageMock.On("SavePing", mock.Anything, mock.AnythingOfType("*heartbeats.Ping")).Return(nil).Once()
+		storageMock.On("Patch", mock.Anything, heartbeat.ID(), map[string]any{
+			"status":     Started,
+			"started_at": ptr.To(sqlstorage.SQLTime(now)),
+		}).Return(nil).Once()
+
+		// Run
+		err := svc.Ping(ctx, &PingCmd{HeartbeatID: heartbeat.ID(), Kind: Start})
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("Ping with an unknown heartbeat", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		storageMock.On("GetByID", mock.Anything, uuid.UUID("some-id")).Return(nil, errNotFound).Once()
+
+		err := svc.Ping(ctx, &PingCmd{HeartbeatID: uuid.UUID("some-id"), Kind: Success})
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("markLate marks the late heartbeats as down", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(storageMock, alertsMock, tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+		onTime := NewFakeHeartbeat(t).WithLastPing(now.Add(-24 * time.Hour)).Build()
+		late := NewFakeHeartbeat(t).WithLastPing(now.Add(-26 * time.Hour)).Build()
+		stuck := NewFakeHeartbeat(t).WithStart(now.Add(-2 * time.Hour)).Build()
+		neverPinged := NewFakeHeartbeat(t).Build()
+
+		// Mocks
+		storageMock.On("GetAll", mock.Anything, (*sqlstorage.PaginateCmd)(nil)).
+			Return([]Heartbeat{*onTime, *late, *stuck, *neverPinged}, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+
+		storageMock.On("Patch", mock.Anything, late.ID(), map[string]any{"status": Down}).Return(nil).Once()
+		alertsMock.On("Fire", mock.Anything, &alerts.FireCmd{
+			Name:     HeartbeatDownAlert,
+			Labels:   map[string]string{HeartbeatLabel: late.Name(), HeartbeatIDLabel: string(late.ID())},
+			Severity: notifications.Critical,
+			Summary:  late.Name() + " is late: no ping since 2024-05-31 10:00:00",
+		}).Return(&alerts.Alert{}, nil).Once()
+
+		storageMock.On("Patch", mock.Anything, stuck.ID(), map[string]any{"status": Down}).Return(nil).Once()
+		alertsMock.On("Fire", mock.Anything, &alerts.FireCmd{
+			Name:     HeartbeatDownAlert,
+			Labels:   map[string]string{HeartbeatLabel: stuck.Name(), HeartbeatIDLabel: string(stuck.ID())},
+			Severity: notifications.Critical,
+			Summary:  stuck.Name() + " is late: the job started at 2024-06-01 10:00:00 is still running",
+		}).Return(&alerts.Alert{}, nil).Once()
+
+		storageMock.On("DeletePingsBefore", mock.Anything, now.Add(-pingsRetention)).Return(nil).Once()
+
+		// Run
+		err := svc.markLate(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,277 @@
+package heartbeats
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const (
+	heartbeatsTableName = "heartbeats"
+	pingsTableName      = "heartbeat_pings"
+)
+
+var errNotFound = errors.New("not found")
+
+var (
+	allHeartbeatFields = []string{"id", "name", "status", "period", "grace", "last_ping_at", "started_at", "created_at", "created_by"}
+	allPingFields      = []string{"id", "heartbeat_id", "at", "kind", "exit_code", "duration", "body", "remote_addr"}
+)
+
+type sqlStorage struct {
+	db *sql.DB
+}
+
+func newSqlStorage(db *sql.DB) *sqlStorage {
+	return &sqlStorage{db}
+}
+
+func (s *sqlStorage) Save(ctx context.Context, h *Heartbeat) error {
+	_, err := sq.
+		Insert(heartbeatsTableName).
+		Columns(allHeartbeatFields...).
+		Values(
+			h.id,
+			h.name,
+			h.status,
+			int64(h.period.Seconds()),
+			int64(h.grace.Seconds()),
+			optionalTime(h.lastPingAt),
+			optionalTime(h.startedAt),
+			ptr.To(sqlstorage.SQLTime(h.createdAt)),
+			h.createdBy,
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetByID(ctx context.Context, id uuid.UUID) (*Heartbeat, error) {
+	row := sq.
+		Select(allHeartbeatFields...).
+		From(heartbeatsTableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		QueryRowContext(ctx)
+
+	res, err := s.scanHeartbeat(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Heartbeat, error) {
+	rows, err := sqlstorage.PaginateSelection(sq.
+		Select(allHeartbeatFields...).
+		From(heartbeatsTableName), cmd).
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	heartbeats := []Heartbeat{}
+
+	for rows.Next() {
+		res, err := s.scanHeartbeat(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		heartbeats = append(heartbeats, *res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return heartbeats, nil
+}
+
+func (s *sqlStorage) Patch(ctx context.Context, id uuid.UUID, fields map[string]any) error {
+	_, err := sq.Update(heartbeatsTableName).
+		SetMap(fields).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := sq.
+		Delete(pingsTableName).
+		Where(sq.Eq{"heartbeat_id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete the pings: %w", err)
+	}
+
+	_, err = sq.
+		Delete(heartbeatsTableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) SavePing(ctx context.Context, p *Ping) error {
+	var duration *int64
+	if p.duration != nil {
+		duration = ptr.To(p.duration.Milliseconds())
+	}
+
+	_, err := sq.
+		Insert(pingsTableName).
+		Columns(allPingFields...).
+		Values(
+			p.id,
+			p.heartbeatID,
+			ptr.To(sqlstorage.SQLTime(p.at)),
+			p.kind,
+			p.exitCode,
+			duration,
+			p.body,
+			p.remoteAddr,
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetPings(ctx context.Context, heartbeatID uuid.UUID, limit int) ([]Ping, error) {
+	rows, err := sq.
+		Select(allPingFields...).
+		From(pingsTableName).
+		Where(sq.Eq{"heartbeat_id": heartbeatID}).
+		OrderBy("at DESC").
+		Limit(uint64(limit)).
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	pings := []Ping{}
+
+	for rows.Next() {
+		var res Ping
+		var sqlAt sqlstorage.SQLTime
+		var duration *int64
+
+		err := rows.Scan(
+			&res.id,
+			&res.heartbeatID,
+			&sqlAt,
+			&res.kind,
+			&res.exitCode,
+			&duration,
+			&res.body,
+			&res.remoteAddr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		res.at = sqlAt.Time()
+		if duration != nil {
+			res.duration = ptr.To(time.Duration(*duration) * time.Millisecond)
+		}
+
+		pings = append(pings, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return pings, nil
+}
+
+func (s *sqlStorage) DeletePingsBefore(ctx context.Context, before time.Time) error {
+	_, err := sq.
+		Delete(pingsTableName).
+		Where(sq.Lt{"at": sqlstorage.SQLTime(before)}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) scanHeartbeat(row sqlstorage.RowScanner) (*Heartbeat, error) {
+	var res Heartbeat
+	var period, grace int64
+	var sqlLastPingAt, sqlStartedAt *sqlstorage.SQLTime
+	var sqlCreatedAt sqlstorage.SQLTime
+
+	err := row.Scan(
+		&res.id,
+		&res.name,
+		&res.status,
+		&period,
+		&grace,
+		&sqlLastPingAt,
+		&sqlStartedAt,
+		&sqlCreatedAt,
+		&res.createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res.period = time.Duration(period) * time.Second
+	res.grace = time.Duration(grace) * time.Second
+	res.createdAt = sqlCreatedAt.Time()
+
+	if sqlLastPingAt != nil {
+		res.lastPingAt = ptr.To(sqlLastPingAt.Time())
+	}
+
+	if sqlStartedAt != nil {
+		res.startedAt = ptr.To(sqlStartedAt.Time())
+	}
+
+	return &res, nil
+}
+
+func optionalTime(t *time.Time) *sqlstorage.SQLTime {
+	if t == nil {
+		return nil
+	}
+
+	return ptr.To(sqlstorage.SQLTime(*t))
+}
@@ -0,0 +1,109 @@
+package heartbeats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatsSqlStorage(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqlstorage.NewTestStorage(t)
+	store := newSqlStorage(db)
+
+	now := time.Now().UTC()
+	heartbeat := NewFakeHeartbeat(t).Build()
+	oldPing := NewFakePing(t, heartbeat).WithAt(now.Add(-time.Hour)).WithKind(Start).Build()
+	latestPing := NewFakePing(t, heartbeat).
+		WithAt(now).
+		WithExitCode(2).
+		WithKind(Fail).
+		WithDuration(3 * time.Second).
+		Build()
+
+	t.Run("GetAll with nothing", func(t *testing.T) {
+		res, err := store.GetAll(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Save success", func(t *testing.T) {
+		err := store.Save(ctx, heartbeat)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetByID success", func(t *testing.T) {
+		res, err := store.GetByID(ctx, heartbeat.ID())
+		require.NoError(t, err)
+		assert.Equal(t, heartbeat, res)
+	})
+
+	t.Run("Patch success", func(t *testing.T) {
+		err := store.Patch(ctx, heartbeat.ID(), map[string]any{
+			"status":       Up,
+			"last_ping_at": ptr.To(sqlstorage.SQLTime(now)),
+		})
+		require.NoError(t, err)
+
+		res, err := store.GetByID(ctx, heartbeat.ID())
+		require.NoError(t, err)
+		assert.Equal(t, Up, res.Status())
+		assert.Equal(t, &now, res.LastPingAt())
+		assert.Nil(t, res.StartedAt())
+	})
+
+	t.Run("GetAll success", func(t *testing.T) {
+		res, err := store.GetAll(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, heartbeat.ID(), res[0].ID())
+	})
+
+	t.Run("SavePing success", func(t *testing.T) {
+		err := store.SavePing(ctx, oldPing)
+		require.NoError(t, err)
+
+		err = store.SavePing(ctx, latestPing)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetPings success", func(t *testing.T) {
+		res, err := store.GetPings(ctx, heartbeat.ID(), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []Ping{*latestPing, *oldPing}, res)
+	})
+
+	t.Run("GetPings with a limit", func(t *testing.T) {
+		res, err := store.GetPings(ctx, heartbeat.ID(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, []Ping{*latestPing}, res)
+	})
+
+	t.Run("DeletePingsBefore success", func(t *testing.T) {
+		err := store.DeletePingsBefore(ctx, now.Add(-time.Minute))
+		require.NoError(t, err)
+
+		res, err := store.GetPings(ctx, heartbeat.ID(), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []Ping{*latestPing}, res)
+	})
+
+	t.Run("Delete success", func(t *testing.T) {
+		err := store.Delete(ctx, heartbeat.ID())
+		require.NoError(t, err)
+
+		res, err := store.GetByID(ctx, heartbeat.ID())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+
+		pings, err := store.GetPings(ctx, heartbeat.ID(), 10)
+		require.NoError(t, err)
+		assert.Empty(t, pings)
+	})
+}
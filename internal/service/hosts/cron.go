@@ -0,0 +1,54 @@
+package hosts
+
+import (
+	"context"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+)
+
+// StaleCron fires an alert for the hosts which stopped to push.
+type StaleCron struct {
+	service Service
+}
+
+func newStaleCron(service Service) *StaleCron {
+	return &StaleCron{service: service}
+}
+
+func (c *StaleCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "hosts-stale",
+		Schedule: scheduler.Every(30 * time.Second),
+		Jitter:   5 * time.Second,
+		Timeout:  30 * time.Second,
+		Runner:   c,
+	}
+}
+
+func (c *StaleCron) Run(ctx context.Context) error {
+	return c.service.markStale(ctx)
+}
+
+// PurgeCron deletes the stats older than the retention.
+type PurgeCron struct {
+	service Service
+}
+
+func newPurgeCron(service Service) *PurgeCron {
+	return &PurgeCron{service: service}
+}
+
+func (c *PurgeCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "hosts-purge",
+		Schedule: scheduler.Every(time.Hour),
+		Jitter:   time.Minute,
+		Timeout:  5 * time.Minute,
+		Runner:   c,
+	}
+}
+
+func (c *PurgeCron) Run(ctx context.Context) error {
+	return c.service.purgeStats(ctx)
+}
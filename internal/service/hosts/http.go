@@ -0,0 +1,67 @@
+package hosts
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/logger"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/go-chi/chi/v5"
+)
+
+// PushPath is the endpoint receiving the reports of the agents.
+const PushPath = "/api/agents/push"
+
+// HTTPHandler exposes the endpoint called by the agents. The requests are
+// authenticated with the host token passed as a bearer token.
+type HTTPHandler struct {
+	service Service
+}
+
+func NewHTTPHandler(service Service) *HTTPHandler {
+	return &HTTPHandler{service: service}
+}
+
+func (h *HTTPHandler) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Logger, mids.RealIP)
+	}
+
+	r.Post(PushPath, h.push)
+}
+
+func (h *HTTPHandler) push(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	var report Report
+	err := json.NewDecoder(io.LimitReader(r.Body, MaxReportSize)).Decode(&report)
+	if err != nil {
+		http.Error(w, "invalid report: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Push(r.Context(), &PushCmd{
+		Token:  secret.NewText(token),
+		Report: &report,
+	})
+	switch {
+	case errors.Is(err, errs.ErrUnauthorized):
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	case errors.Is(err, errs.ErrValidation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err != nil:
+		logger.LogEntrySetError(r.Context(), err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
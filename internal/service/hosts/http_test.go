@@ -0,0 +1,84 @@
+package hosts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushHTTPHandler(t *testing.T) {
+	report := NewFakeReport(t, "web-1")
+	rawReport, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name   string
+		Auth   string
+		Body   string
+		Err    error
+		Push   bool
+		Status int
+	}{
+		{
+			Name:   "success",
+			Auth:   "Bearer some-token",
+			Body:   string(rawReport),
+			Push:   true,
+			Status: http.StatusNoContent,
+		},
+		{
+			Name:   "missing token",
+			Auth:   "",
+			Body:   string(rawReport),
+			Status: http.StatusUnauthorized,
+		},
+		{
+			Name:   "invalid body",
+			Auth:   "Bearer some-token",
+			Body:   "not json",
+			Status: http.StatusBadRequest,
+		},
+		{
+			Name:   "unknown token",
+			Auth:   "Bearer some-token",
+			Body:   string(rawReport),
+			Push:   true,
+			Err:    errs.Unauthorized(ErrInvalidToken),
+			Status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			serviceMock := NewMockService(t)
+			srv := chi.NewRouter()
+			NewHTTPHandler(serviceMock).Register(srv, nil)
+
+			if test.Push {
+				serviceMock.On("Push", mock.Anything, mock.MatchedBy(func(cmd *PushCmd) bool {
+					return cmd.Token.Equals(secret.NewText("some-token")) &&
+						cmd.Report.Hostname() == "web-1" &&
+						cmd.Report.Stats().Time().Equal(report.Stats().Time())
+				})).Return(test.Err).Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, PushPath, strings.NewReader(test.Body))
+			if test.Auth != "" {
+				r.Header.Set("Authorization", test.Auth)
+			}
+			srv.ServeHTTP(w, r)
+
+			assert.Equal(t, test.Status, w.Code)
+		})
+	}
+}
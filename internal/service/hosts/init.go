@@ -0,0 +1,55 @@
+package hosts
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"go.uber.org/fx"
+)
+
+type Result struct {
+	fx.Out
+	Service Service
+	Watcher sqlstorage.SQLChangeHook `group:"hooks"`
+	Cron    *StaleCron
+	Purge   *PurgeCron
+}
+
+type Service interface {
+	Create(ctx context.Context, cmd *CreateCmd) (*Host, error)
+	GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Host, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Host, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	UpdateTags(ctx context.Context, cmd *UpdateTagsCmd) (*Host, error)
+	// RegenerateToken replaces the host token, the previous one is revoked.
+	RegenerateToken(ctx context.Context, host *Host) (*Host, error)
+	// Push registers a report sent by the agent owning the token.
+	Push(ctx context.Context, cmd *PushCmd) error
+	GetLatestStats(ctx context.Context, host *Host) (*sysstats.Stats, error)
+	GetStatsRange(ctx context.Context, host *Host, start, end time.Time) ([]sysstats.Stats, error)
+	// GetSummaries returns the state of every host for the fleet overview.
+	GetSummaries(ctx context.Context) ([]Summary, error)
+	// Watch returns a channel receiving an event each time a host or its
+	// stats change. It's closed with the context.
+	Watch(ctx context.Context) chan struct{}
+	markStale(ctx context.Context) error
+	purgeStats(ctx context.Context) error
+}
+
+func Init(db *sql.DB, alerts alerts.Service, tools tools.Tools) Result {
+	storage := newSqlStorage(db)
+	svc := newService(storage, alerts, tools)
+
+	return Result{
+		Service: svc,
+		Watcher: svc,
+		Cron:    newStaleCron(svc),
+		Purge:   newPurgeCron(svc),
+	}
+}
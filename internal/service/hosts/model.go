@@ -0,0 +1,215 @@
+package hosts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	v "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// MaxReportSize is the max size of a report pushed by an agent.
+	MaxReportSize = 1024 * 1024
+
+	// StaleAfter is the duration without any push after which a host is
+	// considered as stale. The agents push every 5 seconds.
+	StaleAfter = 30 * time.Second
+
+	MaxTags = 10
+)
+
+var tagRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,29}$`)
+
+// Host is a remote machine running zapette in agent mode. The agent
+// authenticates its pushes with the host token, only its hash is saved.
+type Host struct {
+	createdAt  time.Time
+	lastSeenAt *time.Time
+	id         uuid.UUID
+	name       string
+	token      secret.Text
+	tokenHash  string
+	hostname   string
+	createdBy  uuid.UUID
+	tags       []string
+	uptime     time.Duration
+}
+
+func (h Host) ID() uuid.UUID          { return h.id }
+func (h Host) Name() string           { return h.name }
+func (h Host) Hostname() string       { return h.hostname }
+func (h Host) Uptime() time.Duration  { return h.uptime }
+func (h Host) LastSeenAt() *time.Time { return h.lastSeenAt }
+func (h Host) CreatedAt() time.Time   { return h.createdAt }
+func (h Host) CreatedBy() uuid.UUID   { return h.createdBy }
+
+// Token is only set on the host returned by Create and RegenerateToken, it
+// can't be read again.
+func (h Host) Token() secret.Text { return h.token }
+
+// Tags are sorted and lowercased.
+func (h Host) Tags() []string { return h.tags }
+
+func (h Host) HasTag(tag string) bool { return slices.Contains(h.tags, tag) }
+
+// IsStale returns true if the host has been seen but haven't pushed since
+// StaleAfter. A host never seen is not stale: the agent isn't setup yet.
+func (h Host) IsStale(now time.Time) bool {
+	return h.lastSeenAt != nil && now.Sub(*h.lastSeenAt) > StaleAfter
+}
+
+// Summary is the state of a host displayed on the fleet overview.
+type Summary struct {
+	host   Host
+	stats  *sysstats.Stats
+	cpu    *float64
+	alerts []alerts.Alert
+}
+
+func (s Summary) Host() Host { return s.host }
+
+// Stats are the latest stats pushed, nil if the agent never pushed.
+func (s Summary) Stats() *sysstats.Stats { return s.stats }
+
+// CPU is the CPU usage percentage between the two latest pushes, nil if
+// unknown.
+func (s Summary) CPU() *float64 { return s.cpu }
+
+// Alerts are the firing alerts of the host.
+func (s Summary) Alerts() []alerts.Alert { return s.alerts }
+
+// FullestDisk returns the disk with the highest usage, nil if there is
+// no disk.
+func (s Summary) FullestDisk() *sysstats.Disk {
+	if s.stats == nil {
+		return nil
+	}
+
+	var res *sysstats.Disk
+	for _, disk := range s.stats.Disks() {
+		if res == nil || disk.PercentageUsed() > res.PercentageUsed() {
+			res = &disk
+		}
+	}
+
+	return res
+}
+
+// Report is the payload pushed by an agent.
+type Report struct {
+	stats    *sysstats.Stats
+	hostname string
+	uptime   time.Duration
+}
+
+func NewReport(hostname string, uptime time.Duration, stats *sysstats.Stats) *Report {
+	return &Report{
+		stats:    stats,
+		hostname: hostname,
+		uptime:   uptime,
+	}
+}
+
+func (r Report) Hostname() string       { return r.hostname }
+func (r Report) Uptime() time.Duration  { return r.uptime }
+func (r Report) Stats() *sysstats.Stats { return r.stats }
+
+type reportJSON struct {
+	Hostname string `json:"hostname"`
+	// Uptime is in seconds.
+	Uptime int64 `json:"uptime"`
+	// Stats is the binary encoding of the stats, the same one used for
+	// the storage.
+	Stats []byte `json:"stats"`
+}
+
+func (r *Report) MarshalJSON() ([]byte, error) {
+	rawStats, err := r.stats.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the stats: %w", err)
+	}
+
+	return json.Marshal(reportJSON{
+		Hostname: r.hostname,
+		Uptime:   int64(r.uptime.Seconds()),
+		Stats:    rawStats,
+	})
+}
+
+func (r *Report) UnmarshalJSON(b []byte) error {
+	var res reportJSON
+
+	err := json.Unmarshal(b, &res)
+	if err != nil {
+		return err
+	}
+
+	var stats sysstats.Stats
+	err = stats.UnmarshalBinary(res.Stats)
+	if err != nil {
+		return fmt.Errorf("invalid stats: %w", err)
+	}
+
+	r.hostname = res.Hostname
+	r.uptime = time.Duration(res.Uptime) * time.Second
+	r.stats = &stats
+
+	return nil
+}
+
+type CreateCmd struct {
+	CreatedBy *users.User
+	Name      string
+}
+
+func (t CreateCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.CreatedBy, v.Required),
+		v.Field(&t.Name, v.Required, v.Length(1, 50)),
+	)
+}
+
+type UpdateTagsCmd struct {
+	Host *Host
+	Tags []string
+}
+
+func (t UpdateTagsCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Host, v.Required),
+		v.Field(&t.Tags, v.Length(0, MaxTags), v.Each(v.Match(tagRegexp))),
+	)
+}
+
+type PushCmd struct {
+	Report *Report
+	Token  secret.Text
+}
+
+func (t PushCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Token, v.By(func(_ any) error {
+			if t.Token.Raw() == "" {
+				return errors.New("cannot be blank")
+			}
+
+			return nil
+		})),
+		v.Field(&t.Report, v.By(func(_ any) error {
+			if t.Report == nil || t.Report.stats == nil || t.Report.stats.Time().IsZero() {
+				return errors.New("must contain some stats")
+			}
+
+			return nil
+		})),
+	)
+}
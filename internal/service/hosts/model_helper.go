@@ -0,0 +1,111 @@
+package hosts
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+type FakeHostBuilder struct {
+	t    testing.TB
+	host *Host
+}
+
+func NewFakeHost(t testing.TB) *FakeHostBuilder {
+	t.Helper()
+
+	uuidProvider := uuid.NewProvider()
+	createdAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+	token := secret.NewText(string(uuidProvider.New()))
+
+	return &FakeHostBuilder{
+		t: t,
+		host: &Host{
+			id:         uuidProvider.New(),
+			name:       gofakeit.AppName(),
+			token:      token,
+			tokenHash:  token.Hash(),
+			hostname:   "",
+			uptime:     0,
+			lastSeenAt: nil,
+			tags:       []string{},
+			createdAt:  createdAt.UTC(),
+			createdBy:  uuidProvider.New(),
+		},
+	}
+}
+
+// WithLastSeen sets the host as seen at the given time with a random
+// hostname and uptime.
+func (f *FakeHostBuilder) WithLastSeen(at time.Time) *FakeHostBuilder {
+	f.host.hostname = gofakeit.DomainName()
+	f.host.uptime = time.Duration(gofakeit.Number(60, 1000000)) * time.Second
+	f.host.lastSeenAt = ptr.To(at.UTC())
+
+	return f
+}
+
+// WithoutToken clears the token, like on the hosts read from the storage.
+func (f *FakeHostBuilder) WithoutToken() *FakeHostBuilder {
+	f.host.token = secret.Text{}
+
+	return f
+}
+
+func (f *FakeHostBuilder) WithName(name string) *FakeHostBuilder {
+	f.host.name = name
+
+	return f
+}
+
+func (f *FakeHostBuilder) WithTags(tags ...string) *FakeHostBuilder {
+	f.host.tags = tags
+
+	return f
+}
+
+func (f *FakeHostBuilder) Build() *Host {
+	return f.host
+}
+
+func (f *FakeHostBuilder) BuildAndStore(ctx context.Context, db *sql.DB) *Host {
+	f.t.Helper()
+
+	storage := newSqlStorage(db)
+
+	err := storage.Save(ctx, f.host)
+	require.NoError(f.t, err)
+
+	return f.host
+}
+
+// NewFakeSummary returns the summary of the host, used by the fleet
+// overview.
+func NewFakeSummary(host *Host, stats *sysstats.Stats, cpu *float64, alerts ...alerts.Alert) *Summary {
+	return &Summary{
+		host:   *host,
+		stats:  stats,
+		cpu:    cpu,
+		alerts: alerts,
+	}
+}
+
+// NewFakeReport returns a report of the given host with some random stats.
+func NewFakeReport(t testing.TB, hostname string) *Report {
+	t.Helper()
+
+	return &Report{
+		stats:    sysstats.NewFakeStats(t).Build(),
+		hostname: hostname,
+		uptime:   time.Duration(gofakeit.Number(60, 1000000)) * time.Second,
+	}
+}
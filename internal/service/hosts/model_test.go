@@ -0,0 +1,55 @@
+package hosts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostModel(t *testing.T) {
+	now := time.Now()
+
+	t.Run("IsStale", func(t *testing.T) {
+		assert.False(t, NewFakeHost(t).Build().IsStale(now))
+		assert.False(t, NewFakeHost(t).WithLastSeen(now.Add(-5*time.Second)).Build().IsStale(now))
+		assert.True(t, NewFakeHost(t).WithLastSeen(now.Add(-time.Minute)).Build().IsStale(now))
+	})
+
+	t.Run("Report JSON round trip", func(t *testing.T) {
+		report := NewFakeReport(t, "web-1")
+
+		raw, err := json.Marshal(report)
+		require.NoError(t, err)
+
+		var res Report
+		err = json.Unmarshal(raw, &res)
+		require.NoError(t, err)
+
+		assert.Equal(t, "web-1", res.Hostname())
+		assert.Equal(t, report.Uptime(), res.Uptime())
+		assert.Equal(t, report.Stats().Time(), res.Stats().Time())
+		assert.Equal(t, report.Stats().Memory(), res.Stats().Memory())
+	})
+
+	t.Run("Report with invalid stats", func(t *testing.T) {
+		var res Report
+		err := json.Unmarshal([]byte(`{"hostname": "web-1", "uptime": 10, "stats": "AAEC"}`), &res)
+		require.Error(t, err)
+	})
+
+	t.Run("Summary FullestDisk", func(t *testing.T) {
+		host := NewFakeHost(t).Build()
+		stats := sysstats.NewFakeStats(t).WithDisks(
+			sysstats.NewFakeDisk("/", 100*datasize.GB, 60*datasize.GB),
+			sysstats.NewFakeDisk("/var", 100*datasize.GB, 10*datasize.GB),
+		).Build()
+
+		assert.Equal(t, "/var", NewFakeSummary(host, stats, nil).FullestDisk().MountPoint())
+		assert.Nil(t, NewFakeSummary(host, nil, nil).FullestDisk())
+	})
+}
@@ -0,0 +1,394 @@
+package hosts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const (
+	HostStaleAlert = "host-stale"
+	HostLabel      = "host"
+	// HostIDLabel identifies the host inside the alerts, the names aren't
+	// unique.
+	HostIDLabel = "host_id"
+
+	// statsRetention is the duration the stats pushed by the agents are
+	// kept.
+	statsRetention = 7 * 24 * time.Hour
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type storage interface {
+	Save(ctx context.Context, host *Host) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Host, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Host, error)
+	GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Host, error)
+	Patch(ctx context.Context, id uuid.UUID, fields map[string]any) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	SaveStats(ctx context.Context, hostID uuid.UUID, stats *sysstats.Stats) error
+	GetLatestStats(ctx context.Context, hostID uuid.UUID) (*sysstats.Stats, error)
+	GetLastStats(ctx context.Context, hostID uuid.UUID, limit int) ([]sysstats.Stats, error)
+	GetStatsRange(ctx context.Context, hostID uuid.UUID, start, end time.Time) ([]sysstats.Stats, error)
+	DeleteStatsBefore(ctx context.Context, before time.Time) error
+}
+
+type service struct {
+	storage     storage
+	alerts      alerts.Service
+	clock       clock.Clock
+	uuid        uuid.Service
+	watchers    []chan struct{}
+	watcherLock *sync.Mutex
+}
+
+func newService(storage storage, alerts alerts.Service, tools tools.Tools) *service {
+	return &service{
+		storage:     storage,
+		alerts:      alerts,
+		clock:       tools.Clock(),
+		uuid:        tools.UUID(),
+		watchers:    []chan struct{}{},
+		watcherLock: new(sync.Mutex),
+	}
+}
+
+func (s *service) SQLHookName() string {
+	return "hosts-svc"
+}
+
+// RunSQLHook run as a hook for any db update (insert, update, delete).
+func (s *service) RunSQLHook(ctx context.Context, table string) error {
+	if table != hostsTableName && table != statsTableName {
+		return nil
+	}
+
+	s.watcherLock.Lock()
+	defer s.watcherLock.Unlock()
+
+	// Skip the event if one is already waiting to be processed.
+	for _, watcher := range s.watchers {
+		select {
+		case watcher <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (s *service) Watch(ctx context.Context) chan struct{} {
+	c := make(chan struct{}, 1)
+
+	go func() {
+		<-ctx.Done()
+
+		s.watcherLock.Lock()
+		defer s.watcherLock.Unlock()
+		s.watchers = slices.DeleteFunc(s.watchers, func(n chan struct{}) bool {
+			return n == c
+		})
+		close(c)
+	}()
+
+	s.watcherLock.Lock()
+	defer s.watcherLock.Unlock()
+	s.watchers = append(s.watchers, c)
+
+	return c
+}
+
+func (s *service) Create(ctx context.Context, cmd *CreateCmd) (*Host, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	id := s.uuid.New()
+	token := secret.NewText(string(s.uuid.New()))
+
+	host := Host{
+		id:         id,
+		name:       cmd.Name,
+		token:      token,
+		tokenHash:  token.Hash(),
+		hostname:   "",
+		uptime:     0,
+		lastSeenAt: nil,
+		tags:       []string{},
+		createdAt:  s.clock.Now(),
+		createdBy:  cmd.CreatedBy.ID(),
+	}
+
+	err = s.storage.Save(ctx, &host)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to save the host: %w", err))
+	}
+
+	return &host, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*Host, error) {
+	res, err := s.storage.GetByID(ctx, id)
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Host, error) {
+	res, err := s.storage.GetAll(ctx, cmd)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+	host, err := s.GetByID(ctx, id)
+	if errors.Is(err, errs.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Delete(ctx, id)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Delete: %w", err))
+	}
+
+	err = s.alerts.Resolve(ctx, staleResolveCmd(host))
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to resolve the alert: %w", err))
+	}
+
+	return nil
+}
+
+// RegenerateToken replaces the host token, the previous one is revoked.
+func (s *service) RegenerateToken(ctx context.Context, host *Host) (*Host, error) {
+	token := secret.NewText(string(s.uuid.New()))
+
+	err := s.storage.Patch(ctx, host.id, map[string]any{"token_hash": token.Hash()})
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to patch the host: %w", err))
+	}
+
+	res := *host
+	res.token = token
+	res.tokenHash = token.Hash()
+
+	return &res, nil
+}
+
+func (s *service) UpdateTags(ctx context.Context, cmd *UpdateTagsCmd) (*Host, error) {
+	tags := []string{}
+	for _, tag := range cmd.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	cmd = &UpdateTagsCmd{Host: cmd.Host, Tags: tags}
+
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	rawTags, err := marshalTags(tags)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	err = s.storage.Patch(ctx, cmd.Host.id, map[string]any{"tags": rawTags})
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to Patch: %w", err))
+	}
+
+	host := *cmd.Host
+	host.tags = tags
+
+	return &host, nil
+}
+
+func (s *service) Push(ctx context.Context, cmd *PushCmd) error {
+	err := cmd.Validate()
+	if err != nil {
+		return errs.Validation(err)
+	}
+
+	host, err := s.storage.GetByTokenHash(ctx, cmd.Token.Hash())
+	if errors.Is(err, errNotFound) {
+		return errs.Unauthorized(ErrInvalidToken)
+	}
+
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to get the host: %w", err))
+	}
+
+	now := s.clock.Now()
+
+	err = s.storage.SaveStats(ctx, host.id, cmd.Report.stats)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to save the stats: %w", err))
+	}
+
+	err = s.storage.Patch(ctx, host.id, map[string]any{
+		"hostname":     cmd.Report.hostname,
+		"uptime":       int64(cmd.Report.uptime.Seconds()),
+		"last_seen_at": ptr.To(sqlstorage.SQLTime(now)),
+	})
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to patch the host: %w", err))
+	}
+
+	if host.IsStale(now) {
+		err = s.alerts.Resolve(ctx, staleResolveCmd(host))
+		if err != nil {
+			return errs.Internal(fmt.Errorf("failed to resolve the alert: %w", err))
+		}
+	}
+
+	return nil
+}
+
+// purgeStats deletes the stats older than statsRetention.
+func (s *service) purgeStats(ctx context.Context) error {
+	err := s.storage.DeleteStatsBefore(ctx, s.clock.Now().Add(-statsRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete the old stats: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) GetSummaries(ctx context.Context) ([]Summary, error) {
+	hosts, err := s.storage.GetAll(ctx, nil)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to GetAll: %w", err))
+	}
+
+	firing, err := s.alerts.GetFiring(ctx)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to get the firing alerts: %w", err))
+	}
+
+	res := make([]Summary, len(hosts))
+	for i, host := range hosts {
+		res[i] = Summary{host: host}
+
+		for _, alert := range firing {
+			if alert.Labels()[HostIDLabel] == string(host.id) {
+				res[i].alerts = append(res[i].alerts, alert)
+			}
+		}
+
+		stats, err := s.storage.GetLastStats(ctx, host.id, 2)
+		if err != nil {
+			return nil, errs.Internal(fmt.Errorf("failed to get the stats of %q: %w", host.name, err))
+		}
+
+		if len(stats) > 0 {
+			res[i].stats = &stats[0]
+		}
+
+		if len(stats) > 1 {
+			res[i].cpu = stats[0].CPUPercent(&stats[1])
+		}
+	}
+
+	return res, nil
+}
+
+// markStale fires an alert for each host which stopped to push.
+func (s *service) markStale(ctx context.Context) error {
+	hosts, err := s.storage.GetAll(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to GetAll: %w", err)
+	}
+
+	now := s.clock.Now()
+
+	for _, host := range hosts {
+		if !host.IsStale(now) {
+			continue
+		}
+
+		_, err = s.alerts.Fire(ctx, &alerts.FireCmd{
+			Name:     HostStaleAlert,
+			Labels:   staleLabels(&host),
+			Severity: notifications.Critical,
+			Summary:  fmt.Sprintf("%s is stale: no stats received since %s", host.name, host.lastSeenAt.Format(time.DateTime)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Fire: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func staleResolveCmd(host *Host) *alerts.ResolveCmd {
+	return &alerts.ResolveCmd{
+		Name:   HostStaleAlert,
+		Labels: staleLabels(host),
+	}
+}
+
+func staleLabels(host *Host) map[string]string {
+	return map[string]string{
+		HostLabel:   host.name,
+		HostIDLabel: string(host.id),
+	}
+}
+
+func (s *service) GetLatestStats(ctx context.Context, host *Host) (*sysstats.Stats, error) {
+	res, err := s.storage.GetLatestStats(ctx, host.id)
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) GetStatsRange(ctx context.Context, host *Host, start, end time.Time) ([]sysstats.Stats, error) {
+	res, err := s.storage.GetStatsRange(ctx, host.id, start, end)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
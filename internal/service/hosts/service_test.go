@@ -0,0 +1,346 @@
+package hosts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostsService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		// Data
+		user := users.NewFakeUser(t).Build()
+		now := time.Now()
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-host-id")).Once()
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-token")).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("Save", mock.Anything, mock.AnythingOfType("*hosts.Host")).Return(nil).Once()
+
+		// Run
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: user,
+			Name:      "web-1",
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, uuid.UUID("some-host-id"), res.ID())
+		assert.Equal(t, "web-1", res.Name())
+		assert.Equal(t, "some-token", res.Token().Raw())
+		assert.Equal(t, secret.NewText("some-token").Hash(), res.tokenHash)
+		assert.Nil(t, res.LastSeenAt())
+		assert.Empty(t, res.Tags())
+		assert.Equal(t, user.ID(), res.CreatedBy())
+		assert.Equal(t, now, res.CreatedAt())
+	})
+
+	t.Run("Create with an empty name", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), alerts.NewMockService(t), tools.NewMock(t))
+
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: users.NewFakeUser(t).Build(),
+			Name:      "",
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools.NewMock(t))
+
+		storageMock.On("GetByID", mock.Anything, uuid.UUID("some-id")).Return(nil, errNotFound).Once()
+
+		res, err := svc.GetByID(ctx, uuid.UUID("some-id"))
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("Push success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		// Data
+		host := NewFakeHost(t).Build()
+		report := NewFakeReport(t, "web-1.example.com")
+		now := time.Now()
+
+		// Mocks
+		storageMock.On("GetByTokenHash", mock.Anything, host.Token().Hash()).Return(host, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("SaveStats", mock.Anything, host.ID(), report.Stats()).Return(nil).Once()
+		storageMock.On("Patch", mock.Anything, host.ID(), map[string]any{
+			"hostname":     "web-1.example.com",
+			"uptime":       int64(report.Uptime().Seconds()),
+			"last_seen_at": ptr.To(sqlstorage.SQLTime(now)),
+		}).Return(nil).Once()
+
+		// Run
+		err := svc.Push(ctx, &PushCmd{Token: host.Token(), Report: report})
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("purgeStats deletes the old stats", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		// Data
+		now := time.Now()
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("DeleteStatsBefore", mock.Anything, now.Add(-statsRetention)).Return(nil).Once()
+
+		// Run
+		err := svc.purgeStats(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("Push resolves the stale alert", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(storageMock, alertsMock, tools)
+
+		// Data
+		now := time.Now()
+		host := NewFakeHost(t).WithLastSeen(now.Add(-time.Hour)).Build()
+		report := NewFakeReport(t, "web-1")
+
+		// Mocks
+		storageMock.On("GetByTokenHash", mock.Anything, host.Token().Hash()).Return(host, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("SaveStats", mock.Anything, host.ID(), report.Stats()).Return(nil).Once()
+		storageMock.On("Patch", mock.Anything, host.ID(), mock.Anything).Return(nil).Once()
+		alertsMock.On("Resolve", mock.Anything, &alerts.ResolveCmd{
+			Name:   HostStaleAlert,
+			Labels: map[string]string{HostLabel: host.Name(), HostIDLabel: string(host.ID())},
+		}).Return(nil).Once()
+
+		// Run
+		err := svc.Push(ctx, &PushCmd{Token: host.Token(), Report: report})
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("Push with an unknown token", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools.NewMock(t))
+
+		storageMock.On("GetByTokenHash", mock.Anything, secret.NewText("some-token").Hash()).Return(nil, errNotFound).Once()
+
+		err := svc.Push(ctx, &PushCmd{
+			Token:  secret.NewText("some-token"),
+			Report: NewFakeReport(t, "web-1"),
+		})
+		require.ErrorIs(t, err, errs.ErrUnauthorized)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Push without stats", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), alerts.NewMockService(t), tools.NewMock(t))
+
+		err := svc.Push(ctx, &PushCmd{
+			Token:  secret.NewText("some-token"),
+			Report: NewReport("web-1", time.Hour, nil),
+		})
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Push with a storage error", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		host := NewFakeHost(t).Build()
+		report := NewFakeReport(t, "web-1")
+
+		storageMock.On("GetByTokenHash", mock.Anything, host.Token().Hash()).Return(host, nil).Once()
+		tools.ClockMock.On("Now").Return(time.Now()).Once()
+		storageMock.On("SaveStats", mock.Anything, host.ID(), report.Stats()).Return(fmt.Errorf("some-error")).Once()
+
+		err := svc.Push(ctx, &PushCmd{Token: host.Token(), Report: report})
+		require.ErrorIs(t, err, errs.ErrInternal)
+		require.ErrorContains(t, err, "some-error")
+	})
+
+	t.Run("GetLatestStats without any push", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools.NewMock(t))
+
+		host := NewFakeHost(t).Build()
+
+		storageMock.On("GetLatestStats", mock.Anything, host.ID()).Return(nil, errNotFound).Once()
+
+		res, err := svc.GetLatestStats(ctx, host)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("RegenerateToken success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools)
+
+		host := NewFakeHost(t).Build()
+
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-new-token")).Once()
+		storageMock.On("Patch", mock.Anything, host.ID(), map[string]any{"token_hash": secret.NewText("some-new-token").Hash()}).Return(nil).Once()
+
+		res, err := svc.RegenerateToken(ctx, host)
+		require.NoError(t, err)
+		assert.Equal(t, host.ID(), res.ID())
+		assert.Equal(t, "some-new-token", res.Token().Raw())
+	})
+
+	t.Run("UpdateTags success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, alerts.NewMockService(t), tools.NewMock(t))
+
+		host := NewFakeHost(t).Build()
+
+		storageMock.On("Patch", mock.Anything, host.ID(), map[string]any{"tags": `["db","prod"]`}).Return(nil).Once()
+
+		res, err := svc.UpdateTags(ctx, &UpdateTagsCmd{Host: host, Tags: []string{" Prod", "db", "", "prod"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"db", "prod"}, res.Tags())
+	})
+
+	t.Run("UpdateTags with an invalid tag", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), alerts.NewMockService(t), tools.NewMock(t))
+
+		res, err := svc.UpdateTags(ctx, &UpdateTagsCmd{Host: NewFakeHost(t).Build(), Tags: []string{"not a tag"}})
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("GetSummaries success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(storageMock, alertsMock, tools.NewMock(t))
+
+		// Data
+		now := time.Now()
+		web := NewFakeHost(t).WithName("web").WithLastSeen(now).Build()
+		db := NewFakeHost(t).WithName("db").Build()
+		prev := sysstats.NewFakeStats(t).WithTime(now.Add(-5 * time.Second)).WithCPU(sysstats.NewFakeCPU(1000, 10000)).Build()
+		latest := sysstats.NewFakeStats(t).WithTime(now).WithCPU(sysstats.NewFakeCPU(1500, 12000)).Build()
+		webAlert := alerts.NewFakeAlert(t).WithLabels(map[string]string{HostLabel: "web", HostIDLabel: string(web.ID())}).Build()
+		// Another host with the same name.
+		otherAlert := alerts.NewFakeAlert(t).WithLabels(map[string]string{HostLabel: "web", HostIDLabel: "other-id"}).Build()
+
+		// Mocks
+		storageMock.On("GetAll", mock.Anything, (*sqlstorage.PaginateCmd)(nil)).Return([]Host{*web, *db}, nil).Once()
+		alertsMock.On("GetFiring", mock.Anything).Return([]alerts.Alert{*webAlert, *otherAlert}, nil).Once()
+		storageMock.On("GetLastStats", mock.Anything, web.ID(), 2).Return([]sysstats.Stats{*latest, *prev}, nil).Once()
+		storageMock.On("GetLastStats", mock.Anything, db.ID(), 2).Return([]sysstats.Stats{}, nil).Once()
+
+		// Run
+		res, err := svc.GetSummaries(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		assert.Equal(t, *web, res[0].Host())
+		assert.Equal(t, latest, res[0].Stats())
+		require.NotNil(t, res[0].CPU())
+		assert.InDelta(t, 25.0, *res[0].CPU(), 0.001)
+		assert.Equal(t, []alerts.Alert{*webAlert}, res[0].Alerts())
+
+		assert.Equal(t, *db, res[1].Host())
+		assert.Nil(t, res[1].Stats())
+		assert.Nil(t, res[1].CPU())
+		assert.Empty(t, res[1].Alerts())
+	})
+
+	t.Run("markStale success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(storageMock, alertsMock, tools)
+
+		// Data
+		now := time.Now()
+		stale := NewFakeHost(t).WithName("stale").WithLastSeen(now.Add(-time.Minute)).Build()
+		up := NewFakeHost(t).WithLastSeen(now).Build()
+		neverSeen := NewFakeHost(t).Build()
+
+		// Mocks
+		storageMock.On("GetAll", mock.Anything, (*sqlstorage.PaginateCmd)(nil)).Return([]Host{*stale, *up, *neverSeen}, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		alertsMock.On("Fire", mock.Anything, mock.MatchedBy(func(cmd *alerts.FireCmd) bool {
+			return cmd.Name == HostStaleAlert && cmd.Labels[HostIDLabel] == string(stale.ID())
+		})).Return(alerts.NewFakeAlert(t).Build(), nil).Once()
+
+		// Run
+		err := svc.markStale(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("Delete success", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		alertsMock := alerts.NewMockService(t)
+		svc := newService(storageMock, alertsMock, tools.NewMock(t))
+
+		host := NewFakeHost(t).Build()
+
+		storageMock.On("GetByID", mock.Anything, host.ID()).Return(host, nil).Once()
+		storageMock.On("Delete", mock.Anything, host.ID()).Return(nil).Once()
+		alertsMock.On("Resolve", mock.Anything, &alerts.ResolveCmd{
+			Name:   HostStaleAlert,
+			Labels: map[string]string{HostLabel: host.Name(), HostIDLabel: string(host.ID())},
+		}).Return(nil).Once()
+
+		err := svc.Delete(ctx, host.ID())
+		require.NoError(t, err)
+	})
+}
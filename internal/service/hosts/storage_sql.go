@@ -0,0 +1,328 @@
+package hosts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const (
+	hostsTableName = "hosts"
+	statsTableName = "host_stats"
+)
+
+var errNotFound = errors.New("not found")
+
+var (
+	allHostFields  = []string{"id", "name", "token_hash", "hostname", "uptime", "last_seen_at", "tags", "created_at", "created_by"}
+	allStatsFields = []string{"host_id", "time", "content"}
+)
+
+type sqlStorage struct {
+	db *sql.DB
+}
+
+func newSqlStorage(db *sql.DB) *sqlStorage {
+	return &sqlStorage{db}
+}
+
+func (s *sqlStorage) Save(ctx context.Context, h *Host) error {
+	rawTags, err := marshalTags(h.tags)
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.
+		Insert(hostsTableName).
+		Columns(allHostFields...).
+		Values(
+			h.id,
+			h.name,
+			h.tokenHash,
+			h.hostname,
+			int64(h.uptime.Seconds()),
+			optionalTime(h.lastSeenAt),
+			rawTags,
+			ptr.To(sqlstorage.SQLTime(h.createdAt)),
+			h.createdBy,
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetByID(ctx context.Context, id uuid.UUID) (*Host, error) {
+	return s.getByKeys(ctx, sq.Eq{"id": id})
+}
+
+func (s *sqlStorage) GetByTokenHash(ctx context.Context, tokenHash string) (*Host, error) {
+	return s.getByKeys(ctx, sq.Eq{"token_hash": tokenHash})
+}
+
+func (s *sqlStorage) getByKeys(ctx context.Context, wheres ...any) (*Host, error) {
+	query := sq.
+		Select(allHostFields...).
+		From(hostsTableName)
+
+	for _, where := range wheres {
+		query = query.Where(where)
+	}
+
+	res, err := s.scanHost(query.RunWith(s.db).QueryRowContext(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Host, error) {
+	rows, err := sqlstorage.PaginateSelection(sq.
+		Select(allHostFields...).
+		From(hostsTableName), cmd).
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	hosts := []Host{}
+
+	for rows.Next() {
+		res, err := s.scanHost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		hosts = append(hosts, *res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return hosts, nil
+}
+
+func (s *sqlStorage) Patch(ctx context.Context, id uuid.UUID, fields map[string]any) error {
+	_, err := sq.Update(hostsTableName).
+		SetMap(fields).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+// Delete the host and all its stats.
+func (s *sqlStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := sq.
+		Delete(statsTableName).
+		Where(sq.Eq{"host_id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete the stats: %w", err)
+	}
+
+	_, err = sq.
+		Delete(hostsTableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+// SaveStats saves the stats pushed by the host. A second push for the same
+// second replaces the first one.
+func (s *sqlStorage) SaveStats(ctx context.Context, hostID uuid.UUID, stats *sysstats.Stats) error {
+	rawStats, err := stats.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal the stats: %w", err)
+	}
+
+	_, err = sq.
+		Insert(statsTableName).
+		Columns(allStatsFields...).
+		Values(hostID, stats.Time().Unix(), rawStats).
+		Suffix("ON CONFLICT(host_id, time) DO UPDATE SET content = excluded.content").
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetLatestStats(ctx context.Context, hostID uuid.UUID) (*sysstats.Stats, error) {
+	res, err := s.GetLastStats(ctx, hostID, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, errNotFound
+	}
+
+	return &res[0], nil
+}
+
+// GetLastStats returns the last stats of the host, the most recent first.
+func (s *sqlStorage) GetLastStats(ctx context.Context, hostID uuid.UUID, limit int) ([]sysstats.Stats, error) {
+	rows, err := sq.
+		Select("content").
+		From(statsTableName).
+		Where(sq.Eq{"host_id": hostID}).
+		OrderBy("time DESC").
+		Limit(uint64(limit)).
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return scanStats(rows)
+}
+
+func (s *sqlStorage) GetStatsRange(ctx context.Context, hostID uuid.UUID, start, end time.Time) ([]sysstats.Stats, error) {
+	rows, err := sq.
+		Select("content").
+		From(statsTableName).
+		Where(sq.And{sq.Eq{"host_id": hostID}, sq.Gt{"time": start.Unix()}, sq.LtOrEq{"time": end.Unix()}}).
+		OrderBy("time ASC").
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return scanStats(rows)
+}
+
+func (s *sqlStorage) DeleteStatsBefore(ctx context.Context, before time.Time) error {
+	_, err := sq.
+		Delete(statsTableName).
+		Where(sq.Lt{"time": before.Unix()}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func scanStats(rows *sql.Rows) ([]sysstats.Stats, error) {
+	defer rows.Close()
+
+	res := []sysstats.Stats{}
+
+	for rows.Next() {
+		var rawContent []byte
+		var stats sysstats.Stats
+
+		err := rows.Scan(&rawContent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		err = stats.UnmarshalBinary(rawContent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the stats: %w", err)
+		}
+
+		res = append(res, stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) scanHost(row sqlstorage.RowScanner) (*Host, error) {
+	var res Host
+	var uptime int64
+	var sqlLastSeenAt *sqlstorage.SQLTime
+	var sqlCreatedAt sqlstorage.SQLTime
+	var rawTags string
+
+	err := row.Scan(
+		&res.id,
+		&res.name,
+		&res.tokenHash,
+		&res.hostname,
+		&uptime,
+		&sqlLastSeenAt,
+		&rawTags,
+		&sqlCreatedAt,
+		&res.createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res.uptime = time.Duration(uptime) * time.Second
+
+	err = json.Unmarshal([]byte(rawTags), &res.tags)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tags: %w", err)
+	}
+	res.createdAt = sqlCreatedAt.Time()
+
+	if sqlLastSeenAt != nil {
+		res.lastSeenAt = ptr.To(sqlLastSeenAt.Time())
+	}
+
+	return &res, nil
+}
+
+// marshalTags returns the tags as a JSON array, the format of the tags
+// column.
+func marshalTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	raw, err := json.Marshal(tags)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the tags: %w", err)
+	}
+
+	return string(raw), nil
+}
+
+func optionalTime(t *time.Time) *sqlstorage.SQLTime {
+	if t == nil {
+		return nil
+	}
+
+	return ptr.To(sqlstorage.SQLTime(*t))
+}
@@ -0,0 +1,143 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostsSqlStorage(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqlstorage.NewTestStorage(t)
+	store := newSqlStorage(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	host := NewFakeHost(t).WithTags("db", "prod").Build()
+	// Only the token hash is saved.
+	storedHost := *host
+	storedHost.token = secret.Text{}
+	oldStats := sysstats.NewFakeStats(t).WithTime(now.Add(-time.Hour)).Build()
+	latestStats := sysstats.NewFakeStats(t).WithTime(now).Build()
+
+	t.Run("GetAll with nothing", func(t *testing.T) {
+		res, err := store.GetAll(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Save success", func(t *testing.T) {
+		err := store.Save(ctx, host)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetByID success", func(t *testing.T) {
+		res, err := store.GetByID(ctx, host.ID())
+		require.NoError(t, err)
+		assert.Equal(t, &storedHost, res)
+	})
+
+	t.Run("GetByTokenHash success", func(t *testing.T) {
+		res, err := store.GetByTokenHash(ctx, host.Token().Hash())
+		require.NoError(t, err)
+		assert.Equal(t, &storedHost, res)
+	})
+
+	t.Run("GetByTokenHash not found", func(t *testing.T) {
+		res, err := store.GetByTokenHash(ctx, NewFakeHost(t).Build().Token().Hash())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("Patch success", func(t *testing.T) {
+		err := store.Patch(ctx, host.ID(), map[string]any{
+			"hostname":     "web-1",
+			"uptime":       3600,
+			"last_seen_at": ptr.To(sqlstorage.SQLTime(now)),
+		})
+		require.NoError(t, err)
+
+		res, err := store.GetByID(ctx, host.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "web-1", res.Hostname())
+		assert.Equal(t, time.Hour, res.Uptime())
+		assert.Equal(t, &now, res.LastSeenAt())
+		assert.Equal(t, []string{"db", "prod"}, res.Tags())
+	})
+
+	t.Run("Patch the tags", func(t *testing.T) {
+		err := store.Patch(ctx, host.ID(), map[string]any{"tags": `["web"]`})
+		require.NoError(t, err)
+
+		res, err := store.GetByID(ctx, host.ID())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"web"}, res.Tags())
+	})
+
+	t.Run("GetLatestStats not found", func(t *testing.T) {
+		res, err := store.GetLatestStats(ctx, host.ID())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("SaveStats success", func(t *testing.T) {
+		require.NoError(t, store.SaveStats(ctx, host.ID(), oldStats))
+		require.NoError(t, store.SaveStats(ctx, host.ID(), latestStats))
+
+		// A second push for the same second replaces the first one.
+		require.NoError(t, store.SaveStats(ctx, host.ID(), latestStats))
+	})
+
+	t.Run("GetLatestStats success", func(t *testing.T) {
+		res, err := store.GetLatestStats(ctx, host.ID())
+		require.NoError(t, err)
+		assert.Equal(t, latestStats.Time(), res.Time())
+		assert.Equal(t, latestStats.Memory(), res.Memory())
+	})
+
+	t.Run("GetLastStats success", func(t *testing.T) {
+		res, err := store.GetLastStats(ctx, host.ID(), 2)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Equal(t, latestStats.Time(), res[0].Time())
+		assert.Equal(t, oldStats.Time(), res[1].Time())
+	})
+
+	t.Run("GetStatsRange success", func(t *testing.T) {
+		res, err := store.GetStatsRange(ctx, host.ID(), now.Add(-2*time.Hour), now)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Equal(t, oldStats.Time(), res[0].Time())
+		assert.Equal(t, latestStats.Time(), res[1].Time())
+	})
+
+	t.Run("DeleteStatsBefore success", func(t *testing.T) {
+		err := store.DeleteStatsBefore(ctx, now.Add(-time.Minute))
+		require.NoError(t, err)
+
+		res, err := store.GetStatsRange(ctx, host.ID(), now.Add(-2*time.Hour), now)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, latestStats.Time(), res[0].Time())
+	})
+
+	t.Run("Delete success", func(t *testing.T) {
+		err := store.Delete(ctx, host.ID())
+		require.NoError(t, err)
+
+		res, err := store.GetByID(ctx, host.ID())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+
+		stats, err := store.GetLatestStats(ctx, host.ID())
+		assert.Nil(t, stats)
+		require.ErrorIs(t, err, errNotFound)
+	})
+}
@@ -0,0 +1,45 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"os/exec"
+
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/spf13/afero"
+)
+
+type Service interface {
+	// SourceName returns "journal" or "files".
+	SourceName() string
+	// Search returns the last entries matching the filter, oldest first.
+	Search(ctx context.Context, filter *Filter, limit int) ([]Entry, error)
+	// Tail streams the new entries matching the filter until the context is
+	// canceled. The time range of the filter is ignored.
+	Tail(ctx context.Context, filter *Filter) (<-chan Entry, error)
+}
+
+// journalDirs are the persistent and the volatile journal storages.
+var journalDirs = []string{"/var/log/journal", "/run/log/journal"}
+
+// Init reads the systemd journal with journalctl if there is one and falls
+// back on the /var/log/*.log files otherwise.
+func Init(fs afero.Fs, tools tools.Tools) Service {
+	path, err := exec.LookPath("journalctl")
+	if err == nil && hasJournal(fs) {
+		return newService(newJournalSource(path))
+	}
+
+	return newService(newFilesSource(fs, defaultLogDir, tools.Clock(), tools.Logger().With(slog.String("source", "logs"))))
+}
+
+func hasJournal(fs afero.Fs) bool {
+	for _, dir := range journalDirs {
+		entries, err := afero.ReadDir(fs, dir)
+		if err == nil && len(entries) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
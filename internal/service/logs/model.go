@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	v "github.com/go-ozzo/ozzo-validation"
+)
+
+// Priority is the syslog severity of an entry, from 0 (emerg) to 7 (debug).
+type Priority int
+
+const (
+	Emergency Priority = iota
+	Alert
+	Critical
+	Error
+	Warning
+	Notice
+	Info
+	Debug
+)
+
+var AllPriorities = []Priority{Emergency, Alert, Critical, Error, Warning, Notice, Info, Debug}
+
+var priorityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+func (p Priority) String() string {
+	if p < Emergency || p > Debug {
+		return "unknown"
+	}
+
+	return priorityNames[p]
+}
+
+// ParsePriority parses either a priority name ("err") or its value ("3").
+func ParsePriority(s string) (Priority, error) {
+	for i, name := range priorityNames {
+		if s == name || (len(s) == 1 && s[0] == byte('0'+i)) {
+			return Priority(i), nil
+		}
+	}
+
+	return 0, errInvalidPriority
+}
+
+var errInvalidPriority = errors.New("invalid priority")
+
+// Entry is a single log line.
+type Entry struct {
+	at       time.Time
+	unit     string
+	ident    string
+	message  string
+	priority Priority
+}
+
+func (e Entry) At() time.Time { return e.at }
+
+// Unit is the systemd unit which produced the entry. For the files source
+// it's the name of the log file without the ".log" extension.
+func (e Entry) Unit() string { return e.unit }
+
+// Ident is the syslog identifier, usually the program name.
+func (e Entry) Ident() string      { return e.ident }
+func (e Entry) Message() string    { return e.message }
+func (e Entry) Priority() Priority { return e.priority }
+
+// Filter selects the entries to return. All the fields are optional.
+type Filter struct {
+	Since time.Time
+	Until time.Time
+	// Unit matches either the unit or the syslog identifier.
+	Unit string
+	// Search is a case insensitive text to find in the message.
+	Search string
+	// Priority is the least important priority to return. Nil means all.
+	Priority *Priority
+}
+
+func (t Filter) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Until, v.By(func(any) error {
+			if !t.Since.IsZero() && !t.Until.IsZero() && t.Until.Before(t.Since) {
+				return errors.New("must be after the start")
+			}
+			return nil
+		})),
+		v.Field(&t.Unit, v.Length(0, 256)),
+		v.Field(&t.Search, v.Length(0, 256)),
+		v.Field(&t.Priority, v.Min(Emergency), v.Max(Debug)),
+	)
+}
+
+// Match reports whether the entry matches all the filter fields.
+func (t Filter) Match(e *Entry) bool {
+	if !t.Since.IsZero() && e.at.Before(t.Since) {
+		return false
+	}
+
+	if !t.Until.IsZero() && e.at.After(t.Until) {
+		return false
+	}
+
+	if t.Unit != "" && e.unit != t.Unit && e.ident != t.Unit {
+		return false
+	}
+
+	if t.Priority != nil && e.priority > *t.Priority {
+		return false
+	}
+
+	if t.Search != "" && !strings.Contains(strings.ToLower(e.message), strings.ToLower(t.Search)) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,59 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type FakeEntryBuilder struct {
+	t     testing.TB
+	entry *Entry
+}
+
+func NewFakeEntry(t testing.TB) *FakeEntryBuilder {
+	t.Helper()
+
+	ident := strings.ToLower(gofakeit.AppName())
+
+	return &FakeEntryBuilder{
+		t: t,
+		entry: &Entry{
+			at:       gofakeit.DateRange(time.Now().Add(-time.Hour), time.Now()).UTC(),
+			unit:     ident + ".service",
+			ident:    ident,
+			message:  gofakeit.Sentence(8),
+			priority: AllPriorities[gofakeit.Number(0, len(AllPriorities)-1)],
+		},
+	}
+}
+
+func (f *FakeEntryBuilder) WithTime(at time.Time) *FakeEntryBuilder {
+	f.entry.at = at
+
+	return f
+}
+
+func (f *FakeEntryBuilder) WithUnit(unit string) *FakeEntryBuilder {
+	f.entry.unit = unit
+
+	return f
+}
+
+func (f *FakeEntryBuilder) WithMessage(message string) *FakeEntryBuilder {
+	f.entry.message = message
+
+	return f
+}
+
+func (f *FakeEntryBuilder) WithPriority(priority Priority) *FakeEntryBuilder {
+	f.entry.priority = priority
+
+	return f
+}
+
+func (f *FakeEntryBuilder) Build() *Entry {
+	return f.entry
+}
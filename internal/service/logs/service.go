@@ -0,0 +1,72 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+)
+
+// MaxLimit is the maximum number of entries returned by Search.
+const MaxLimit = 1000
+
+// source reads the entries from a log backend.
+type source interface {
+	Name() string
+	// Read returns the last limit entries matching the filter, oldest
+	// first.
+	Read(ctx context.Context, filter *Filter, limit int) ([]Entry, error)
+	// Follow streams the new entries matching the filter until the context
+	// is canceled. The channel is closed when the streaming stops.
+	Follow(ctx context.Context, filter *Filter) (<-chan Entry, error)
+}
+
+type service struct {
+	source source
+}
+
+func newService(source source) *service {
+	return &service{source: source}
+}
+
+func (s *service) SourceName() string {
+	return s.source.Name()
+}
+
+func (s *service) Search(ctx context.Context, filter *Filter, limit int) ([]Entry, error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	if limit <= 0 || limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	res, err := s.source.Read(ctx, filter, limit)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to read the %s logs: %w", s.source.Name(), err))
+	}
+
+	return res, nil
+}
+
+func (s *service) Tail(ctx context.Context, filter *Filter) (<-chan Entry, error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	// The tail only returns the new entries, the time range is meaningless.
+	tailFilter := *filter
+	tailFilter.Since = time.Time{}
+	tailFilter.Until = time.Time{}
+
+	res, err := s.source.Follow(ctx, &tailFilter)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to follow the %s logs: %w", s.source.Name(), err))
+	}
+
+	return res, nil
+}
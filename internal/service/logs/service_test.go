@@ -0,0 +1,143 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Search success", func(t *testing.T) {
+		t.Parallel()
+		sourceMock := newMockSource(t)
+		svc := newService(sourceMock)
+
+		// Data
+		filter := Filter{Unit: "nginx.service", Priority: ptr.To(Warning)}
+		entry := NewFakeEntry(t).WithUnit("nginx.service").WithPriority(Error).Build()
+
+		// Mocks
+		sourceMock.On("Read", mock.Anything, &filter, 100).Return([]Entry{*entry}, nil).Once()
+
+		// Run
+		res, err := svc.Search(ctx, &filter, 100)
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, []Entry{*entry}, res)
+	})
+
+	t.Run("Search caps the limit", func(t *testing.T) {
+		t.Parallel()
+		sourceMock := newMockSource(t)
+		svc := newService(sourceMock)
+
+		sourceMock.On("Read", mock.Anything, &Filter{}, MaxLimit).Return([]Entry{}, nil).Once()
+
+		res, err := svc.Search(ctx, &Filter{}, MaxLimit+1)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Search with an invalid time range", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockSource(t))
+
+		now := time.Now()
+		res, err := svc.Search(ctx, &Filter{Since: now, Until: now.Add(-time.Hour)}, 100)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Search with a source error", func(t *testing.T) {
+		t.Parallel()
+		sourceMock := newMockSource(t)
+		svc := newService(sourceMock)
+
+		sourceMock.On("Read", mock.Anything, &Filter{}, 10).Return(nil, fmt.Errorf("some-error")).Once()
+		sourceMock.On("Name").Return("journal").Once()
+
+		res, err := svc.Search(ctx, &Filter{}, 10)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrInternal)
+		require.ErrorContains(t, err, "some-error")
+	})
+
+	t.Run("Tail drops the time range", func(t *testing.T) {
+		t.Parallel()
+		sourceMock := newMockSource(t)
+		svc := newService(sourceMock)
+
+		// Data
+		now := time.Now()
+		entryCh := make(chan Entry)
+
+		// Mocks
+		sourceMock.On("Follow", mock.Anything, &Filter{Search: "oom"}).Return((<-chan Entry)(entryCh), nil).Once()
+
+		// Run
+		res, err := svc.Tail(ctx, &Filter{Since: now.Add(-time.Hour), Until: now, Search: "oom"})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, (<-chan Entry)(entryCh), res)
+	})
+}
+
+func TestFilter(t *testing.T) {
+	now := time.Now()
+	entry := NewFakeEntry(t).
+		WithTime(now).
+		WithUnit("nginx.service").
+		WithPriority(Warning).
+		WithMessage("Out of memory: Killed process 42").
+		Build()
+
+	tests := []struct {
+		Name     string
+		Filter   Filter
+		Expected bool
+	}{
+		{Name: "empty", Filter: Filter{}, Expected: true},
+		{Name: "in the time range", Filter: Filter{Since: now.Add(-time.Minute), Until: now.Add(time.Minute)}, Expected: true},
+		{Name: "before the time range", Filter: Filter{Since: now.Add(time.Minute)}, Expected: false},
+		{Name: "after the time range", Filter: Filter{Until: now.Add(-time.Minute)}, Expected: false},
+		{Name: "same unit", Filter: Filter{Unit: "nginx.service"}, Expected: true},
+		{Name: "same ident", Filter: Filter{Unit: entry.Ident()}, Expected: true},
+		{Name: "other unit", Filter: Filter{Unit: "cron.service"}, Expected: false},
+		{Name: "important enough", Filter: Filter{Priority: ptr.To(Warning)}, Expected: true},
+		{Name: "not important enough", Filter: Filter{Priority: ptr.To(Error)}, Expected: false},
+		{Name: "search ignore the case", Filter: Filter{Search: "out of MEMORY"}, Expected: true},
+		{Name: "search not found", Filter: Filter{Search: "segfault"}, Expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, test.Filter.Match(entry))
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for _, priority := range AllPriorities {
+		res, err := ParsePriority(priority.String())
+		require.NoError(t, err)
+		assert.Equal(t, priority, res)
+
+		res, err = ParsePriority(fmt.Sprint(int(priority)))
+		require.NoError(t, err)
+		assert.Equal(t, priority, res)
+	}
+
+	_, err := ParsePriority("8")
+	require.ErrorIs(t, err, errInvalidPriority)
+}
@@ -0,0 +1,278 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/spf13/afero"
+)
+
+const (
+	defaultLogDir = "/var/log"
+
+	// filesPollInterval is the interval between two checks of the files
+	// size while following them.
+	filesPollInterval = time.Second
+)
+
+// syslogLine matches the "<time> <host> <ident>[<pid>]: <message>" lines
+// where time is either RFC3339 or the classic "Jan _2 15:04:05".
+var syslogLine = regexp.MustCompile(`^(\d{4}-\d\d-\d\dT\S+|[A-Z][a-z]{2} [ \d]\d \d\d:\d\d:\d\d) \S+ ([^\s:\[]+)(?:\[\d+\])?: (.*)$`)
+
+// datedLine matches the "2006-01-02 15:04:05 <message>" lines written by
+// tools like dpkg.
+var datedLine = regexp.MustCompile(`^(\d{4}-\d\d-\d\d \d\d:\d\d:\d\d) (.*)$`)
+
+// filesSource reads the "*.log" files of a directory. It's used when the
+// journal isn't available.
+//
+// The files don't have any priority so all the entries are [Info].
+type filesSource struct {
+	fs           afero.Fs
+	clock        clock.Clock
+	log          *slog.Logger
+	dir          string
+	pollInterval time.Duration
+}
+
+func newFilesSource(fs afero.Fs, dir string, clock clock.Clock, log *slog.Logger) *filesSource {
+	return &filesSource{
+		fs:           fs,
+		clock:        clock,
+		log:          log,
+		dir:          dir,
+		pollInterval: filesPollInterval,
+	}
+}
+
+func (s *filesSource) Name() string { return "files" }
+
+func (s *filesSource) Read(ctx context.Context, filter *Filter, limit int) ([]Entry, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	res := []Entry{}
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// Some files aren't readable by everyone, auth.log for example. They
+		// must not hide the other ones.
+		entries, err := s.readFile(file, filter, limit)
+		if err != nil {
+			s.log.Warn("failed to read a log file, skip it", slog.String("error", err.Error()))
+			continue
+		}
+
+		res = append(res, entries...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].at.Before(res[j].at) })
+
+	if len(res) > limit {
+		res = res[len(res)-limit:]
+	}
+
+	return res, nil
+}
+
+func (s *filesSource) Follow(ctx context.Context, filter *Filter) (<-chan Entry, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	// Start at the end of the files, only the new lines are streamed.
+	offsets := make(map[string]int64, len(files))
+	followed := []string{}
+	for _, file := range files {
+		info, err := s.fs.Stat(file)
+		if err != nil {
+			s.log.Warn("failed to stat a log file, skip it", slog.String("error", err.Error()))
+			continue
+		}
+
+		offsets[file] = info.Size()
+		followed = append(followed, file)
+	}
+
+	res := make(chan Entry)
+	go func() {
+		defer close(res)
+
+		ticker := time.NewTicker(s.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			for _, file := range followed {
+				for _, entry := range s.readNewLines(file, offsets, filter) {
+					select {
+					case res <- entry:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return res, nil
+}
+
+func (s *filesSource) files() ([]string, error) {
+	files, err := afero.Glob(s.fs, path.Join(s.dir, "*.log"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the log files: %w", err)
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (s *filesSource) readFile(file string, filter *Filter, limit int) ([]Entry, error) {
+	f, err := s.fs.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %q: %w", file, err)
+	}
+	defer f.Close()
+
+	res := newRing(limit)
+	err = s.scan(f, file, func(entry *Entry) {
+		if filter.Match(entry) {
+			res.push(*entry)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", file, err)
+	}
+
+	return res.entries(), nil
+}
+
+// readNewLines returns the entries written after the offset and moves it.
+// A file smaller than the offset has been truncated or rotated and is read
+// from the start.
+func (s *filesSource) readNewLines(file string, offsets map[string]int64, filter *Filter) []Entry {
+	f, err := s.fs.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+
+	offset := offsets[file]
+	if info.Size() < offset {
+		offset = 0
+	}
+
+	content, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return nil
+	}
+
+	// Keep the incomplete last line for the next read.
+	end := bytes.LastIndexByte(content, '\n') + 1
+	offsets[file] = offset + int64(end)
+
+	res := []Entry{}
+	_ = s.scan(bytes.NewReader(content[:end]), file, func(entry *Entry) {
+		if filter.Match(entry) {
+			res = append(res, *entry)
+		}
+	})
+
+	return res
+}
+
+func (s *filesSource) scan(r io.Reader, file string, fn func(*Entry)) error {
+	unit := strings.TrimSuffix(path.Base(file), ".log")
+	now := s.clock.Now()
+
+	var last time.Time
+	return readLines(r, func(line []byte) {
+		entry := parseSyslogLine(string(line), now)
+		entry.unit = unit
+
+		// The lines without a timestamp are usually the continuation of a
+		// multi-line message.
+		if entry.at.IsZero() {
+			entry.at = last
+		}
+		last = entry.at
+
+		fn(&entry)
+	})
+}
+
+// parseSyslogLine parses a syslog formatted line. The unknown formats are
+// returned as a message without time nor ident.
+func parseSyslogLine(line string, now time.Time) Entry {
+	matches := syslogLine.FindStringSubmatch(line)
+	if matches == nil {
+		return parseDatedLine(line, now)
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, matches[1])
+	if err != nil {
+		at, err = time.ParseInLocation(time.Stamp, matches[1], now.Location())
+		if err != nil {
+			return Entry{message: line, priority: Info}
+		}
+
+		// The classic format doesn't have the year. A date in the future is
+		// from the previous year.
+		at = at.AddDate(now.Year(), 0, 0)
+		if at.After(now.Add(24 * time.Hour)) {
+			at = at.AddDate(-1, 0, 0)
+		}
+	}
+
+	return Entry{
+		at:       at,
+		ident:    matches[2],
+		message:  matches[3],
+		priority: Info,
+	}
+}
+
+func parseDatedLine(line string, now time.Time) Entry {
+	res := Entry{message: line, priority: Info}
+
+	matches := datedLine.FindStringSubmatch(line)
+	if matches == nil {
+		return res
+	}
+
+	at, err := time.ParseInLocation(time.DateTime, matches[1], now.Location())
+	if err != nil {
+		return res
+	}
+
+	res.at = at
+	res.message = matches[2]
+
+	return res
+}
@@ -0,0 +1,146 @@
+package logs
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/neilotoole/slogt"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesSource(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	newSource := func(t *testing.T) (*filesSource, afero.Fs) {
+		fs := afero.NewMemMapFs()
+
+		require.NoError(t, afero.WriteFile(fs, "/var/log/syslog.log", []byte(
+			"Mar 10 11:00:00 host cron[123]: (root) CMD (backup)\n"+
+				"Mar 10 11:30:00 host kernel: Out of memory: Killed process 42\n"+
+				"  continuation line\n"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "/var/log/auth.log", []byte(
+			"2024-03-10T11:15:00.5+00:00 host sshd[99]: Accepted publickey for root\n"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "/var/log/ignored.gz", []byte("foo\n"), 0o644))
+
+		source := newFilesSource(fs, "/var/log", &clock.Stub{Time: now}, slogt.New(t))
+		source.pollInterval = 10 * time.Millisecond
+
+		return source, fs
+	}
+
+	t.Run("Read merges the files by time", func(t *testing.T) {
+		source, _ := newSource(t)
+
+		res, err := source.Read(ctx, &Filter{}, 100)
+		require.NoError(t, err)
+		require.Len(t, res, 4)
+
+		assert.Equal(t, "cron", res[0].Ident())
+		assert.Equal(t, "syslog", res[0].Unit())
+		assert.Equal(t, time.Date(2024, time.March, 10, 11, 0, 0, 0, time.UTC), res[0].At())
+		assert.Equal(t, "(root) CMD (backup)", res[0].Message())
+		assert.Equal(t, Info, res[0].Priority())
+
+		assert.Equal(t, "sshd", res[1].Ident())
+		assert.Equal(t, "auth", res[1].Unit())
+
+		assert.Equal(t, "Out of memory: Killed process 42", res[2].Message())
+		assert.Equal(t, "  continuation line", res[3].Message())
+		assert.Equal(t, res[2].At(), res[3].At())
+	})
+
+	t.Run("Read skips the unreadable files", func(t *testing.T) {
+		source, fs := newSource(t)
+		source.fs = &unreadableFs{Fs: fs, path: "/var/log/auth.log"}
+
+		res, err := source.Read(ctx, &Filter{}, 10)
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+		assert.Equal(t, "syslog", res[0].Unit())
+	})
+
+	t.Run("Read with a filter and a limit", func(t *testing.T) {
+		source, _ := newSource(t)
+
+		res, err := source.Read(ctx, &Filter{Unit: "syslog"}, 2)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Equal(t, "kernel", res[0].Ident())
+
+		res, err = source.Read(ctx, &Filter{Search: "publickey"}, 100)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, "sshd", res[0].Ident())
+	})
+
+	t.Run("Follow streams the new lines", func(t *testing.T) {
+		source, fs := newSource(t)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		entryCh, err := source.Follow(ctx, &Filter{Unit: "auth"})
+		require.NoError(t, err)
+
+		f, err := fs.OpenFile("/var/log/auth.log", os.O_WRONLY|os.O_APPEND, 0o644)
+		require.NoError(t, err)
+		_, err = f.WriteString("2024-03-10T11:59:00Z host sshd[99]: Disconnected\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		select {
+		case entry := <-entryCh:
+			assert.Equal(t, "Disconnected", entry.Message())
+		case <-time.After(time.Second):
+			t.Fatal("no entry received")
+		}
+
+		cancel()
+		_, ok := <-entryCh
+		assert.False(t, ok)
+	})
+}
+
+func Test_parseSyslogLine(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("classic format from the previous year", func(t *testing.T) {
+		res := parseSyslogLine("Dec 31 23:59:59 host app: happy new year", now)
+		assert.Equal(t, time.Date(2023, time.December, 31, 23, 59, 59, 0, time.UTC), res.At())
+		assert.Equal(t, "app", res.Ident())
+		assert.Equal(t, "happy new year", res.Message())
+	})
+
+	t.Run("dated format", func(t *testing.T) {
+		res := parseSyslogLine("2023-12-31 20:00:02 status installed nginx:amd64", now)
+		assert.Equal(t, time.Date(2023, time.December, 31, 20, 0, 2, 0, time.UTC), res.At())
+		assert.Empty(t, res.Ident())
+		assert.Equal(t, "status installed nginx:amd64", res.Message())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		res := parseSyslogLine("some random text", now)
+		assert.True(t, res.At().IsZero())
+		assert.Equal(t, "some random text", res.Message())
+	})
+}
+
+// unreadableFs fails to open a file like an unreadable one.
+type unreadableFs struct {
+	afero.Fs
+	path string
+}
+
+func (f *unreadableFs) Open(name string) (afero.File, error) {
+	if name == f.path {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrPermission}
+	}
+
+	return f.Fs.Open(name)
+}
@@ -0,0 +1,275 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxLineSize is the maximum size of a log line. The longer lines are
+	// truncated, see readLines.
+	maxLineSize = 1024 * 1024
+
+	// searchLines is the number of journal entries scanned by a text search.
+	// The search is done on our side so it can't be a limit for journalctl.
+	searchLines = 100_000
+)
+
+// journalSource reads the systemd journal with "journalctl --output=json".
+// It avoids the cgo bindings of libsystemd.
+type journalSource struct {
+	path string
+}
+
+func newJournalSource(path string) *journalSource {
+	return &journalSource{path: path}
+}
+
+func (s *journalSource) Name() string { return "journal" }
+
+func (s *journalSource) Read(ctx context.Context, filter *Filter, limit int) ([]Entry, error) {
+	args := journalArgs(filter)
+
+	lines := limit
+	if filter.Search != "" {
+		lines = searchLines
+	}
+
+	args = append(args, "--lines="+strconv.Itoa(lines))
+
+	res := newRing(limit)
+	err := s.run(ctx, args, func(entry *Entry) {
+		if filter.Match(entry) {
+			res.push(*entry)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.entries(), nil
+}
+
+func (s *journalSource) Follow(ctx context.Context, filter *Filter) (<-chan Entry, error) {
+	args := append(journalArgs(filter), "--follow", "--lines=0")
+
+	cmd, stdout, err := s.start(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(chan Entry)
+	go func() {
+		defer close(res)
+		defer cmd.Wait() //nolint:errcheck // Killed by the context.
+
+		_ = scanJournal(stdout, func(entry *Entry) {
+			if !filter.Match(entry) {
+				return
+			}
+
+			select {
+			case res <- *entry:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return res, nil
+}
+
+func (s *journalSource) run(ctx context.Context, args []string, fn func(*Entry)) error {
+	cmd, stdout, err := s.start(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	err = scanJournal(stdout, fn)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("failed to read the journalctl output: %w", err)
+	}
+
+	err = cmd.Wait()
+	if err != nil {
+		return fmt.Errorf("journalctl failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *journalSource) start(ctx context.Context, args []string) (*exec.Cmd, io.Reader, error) {
+	cmd := exec.CommandContext(ctx, s.path, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the stdout pipe: %w", err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start journalctl: %w", err)
+	}
+
+	return cmd, stdout, nil
+}
+
+// journalArgs translates the filter into the journalctl arguments. The
+// remaining checks are done with Filter.Match.
+func journalArgs(filter *Filter) []string {
+	args := []string{"--output=json", "--no-pager", "--quiet"}
+
+	if !filter.Since.IsZero() {
+		args = append(args, "--since=@"+strconv.FormatInt(filter.Since.Unix(), 10))
+	}
+
+	if !filter.Until.IsZero() {
+		args = append(args, "--until=@"+strconv.FormatInt(filter.Until.Unix(), 10))
+	}
+
+	if filter.Priority != nil {
+		args = append(args, "--priority=0.."+strconv.Itoa(int(*filter.Priority)))
+	}
+
+	if filter.Unit != "" {
+		// The "+" is a logical OR between the matches.
+		args = append(args, "_SYSTEMD_UNIT="+filter.Unit, "+", "SYSLOG_IDENTIFIER="+filter.Unit)
+	}
+
+	return args
+}
+
+// scanJournal parses the journalctl lines. A truncated line isn't valid JSON
+// and is skipped.
+func scanJournal(r io.Reader, fn func(*Entry)) error {
+	return readLines(r, func(line []byte) {
+		entry, ok := parseJournalLine(line)
+		if ok {
+			fn(entry)
+		}
+	})
+}
+
+// readLines calls fn for each line of r, without the line ending. The lines
+// longer than maxLineSize are truncated, the remaining bytes are dropped.
+func readLines(r io.Reader, fn func(line []byte)) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	line := []byte{}
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(line) < maxLineSize {
+			line = append(line, chunk[:min(len(chunk), maxLineSize-len(line))]...)
+		}
+
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case errors.Is(err, io.EOF):
+			if len(line) > 0 {
+				fn(line)
+			}
+			return nil
+		case err != nil:
+			return err
+		}
+
+		fn(bytes.TrimRight(line, "\r\n"))
+		line = line[:0]
+	}
+}
+
+type journalLine struct {
+	Timestamp string          `json:"__REALTIME_TIMESTAMP"`
+	Priority  string          `json:"PRIORITY"`
+	Unit      string          `json:"_SYSTEMD_UNIT"`
+	Ident     string          `json:"SYSLOG_IDENTIFIER"`
+	Message   json.RawMessage `json:"MESSAGE"`
+}
+
+func parseJournalLine(line []byte) (*Entry, bool) {
+	var raw journalLine
+
+	err := json.Unmarshal(line, &raw)
+	if err != nil {
+		return nil, false
+	}
+
+	usec, err := strconv.ParseInt(raw.Timestamp, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	priority, err := ParsePriority(raw.Priority)
+	if err != nil {
+		priority = Info
+	}
+
+	return &Entry{
+		at:       time.UnixMicro(usec),
+		unit:     raw.Unit,
+		ident:    raw.Ident,
+		message:  parseJournalMessage(raw.Message),
+		priority: priority,
+	}, true
+}
+
+// parseJournalMessage handles the two MESSAGE formats: a string, or an array
+// of bytes when the message isn't valid UTF-8.
+func parseJournalMessage(raw json.RawMessage) string {
+	var msg string
+
+	err := json.Unmarshal(raw, &msg)
+	if err == nil {
+		return msg
+	}
+
+	var ints []int
+	err = json.Unmarshal(raw, &ints)
+	if err != nil {
+		return ""
+	}
+
+	bytes := make([]byte, len(ints))
+	for i, b := range ints {
+		bytes[i] = byte(b)
+	}
+
+	return string(bytes)
+}
+
+// ring keeps the last n entries pushed.
+type ring struct {
+	buf  []Entry
+	next int
+	full bool
+}
+
+func newRing(n int) *ring {
+	return &ring{buf: make([]Entry, n)}
+}
+
+func (r *ring) push(e Entry) {
+	r.buf[r.next] = e
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *ring) entries() []Entry {
+	if !r.full {
+		return append([]Entry{}, r.buf[:r.next]...)
+	}
+
+	return append(append([]Entry{}, r.buf[r.next:]...), r.buf[:r.next]...)
+}
@@ -0,0 +1,78 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseJournalLine(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		res, ok := parseJournalLine([]byte(`{"__REALTIME_TIMESTAMP":"1710068400000000","PRIORITY":"3","_SYSTEMD_UNIT":"nginx.service","SYSLOG_IDENTIFIER":"nginx","MESSAGE":"worker crashed"}`))
+		assert.True(t, ok)
+		assert.Equal(t, time.UnixMicro(1710068400000000), res.At())
+		assert.Equal(t, Error, res.Priority())
+		assert.Equal(t, "nginx.service", res.Unit())
+		assert.Equal(t, "nginx", res.Ident())
+		assert.Equal(t, "worker crashed", res.Message())
+	})
+
+	t.Run("with a binary message", func(t *testing.T) {
+		res, ok := parseJournalLine([]byte(`{"__REALTIME_TIMESTAMP":"1710068400000000","MESSAGE":[104,105]}`))
+		assert.True(t, ok)
+		assert.Equal(t, "hi", res.Message())
+		assert.Equal(t, Info, res.Priority())
+	})
+
+	t.Run("without timestamp", func(t *testing.T) {
+		res, ok := parseJournalLine([]byte(`{"MESSAGE":"foo"}`))
+		assert.False(t, ok)
+		assert.Nil(t, res)
+	})
+}
+
+func Test_journalArgs(t *testing.T) {
+	res := journalArgs(&Filter{
+		Since:    time.Unix(1710068400, 0),
+		Until:    time.Unix(1710072000, 0),
+		Unit:     "nginx",
+		Search:   "crash",
+		Priority: ptr.To(Warning),
+	})
+
+	assert.Equal(t, []string{
+		"--output=json", "--no-pager", "--quiet",
+		"--since=@1710068400",
+		"--until=@1710072000",
+		"--priority=0..4",
+		"_SYSTEMD_UNIT=nginx", "+", "SYSLOG_IDENTIFIER=nginx",
+	}, res)
+}
+
+func Test_readLines(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lines := []string{}
+		err := readLines(strings.NewReader("foo\r\nbar\n\nbaz"), func(line []byte) {
+			lines = append(lines, string(line))
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo", "bar", "", "baz"}, lines)
+	})
+
+	t.Run("with a line too long", func(t *testing.T) {
+		long := strings.Repeat("a", maxLineSize+10)
+
+		lines := []string{}
+		err := readLines(strings.NewReader("foo\n"+long+"\nbar\n"), func(line []byte) {
+			lines = append(lines, string(line))
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo", long[:maxLineSize], "bar"}, lines)
+	})
+}
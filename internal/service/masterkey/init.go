@@ -0,0 +1,58 @@
+package masterkey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/spf13/afero"
+)
+
+type Config struct {
+	// Passphrase seals the master key instead of the keyfile saved in the
+	// data folder.
+	Passphrase secret.Text
+}
+
+// Service gives access to the master key used to seal all the other keys.
+//
+// The master key itself never leaves the service and is kept inside a
+// memguard enclave. It's sealed either with a keyfile saved in the data
+// folder or with a key derived from a passphrase.
+type Service interface {
+	SealKey(key *secret.Key) (*secret.SealedKey, error)
+	Open(key *secret.SealedKey) (*secret.Key, error)
+	// Rotate seals the master key with a new keyfile, or with the passphrase
+	// if not empty.
+	Rotate(ctx context.Context, passphrase secret.Text) error
+}
+
+func Init(ctx context.Context, cfg Config, folderPath string, fs afero.Fs, config config.Service, tools tools.Tools) (Service, error) {
+	svc := newService(fs, folderPath, config, cfg, tools.Clock())
+
+	err := svc.loadOrGenerate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the master key: %w", err)
+	}
+
+	return svc, nil
+}
+
+// Check returns an error if the master key sealed inside config can't be
+// opened with the keyfiles of the data folder or with the passphrase. It's
+// used to check a backup before restoring it, nothing is modified.
+func Check(ctx context.Context, cfg Config, folderPath string, fs afero.Fs, config config.Service) error {
+	svc := newService(fs, folderPath, config, cfg, clock.NewDefault())
+
+	sealedKey, salt, err := config.GetMasterKey(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to GetMasterKey: %w", err)
+	}
+
+	_, _, err = svc.open(sealedKey, salt)
+
+	return err
+}
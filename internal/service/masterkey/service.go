@@ -0,0 +1,425 @@
+package masterkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/awnumar/memguard"
+	"github.com/spf13/afero"
+)
+
+const (
+	keyfileName = "master.key"
+	// newKeyfileName is the keyfile written by a rotation. It replaces the
+	// keyfile once the master key is sealed with it.
+	newKeyfileName = "master.key.new"
+	// previousKeyfilePrefix starts the keyfiles replaced by a rotation. They
+	// are kept in order to open the master key of the older backups, with
+	// the replacement time appended.
+	previousKeyfilePrefix = "master.key."
+	previousKeyfileFormat = "20060102T150405Z"
+)
+
+var (
+	ErrKeyfileNotFound    = errors.New("keyfile not found")
+	ErrNotLoaded          = errors.New("master key not loaded")
+	ErrPassphraseRequired = errors.New("the master key is sealed with a passphrase")
+	ErrInvalidPassphrase  = errors.New("invalid passphrase")
+	ErrUnusedPassphrase   = errors.New("the master key is sealed with the keyfile, rotate it to use the passphrase")
+)
+
+type service struct {
+	fs             afero.Fs
+	config         config.Service
+	clock          clock.Clock
+	passphrase     secret.Text
+	folderPath     string
+	keyfilePath    string
+	newKeyfilePath string
+	enclave        *memguard.Enclave
+}
+
+func newService(fs afero.Fs, folderPath string, config config.Service, cfg Config, clock clock.Clock) *service {
+	return &service{
+		fs:             fs,
+		config:         config,
+		clock:          clock,
+		passphrase:     cfg.Passphrase,
+		folderPath:     folderPath,
+		keyfilePath:    path.Join(folderPath, keyfileName),
+		newKeyfilePath: path.Join(folderPath, newKeyfileName),
+		enclave:        nil,
+	}
+}
+
+func (s *service) SealKey(key *secret.Key) (*secret.SealedKey, error) {
+	if s.enclave == nil {
+		return nil, ErrNotLoaded
+	}
+
+	return secret.SealKeyWithEnclave(s.enclave, key)
+}
+
+func (s *service) Open(key *secret.SealedKey) (*secret.Key, error) {
+	if s.enclave == nil {
+		return nil, ErrNotLoaded
+	}
+
+	return key.OpenWithEnclave(s.enclave)
+}
+
+// Rotate seals the master key again, with a new keyfile or with the given
+// passphrase. The master key itself doesn't change so the keys it seals stay
+// valid. The previous keyfile is kept for the older backups, see
+// archiveKeyfile.
+func (s *service) Rotate(ctx context.Context, passphrase secret.Text) error {
+	if s.enclave == nil {
+		return ErrNotLoaded
+	}
+
+	buf, err := s.enclave.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open the master key enclave: %w", err)
+	}
+	defer buf.Destroy()
+
+	masterKey, err := secret.KeyFromRaw(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if passphrase.Raw() != "" {
+		return s.sealWithPassphrase(ctx, masterKey, passphrase)
+	}
+
+	// The new keyfile is written aside until the master key is sealed with
+	// it, see openWithKeyfile.
+	keyfileKey, err := s.writeNewKeyfile(s.newKeyfilePath)
+	if err != nil {
+		return err
+	}
+
+	err = s.seal(ctx, masterKey, keyfileKey, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.replaceKeyfile()
+}
+
+// loadOrGenerate loads the master key sealed inside the config with the
+// keyfile saved in the data folder, or with the passphrase. A new master key
+// is generated during the first start.
+func (s *service) loadOrGenerate(ctx context.Context) error {
+	sealedKey, salt, err := s.config.GetMasterKey(ctx)
+	if errors.Is(err, errs.ErrNotFound) {
+		return s.generate(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to GetMasterKey: %w", err)
+	}
+
+	masterKey, keyfilePath, err := s.open(sealedKey, salt)
+	if err != nil {
+		return err
+	}
+
+	switch keyfilePath {
+	case "", s.keyfilePath:
+	case s.newKeyfilePath:
+		// A rotation was interrupted after the master key was sealed with
+		// the new keyfile.
+		err = s.replaceKeyfile()
+	default:
+		// An older backup was restored, its master key is sealed again with
+		// the current keyfile or passphrase.
+		if s.passphrase.Raw() != "" {
+			err = s.sealWithPassphrase(ctx, masterKey, s.passphrase)
+		} else {
+			err = s.sealWithKeyfile(ctx, masterKey)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.enclave = memguard.NewEnclave(masterKey.Raw())
+
+	return nil
+}
+
+// open opens the sealed master key with the passphrase if a salt is given,
+// else with the keyfiles. It returns the path of the keyfile used.
+func (s *service) open(sealedKey *secret.SealedKey, salt []byte) (*secret.Key, string, error) {
+	if salt != nil {
+		masterKey, err := s.openWithPassphrase(sealedKey, salt)
+		return masterKey, "", err
+	}
+
+	if s.passphrase.Raw() == "" {
+		return s.openWithKeyfile(sealedKey)
+	}
+
+	// The current keyfile is ignored, the passphrase must be set with a
+	// rotation. Only a backup made before the rotation to the passphrase is
+	// accepted.
+	masterKey, keyfilePath, err := s.openWithPreviousKeyfiles(sealedKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if masterKey == nil {
+		return nil, "", ErrUnusedPassphrase
+	}
+
+	return masterKey, keyfilePath, nil
+}
+
+func (s *service) generate(ctx context.Context) error {
+	masterKey, err := secret.NewKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate the master key: %w", err)
+	}
+
+	if s.passphrase.Raw() != "" {
+		err = s.sealWithPassphrase(ctx, masterKey, s.passphrase)
+	} else {
+		err = s.sealWithKeyfile(ctx, masterKey)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.enclave = memguard.NewEnclave(masterKey.Raw())
+
+	return nil
+}
+
+func (s *service) sealWithKeyfile(ctx context.Context, masterKey *secret.Key) error {
+	keyfileKey, err := s.readKeyfile(s.keyfilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		keyfileKey, err = s.writeNewKeyfile(s.keyfilePath)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.seal(ctx, masterKey, keyfileKey, nil)
+}
+
+func (s *service) sealWithPassphrase(ctx context.Context, masterKey *secret.Key, passphrase secret.Text) error {
+	salt, err := secret.NewSalt()
+	if err != nil {
+		return err
+	}
+
+	passphraseKey, err := secret.DeriveKey(passphrase, salt)
+	if err != nil {
+		return fmt.Errorf("failed to derive the passphrase key: %w", err)
+	}
+
+	err = s.seal(ctx, masterKey, passphraseKey, salt)
+	if err != nil {
+		return err
+	}
+
+	// The keyfile isn't used from now on, it's only kept for the older
+	// backups.
+	err = s.archiveKeyfile()
+	if err != nil {
+		return err
+	}
+
+	err = s.fs.Remove(s.newKeyfilePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove %q: %w", s.newKeyfilePath, err)
+	}
+
+	return nil
+}
+
+func (s *service) seal(ctx context.Context, masterKey, encryptionKey *secret.Key, salt []byte) error {
+	sealedKey, err := secret.SealKey(encryptionKey, masterKey)
+	if err != nil {
+		return fmt.Errorf("failed to seal the master key: %w", err)
+	}
+
+	err = s.config.SetMasterKey(ctx, sealedKey, salt)
+	if err != nil {
+		return fmt.Errorf("failed to SetMasterKey: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) openWithPassphrase(sealedKey *secret.SealedKey, salt []byte) (*secret.Key, error) {
+	if s.passphrase.Raw() == "" {
+		return nil, ErrPassphraseRequired
+	}
+
+	passphraseKey, err := secret.DeriveKey(s.passphrase, salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the passphrase key: %w", err)
+	}
+
+	masterKey, err := sealedKey.Open(passphraseKey)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	return masterKey, nil
+}
+
+// openWithKeyfile opens the master key with the keyfile, then with the new
+// keyfile of an interrupted rotation and finally with the previous keyfiles.
+// It returns the path of the keyfile used.
+func (s *service) openWithKeyfile(sealedKey *secret.SealedKey) (*secret.Key, string, error) {
+	keyfileKey, err := s.readKeyfile(s.keyfilePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, "", err
+	}
+
+	if keyfileKey != nil {
+		masterKey, err := sealedKey.Open(keyfileKey)
+		if err == nil {
+			return masterKey, s.keyfilePath, nil
+		}
+	}
+
+	newKeyfileKey, err := s.readKeyfile(s.newKeyfilePath)
+	if err == nil {
+		masterKey, err := sealedKey.Open(newKeyfileKey)
+		if err == nil {
+			return masterKey, s.newKeyfilePath, nil
+		}
+	}
+
+	masterKey, keyfilePath, err := s.openWithPreviousKeyfiles(sealedKey)
+	if err != nil || masterKey != nil {
+		return masterKey, keyfilePath, err
+	}
+
+	if keyfileKey == nil {
+		return nil, "", fmt.Errorf("%w: %q", ErrKeyfileNotFound, s.keyfilePath)
+	}
+
+	return nil, "", fmt.Errorf("failed to open the master key with %q", s.keyfilePath)
+}
+
+// openWithPreviousKeyfiles opens the master key with the keyfiles replaced
+// by a rotation, the most recent first. It returns a nil key if none of them
+// opens it.
+func (s *service) openWithPreviousKeyfiles(sealedKey *secret.SealedKey) (*secret.Key, string, error) {
+	entries, err := afero.ReadDir(s.fs, s.folderPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", nil
+	}
+
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %q: %w", s.folderPath, err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		raw, ok := strings.CutPrefix(entry.Name(), previousKeyfilePrefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		_, err = time.Parse(previousKeyfileFormat, raw)
+		if err == nil {
+			names = append(names, entry.Name())
+		}
+	}
+
+	// The names are sorted by date with the alphabetical order.
+	slices.Sort(names)
+	slices.Reverse(names)
+
+	for _, name := range names {
+		keyfilePath := path.Join(s.folderPath, name)
+
+		keyfileKey, err := s.readKeyfile(keyfilePath)
+		if err != nil {
+			return nil, "", err
+		}
+
+		masterKey, err := sealedKey.Open(keyfileKey)
+		if err == nil {
+			return masterKey, keyfilePath, nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// replaceKeyfile replaces the keyfile by the new one written by a rotation.
+func (s *service) replaceKeyfile() error {
+	err := s.archiveKeyfile()
+	if err != nil {
+		return err
+	}
+
+	err = s.fs.Rename(s.newKeyfilePath, s.keyfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to replace %q: %w", s.keyfilePath, err)
+	}
+
+	return nil
+}
+
+// archiveKeyfile renames the keyfile with the current time. It can't open
+// the master key anymore but it's still needed to restore the backups made
+// before the rotation.
+func (s *service) archiveKeyfile() error {
+	previousPath := path.Join(s.folderPath, previousKeyfilePrefix+s.clock.Now().UTC().Format(previousKeyfileFormat))
+
+	err := s.fs.Rename(s.keyfilePath, previousPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to move %q: %w", s.keyfilePath, err)
+	}
+
+	return nil
+}
+
+func (s *service) readKeyfile(keyfilePath string) (*secret.Key, error) {
+	rawFile, err := afero.ReadFile(s.fs, keyfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", keyfilePath, err)
+	}
+
+	key, err := secret.KeyFromBase64(strings.TrimSpace(string(rawFile)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid keyfile %q: %w", keyfilePath, err)
+	}
+
+	return key, nil
+}
+
+func (s *service) writeNewKeyfile(keyfilePath string) (*secret.Key, error) {
+	key, err := secret.NewKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the keyfile key: %w", err)
+	}
+
+	err = afero.WriteFile(s.fs, keyfilePath, []byte(key.Base64()+"\n"), 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write %q: %w", keyfilePath, err)
+	}
+
+	return key, nil
+}
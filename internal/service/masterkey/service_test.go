@@ -0,0 +1,367 @@
+package masterkey
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMasterKeyService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("first start generate a keyfile and a master key", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configMock := config.NewMockService(t)
+		svc := newService(afs, "/foo", configMock, Config{}, clock.NewDefault())
+
+		var sealedKey *secret.SealedKey
+
+		configMock.On("GetMasterKey", mock.Anything).Return(nil, nil, errs.ErrNotFound).Once()
+		configMock.On("SetMasterKey", mock.Anything, mock.AnythingOfType("*secret.SealedKey"), []byte(nil)).
+			Run(func(args mock.Arguments) { sealedKey = args.Get(1).(*secret.SealedKey) }).
+			Return(nil).Once()
+
+		err := svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		info, err := afs.Stat("/foo/master.key")
+		require.NoError(t, err)
+		assert.Equal(t, "-rw-------", info.Mode().String())
+
+		// The sealed key must be openable with the keyfile.
+		keyfileKey, err := svc.readKeyfile("/foo/master.key")
+		require.NoError(t, err)
+		_, err = sealedKey.Open(keyfileKey)
+		require.NoError(t, err)
+	})
+
+	t.Run("restart load the existing master key", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configMock := config.NewMockService(t)
+		svc := newService(afs, "/foo", configMock, Config{}, clock.NewDefault())
+
+		masterKey, err := secret.NewKey()
+		require.NoError(t, err)
+		keyfileKey, err := svc.writeNewKeyfile("/foo/master.key")
+		require.NoError(t, err)
+		sealedKey, err := secret.SealKey(keyfileKey, masterKey)
+		require.NoError(t, err)
+
+		configMock.On("GetMasterKey", mock.Anything).Return(sealedKey, nil, nil).Once()
+
+		err = svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		// A key sealed by the service can be opened with the master key.
+		someKey, err := secret.NewKey()
+		require.NoError(t, err)
+
+		res, err := svc.SealKey(someKey)
+		require.NoError(t, err)
+
+		opened, err := res.Open(masterKey)
+		require.NoError(t, err)
+		assert.True(t, someKey.Equals(opened))
+
+		opened, err = svc.Open(res)
+		require.NoError(t, err)
+		assert.True(t, someKey.Equals(opened))
+	})
+
+	t.Run("restart with a missing keyfile", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configMock := config.NewMockService(t)
+		svc := newService(afs, "/foo", configMock, Config{}, clock.NewDefault())
+
+		someKey, err := secret.NewKey()
+		require.NoError(t, err)
+		sealedKey, err := secret.SealKey(someKey, someKey)
+		require.NoError(t, err)
+
+		configMock.On("GetMasterKey", mock.Anything).Return(sealedKey, nil, nil).Once()
+
+		err = svc.loadOrGenerate(ctx)
+		require.ErrorIs(t, err, ErrKeyfileNotFound)
+	})
+
+	t.Run("SealKey without a loaded key", func(t *testing.T) {
+		svc := newService(afero.NewMemMapFs(), "/foo", config.NewMockService(t), Config{}, clock.NewDefault())
+
+		someKey, err := secret.NewKey()
+		require.NoError(t, err)
+
+		res, err := svc.SealKey(someKey)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrNotLoaded)
+	})
+
+	t.Run("first start with a passphrase", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configSvc := newTestConfig(t)
+		svc := newService(afs, "/foo", configSvc, Config{Passphrase: secret.NewText("some passphrase")}, clock.NewDefault())
+
+		err := svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		_, err = afs.Stat("/foo/master.key")
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		_, salt, err := configSvc.GetMasterKey(ctx)
+		require.NoError(t, err)
+		assert.Len(t, salt, secret.SaltLength)
+
+		// Restart with the same passphrase.
+		svc2 := newService(afs, "/foo", configSvc, Config{Passphrase: secret.NewText("some passphrase")}, clock.NewDefault())
+		err = svc2.loadOrGenerate(ctx)
+		require.NoError(t, err)
+		assertSameMasterKey(t, svc, svc2)
+	})
+
+	t.Run("restart with a missing or an invalid passphrase", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configSvc := newTestConfig(t)
+		svc := newService(afs, "/foo", configSvc, Config{Passphrase: secret.NewText("some passphrase")}, clock.NewDefault())
+
+		err := svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		err = newService(afs, "/foo", configSvc, Config{}, clock.NewDefault()).loadOrGenerate(ctx)
+		require.ErrorIs(t, err, ErrPassphraseRequired)
+
+		err = newService(afs, "/foo", configSvc, Config{Passphrase: secret.NewText("invalid")}, clock.NewDefault()).loadOrGenerate(ctx)
+		require.ErrorIs(t, err, ErrInvalidPassphrase)
+	})
+
+	t.Run("restart with a passphrase not used yet", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configSvc := newTestConfig(t)
+
+		err := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault()).loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		err = newService(afs, "/foo", configSvc, Config{Passphrase: secret.NewText("some passphrase")}, clock.NewDefault()).loadOrGenerate(ctx)
+		require.ErrorIs(t, err, ErrUnusedPassphrase)
+	})
+
+	t.Run("Rotate with a new keyfile", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configSvc := newTestConfig(t)
+		svc := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+
+		err := svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		oldKeyfile, err := afero.ReadFile(afs, "/foo/master.key")
+		require.NoError(t, err)
+		oldSealedKey, _, err := configSvc.GetMasterKey(ctx)
+		require.NoError(t, err)
+
+		err = svc.Rotate(ctx, secret.Empty)
+		require.NoError(t, err)
+
+		newKeyfile, err := afero.ReadFile(afs, "/foo/master.key")
+		require.NoError(t, err)
+		assert.NotEqual(t, oldKeyfile, newKeyfile)
+		_, err = afs.Stat("/foo/master.key.new")
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		// The previous keyfile is kept for the older backups.
+		previous, err := afero.Glob(afs, "/foo/master.key.2*")
+		require.NoError(t, err)
+		require.Len(t, previous, 1)
+		previousKeyfile, err := afero.ReadFile(afs, previous[0])
+		require.NoError(t, err)
+		assert.Equal(t, oldKeyfile, previousKeyfile)
+
+		newSealedKey, _, err := configSvc.GetMasterKey(ctx)
+		require.NoError(t, err)
+		assert.False(t, oldSealedKey.Equals(newSealedKey))
+
+		svc2 := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+		err = svc2.loadOrGenerate(ctx)
+		require.NoError(t, err)
+		assertSameMasterKey(t, svc, svc2)
+	})
+
+	t.Run("Rotate from the keyfile to a passphrase and back", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configSvc := newTestConfig(t)
+		svc := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+
+		err := svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		err = svc.Rotate(ctx, secret.NewText("some passphrase"))
+		require.NoError(t, err)
+
+		_, err = afs.Stat("/foo/master.key")
+		require.ErrorIs(t, err, os.ErrNotExist)
+		previous, err := afero.Glob(afs, "/foo/master.key.2*")
+		require.NoError(t, err)
+		assert.Len(t, previous, 1)
+
+		svc2 := newService(afs, "/foo", configSvc, Config{Passphrase: secret.NewText("some passphrase")}, clock.NewDefault())
+		err = svc2.loadOrGenerate(ctx)
+		require.NoError(t, err)
+		assertSameMasterKey(t, svc, svc2)
+
+		err = svc2.Rotate(ctx, secret.Empty)
+		require.NoError(t, err)
+
+		svc3 := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+		err = svc3.loadOrGenerate(ctx)
+		require.NoError(t, err)
+		assertSameMasterKey(t, svc, svc3)
+	})
+
+	t.Run("restart after an interrupted rotation", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configSvc := newTestConfig(t)
+		svc := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+
+		err := svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		// The master key is sealed with the new keyfile but the keyfile
+		// isn't replaced yet.
+		newKeyfileKey, err := svc.writeNewKeyfile("/foo/master.key.new")
+		require.NoError(t, err)
+		err = svc.seal(ctx, mustMasterKey(t, svc), newKeyfileKey, nil)
+		require.NoError(t, err)
+
+		svc2 := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+		err = svc2.loadOrGenerate(ctx)
+		require.NoError(t, err)
+		assertSameMasterKey(t, svc, svc2)
+
+		keyfileKey, err := svc2.readKeyfile("/foo/master.key")
+		require.NoError(t, err)
+		assert.True(t, newKeyfileKey.Equals(keyfileKey))
+		_, err = afs.Stat("/foo/master.key.new")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("restart with a backup made before a rotation", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configSvc := newTestConfig(t)
+		svc := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+
+		err := svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		backupSealedKey, _, err := configSvc.GetMasterKey(ctx)
+		require.NoError(t, err)
+
+		err = svc.Rotate(ctx, secret.Empty)
+		require.NoError(t, err)
+
+		// Restore the backup.
+		err = configSvc.SetMasterKey(ctx, backupSealedKey, nil)
+		require.NoError(t, err)
+
+		err = Check(ctx, Config{}, "/foo", afs, configSvc)
+		require.NoError(t, err)
+
+		svc2 := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+		err = svc2.loadOrGenerate(ctx)
+		require.NoError(t, err)
+		assertSameMasterKey(t, svc, svc2)
+
+		// The master key is sealed again with the current keyfile.
+		sealedKey, _, err := configSvc.GetMasterKey(ctx)
+		require.NoError(t, err)
+		keyfileKey, err := svc2.readKeyfile("/foo/master.key")
+		require.NoError(t, err)
+		_, err = sealedKey.Open(keyfileKey)
+		require.NoError(t, err)
+	})
+
+	t.Run("restart with a backup made before a rotation to a passphrase", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		configSvc := newTestConfig(t)
+		svc := newService(afs, "/foo", configSvc, Config{}, clock.NewDefault())
+
+		err := svc.loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		backupSealedKey, _, err := configSvc.GetMasterKey(ctx)
+		require.NoError(t, err)
+
+		err = svc.Rotate(ctx, secret.NewText("some passphrase"))
+		require.NoError(t, err)
+
+		// Restore the backup.
+		err = configSvc.SetMasterKey(ctx, backupSealedKey, nil)
+		require.NoError(t, err)
+
+		svc2 := newService(afs, "/foo", configSvc, Config{Passphrase: secret.NewText("some passphrase")}, clock.NewDefault())
+		err = svc2.loadOrGenerate(ctx)
+		require.NoError(t, err)
+		assertSameMasterKey(t, svc, svc2)
+
+		_, salt, err := configSvc.GetMasterKey(ctx)
+		require.NoError(t, err)
+		assert.Len(t, salt, secret.SaltLength)
+	})
+
+	t.Run("Check with an unknown keyfile", func(t *testing.T) {
+		configSvc := newTestConfig(t)
+
+		err := newService(afero.NewMemMapFs(), "/foo", configSvc, Config{}, clock.NewDefault()).loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		afs := afero.NewMemMapFs()
+		err = newService(afs, "/foo", newTestConfig(t), Config{}, clock.NewDefault()).loadOrGenerate(ctx)
+		require.NoError(t, err)
+
+		err = Check(ctx, Config{}, "/foo", afs, configSvc)
+		require.Error(t, err)
+	})
+}
+
+func newTestConfig(t *testing.T) config.Service {
+	t.Helper()
+
+	db := sqlstorage.NewTestStorage(t)
+
+	return config.Init(db, tools.NewToolboxForTest(t)).Service
+}
+
+func mustMasterKey(t *testing.T, svc *service) *secret.Key {
+	t.Helper()
+
+	buf, err := svc.enclave.Open()
+	require.NoError(t, err)
+	defer buf.Destroy()
+
+	res, err := secret.KeyFromRaw(buf.Bytes())
+	require.NoError(t, err)
+
+	return res
+}
+
+// assertSameMasterKey checks that a key sealed by s1 can be opened by s2.
+func assertSameMasterKey(t *testing.T, s1, s2 *service) {
+	t.Helper()
+
+	someKey, err := secret.NewKey()
+	require.NoError(t, err)
+
+	sealedKey, err := s1.SealKey(someKey)
+	require.NoError(t, err)
+
+	res, err := s2.Open(sealedKey)
+	require.NoError(t, err)
+	assert.True(t, someKey.Equals(res))
+}
@@ -0,0 +1,26 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Peltoche/zapette/internal/service/masterkey"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+type Service interface {
+	Create(ctx context.Context, cmd *CreateCmd) (*Channel, error)
+	GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Channel, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Channel, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	SendTest(ctx context.Context, channel *Channel) error
+	Notify(ctx context.Context, msg *Message) error
+}
+
+func Init(db *sql.DB, masterkey masterkey.Service, tools tools.Tools) Service {
+	storage := newSqlStorage(db)
+
+	return newService(storage, masterkey, tools)
+}
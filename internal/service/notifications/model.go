@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	v "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+type Kind string
+
+const (
+	// Webhook POST a JSON payload to an URL. If a secret is set, the payload
+	// is signed with an HMAC-SHA256 inside the "X-Zapette-Signature" header.
+	Webhook Kind = "webhook"
+	// SMTP send an email. The secret is the SMTP password.
+	SMTP Kind = "smtp"
+	// Ntfy POST the message to a ntfy topic URL. The secret is an optional
+	// access token.
+	Ntfy Kind = "ntfy"
+	// Gotify POST the message to a Gotify server. The secret is the
+	// application token.
+	Gotify Kind = "gotify"
+)
+
+var AllKinds = []Kind{Webhook, SMTP, Ntfy, Gotify}
+
+type Severity string
+
+const (
+	Info     Severity = "info"
+	Warning  Severity = "warning"
+	Critical Severity = "critical"
+)
+
+// Settings contains all the non-secret settings of a channel. Only the fields
+// used by the channel kind are set.
+type Settings struct {
+	URL      string   `json:"url,omitempty"`
+	Host     string   `json:"host,omitempty"`
+	Username string   `json:"username,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+	Port     int      `json:"port,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+}
+
+func (s Settings) validateFor(kind Kind) error {
+	switch kind {
+	case SMTP:
+		return v.ValidateStruct(&s,
+			v.Field(&s.Host, v.Required, is.Host),
+			v.Field(&s.Port, v.Required, v.Min(1), v.Max(65535)),
+			v.Field(&s.From, v.Required, is.Email),
+			v.Field(&s.To, v.Required, v.Each(is.Email)),
+		)
+	default:
+		return v.ValidateStruct(&s,
+			v.Field(&s.URL, v.Required, is.URL),
+			v.Field(&s.Priority, v.Min(0), v.Max(10)),
+		)
+	}
+}
+
+// Channel is a destination for the notifications.
+type Channel struct {
+	createdAt time.Time
+	sealedKey *secret.SealedKey
+	secret    *secret.SealedText
+	id        uuid.UUID
+	name      string
+	kind      Kind
+	createdBy uuid.UUID
+	settings  Settings
+}
+
+func (c Channel) ID() uuid.UUID        { return c.id }
+func (c Channel) Name() string         { return c.name }
+func (c Channel) Kind() Kind           { return c.kind }
+func (c Channel) Settings() Settings   { return c.settings }
+func (c Channel) HasSecret() bool      { return c.secret != nil }
+func (c Channel) CreatedAt() time.Time { return c.createdAt }
+func (c Channel) CreatedBy() uuid.UUID { return c.createdBy }
+
+func (c *Channel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"id":        c.id,
+		"name":      c.name,
+		"kind":      c.kind,
+		"settings":  c.settings,
+		"createdAt": c.createdAt,
+		"createdBy": c.createdBy,
+	})
+}
+
+// Message is the content sent to all the channels.
+type Message struct {
+	At       time.Time         `json:"at"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Severity Severity          `json:"severity"`
+}
+
+type CreateCmd struct {
+	CreatedBy *users.User
+	Name      string
+	Kind      Kind
+	Secret    secret.Text
+	Settings  Settings
+}
+
+func (t CreateCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.CreatedBy, v.Required),
+		v.Field(&t.Name, v.Required, v.Length(1, 50)),
+		v.Field(&t.Kind, v.Required, v.In(Webhook, SMTP, Ntfy, Gotify)),
+		v.Field(&t.Settings, v.By(func(_ any) error { return t.Settings.validateFor(t.Kind) })),
+	)
+}
@@ -0,0 +1,84 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+type FakeChannelBuilder struct {
+	t       testing.TB
+	channel *Channel
+}
+
+func NewFakeChannel(t testing.TB) *FakeChannelBuilder {
+	t.Helper()
+
+	uuidProvider := uuid.NewProvider()
+	createdAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+
+	someKey, err := secret.NewKey()
+	require.NoError(t, err)
+
+	sealedKey, err := secret.SealKey(someKey, someKey)
+	require.NoError(t, err)
+
+	return &FakeChannelBuilder{
+		t: t,
+		channel: &Channel{
+			id:        uuidProvider.New(),
+			name:      gofakeit.AppName(),
+			kind:      Webhook,
+			settings:  Settings{URL: gofakeit.URL()},
+			sealedKey: sealedKey,
+			secret:    nil,
+			createdAt: createdAt.UTC(),
+			createdBy: uuidProvider.New(),
+		},
+	}
+}
+
+func (f *FakeChannelBuilder) WithKind(kind Kind) *FakeChannelBuilder {
+	f.channel.kind = kind
+
+	return f
+}
+
+func (f *FakeChannelBuilder) WithSettings(settings Settings) *FakeChannelBuilder {
+	f.channel.settings = settings
+
+	return f
+}
+
+// WithSecret seals the given secret with channelKey.
+func (f *FakeChannelBuilder) WithSecret(channelKey *secret.Key, channelSecret string) *FakeChannelBuilder {
+	f.t.Helper()
+
+	sealedSecret, err := secret.SealText(channelKey, secret.NewText(channelSecret))
+	require.NoError(f.t, err)
+
+	f.channel.secret = sealedSecret
+
+	return f
+}
+
+func (f *FakeChannelBuilder) Build() *Channel {
+	return f.channel
+}
+
+func (f *FakeChannelBuilder) BuildAndStore(ctx context.Context, db *sql.DB) *Channel {
+	f.t.Helper()
+
+	storage := newSqlStorage(db)
+
+	err := storage.Save(ctx, f.channel)
+	require.NoError(f.t, err)
+
+	return f.channel
+}
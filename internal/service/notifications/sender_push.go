@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+)
+
+// ntfySender publishes the messages on a ntfy topic.
+//
+// The channel URL is the full topic URL (e.g. https://ntfy.sh/my-topic).
+type ntfySender struct {
+	client *http.Client
+}
+
+func newNtfySender(client *http.Client) *ntfySender {
+	return &ntfySender{client: client}
+}
+
+func (s *ntfySender) Send(ctx context.Context, channel *Channel, token secret.Text, msg *Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.settings.URL, strings.NewReader(msg.Body))
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	req.Header.Set("Title", msg.Title)
+	req.Header.Set("Tags", string(msg.Severity))
+	if channel.settings.Priority > 0 {
+		req.Header.Set("Priority", strconv.Itoa(channel.settings.Priority))
+	}
+
+	if token.Raw() != "" {
+		req.Header.Set("Authorization", "Bearer "+token.Raw())
+	}
+
+	return doPushRequest(s.client, req)
+}
+
+// gotifySender pushes the messages to a Gotify server.
+//
+// The channel URL is the server root URL and the secret is an application
+// token.
+type gotifySender struct {
+	client *http.Client
+}
+
+func newGotifySender(client *http.Client) *gotifySender {
+	return &gotifySender{client: client}
+}
+
+func (s *gotifySender) Send(ctx context.Context, channel *Channel, token secret.Text, msg *Message) error {
+	payload, err := json.Marshal(map[string]any{
+		"title":    msg.Title,
+		"message":  msg.Body,
+		"priority": channel.settings.Priority,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the payload: %w", err)
+	}
+
+	url := strings.TrimSuffix(channel.settings.URL, "/") + "/message"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", token.Raw())
+
+	return doPushRequest(s.client, req)
+}
+
+func doPushRequest(client *http.Client, req *http.Request) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.StatusCode)
+	}
+
+	return nil
+}
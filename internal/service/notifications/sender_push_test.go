@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushSenders(t *testing.T) {
+	ctx := context.Background()
+
+	msg := &Message{
+		At:       time.Now(),
+		Title:    "some-title",
+		Body:     "some-body",
+		Severity: Critical,
+	}
+
+	t.Run("ntfy success", func(t *testing.T) {
+		var req *http.Request
+		var body []byte
+
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			req = r
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer srv.Close()
+
+		sender := newNtfySender(srv.Client())
+		channel := NewFakeChannel(t).WithKind(Ntfy).WithSettings(Settings{URL: srv.URL + "/some-topic", Priority: 4}).Build()
+
+		err := sender.Send(ctx, channel, secret.NewText("some-token"), msg)
+		require.NoError(t, err)
+
+		assert.Equal(t, "/some-topic", req.URL.Path)
+		assert.Equal(t, "some-title", req.Header.Get("Title"))
+		assert.Equal(t, "4", req.Header.Get("Priority"))
+		assert.Equal(t, "critical", req.Header.Get("Tags"))
+		assert.Equal(t, "Bearer some-token", req.Header.Get("Authorization"))
+		assert.Equal(t, "some-body", string(body))
+	})
+
+	t.Run("ntfy with an error status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer srv.Close()
+
+		sender := newNtfySender(srv.Client())
+		channel := NewFakeChannel(t).WithKind(Ntfy).WithSettings(Settings{URL: srv.URL}).Build()
+
+		err := sender.Send(ctx, channel, secret.Empty, msg)
+		require.ErrorIs(t, err, ErrUnexpectedStatus)
+	})
+
+	t.Run("gotify success", func(t *testing.T) {
+		var req *http.Request
+		var body map[string]any
+
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			req = r
+			_ = json.NewDecoder(r.Body).Decode(&body)
+		}))
+		defer srv.Close()
+
+		sender := newGotifySender(srv.Client())
+		channel := NewFakeChannel(t).WithKind(Gotify).WithSettings(Settings{URL: srv.URL + "/", Priority: 8}).Build()
+
+		err := sender.Send(ctx, channel, secret.NewText("some-token"), msg)
+		require.NoError(t, err)
+
+		assert.Equal(t, "/message", req.URL.Path)
+		assert.Equal(t, "some-token", req.Header.Get("X-Gotify-Key"))
+		assert.Equal(t, map[string]any{
+			"title":    "some-title",
+			"message":  "some-body",
+			"priority": float64(8),
+		}, body)
+	})
+}
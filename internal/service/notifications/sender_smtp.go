@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+)
+
+type smtpSender struct {
+	timeout time.Duration
+}
+
+func newSMTPSender(timeout time.Duration) *smtpSender {
+	return &smtpSender{timeout: timeout}
+}
+
+// Send sends the message by email. STARTTLS is used as soon as the server
+// supports it.
+func (s *smtpSender) Send(ctx context.Context, channel *Channel, password secret.Text, msg *Message) error {
+	settings := channel.settings
+	addr := net.JoinHostPort(settings.Host, strconv.Itoa(settings.Port))
+
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %q: %w", addr, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(s.timeout))
+	}
+
+	client, err := smtp.NewClient(conn, settings.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake error: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: settings.Host, MinVersion: tls.VersionTLS12})
+		if err != nil {
+			return fmt.Errorf("STARTTLS error: %w", err)
+		}
+	}
+
+	if settings.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", settings.Username, password.Raw(), settings.Host))
+		if err != nil {
+			return fmt.Errorf("authentication error: %w", err)
+		}
+	}
+
+	err = client.Mail(settings.From)
+	if err != nil {
+		return fmt.Errorf("MAIL FROM error: %w", err)
+	}
+
+	for _, to := range settings.To {
+		err = client.Rcpt(to)
+		if err != nil {
+			return fmt.Errorf("RCPT TO %q error: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA error: %w", err)
+	}
+
+	_, err = w.Write(formatEmail(&settings, msg))
+	if err != nil {
+		return fmt.Errorf("failed to write the email: %w", err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("failed to send the email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func formatEmail(settings *Settings, msg *Message) []byte {
+	buf := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buf, "From: %s\r\n", settings.From)
+	fmt.Fprintf(buf, "To: %s\r\n", strings.Join(settings.To, ", "))
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", fmt.Sprintf("[%s] %s", msg.Severity, msg.Title)))
+	fmt.Fprintf(buf, "Date: %s\r\n", msg.At.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	buf.WriteString("\r\n")
+
+	return buf.Bytes()
+}
@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer is a minimal SMTP server accepting a single email.
+type fakeSMTPServer struct {
+	ln    net.Listener
+	rcpts []string
+	from  string
+	data  string
+	done  chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &fakeSMTPServer{ln: ln, done: make(chan struct{})}
+	t.Cleanup(func() { ln.Close() })
+
+	go srv.serve()
+
+	return srv
+}
+
+func (s *fakeSMTPServer) Port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	write := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	write("220 localhost ESMTP")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			write("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			write("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.rcpts = append(s.rcpts, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			write("250 OK")
+		case cmd == "DATA":
+			write("354 Go ahead")
+
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+
+			s.data = data.String()
+			write("250 OK")
+		case cmd == "QUIT":
+			write("221 Bye")
+			return
+		default:
+			write("502 Unsupported")
+		}
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Send success", func(t *testing.T) {
+		srv := newFakeSMTPServer(t)
+
+		sender := newSMTPSender(time.Second)
+		channel := NewFakeChannel(t).WithKind(SMTP).WithSettings(Settings{
+			Host: "127.0.0.1",
+			Port: srv.Port(),
+			From: "zapette@example.com",
+			To:   []string{"alice@example.com", "bob@example.com"},
+		}).Build()
+
+		err := sender.Send(ctx, channel, secret.Empty, &Message{
+			At:       time.Now(),
+			Title:    "some-title",
+			Body:     "some-body",
+			Severity: Critical,
+		})
+		require.NoError(t, err)
+
+		<-srv.done
+		assert.Equal(t, "zapette@example.com", srv.from)
+		assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, srv.rcpts)
+		assert.Contains(t, srv.data, "Subject: [critical] some-title\r\n")
+		assert.Contains(t, srv.data, "To: alice@example.com, bob@example.com\r\n")
+		assert.Contains(t, srv.data, "\r\n\r\nsome-body\r\n")
+	})
+
+	t.Run("Send with an unreachable server", func(t *testing.T) {
+		sender := newSMTPSender(time.Second)
+		channel := NewFakeChannel(t).WithKind(SMTP).WithSettings(Settings{
+			Host: "127.0.0.1",
+			Port: 1,
+			From: "zapette@example.com",
+			To:   []string{"alice@example.com"},
+		}).Build()
+
+		err := sender.Send(ctx, channel, secret.Empty, &Message{At: time.Now()})
+		require.ErrorContains(t, err, "failed to connect")
+	})
+}
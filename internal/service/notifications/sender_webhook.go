@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+)
+
+const (
+	SignatureHeader = "X-Zapette-Signature"
+
+	webhookMaxRetries = 3
+	webhookBackoff    = time.Second
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected status code")
+
+// permanentError is an error which won't be fixed by a retry.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+type webhookSender struct {
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+func newWebhookSender(client *http.Client) *webhookSender {
+	return &webhookSender{
+		client:     client,
+		maxRetries: webhookMaxRetries,
+		backoff:    webhookBackoff,
+	}
+}
+
+// Send POST the message as a JSON payload. A request failing with a network
+// error, a 429 or a 5xx status code is retried with an exponential backoff.
+func (s *webhookSender) Send(ctx context.Context, channel *Channel, signingKey secret.Text, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the payload: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = s.post(ctx, channel.settings.URL, payload, signingKey)
+		if err == nil {
+			return nil
+		}
+
+		var permErr *permanentError
+		if errors.As(err, &permErr) || attempt >= s.maxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(s.backoff << attempt):
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+	}
+}
+
+func (s *webhookSender) post(ctx context.Context, url string, payload []byte, signingKey secret.Text) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to create the request: %w", err)}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "zapette")
+
+	if signingKey.Raw() != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(signingKey, payload))
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return nil
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500:
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.StatusCode)
+	default:
+		return &permanentError{fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.StatusCode)}
+	}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the payload. The receiver can
+// use it to check the "X-Zapette-Signature" header.
+func Sign(key secret.Text, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(key.Raw()))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,123 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSender(t *testing.T) {
+	ctx := context.Background()
+
+	msg := &Message{
+		At:       time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC),
+		Labels:   map[string]string{"host": "some-host"},
+		Title:    "some-title",
+		Body:     "some-body",
+		Severity: Warning,
+	}
+
+	t.Run("Send a signed payload", func(t *testing.T) {
+		var payload []byte
+		var signature string
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, _ = io.ReadAll(r.Body)
+			signature = r.Header.Get(SignatureHeader)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		sender := newWebhookSender(srv.Client())
+		channel := NewFakeChannel(t).WithSettings(Settings{URL: srv.URL}).Build()
+
+		err := sender.Send(ctx, channel, secret.NewText("some-key"), msg)
+		require.NoError(t, err)
+
+		var res Message
+		require.NoError(t, json.Unmarshal(payload, &res))
+		assert.Equal(t, *msg, res)
+		assert.Equal(t, "sha256="+Sign(secret.NewText("some-key"), payload), signature)
+	})
+
+	t.Run("Send without a key doesn't sign", func(t *testing.T) {
+		var signature string
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature = r.Header.Get(SignatureHeader)
+		}))
+		defer srv.Close()
+
+		sender := newWebhookSender(srv.Client())
+		channel := NewFakeChannel(t).WithSettings(Settings{URL: srv.URL}).Build()
+
+		err := sender.Send(ctx, channel, secret.Empty, msg)
+		require.NoError(t, err)
+		assert.Empty(t, signature)
+	})
+
+	t.Run("Send retries the server errors", func(t *testing.T) {
+		var calls atomic.Int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+		}))
+		defer srv.Close()
+
+		sender := newWebhookSender(srv.Client())
+		sender.backoff = time.Millisecond
+		channel := NewFakeChannel(t).WithSettings(Settings{URL: srv.URL}).Build()
+
+		err := sender.Send(ctx, channel, secret.Empty, msg)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("Send stops after the max retries", func(t *testing.T) {
+		var calls atomic.Int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		sender := newWebhookSender(srv.Client())
+		sender.backoff = time.Millisecond
+		channel := NewFakeChannel(t).WithSettings(Settings{URL: srv.URL}).Build()
+
+		err := sender.Send(ctx, channel, secret.Empty, msg)
+		require.ErrorIs(t, err, ErrUnexpectedStatus)
+		assert.Equal(t, int32(webhookMaxRetries+1), calls.Load())
+	})
+
+	t.Run("Send doesn't retry the client errors", func(t *testing.T) {
+		var calls atomic.Int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		sender := newWebhookSender(srv.Client())
+		sender.backoff = time.Millisecond
+		channel := NewFakeChannel(t).WithSettings(Settings{URL: srv.URL}).Build()
+
+		err := sender.Send(ctx, channel, secret.Empty, msg)
+		require.ErrorIs(t, err, ErrUnexpectedStatus)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
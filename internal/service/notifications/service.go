@@ -0,0 +1,210 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/masterkey"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const sendTimeout = 30 * time.Second
+
+var ErrUnsupportedKind = errors.New("unsupported channel kind")
+
+type storage interface {
+	Save(ctx context.Context, channel *Channel) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Channel, error)
+	GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Channel, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// sender delivers a message to a given channel kind.
+type sender interface {
+	Send(ctx context.Context, channel *Channel, secret secret.Text, msg *Message) error
+}
+
+type service struct {
+	storage   storage
+	masterkey masterkey.Service
+	clock     clock.Clock
+	uuid      uuid.Service
+	log       *slog.Logger
+	senders   map[Kind]sender
+}
+
+func newService(storage storage, masterkey masterkey.Service, tools tools.Tools) *service {
+	client := &http.Client{Timeout: sendTimeout}
+
+	return &service{
+		storage:   storage,
+		masterkey: masterkey,
+		clock:     tools.Clock(),
+		uuid:      tools.UUID(),
+		log:       tools.Logger().With(slog.String("source", "notifications")),
+		senders: map[Kind]sender{
+			Webhook: newWebhookSender(client),
+			SMTP:    newSMTPSender(sendTimeout),
+			Ntfy:    newNtfySender(client),
+			Gotify:  newGotifySender(client),
+		},
+	}
+}
+
+func (s *service) Create(ctx context.Context, cmd *CreateCmd) (*Channel, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	// Each channel have its own key, sealed with the master key. This key
+	// is used to encrypt the channel secret.
+	channelKey, err := secret.NewKey()
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to generate the channel key: %w", err))
+	}
+
+	sealedKey, err := s.masterkey.SealKey(channelKey)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to seal the channel key: %w", err))
+	}
+
+	var sealedSecret *secret.SealedText
+	if cmd.Secret.Raw() != "" {
+		sealedSecret, err = secret.SealText(channelKey, cmd.Secret)
+		if err != nil {
+			return nil, errs.Internal(fmt.Errorf("failed to seal the channel secret: %w", err))
+		}
+	}
+
+	channel := Channel{
+		id:        s.uuid.New(),
+		name:      cmd.Name,
+		kind:      cmd.Kind,
+		settings:  cmd.Settings,
+		sealedKey: sealedKey,
+		secret:    sealedSecret,
+		createdAt: s.clock.Now(),
+		createdBy: cmd.CreatedBy.ID(),
+	}
+
+	err = s.storage.Save(ctx, &channel)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to save the channel: %w", err))
+	}
+
+	return &channel, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*Channel, error) {
+	res, err := s.storage.GetByID(ctx, id)
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Channel, error) {
+	res, err := s.storage.GetAll(ctx, cmd)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+	err := s.storage.Delete(ctx, id)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to Delete: %w", err))
+	}
+
+	return nil
+}
+
+func (s *service) SendTest(ctx context.Context, channel *Channel) error {
+	return s.send(ctx, channel, &Message{
+		At:       s.clock.Now(),
+		Title:    "Zapette test notification",
+		Body:     fmt.Sprintf("If you can read this, the channel %q is properly configured.", channel.name),
+		Severity: Info,
+	})
+}
+
+// Notify sends the message to all the channels.
+//
+// A failing channel doesn't prevent the message to be sent to the others.
+func (s *service) Notify(ctx context.Context, msg *Message) error {
+	channels, err := s.GetAll(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to GetAll: %w", err)
+	}
+
+	var sendErrs []error
+	for i := range channels {
+		err = s.send(ctx, &channels[i], msg)
+		if err != nil {
+			s.log.Error("failed to send a notification",
+				slog.String("channel", channels[i].name),
+				slog.String("error", err.Error()))
+			sendErrs = append(sendErrs, fmt.Errorf("channel %q: %w", channels[i].name, err))
+		}
+	}
+
+	if len(sendErrs) > 0 {
+		return errs.Internal(errors.Join(sendErrs...))
+	}
+
+	return nil
+}
+
+func (s *service) send(ctx context.Context, channel *Channel, msg *Message) error {
+	sender, ok := s.senders[channel.kind]
+	if !ok {
+		return errs.Internal(fmt.Errorf("%w: %q", ErrUnsupportedKind, channel.kind))
+	}
+
+	channelSecret, err := s.openSecret(channel)
+	if err != nil {
+		return errs.Internal(err)
+	}
+
+	err = sender.Send(ctx, channel, channelSecret, msg)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to send the %s notification: %w", channel.kind, err))
+	}
+
+	return nil
+}
+
+func (s *service) openSecret(channel *Channel) (secret.Text, error) {
+	if channel.secret == nil {
+		return secret.Empty, nil
+	}
+
+	channelKey, err := s.masterkey.Open(channel.sealedKey)
+	if err != nil {
+		return secret.Empty, fmt.Errorf("failed to open the channel key: %w", err)
+	}
+
+	res, err := channel.secret.Open(channelKey)
+	if err != nil {
+		return secret.Empty, fmt.Errorf("failed to open the channel secret: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,171 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/masterkey"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationsService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		masterkeyMock := masterkey.NewMockService(t)
+		svc := newService(storageMock, masterkeyMock, tools)
+
+		// Data
+		user := users.NewFakeUser(t).Build()
+		now := time.Now()
+		sealedKey := NewFakeChannel(t).Build().sealedKey
+
+		// Mocks
+		masterkeyMock.On("SealKey", mock.AnythingOfType("*secret.Key")).Return(sealedKey, nil).Once()
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-channel-id")).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("Save", mock.Anything, mock.AnythingOfType("*notifications.Channel")).Return(nil).Once()
+
+		// Run
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: user,
+			Name:      "My hook",
+			Kind:      Webhook,
+			Secret:    secret.NewText("some-signing-key"),
+			Settings:  Settings{URL: "https://example.com/hook"},
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, uuid.UUID("some-channel-id"), res.ID())
+		assert.Equal(t, "My hook", res.Name())
+		assert.Equal(t, Webhook, res.Kind())
+		assert.Equal(t, user.ID(), res.CreatedBy())
+		assert.Equal(t, now, res.CreatedAt())
+		assert.Equal(t, sealedKey, res.sealedKey)
+		assert.True(t, res.HasSecret())
+	})
+
+	t.Run("Create with an invalid smtp config", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		masterkeyMock := masterkey.NewMockService(t)
+		svc := newService(storageMock, masterkeyMock, tools)
+
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: users.NewFakeUser(t).Build(),
+			Name:      "My mail",
+			Kind:      SMTP,
+			Settings:  Settings{Host: "localhost", Port: 25, From: "not-an-email"},
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Create with an unknown kind", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		masterkeyMock := masterkey.NewMockService(t)
+		svc := newService(storageMock, masterkeyMock, tools)
+
+		res, err := svc.Create(ctx, &CreateCmd{
+			CreatedBy: users.NewFakeUser(t).Build(),
+			Name:      "My pigeon",
+			Kind:      Kind("pigeon"),
+			Settings:  Settings{URL: "https://example.com"},
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		masterkeyMock := masterkey.NewMockService(t)
+		svc := newService(storageMock, masterkeyMock, tools)
+
+		storageMock.On("GetByID", mock.Anything, uuid.UUID("some-id")).Return(nil, errNotFound).Once()
+
+		res, err := svc.GetByID(ctx, uuid.UUID("some-id"))
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("SendTest open the secret and send the message", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		masterkeyMock := masterkey.NewMockService(t)
+		senderMock := newMockSender(t)
+		svc := newService(storageMock, masterkeyMock, tools)
+		svc.senders[Webhook] = senderMock
+
+		// Data
+		channelKey, err := secret.NewKey()
+		require.NoError(t, err)
+		channel := NewFakeChannel(t).WithSecret(channelKey, "some-secret").Build()
+		now := time.Now()
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		masterkeyMock.On("Open", channel.sealedKey).Return(channelKey, nil).Once()
+		senderMock.On("Send", mock.Anything, channel, secret.NewText("some-secret"), &Message{
+			At:       now,
+			Title:    "Zapette test notification",
+			Body:     "If you can read this, the channel \"" + channel.Name() + "\" is properly configured.",
+			Severity: Info,
+		}).Return(nil).Once()
+
+		// Run
+		err = svc.SendTest(ctx, channel)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("Notify send to all the channels even if one fails", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		masterkeyMock := masterkey.NewMockService(t)
+		webhookMock := newMockSender(t)
+		ntfyMock := newMockSender(t)
+		svc := newService(storageMock, masterkeyMock, tools)
+		svc.senders[Webhook] = webhookMock
+		svc.senders[Ntfy] = ntfyMock
+
+		// Data
+		webhook := NewFakeChannel(t).Build()
+		ntfy := NewFakeChannel(t).WithKind(Ntfy).Build()
+		msg := &Message{At: time.Now(), Title: "some-title", Body: "some-body", Severity: Critical}
+
+		// Mocks
+		storageMock.On("GetAll", mock.Anything, mock.Anything).Return([]Channel{*webhook, *ntfy}, nil).Once()
+		webhookMock.On("Send", mock.Anything, webhook, secret.Empty, msg).Return(errors.New("some-error")).Once()
+		ntfyMock.On("Send", mock.Anything, ntfy, secret.Empty, msg).Return(nil).Once()
+
+		// Run
+		err := svc.Notify(ctx, msg)
+
+		// Asserts
+		require.ErrorIs(t, err, errs.ErrInternal)
+		require.ErrorContains(t, err, "some-error")
+	})
+}
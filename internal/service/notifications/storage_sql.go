@@ -0,0 +1,147 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const tableName = "notification_channels"
+
+var errNotFound = errors.New("not found")
+
+var allFields = []string{"id", "name", "kind", "settings", "sealed_key", "secret", "created_at", "created_by"}
+
+type sqlStorage struct {
+	db *sql.DB
+}
+
+func newSqlStorage(db *sql.DB) *sqlStorage {
+	return &sqlStorage{db}
+}
+
+func (s *sqlStorage) Save(ctx context.Context, c *Channel) error {
+	rawSettings, err := json.Marshal(c.settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the settings: %w", err)
+	}
+
+	_, err = sq.
+		Insert(tableName).
+		Columns(allFields...).
+		Values(
+			c.id,
+			c.name,
+			c.kind,
+			string(rawSettings),
+			c.sealedKey,
+			c.secret,
+			ptr.To(sqlstorage.SQLTime(c.createdAt)),
+			c.createdBy,
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetByID(ctx context.Context, id uuid.UUID) (*Channel, error) {
+	row := sq.
+		Select(allFields...).
+		From(tableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		QueryRowContext(ctx)
+
+	res, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) GetAll(ctx context.Context, cmd *sqlstorage.PaginateCmd) ([]Channel, error) {
+	rows, err := sqlstorage.PaginateSelection(sq.
+		Select(allFields...).
+		From(tableName), cmd).
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []Channel{}
+
+	for rows.Next() {
+		res, err := s.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		channels = append(channels, *res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return channels, nil
+}
+
+func (s *sqlStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := sq.
+		Delete(tableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) scan(row sqlstorage.RowScanner) (*Channel, error) {
+	var res Channel
+	var rawSettings string
+	var sqlCreatedAt sqlstorage.SQLTime
+
+	err := row.Scan(
+		&res.id,
+		&res.name,
+		&res.kind,
+		&rawSettings,
+		&res.sealedKey,
+		&res.secret,
+		&sqlCreatedAt,
+		&res.createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(rawSettings), &res.settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+
+	res.createdAt = sqlCreatedAt.Time()
+
+	return &res, nil
+}
@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationChannelsSqlStorage(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqlstorage.NewTestStorage(t)
+	store := newSqlStorage(db)
+
+	channelKey, err := secret.NewKey()
+	require.NoError(t, err)
+
+	channel := NewFakeChannel(t).WithSecret(channelKey, "some-secret").Build()
+	channelWithoutSecret := NewFakeChannel(t).
+		WithKind(SMTP).
+		WithSettings(Settings{Host: "localhost", Port: 25, From: "foo@bar.com", To: []string{"bar@foo.com"}}).
+		Build()
+
+	t.Run("GetAll with nothing", func(t *testing.T) {
+		res, err := store.GetAll(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Save success", func(t *testing.T) {
+		err := store.Save(ctx, channel)
+		require.NoError(t, err)
+	})
+
+	t.Run("Save without secret success", func(t *testing.T) {
+		err := store.Save(ctx, channelWithoutSecret)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetByID success", func(t *testing.T) {
+		res, err := store.GetByID(ctx, channel.ID())
+		require.NoError(t, err)
+		assert.Equal(t, channel, res)
+
+		secret, err := res.secret.Open(channelKey)
+		require.NoError(t, err)
+		assert.Equal(t, "some-secret", secret.Raw())
+	})
+
+	t.Run("GetByID without secret success", func(t *testing.T) {
+		res, err := store.GetByID(ctx, channelWithoutSecret.ID())
+		require.NoError(t, err)
+		assert.Equal(t, channelWithoutSecret, res)
+		assert.False(t, res.HasSecret())
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+		res, err := store.GetByID(ctx, "some-invalid-id")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("GetAll success", func(t *testing.T) {
+		res, err := store.GetAll(ctx, nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []Channel{*channel, *channelWithoutSecret}, res)
+	})
+
+	t.Run("Delete success", func(t *testing.T) {
+		err := store.Delete(ctx, channel.ID())
+		require.NoError(t, err)
+
+		res, err := store.GetByID(ctx, channel.ID())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+}
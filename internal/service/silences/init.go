@@ -0,0 +1,25 @@
+package silences
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+type Service interface {
+	CreateSilence(ctx context.Context, cmd *CreateSilenceCmd) (*Silence, error)
+	GetSilences(ctx context.Context) ([]Silence, error)
+	ExpireSilence(ctx context.Context, id uuid.UUID) error
+	CreateMaintenanceWindow(ctx context.Context, cmd *CreateMaintenanceWindowCmd) (*MaintenanceWindow, error)
+	GetMaintenanceWindows(ctx context.Context) ([]MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, id uuid.UUID) error
+	GetMuteReason(ctx context.Context, labels map[string]string) (*MuteReason, error)
+}
+
+func Init(db *sql.DB, tools tools.Tools) Service {
+	storage := newSqlStorage(db)
+
+	return newService(storage, tools)
+}
@@ -0,0 +1,279 @@
+package silences
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	v "github.com/go-ozzo/ozzo-validation"
+)
+
+const maxWindowDuration = 7 * 24 * time.Hour
+
+var ErrInvalidMatcher = errors.New("invalid matcher")
+
+// Matcher matches an alert label against a value or, if IsRegex is set,
+// against a regex anchored on both ends.
+//
+// The alert name is available under the "alertname" label.
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+}
+
+func (m Matcher) Validate() error {
+	return v.ValidateStruct(&m,
+		v.Field(&m.Name, v.Required, v.Length(1, 100)),
+		v.Field(&m.Value, v.By(func(_ any) error {
+			if !m.IsRegex {
+				return nil
+			}
+
+			_, err := regexp.Compile("^(?:" + m.Value + ")$")
+			return err
+		})),
+	)
+}
+
+func (m Matcher) Matches(labels map[string]string) bool {
+	label := labels[m.Name]
+
+	if !m.IsRegex {
+		return label == m.Value
+	}
+
+	re, err := regexp.Compile("^(?:" + m.Value + ")$")
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(label)
+}
+
+func (m Matcher) String() string {
+	if m.IsRegex {
+		return m.Name + "=~" + m.Value
+	}
+
+	return m.Name + "=" + m.Value
+}
+
+// Matchers is a set of Matcher. They all need to match for the set to match.
+// An empty set matches all the alerts.
+type Matchers []Matcher
+
+func (m Matchers) Matches(labels map[string]string) bool {
+	for _, matcher := range m {
+		if !matcher.Matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m Matchers) String() string {
+	if len(m) == 0 {
+		return "all alerts"
+	}
+
+	res := make([]string, len(m))
+	for i, matcher := range m {
+		res[i] = matcher.String()
+	}
+
+	return strings.Join(res, ", ")
+}
+
+// ParseMatchers parses a comma separated list of matchers with the format
+// `name=value` or `name=~regex`.
+func ParseMatchers(str string) (Matchers, error) {
+	res := Matchers{}
+
+	for _, raw := range strings.Split(str, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(raw, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMatcher, raw)
+		}
+
+		matcher := Matcher{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)}
+		if strings.HasPrefix(value, "~") {
+			matcher.Value = strings.TrimSpace(value[1:])
+			matcher.IsRegex = true
+		}
+
+		err := matcher.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidMatcher, raw, err)
+		}
+
+		res = append(res, matcher)
+	}
+
+	return res, nil
+}
+
+// Silence mutes the notifications of the matching alerts between startsAt
+// and endsAt.
+type Silence struct {
+	startsAt  time.Time
+	endsAt    time.Time
+	createdAt time.Time
+	id        uuid.UUID
+	comment   string
+	createdBy uuid.UUID
+	matchers  Matchers
+}
+
+func (s Silence) ID() uuid.UUID        { return s.id }
+func (s Silence) Matchers() Matchers   { return s.matchers }
+func (s Silence) StartsAt() time.Time  { return s.startsAt }
+func (s Silence) EndsAt() time.Time    { return s.endsAt }
+func (s Silence) Comment() string      { return s.comment }
+func (s Silence) CreatedAt() time.Time { return s.createdAt }
+func (s Silence) CreatedBy() uuid.UUID { return s.createdBy }
+
+func (s Silence) IsActive(now time.Time) bool {
+	return !now.Before(s.startsAt) && now.Before(s.endsAt)
+}
+
+func (s *Silence) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"id":        s.id,
+		"matchers":  s.matchers,
+		"startsAt":  s.startsAt,
+		"endsAt":    s.endsAt,
+		"comment":   s.comment,
+		"createdAt": s.createdAt,
+		"createdBy": s.createdBy,
+	})
+}
+
+// MaintenanceWindow mutes the notifications of the matching alerts every
+// week, on the given weekdays, from start (an offset since midnight, in the
+// server timezone) and for duration.
+type MaintenanceWindow struct {
+	createdAt time.Time
+	id        uuid.UUID
+	name      string
+	createdBy uuid.UUID
+	matchers  Matchers
+	weekdays  []time.Weekday
+	start     time.Duration
+	duration  time.Duration
+}
+
+func (w MaintenanceWindow) ID() uuid.UUID            { return w.id }
+func (w MaintenanceWindow) Name() string             { return w.name }
+func (w MaintenanceWindow) Matchers() Matchers       { return w.matchers }
+func (w MaintenanceWindow) Weekdays() []time.Weekday { return w.weekdays }
+func (w MaintenanceWindow) Start() time.Duration     { return w.start }
+func (w MaintenanceWindow) Duration() time.Duration  { return w.duration }
+func (w MaintenanceWindow) CreatedAt() time.Time     { return w.createdAt }
+func (w MaintenanceWindow) CreatedBy() uuid.UUID     { return w.createdBy }
+
+// StartClock returns the start as a "15:04" string.
+func (w MaintenanceWindow) StartClock() string {
+	return fmt.Sprintf("%02d:%02d", int(w.start.Hours()), int(w.start.Minutes())%60)
+}
+
+// IsActive checks if now is inside one of the window occurrences. As an
+// occurrence can last several days, the previous week is checked too.
+func (w MaintenanceWindow) IsActive(now time.Time) bool {
+	for i := 0; i <= 7; i++ {
+		day := now.AddDate(0, 0, -i)
+		if !slices.Contains(w.weekdays, day.Weekday()) {
+			continue
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, now.Location()).Add(w.start)
+		if !now.Before(start) && now.Before(start.Add(w.duration)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *MaintenanceWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"id":        w.id,
+		"name":      w.name,
+		"matchers":  w.matchers,
+		"weekdays":  w.weekdays,
+		"start":     w.StartClock(),
+		"duration":  w.duration.String(),
+		"createdAt": w.createdAt,
+		"createdBy": w.createdBy,
+	})
+}
+
+// MuteReason explains why an alert is muted. Only one of the fields is set.
+type MuteReason struct {
+	Silence *Silence
+	Window  *MaintenanceWindow
+}
+
+func (r MuteReason) String() string {
+	if r.Silence != nil {
+		return fmt.Sprintf("silenced until %s: %s", r.Silence.endsAt.Local().Format(time.DateTime), r.Silence.comment)
+	}
+
+	return fmt.Sprintf("maintenance window %q", r.Window.name)
+}
+
+type CreateSilenceCmd struct {
+	StartsAt  time.Time
+	EndsAt    time.Time
+	CreatedBy *users.User
+	Comment   string
+	Matchers  Matchers
+}
+
+func (t CreateSilenceCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.CreatedBy, v.Required),
+		v.Field(&t.Comment, v.Required, v.Length(1, 500)),
+		v.Field(&t.Matchers),
+		v.Field(&t.StartsAt, v.Required),
+		v.Field(&t.EndsAt, v.Required, v.By(func(_ any) error {
+			if !t.EndsAt.After(t.StartsAt) {
+				return errors.New("must be after the start")
+			}
+
+			return nil
+		})),
+	)
+}
+
+type CreateMaintenanceWindowCmd struct {
+	CreatedBy *users.User
+	Name      string
+	Matchers  Matchers
+	Weekdays  []time.Weekday
+	Start     time.Duration
+	Duration  time.Duration
+}
+
+func (t CreateMaintenanceWindowCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.CreatedBy, v.Required),
+		v.Field(&t.Name, v.Required, v.Length(1, 50)),
+		v.Field(&t.Matchers),
+		v.Field(&t.Weekdays, v.Required, v.Each(v.Min(time.Sunday), v.Max(time.Saturday))),
+		v.Field(&t.Start, v.Min(time.Duration(0)), v.Max(24*time.Hour-time.Minute)),
+		v.Field(&t.Duration, v.Required, v.Min(time.Minute), v.Max(maxWindowDuration)),
+	)
+}
@@ -0,0 +1,120 @@
+package silences
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+type FakeSilenceBuilder struct {
+	t       testing.TB
+	silence *Silence
+}
+
+func NewFakeSilence(t testing.TB) *FakeSilenceBuilder {
+	t.Helper()
+
+	uuidProvider := uuid.NewProvider()
+	createdAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now()).UTC()
+
+	return &FakeSilenceBuilder{
+		t: t,
+		silence: &Silence{
+			id:        uuidProvider.New(),
+			matchers:  Matchers{{Name: "alertname", Value: gofakeit.Word()}},
+			startsAt:  createdAt,
+			endsAt:    createdAt.Add(time.Hour),
+			comment:   gofakeit.Sentence(5),
+			createdAt: createdAt,
+			createdBy: uuidProvider.New(),
+		},
+	}
+}
+
+func (f *FakeSilenceBuilder) WithMatchers(matchers ...Matcher) *FakeSilenceBuilder {
+	f.silence.matchers = matchers
+
+	return f
+}
+
+func (f *FakeSilenceBuilder) WithPeriod(startsAt, endsAt time.Time) *FakeSilenceBuilder {
+	f.silence.startsAt = startsAt.UTC()
+	f.silence.endsAt = endsAt.UTC()
+
+	return f
+}
+
+func (f *FakeSilenceBuilder) Build() *Silence {
+	return f.silence
+}
+
+func (f *FakeSilenceBuilder) BuildAndStore(ctx context.Context, db *sql.DB) *Silence {
+	f.t.Helper()
+
+	storage := newSqlStorage(db)
+
+	err := storage.SaveSilence(ctx, f.silence)
+	require.NoError(f.t, err)
+
+	return f.silence
+}
+
+type FakeMaintenanceWindowBuilder struct {
+	t      testing.TB
+	window *MaintenanceWindow
+}
+
+func NewFakeMaintenanceWindow(t testing.TB) *FakeMaintenanceWindowBuilder {
+	t.Helper()
+
+	uuidProvider := uuid.NewProvider()
+	createdAt := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+
+	return &FakeMaintenanceWindowBuilder{
+		t: t,
+		window: &MaintenanceWindow{
+			id:        uuidProvider.New(),
+			name:      gofakeit.AppName(),
+			matchers:  Matchers{},
+			weekdays:  []time.Weekday{time.Sunday},
+			start:     2 * time.Hour,
+			duration:  time.Hour,
+			createdAt: createdAt.UTC(),
+			createdBy: uuidProvider.New(),
+		},
+	}
+}
+
+func (f *FakeMaintenanceWindowBuilder) WithMatchers(matchers ...Matcher) *FakeMaintenanceWindowBuilder {
+	f.window.matchers = matchers
+
+	return f
+}
+
+func (f *FakeMaintenanceWindowBuilder) WithSchedule(weekdays []time.Weekday, start, duration time.Duration) *FakeMaintenanceWindowBuilder {
+	f.window.weekdays = weekdays
+	f.window.start = start
+	f.window.duration = duration
+
+	return f
+}
+
+func (f *FakeMaintenanceWindowBuilder) Build() *MaintenanceWindow {
+	return f.window
+}
+
+func (f *FakeMaintenanceWindowBuilder) BuildAndStore(ctx context.Context, db *sql.DB) *MaintenanceWindow {
+	f.t.Helper()
+
+	storage := newSqlStorage(db)
+
+	err := storage.SaveMaintenanceWindow(ctx, f.window)
+	require.NoError(f.t, err)
+
+	return f.window
+}
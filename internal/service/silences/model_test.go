@@ -0,0 +1,69 @@
+package silences
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMatchers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		res, err := ParseMatchers("alertname=disk-full, mount=~/var/.*")
+		require.NoError(t, err)
+		assert.Equal(t, Matchers{
+			{Name: "alertname", Value: "disk-full"},
+			{Name: "mount", Value: "/var/.*", IsRegex: true},
+		}, res)
+		assert.Equal(t, "alertname=disk-full, mount=~/var/.*", res.String())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		res, err := ParseMatchers(" ")
+		require.NoError(t, err)
+		assert.Empty(t, res)
+		assert.Equal(t, "all alerts", res.String())
+	})
+
+	t.Run("without value", func(t *testing.T) {
+		res, err := ParseMatchers("alertname")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrInvalidMatcher)
+	})
+
+	t.Run("with an invalid regex", func(t *testing.T) {
+		res, err := ParseMatchers("mount=~[")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrInvalidMatcher)
+	})
+}
+
+func TestMatchers(t *testing.T) {
+	labels := map[string]string{"alertname": "disk-full", "mount": "/var/lib"}
+
+	assert.True(t, Matchers{}.Matches(labels))
+	assert.True(t, Matchers{{Name: "alertname", Value: "disk-full"}}.Matches(labels))
+	assert.True(t, Matchers{{Name: "mount", Value: "/var/.*", IsRegex: true}}.Matches(labels))
+	assert.False(t, Matchers{{Name: "mount", Value: "/var", IsRegex: true}}.Matches(labels))
+	assert.False(t, Matchers{
+		{Name: "alertname", Value: "disk-full"},
+		{Name: "mount", Value: "/"},
+	}.Matches(labels))
+}
+
+func TestMaintenanceWindow_IsActive(t *testing.T) {
+	// Every saturday from 23:00 to 03:00
+	window := NewFakeMaintenanceWindow(t).
+		WithSchedule([]time.Weekday{time.Saturday}, 23*time.Hour, 4*time.Hour).
+		Build()
+
+	// 2024-06-01 is a saturday.
+	assert.False(t, window.IsActive(time.Date(2024, time.June, 1, 22, 59, 0, 0, time.UTC)))
+	assert.True(t, window.IsActive(time.Date(2024, time.June, 1, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, window.IsActive(time.Date(2024, time.June, 2, 2, 59, 0, 0, time.UTC)))
+	assert.False(t, window.IsActive(time.Date(2024, time.June, 2, 3, 0, 0, 0, time.UTC)))
+	assert.False(t, window.IsActive(time.Date(2024, time.June, 5, 23, 30, 0, 0, time.UTC)))
+	assert.True(t, window.IsActive(time.Date(2024, time.June, 8, 23, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "23:00", window.StartClock())
+}
@@ -0,0 +1,169 @@
+package silences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+type storage interface {
+	SaveSilence(ctx context.Context, silence *Silence) error
+	GetSilenceByID(ctx context.Context, id uuid.UUID) (*Silence, error)
+	GetSilencesEndingAfter(ctx context.Context, t time.Time) ([]Silence, error)
+	SetSilenceEnd(ctx context.Context, id uuid.UUID, endsAt time.Time) error
+	SaveMaintenanceWindow(ctx context.Context, window *MaintenanceWindow) error
+	GetAllMaintenanceWindows(ctx context.Context) ([]MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, id uuid.UUID) error
+}
+
+type service struct {
+	storage storage
+	clock   clock.Clock
+	uuid    uuid.Service
+}
+
+func newService(storage storage, tools tools.Tools) *service {
+	return &service{
+		storage: storage,
+		clock:   tools.Clock(),
+		uuid:    tools.UUID(),
+	}
+}
+
+func (s *service) CreateSilence(ctx context.Context, cmd *CreateSilenceCmd) (*Silence, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	silence := Silence{
+		id:        s.uuid.New(),
+		matchers:  cmd.Matchers,
+		startsAt:  cmd.StartsAt,
+		endsAt:    cmd.EndsAt,
+		comment:   cmd.Comment,
+		createdAt: s.clock.Now(),
+		createdBy: cmd.CreatedBy.ID(),
+	}
+
+	err = s.storage.SaveSilence(ctx, &silence)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to save the silence: %w", err))
+	}
+
+	return &silence, nil
+}
+
+// GetSilences returns the active and the upcoming silences.
+func (s *service) GetSilences(ctx context.Context) ([]Silence, error) {
+	res, err := s.storage.GetSilencesEndingAfter(ctx, s.clock.Now())
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+// ExpireSilence ends the silence right now. Expiring an already expired
+// silence does nothing.
+func (s *service) ExpireSilence(ctx context.Context, id uuid.UUID) error {
+	silence, err := s.storage.GetSilenceByID(ctx, id)
+	if errors.Is(err, errNotFound) {
+		return errs.NotFound(err)
+	}
+
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to GetSilenceByID: %w", err))
+	}
+
+	now := s.clock.Now()
+	if !silence.endsAt.After(now) {
+		return nil
+	}
+
+	err = s.storage.SetSilenceEnd(ctx, id, now)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to SetSilenceEnd: %w", err))
+	}
+
+	return nil
+}
+
+func (s *service) CreateMaintenanceWindow(ctx context.Context, cmd *CreateMaintenanceWindowCmd) (*MaintenanceWindow, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	window := MaintenanceWindow{
+		id:        s.uuid.New(),
+		name:      cmd.Name,
+		matchers:  cmd.Matchers,
+		weekdays:  cmd.Weekdays,
+		start:     cmd.Start,
+		duration:  cmd.Duration,
+		createdAt: s.clock.Now(),
+		createdBy: cmd.CreatedBy.ID(),
+	}
+
+	err = s.storage.SaveMaintenanceWindow(ctx, &window)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to save the maintenance window: %w", err))
+	}
+
+	return &window, nil
+}
+
+func (s *service) GetMaintenanceWindows(ctx context.Context) ([]MaintenanceWindow, error) {
+	res, err := s.storage.GetAllMaintenanceWindows(ctx)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) DeleteMaintenanceWindow(ctx context.Context, id uuid.UUID) error {
+	err := s.storage.DeleteMaintenanceWindow(ctx, id)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to DeleteMaintenanceWindow: %w", err))
+	}
+
+	return nil
+}
+
+// GetMuteReason returns the first active silence or maintenance window
+// matching the labels. It returns nil if the labels are not muted.
+func (s *service) GetMuteReason(ctx context.Context, labels map[string]string) (*MuteReason, error) {
+	now := s.clock.Now()
+
+	silences, err := s.storage.GetSilencesEndingAfter(ctx, now)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to GetSilencesEndingAfter: %w", err))
+	}
+
+	for i := range silences {
+		if silences[i].IsActive(now) && silences[i].matchers.Matches(labels) {
+			return &MuteReason{Silence: &silences[i]}, nil
+		}
+	}
+
+	windows, err := s.storage.GetAllMaintenanceWindows(ctx)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to GetAllMaintenanceWindows: %w", err))
+	}
+
+	for i := range windows {
+		if windows[i].IsActive(now) && windows[i].matchers.Matches(labels) {
+			return &MuteReason{Window: &windows[i]}, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,240 @@
+package silences
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSilencesService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("CreateSilence success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, tools)
+
+		// Data
+		user := users.NewFakeUser(t).Build()
+		now := time.Now()
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-silence-id")).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("SaveSilence", mock.Anything, mock.AnythingOfType("*silences.Silence")).Return(nil).Once()
+
+		// Run
+		res, err := svc.CreateSilence(ctx, &CreateSilenceCmd{
+			CreatedBy: user,
+			Matchers:  Matchers{{Name: "alertname", Value: "disk-full"}},
+			StartsAt:  now,
+			EndsAt:    now.Add(time.Hour),
+			Comment:   "Reboot",
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, uuid.UUID("some-silence-id"), res.ID())
+		assert.Equal(t, user.ID(), res.CreatedBy())
+		assert.Equal(t, "Reboot", res.Comment())
+		assert.True(t, res.IsActive(now))
+	})
+
+	t.Run("CreateSilence with an end before the start", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), tools.NewMock(t))
+
+		now := time.Now()
+
+		res, err := svc.CreateSilence(ctx, &CreateSilenceCmd{
+			CreatedBy: users.NewFakeUser(t).Build(),
+			StartsAt:  now,
+			EndsAt:    now.Add(-time.Hour),
+			Comment:   "Reboot",
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("ExpireSilence success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, tools)
+
+		// Data
+		now := time.Now()
+		silence := NewFakeSilence(t).WithPeriod(now.Add(-time.Hour), now.Add(time.Hour)).Build()
+
+		// Mocks
+		storageMock.On("GetSilenceByID", mock.Anything, silence.ID()).Return(silence, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("SetSilenceEnd", mock.Anything, silence.ID(), now).Return(nil).Once()
+
+		// Run
+		err := svc.ExpireSilence(ctx, silence.ID())
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("ExpireSilence with an already expired silence", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, tools)
+
+		now := time.Now()
+		silence := NewFakeSilence(t).WithPeriod(now.Add(-2*time.Hour), now.Add(-time.Hour)).Build()
+
+		storageMock.On("GetSilenceByID", mock.Anything, silence.ID()).Return(silence, nil).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+
+		err := svc.ExpireSilence(ctx, silence.ID())
+		require.NoError(t, err)
+	})
+
+	t.Run("ExpireSilence not found", func(t *testing.T) {
+		t.Parallel()
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, tools.NewMock(t))
+
+		storageMock.On("GetSilenceByID", mock.Anything, uuid.UUID("some-id")).Return(nil, errNotFound).Once()
+
+		err := svc.ExpireSilence(ctx, uuid.UUID("some-id"))
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("CreateMaintenanceWindow success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, tools)
+
+		// Data
+		user := users.NewFakeUser(t).Build()
+		now := time.Now()
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-window-id")).Once()
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("SaveMaintenanceWindow", mock.Anything, mock.AnythingOfType("*silences.MaintenanceWindow")).Return(nil).Once()
+
+		// Run
+		res, err := svc.CreateMaintenanceWindow(ctx, &CreateMaintenanceWindowCmd{
+			CreatedBy: user,
+			Name:      "Nightly backup",
+			Weekdays:  []time.Weekday{time.Sunday, time.Wednesday},
+			Start:     2 * time.Hour,
+			Duration:  time.Hour,
+		})
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, uuid.UUID("some-window-id"), res.ID())
+		assert.Equal(t, "02:00", res.StartClock())
+		assert.Equal(t, []time.Weekday{time.Sunday, time.Wednesday}, res.Weekdays())
+	})
+
+	t.Run("CreateMaintenanceWindow with a too long duration", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockStorage(t), tools.NewMock(t))
+
+		res, err := svc.CreateMaintenanceWindow(ctx, &CreateMaintenanceWindowCmd{
+			CreatedBy: users.NewFakeUser(t).Build(),
+			Name:      "Nightly backup",
+			Weekdays:  []time.Weekday{time.Sunday},
+			Start:     2 * time.Hour,
+			Duration:  8 * 24 * time.Hour,
+		})
+
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("GetMuteReason with an active silence", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, tools)
+
+		// Data
+		now := time.Now()
+		upcoming := NewFakeSilence(t).
+			WithMatchers().
+			WithPeriod(now.Add(time.Hour), now.Add(2*time.Hour)).
+			Build()
+		active := NewFakeSilence(t).
+			WithMatchers(Matcher{Name: "alertname", Value: "disk-full"}).
+			WithPeriod(now.Add(-time.Hour), now.Add(time.Hour)).
+			Build()
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("GetSilencesEndingAfter", mock.Anything, now).Return([]Silence{*upcoming, *active}, nil).Once()
+
+		// Run
+		res, err := svc.GetMuteReason(ctx, map[string]string{"alertname": "disk-full"})
+
+		// Asserts
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, active, res.Silence)
+		assert.Nil(t, res.Window)
+	})
+
+	t.Run("GetMuteReason with an active maintenance window", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, tools)
+
+		// Data
+		now := time.Date(2024, time.June, 1, 23, 30, 0, 0, time.UTC)
+		window := NewFakeMaintenanceWindow(t).
+			WithSchedule([]time.Weekday{time.Saturday}, 23*time.Hour, time.Hour).
+			Build()
+
+		// Mocks
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("GetSilencesEndingAfter", mock.Anything, now).Return([]Silence{}, nil).Once()
+		storageMock.On("GetAllMaintenanceWindows", mock.Anything).Return([]MaintenanceWindow{*window}, nil).Once()
+
+		// Run
+		res, err := svc.GetMuteReason(ctx, map[string]string{"alertname": "disk-full"})
+
+		// Asserts
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, window, res.Window)
+		assert.Equal(t, fmt.Sprintf("maintenance window %q", window.Name()), res.String())
+	})
+
+	t.Run("GetMuteReason not muted", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		svc := newService(storageMock, tools)
+
+		now := time.Now()
+
+		tools.ClockMock.On("Now").Return(now).Once()
+		storageMock.On("GetSilencesEndingAfter", mock.Anything, now).Return([]Silence{}, nil).Once()
+		storageMock.On("GetAllMaintenanceWindows", mock.Anything).Return([]MaintenanceWindow{}, nil).Once()
+
+		res, err := svc.GetMuteReason(ctx, map[string]string{"alertname": "disk-full"})
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,270 @@
+package silences
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const (
+	silencesTableName = "silences"
+	windowsTableName  = "maintenance_windows"
+)
+
+var errNotFound = errors.New("not found")
+
+var (
+	allSilenceFields = []string{"id", "matchers", "starts_at", "ends_at", "comment", "created_at", "created_by"}
+	allWindowFields  = []string{"id", "name", "matchers", "weekdays", "start", "duration", "created_at", "created_by"}
+)
+
+type sqlStorage struct {
+	db *sql.DB
+}
+
+func newSqlStorage(db *sql.DB) *sqlStorage {
+	return &sqlStorage{db}
+}
+
+func (s *sqlStorage) SaveSilence(ctx context.Context, silence *Silence) error {
+	rawMatchers, err := json.Marshal(silence.matchers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the matchers: %w", err)
+	}
+
+	_, err = sq.
+		Insert(silencesTableName).
+		Columns(allSilenceFields...).
+		Values(
+			silence.id,
+			string(rawMatchers),
+			ptr.To(sqlstorage.SQLTime(silence.startsAt)),
+			ptr.To(sqlstorage.SQLTime(silence.endsAt)),
+			silence.comment,
+			ptr.To(sqlstorage.SQLTime(silence.createdAt)),
+			silence.createdBy,
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetSilenceByID(ctx context.Context, id uuid.UUID) (*Silence, error) {
+	row := sq.
+		Select(allSilenceFields...).
+		From(silencesTableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		QueryRowContext(ctx)
+
+	res, err := s.scanSilence(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) GetSilencesEndingAfter(ctx context.Context, t time.Time) ([]Silence, error) {
+	rows, err := sq.
+		Select(allSilenceFields...).
+		From(silencesTableName).
+		Where(sq.Gt{"ends_at": ptr.To(sqlstorage.SQLTime(t))}).
+		OrderBy("starts_at").
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	res := []Silence{}
+
+	for rows.Next() {
+		silence, err := s.scanSilence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		res = append(res, *silence)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) SetSilenceEnd(ctx context.Context, id uuid.UUID, endsAt time.Time) error {
+	_, err := sq.
+		Update(silencesTableName).
+		Set("ends_at", ptr.To(sqlstorage.SQLTime(endsAt))).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) SaveMaintenanceWindow(ctx context.Context, window *MaintenanceWindow) error {
+	rawMatchers, err := json.Marshal(window.matchers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the matchers: %w", err)
+	}
+
+	rawWeekdays, err := json.Marshal(window.weekdays)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the weekdays: %w", err)
+	}
+
+	_, err = sq.
+		Insert(windowsTableName).
+		Columns(allWindowFields...).
+		Values(
+			window.id,
+			window.name,
+			string(rawMatchers),
+			string(rawWeekdays),
+			int64(window.start.Seconds()),
+			int64(window.duration.Seconds()),
+			ptr.To(sqlstorage.SQLTime(window.createdAt)),
+			window.createdBy,
+		).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) GetAllMaintenanceWindows(ctx context.Context) ([]MaintenanceWindow, error) {
+	rows, err := sq.
+		Select(allWindowFields...).
+		From(windowsTableName).
+		OrderBy("name").
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sql error: %w", err)
+	}
+	defer rows.Close()
+
+	res := []MaintenanceWindow{}
+
+	for rows.Next() {
+		window, err := s.scanWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+
+		res = append(res, *window)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *sqlStorage) DeleteMaintenanceWindow(ctx context.Context, id uuid.UUID) error {
+	_, err := sq.
+		Delete(windowsTableName).
+		Where(sq.Eq{"id": id}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sqlStorage) scanSilence(row sqlstorage.RowScanner) (*Silence, error) {
+	var res Silence
+	var rawMatchers string
+	var sqlStartsAt, sqlEndsAt, sqlCreatedAt sqlstorage.SQLTime
+
+	err := row.Scan(
+		&res.id,
+		&rawMatchers,
+		&sqlStartsAt,
+		&sqlEndsAt,
+		&res.comment,
+		&sqlCreatedAt,
+		&res.createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(rawMatchers), &res.matchers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid matchers: %w", err)
+	}
+
+	res.startsAt = sqlStartsAt.Time()
+	res.endsAt = sqlEndsAt.Time()
+	res.createdAt = sqlCreatedAt.Time()
+
+	return &res, nil
+}
+
+func (s *sqlStorage) scanWindow(row sqlstorage.RowScanner) (*MaintenanceWindow, error) {
+	var res MaintenanceWindow
+	var rawMatchers, rawWeekdays string
+	var start, duration int64
+	var sqlCreatedAt sqlstorage.SQLTime
+
+	err := row.Scan(
+		&res.id,
+		&res.name,
+		&rawMatchers,
+		&rawWeekdays,
+		&start,
+		&duration,
+		&sqlCreatedAt,
+		&res.createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(rawMatchers), &res.matchers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid matchers: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(rawWeekdays), &res.weekdays)
+	if err != nil {
+		return nil, fmt.Errorf("invalid weekdays: %w", err)
+	}
+
+	res.start = time.Duration(start) * time.Second
+	res.duration = time.Duration(duration) * time.Second
+	res.createdAt = sqlCreatedAt.Time()
+
+	return &res, nil
+}
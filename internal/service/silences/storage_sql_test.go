@@ -0,0 +1,87 @@
+package silences
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSilencesSqlStorage(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqlstorage.NewTestStorage(t)
+	store := newSqlStorage(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	silence := NewFakeSilence(t).WithPeriod(now.Add(-time.Hour), now.Add(time.Hour)).Build()
+	expiredSilence := NewFakeSilence(t).WithPeriod(now.Add(-2*time.Hour), now.Add(-time.Hour)).Build()
+	window := NewFakeMaintenanceWindow(t).
+		WithMatchers(Matcher{Name: "mount", Value: "/", IsRegex: false}).
+		WithSchedule([]time.Weekday{time.Monday, time.Friday}, 90*time.Minute, 2*time.Hour).
+		Build()
+
+	t.Run("SaveSilence success", func(t *testing.T) {
+		err := store.SaveSilence(ctx, silence)
+		require.NoError(t, err)
+
+		err = store.SaveSilence(ctx, expiredSilence)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetSilenceByID success", func(t *testing.T) {
+		res, err := store.GetSilenceByID(ctx, silence.ID())
+		require.NoError(t, err)
+		assert.Equal(t, silence, res)
+	})
+
+	t.Run("GetSilenceByID not found", func(t *testing.T) {
+		res, err := store.GetSilenceByID(ctx, "some-unknown-id")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("GetSilencesEndingAfter success", func(t *testing.T) {
+		res, err := store.GetSilencesEndingAfter(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, []Silence{*silence}, res)
+	})
+
+	t.Run("SetSilenceEnd success", func(t *testing.T) {
+		err := store.SetSilenceEnd(ctx, silence.ID(), now.Add(-time.Minute))
+		require.NoError(t, err)
+
+		res, err := store.GetSilencesEndingAfter(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("GetAllMaintenanceWindows with nothing", func(t *testing.T) {
+		res, err := store.GetAllMaintenanceWindows(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("SaveMaintenanceWindow success", func(t *testing.T) {
+		err := store.SaveMaintenanceWindow(ctx, window)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetAllMaintenanceWindows success", func(t *testing.T) {
+		res, err := store.GetAllMaintenanceWindows(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []MaintenanceWindow{*window}, res)
+	})
+
+	t.Run("DeleteMaintenanceWindow success", func(t *testing.T) {
+		err := store.DeleteMaintenanceWindow(ctx, window.ID())
+		require.NoError(t, err)
+
+		res, err := store.GetAllMaintenanceWindows(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+}
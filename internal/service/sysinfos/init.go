@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/spf13/afero"
 )
@@ -12,8 +13,10 @@ type Service interface {
 	GetInfos(ctx context.Context) *Infos
 }
 
-func Init(fs afero.Fs, tools tools.Tools) (Service, error) {
-	svc := newService(fs, tools)
+// Init reads the host infos. The config is nil in the agent mode, without any
+// database: the hostname can't be overridden.
+func Init(fs afero.Fs, config config.Service, tools tools.Tools) (Service, error) {
+	svc := newService(fs, config, tools)
 
 	err := svc.fetch(context.Background())
 	if err != nil {
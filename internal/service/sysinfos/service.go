@@ -3,9 +3,11 @@ package sysinfos
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/clock"
 	"github.com/spf13/afero"
@@ -18,15 +20,19 @@ const (
 
 type service struct {
 	fs        afero.Fs
+	config    config.Service
 	clock     clock.Clock
+	logger    *slog.Logger
 	startTime time.Time
 	hostname  string
 }
 
-func newService(fs afero.Fs, tools tools.Tools) *service {
+func newService(fs afero.Fs, config config.Service, tools tools.Tools) *service {
 	return &service{
-		fs:    fs,
-		clock: tools.Clock(),
+		fs:     fs,
+		config: config,
+		clock:  tools.Clock(),
+		logger: tools.Logger(),
 	}
 }
 
@@ -73,6 +79,26 @@ func (s *service) fetchUptime(_ context.Context) (time.Duration, error) {
 func (s *service) GetInfos(ctx context.Context) *Infos {
 	return &Infos{
 		uptime:   time.Since(s.startTime).Truncate(time.Second),
-		hostname: s.hostname,
+		hostname: s.getHostname(ctx),
 	}
 }
+
+// getHostname returns the config.HostnameOverride setting if set, the
+// /etc/hostname content otherwise.
+func (s *service) getHostname(ctx context.Context) string {
+	if s.config == nil {
+		return s.hostname
+	}
+
+	override, err := s.config.GetString(ctx, config.HostnameOverride)
+	if err != nil {
+		s.logger.Error("failed to get the hostname override", slog.String("error", err.Error()))
+		return s.hostname
+	}
+
+	if override == "" {
+		return s.hostname
+	}
+
+	return override
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/startutils"
 	"github.com/spf13/afero"
@@ -23,7 +24,7 @@ func TestFetchSysInfos(t *testing.T) {
 
 		toolsMock.ClockMock.On("Now").Return(now).Once()
 
-		svc := newService(afs, toolsMock)
+		svc := newService(afs, nil, toolsMock)
 
 		err := svc.fetch(context.Background())
 		require.NoError(t, err)
@@ -34,3 +35,40 @@ func TestFetchSysInfos(t *testing.T) {
 		assert.Equal(t, expected, svc.startTime)
 	})
 }
+
+func TestGetInfos(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		toolsMock := tools.NewMock(t)
+		configMock := config.NewMockService(t)
+		svc := newService(afero.NewMemMapFs(), configMock, toolsMock)
+		svc.hostname = "zapettePC"
+
+		configMock.On("GetString", ctx, config.HostnameOverride).Return("", nil).Once()
+
+		res := svc.GetInfos(ctx)
+		assert.Equal(t, "zapettePC", res.Hostname())
+	})
+
+	t.Run("With an hostname override", func(t *testing.T) {
+		toolsMock := tools.NewMock(t)
+		configMock := config.NewMockService(t)
+		svc := newService(afero.NewMemMapFs(), configMock, toolsMock)
+		svc.hostname = "zapettePC"
+
+		configMock.On("GetString", ctx, config.HostnameOverride).Return("example.com", nil).Once()
+
+		res := svc.GetInfos(ctx)
+		assert.Equal(t, "example.com", res.Hostname())
+	})
+
+	t.Run("Without config", func(t *testing.T) {
+		toolsMock := tools.NewMock(t)
+		svc := newService(afero.NewMemMapFs(), nil, toolsMock)
+		svc.hostname = "zapettePC"
+
+		res := svc.GetInfos(ctx)
+		assert.Equal(t, "zapettePC", res.Hostname())
+	})
+}
@@ -6,35 +6,84 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
 )
 
+// purgePeriod is the minimum duration between two deletions of the stats
+// older than the retention.
+const purgePeriod = time.Minute
+
+// SystatsCron collects the stats and the cgroups at the start of each of
+// their collection intervals, based on the computer clock: with a 1m interval
+// they are collected at hh:mm:00.
 type SystatsCron struct {
 	service Service
+	config  config.Service
 	clock   clock.Clock
+	// statsInterval and cgroupsInterval cache the config.CollectionInterval
+	// and config.CGroupsInterval settings. They're reloaded after each change
+	// notified by changes.
+	statsInterval   time.Duration
+	cgroupsInterval time.Duration
+	changes         chan struct{}
+	// lastCGroups is the start of the last interval with the cgroups
+	// collected.
+	lastCGroups time.Time
+	lastPurge   time.Time
 }
 
-func newSystatCron(service Service, tools tools.Tools) *SystatsCron {
+func newSystatCron(ctx context.Context, service Service, config config.Service, tools tools.Tools) *SystatsCron {
 	return &SystatsCron{
-		service: service,
-		clock:   tools.Clock(),
+		service:         service,
+		config:          config,
+		clock:           tools.Clock(),
+		statsInterval:   0,
+		cgroupsInterval: 0,
+		changes:         config.Watch(ctx),
 	}
 }
 
-func (c *SystatsCron) Name() string {
-	return "sysstats"
-}
-
-func (c *SystatsCron) Duration() time.Duration {
-	return 300 * time.Millisecond
+// Job polls every 300ms: a collection starts at most 300ms after the start
+// of its interval.
+func (c *SystatsCron) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "sysstats",
+		Schedule: scheduler.Every(300 * time.Millisecond),
+		Timeout:  10 * time.Second,
+		Runner:   c,
+	}
 }
 
 func (c *SystatsCron) Run(ctx context.Context) error {
+	err := c.loadIntervals(ctx)
+	if err != nil {
+		return err
+	}
+
 	now := c.clock.Now()
 
-	if now.Second()%5 != 0 {
-		// The cron run every 5 seconds based on the computer clock.
+	err = c.collectStats(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	err = c.collectCGroups(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	return c.purge(ctx, now)
+}
+
+func (c *SystatsCron) collectStats(ctx context.Context, now time.Time) error {
+	start := now.Truncate(c.statsInterval)
+
+	if now.Sub(start) >= time.Second {
+		// Too late for this interval, wait for the next one in order to
+		// stay aligned.
 		return nil
 	}
 
@@ -43,8 +92,8 @@ func (c *SystatsCron) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to get the latest stats: %w", err)
 	}
 
-	if latest != nil && now.Sub(latest.time).Seconds() < 5.0 {
-		// This 5s period have been done already.
+	if latest != nil && !latest.time.Before(start) {
+		// This interval have been done already.
 		return nil
 	}
 
@@ -55,3 +104,72 @@ func (c *SystatsCron) Run(ctx context.Context) error {
 
 	return nil
 }
+
+func (c *SystatsCron) collectCGroups(ctx context.Context, now time.Time) error {
+	start := now.Truncate(c.cgroupsInterval)
+
+	if now.Sub(start) >= time.Second || !c.lastCGroups.Before(start) {
+		return nil
+	}
+
+	c.lastCGroups = start
+
+	err := c.service.fetchAndRegisterCGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the cgroups: %w", err)
+	}
+
+	return nil
+}
+
+func (c *SystatsCron) loadIntervals(ctx context.Context) error {
+	select {
+	case <-c.changes:
+		c.statsInterval, c.cgroupsInterval = 0, 0
+	default:
+	}
+
+	if c.statsInterval != 0 {
+		return nil
+	}
+
+	statsInterval, err := c.config.GetDuration(ctx, config.CollectionInterval)
+	if err != nil {
+		return fmt.Errorf("failed to get the collection interval: %w", err)
+	}
+
+	cgroupsInterval, err := c.config.GetDuration(ctx, config.CGroupsInterval)
+	if err != nil {
+		return fmt.Errorf("failed to get the cgroups interval: %w", err)
+	}
+
+	c.statsInterval, c.cgroupsInterval = statsInterval, cgroupsInterval
+
+	return nil
+}
+
+// purge deletes the stats older than the config.StatsRetention setting.
+func (c *SystatsCron) purge(ctx context.Context, now time.Time) error {
+	if now.Sub(c.lastPurge) < purgePeriod {
+		return nil
+	}
+
+	retention, err := c.config.GetDuration(ctx, config.StatsRetention)
+	if err != nil {
+		return fmt.Errorf("failed to get the retention: %w", err)
+	}
+
+	c.lastPurge = now
+
+	if retention == 0 {
+		// The stats are kept forever.
+		return nil
+	}
+
+	err = c.service.deleteBefore(ctx, now.Add(-retention))
+	if err != nil {
+		return fmt.Errorf("failed to delete the old stats: %w", err)
+	}
+
+	return nil
+}
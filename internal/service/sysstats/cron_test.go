@@ -0,0 +1,74 @@
+package sysstats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystatsCron(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Run collects the stats and the cgroups at their own interval", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		serviceMock := NewMockService(t)
+		configMock := config.NewMockService(t)
+
+		// Data
+		start := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+		// Mocks
+		configMock.On("Watch", mock.Anything).Return(make(chan struct{})).Once()
+		configMock.On("GetDuration", mock.Anything, config.CollectionInterval).Return(5*time.Second, nil).Once()
+		configMock.On("GetDuration", mock.Anything, config.CGroupsInterval).Return(time.Minute, nil).Once()
+		configMock.On("GetDuration", mock.Anything, config.StatsRetention).Return(time.Duration(0), nil).Once()
+
+		cron := newSystatCron(ctx, serviceMock, configMock, tools)
+
+		// First run: both the stats and the cgroups are collected.
+		tools.ClockMock.On("Now").Return(start.Add(100 * time.Millisecond)).Once()
+		serviceMock.On("GetLatest", mock.Anything).Return(nil, errNotFound).Once()
+		serviceMock.On("fetchAndRegister", mock.Anything).Return(NewFakeStats(t).WithTime(start).Build(), nil).Once()
+		serviceMock.On("fetchAndRegisterCGroups", mock.Anything).Return(nil).Once()
+
+		err := cron.Run(ctx)
+		require.NoError(t, err)
+
+		// Next stats interval: only the stats are collected.
+		tools.ClockMock.On("Now").Return(start.Add(5*time.Second + 100*time.Millisecond)).Once()
+		serviceMock.On("GetLatest", mock.Anything).Return(NewFakeStats(t).WithTime(start).Build(), nil).Once()
+		serviceMock.On("fetchAndRegister", mock.Anything).Return(NewFakeStats(t).WithTime(start.Add(5*time.Second)).Build(), nil).Once()
+
+		err = cron.Run(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("Run outside the start of the intervals", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		serviceMock := NewMockService(t)
+		configMock := config.NewMockService(t)
+
+		// Data
+		start := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+		// Mocks
+		configMock.On("Watch", mock.Anything).Return(make(chan struct{})).Once()
+		configMock.On("GetDuration", mock.Anything, config.CollectionInterval).Return(5*time.Second, nil).Once()
+		configMock.On("GetDuration", mock.Anything, config.CGroupsInterval).Return(time.Minute, nil).Once()
+		configMock.On("GetDuration", mock.Anything, config.StatsRetention).Return(time.Duration(0), nil).Once()
+		tools.ClockMock.On("Now").Return(start.Add(2 * time.Second)).Once()
+
+		cron := newSystatCron(ctx, serviceMock, configMock, tools)
+
+		// Run
+		err := cron.Run(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+	})
+}
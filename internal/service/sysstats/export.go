@@ -0,0 +1,307 @@
+package sysstats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+)
+
+// Format is a file format used to export and import the stats.
+type Format string
+
+const (
+	// CSV contains a column per Memory field, in bytes. It's made for the
+	// spreadsheets: the CPU and the disks are not exported.
+	CSV Format = "csv"
+	// JSON contains all the stats fields, without any loss. The cgroups are
+	// a separate series and are not exported.
+	JSON Format = "json"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format")
+	ErrInvalidFile   = errors.New("invalid file")
+)
+
+// csvHeader is the first line of a CSV export.
+var csvHeader = []string{
+	"time",
+	"total_mem",
+	"available_mem",
+	"free_mem",
+	"buffers",
+	"cached",
+	"s_reclaimable",
+	"shmem",
+	"total_swap",
+	"free_swap",
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case CSV, JSON:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+	}
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv"
+	}
+
+	return "application/json"
+}
+
+// exportedStats is the JSON representation of a Stats. Unlike
+// Stats.MarshalJSON, the sizes are in bytes so nothing is lost.
+type exportedStats struct {
+	Time   time.Time      `json:"time"`
+	Memory exportedMemory `json:"memory"`
+	CPU    *exportedCPU   `json:"cpu"`
+	Disks  []exportedDisk `json:"disks"`
+}
+
+type exportedMemory struct {
+	TotalMem     uint64 `json:"totalMem"`
+	AvailableMem uint64 `json:"availableMem"`
+	FreeMem      uint64 `json:"freeMem"`
+	Buffers      uint64 `json:"buffers"`
+	Cached       uint64 `json:"cached"`
+	SReclaimable uint64 `json:"sReclaimable"`
+	Shmem        uint64 `json:"shmem"`
+	TotalSwap    uint64 `json:"totalSwap"`
+	FreeSwap     uint64 `json:"freeSwap"`
+}
+
+type exportedCPU struct {
+	Busy  uint64 `json:"busy"`
+	Total uint64 `json:"total"`
+}
+
+type exportedDisk struct {
+	MountPoint string `json:"mountPoint"`
+	Total      uint64 `json:"total"`
+	Available  uint64 `json:"available"`
+}
+
+// Encode writes the stats in the given format. The empty stats are skipped.
+func Encode(w io.Writer, format Format, stats []Stats) error {
+	switch format {
+	case CSV:
+		return encodeCSV(w, stats)
+	case JSON:
+		return encodeJSON(w, stats)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// Decode reads the stats written by Encode.
+func Decode(r io.Reader, format Format) ([]Stats, error) {
+	switch format {
+	case CSV:
+		return decodeCSV(r)
+	case JSON:
+		return decodeJSON(r)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+func memoryFields(m *Memory) []*datasize.ByteSize {
+	return []*datasize.ByteSize{
+		&m.totalMem,
+		&m.availableMem,
+		&m.freeMem,
+		&m.buffers,
+		&m.cached,
+		&m.sReclaimable,
+		&m.shmem,
+		&m.totalSwap,
+		&m.freeSwap,
+	}
+}
+
+func encodeCSV(w io.Writer, stats []Stats) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(csvHeader)
+	if err != nil {
+		return fmt.Errorf("failed to write the header: %w", err)
+	}
+
+	for _, stat := range stats {
+		if stat.IsEmpty() {
+			continue
+		}
+
+		record := []string{stat.Time().UTC().Format(time.RFC3339)}
+		for _, field := range memoryFields(stat.memory) {
+			record = append(record, strconv.FormatUint(uint64(*field), 10))
+		}
+
+		err = writer.Write(record)
+		if err != nil {
+			return fmt.Errorf("failed to write the stats: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func decodeCSV(r io.Reader) ([]Stats, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read the header: %w", ErrInvalidFile, err)
+	}
+
+	for i, name := range csvHeader {
+		if header[i] != name {
+			return nil, fmt.Errorf("%w: the column %d must be %q", ErrInvalidFile, i+1, name)
+		}
+	}
+
+	res := []Stats{}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+
+		stat := Stats{memory: &Memory{}}
+
+		stat.time, err = time.Parse(time.RFC3339, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid time", ErrInvalidFile, line)
+		}
+		stat.time = stat.time.UTC()
+
+		for i, field := range memoryFields(stat.memory) {
+			value, err := strconv.ParseUint(record[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: invalid %s", ErrInvalidFile, line, csvHeader[i+1])
+			}
+
+			*field = datasize.ByteSize(value)
+		}
+
+		res = append(res, stat)
+	}
+}
+
+func encodeJSON(w io.Writer, stats []Stats) error {
+	res := make([]exportedStats, 0, len(stats))
+
+	for _, stat := range stats {
+		if stat.IsEmpty() {
+			continue
+		}
+
+		exported := exportedStats{
+			Time:   stat.Time().UTC(),
+			Memory: exportedMemory{},
+			CPU:    nil,
+			Disks:  make([]exportedDisk, len(stat.disks)),
+		}
+
+		exportedFields := []*uint64{
+			&exported.Memory.TotalMem,
+			&exported.Memory.AvailableMem,
+			&exported.Memory.FreeMem,
+			&exported.Memory.Buffers,
+			&exported.Memory.Cached,
+			&exported.Memory.SReclaimable,
+			&exported.Memory.Shmem,
+			&exported.Memory.TotalSwap,
+			&exported.Memory.FreeSwap,
+		}
+		for i, field := range memoryFields(stat.memory) {
+			*exportedFields[i] = uint64(*field)
+		}
+
+		if stat.cpu != nil {
+			exported.CPU = &exportedCPU{Busy: stat.cpu.busy, Total: stat.cpu.total}
+		}
+
+		for i, disk := range stat.disks {
+			exported.Disks[i] = exportedDisk{
+				MountPoint: disk.mountPoint,
+				Total:      uint64(disk.total),
+				Available:  uint64(disk.available),
+			}
+		}
+
+		res = append(res, exported)
+	}
+
+	return json.NewEncoder(w).Encode(res)
+}
+
+func decodeJSON(r io.Reader) ([]Stats, error) {
+	var exported []exportedStats
+
+	err := json.NewDecoder(r).Decode(&exported)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	res := make([]Stats, len(exported))
+
+	for i, e := range exported {
+		if e.Time.IsZero() {
+			return nil, fmt.Errorf("%w: stats %d: missing time", ErrInvalidFile, i)
+		}
+
+		stat := Stats{
+			time: e.Time.UTC().Truncate(time.Second),
+			memory: &Memory{
+				totalMem:     datasize.ByteSize(e.Memory.TotalMem),
+				availableMem: datasize.ByteSize(e.Memory.AvailableMem),
+				freeMem:      datasize.ByteSize(e.Memory.FreeMem),
+				buffers:      datasize.ByteSize(e.Memory.Buffers),
+				cached:       datasize.ByteSize(e.Memory.Cached),
+				sReclaimable: datasize.ByteSize(e.Memory.SReclaimable),
+				shmem:        datasize.ByteSize(e.Memory.Shmem),
+				totalSwap:    datasize.ByteSize(e.Memory.TotalSwap),
+				freeSwap:     datasize.ByteSize(e.Memory.FreeSwap),
+			},
+			cpu:   nil,
+			disks: nil,
+		}
+
+		if e.CPU != nil {
+			stat.cpu = &CPU{busy: e.CPU.Busy, total: e.CPU.Total}
+		}
+
+		for _, disk := range e.Disks {
+			stat.disks = append(stat.disks, Disk{
+				mountPoint: disk.MountPoint,
+				total:      datasize.ByteSize(disk.Total),
+				available:  datasize.ByteSize(disk.Available),
+			})
+		}
+
+		res[i] = stat
+	}
+
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package sysstats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	stats := []Stats{
+		*NewFakeStats(t).WithTime(now.Add(-time.Minute)).Build(),
+		// The empty ticks of a graph are skipped.
+		{},
+		*NewFakeStats(t).WithTime(now).WithCPU(nil).Build(),
+	}
+
+	t.Run("JSON round trip", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+
+		err := Encode(buf, JSON, stats)
+		require.NoError(t, err)
+
+		res, err := Decode(buf, JSON)
+		require.NoError(t, err)
+		assert.EqualValues(t, []Stats{stats[0], stats[2]}, res)
+	})
+
+	t.Run("CSV round trip", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+
+		err := Encode(buf, CSV, stats)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(buf.String(), "time,total_mem,available_mem,free_mem,buffers,cached,s_reclaimable,shmem,total_swap,free_swap\n"))
+		assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+
+		res, err := Decode(buf, CSV)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		// Only the memory is exported.
+		assert.Equal(t, stats[0].Time(), res[0].Time())
+		assert.Equal(t, stats[0].Memory(), res[0].Memory())
+		assert.Nil(t, res[0].CPU())
+		assert.Empty(t, res[0].Disks())
+		assert.Equal(t, stats[2].Memory(), res[1].Memory())
+	})
+
+	t.Run("Decode a CSV with an invalid header", func(t *testing.T) {
+		res, err := Decode(strings.NewReader("foo,bar\n"), CSV)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrInvalidFile)
+	})
+
+	t.Run("Decode a CSV with an invalid value", func(t *testing.T) {
+		content := strings.Join(csvHeader, ",") + "\n2024-03-10T12:00:00Z,1,2,3,4,5,6,7,8,-9\n"
+
+		res, err := Decode(strings.NewReader(content), CSV)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrInvalidFile)
+		assert.ErrorContains(t, err, "line 2: invalid free_swap")
+	})
+
+	t.Run("Decode an invalid JSON", func(t *testing.T) {
+		res, err := Decode(strings.NewReader(`{"foo": "bar"}`), JSON)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrInvalidFile)
+	})
+
+	t.Run("Encode with an unknown format", func(t *testing.T) {
+		err := Encode(bytes.NewBuffer(nil), Format("xml"), stats)
+		require.ErrorIs(t, err, ErrUnknownFormat)
+	})
+
+	t.Run("ParseFormat", func(t *testing.T) {
+		res, err := ParseFormat("csv")
+		require.NoError(t, err)
+		assert.Equal(t, CSV, res)
+
+		_, err = ParseFormat("xml")
+		require.ErrorIs(t, err, ErrUnknownFormat)
+	})
+}
@@ -3,7 +3,10 @@ package sysstats
 import (
 	"context"
 	"database/sql"
+	"io"
+	"time"
 
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
 	"github.com/spf13/afero"
@@ -19,12 +22,30 @@ type Result struct {
 
 type Service interface {
 	GetLatest(ctx context.Context) (*Stats, error)
+	// GetLatestCGroups returns the cgroups of the latest collection, sorted
+	// by path.
+	GetLatestCGroups(ctx context.Context) ([]CGroup, error)
 	GetStatsForGraph(ctx context.Context, graph *Graph) ([]Stats, error)
+	GetRange(ctx context.Context, start, end time.Time) ([]Stats, error)
+	// Import saves the stats exported by Encode, see Format.
+	Import(ctx context.Context, r io.Reader, format Format) (int, error)
 	Watch(ctx context.Context) chan struct{}
 	fetchAndRegister(ctx context.Context) (*Stats, error)
+	fetchAndRegisterCGroups(ctx context.Context) error
+	deleteBefore(ctx context.Context, before time.Time) error
 }
 
-func Init(db *sql.DB, fs afero.Fs, tools tools.Tools) Result {
+// Collector reads the stats of the local host without saving them. It's used
+// by the agent mode to push the stats to a central server.
+type Collector interface {
+	Collect(ctx context.Context) (*Stats, error)
+}
+
+func NewCollector(fs afero.Fs, tools tools.Tools) Collector {
+	return newService(nil, fs, tools)
+}
+
+func Init(ctx context.Context, db *sql.DB, fs afero.Fs, config config.Service, tools tools.Tools) Result {
 	storage := newSqlStorage(db)
 
 	svc := newService(storage, fs, tools)
@@ -32,6 +53,6 @@ func Init(db *sql.DB, fs afero.Fs, tools tools.Tools) Result {
 	return Result{
 		Service: svc,
 		Watcher: svc,
-		Cron:    newSystatCron(svc, tools),
+		Cron:    newSystatCron(ctx, svc, config, tools),
 	}
 }
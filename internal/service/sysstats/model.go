@@ -5,26 +5,122 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/Peltoche/zapette/internal/tools/datasize"
 )
 
-var FiveMnGraph = Graph{
-	graphSpan: 5 * time.Minute,
-	tickSpan:  5 * time.Second,
-	namespace: MinGraph,
+const (
+	// MaxGraphTicks is the max number of points of a graph. The tick span of
+	// a graph is chosen to stay under it.
+	MaxGraphTicks = 360
+
+	// MinGraphSpan is the smallest time range a graph can display.
+	MinGraphSpan = time.Minute
+
+	// MaxGraphSpan is the largest time range a graph can display.
+	MaxGraphSpan = 31 * 24 * time.Hour
+)
+
+// tickSpans are the resolutions available for a graph, the finest first. A
+// graph never uses a tick span shorter than the collection interval.
+var tickSpans = []time.Duration{
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	2 * time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
 }
 
 type Graph struct {
+	// end is zero for a graph ending now.
+	end       time.Time
 	graphSpan time.Duration
 	tickSpan  time.Duration
 	namespace Namespace
 }
 
+// NewGraph returns a graph displaying the last span, up to now. The interval
+// is the collection interval, see config.CollectionInterval.
+func NewGraph(span, interval time.Duration) Graph {
+	return Graph{
+		graphSpan: span,
+		tickSpan:  tickSpanFor(span, interval),
+		namespace: MinGraph,
+		end:       time.Time{},
+	}
+}
+
+// NewHistoryGraph returns a graph displaying the time range between start
+// and end.
+func NewHistoryGraph(start, end time.Time, interval time.Duration) Graph {
+	span := end.Sub(start)
+
+	return Graph{
+		graphSpan: span,
+		tickSpan:  tickSpanFor(span, interval),
+		namespace: MinGraph,
+		end:       end,
+	}
+}
+
+// tickSpanFor returns the finest tick span keeping a graph of the given
+// span under MaxGraphTicks. The ticks shorter than the interval would be
+// empty and are skipped.
+func tickSpanFor(span, interval time.Duration) time.Duration {
+	for _, tick := range tickSpans {
+		if tick >= interval && span <= tick*MaxGraphTicks {
+			return tick
+		}
+	}
+
+	return tickSpans[len(tickSpans)-1]
+}
+
+// Span returns the time range displayed by the graph.
+func (g *Graph) Span() time.Duration {
+	return g.graphSpan
+}
+
+// TickSpan returns the time range covered by each point of the graph.
+func (g *Graph) TickSpan() time.Duration {
+	return g.tickSpan
+}
+
+// IsLive returns true if the graph ends now and so moves with the time.
+func (g *Graph) IsLive() bool {
+	return g.end.IsZero()
+}
+
+// Range returns the start and the end of the graph.
+func (g *Graph) Range(now time.Time) (time.Time, time.Time) {
+	end := g.end
+	if g.IsLive() {
+		end = now
+	}
+
+	return end.Add(-g.graphSpan), end
+}
+
 func (g *Graph) Ticks() int {
-	return int(g.graphSpan / g.tickSpan)
+	return int((g.graphSpan + g.tickSpan - 1) / g.tickSpan)
 }
 
 type Namespace int
@@ -37,6 +133,8 @@ const (
 type Stats struct {
 	time   time.Time
 	memory *Memory
+	cpu    *CPU
+	disks  []Disk
 }
 
 func (s *Stats) Time() time.Time {
@@ -47,10 +145,39 @@ func (s *Stats) Memory() *Memory {
 	return s.memory
 }
 
+// Disks returns the usage of the mounted filesystems, sorted by mount point.
+func (s *Stats) Disks() []Disk {
+	return s.disks
+}
+
+// CPU returns the CPU time counters. It's nil if /proc/stat is not readable
+// or for the stats saved before the CPU collection.
+func (s *Stats) CPU() *CPU {
+	return s.cpu
+}
+
+// CPUPercent returns the percentage of CPU time used since the previous
+// stats. It's nil if the counters are missing or have been reset by a
+// reboot.
+func (s *Stats) CPUPercent(prev *Stats) *float64 {
+	if prev == nil || s.cpu == nil || prev.cpu == nil {
+		return nil
+	}
+
+	if s.cpu.total <= prev.cpu.total || s.cpu.busy < prev.cpu.busy {
+		return nil
+	}
+
+	res := float64(s.cpu.busy-prev.cpu.busy) / float64(s.cpu.total-prev.cpu.total) * 100
+
+	return &res
+}
+
 func (s *Stats) MarshalJSON() ([]byte, error) {
 	return json.Marshal(map[string]any{
 		"time":   s.time,
 		"memory": s.memory,
+		"disks":  s.disks,
 	})
 }
 
@@ -62,6 +189,20 @@ func (a *Stats) MarshalBinary() ([]byte, error) {
 	rawMemory, _ := a.memory.MarshalBinary()
 	binary.Write(buf, binary.BigEndian, rawMemory)
 
+	binary.Write(buf, binary.BigEndian, uint16(len(a.disks)))
+	for _, disk := range a.disks {
+		rawDisk, _ := disk.MarshalBinary()
+		binary.Write(buf, binary.BigEndian, rawDisk)
+	}
+
+	if a.cpu == nil {
+		buf.WriteByte(0)
+	} else {
+		buf.WriteByte(1)
+		binary.Write(buf, binary.BigEndian, a.cpu.busy)
+		binary.Write(buf, binary.BigEndian, a.cpu.total)
+	}
+
 	return buf.Bytes(), nil
 }
 
@@ -70,6 +211,10 @@ func (a *Stats) IsEmpty() bool {
 }
 
 func (a *Stats) UnmarshalBinary(b []byte) error {
+	if len(b) < 8 {
+		return fmt.Errorf("failed to decode the time: %w", io.ErrUnexpectedEOF)
+	}
+
 	unixSecs := int64(binary.BigEndian.Uint64(b))
 
 	a.time = time.Unix(unixSecs, 0).UTC()
@@ -80,9 +225,65 @@ func (a *Stats) UnmarshalBinary(b []byte) error {
 		return fmt.Errorf("failed to decode the memory: %w", err)
 	}
 
+	// The stats saved before the disks collection stop after the memory.
+	buf := bytes.NewReader(b[8+memoryBinarySize:])
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	var nbDisks uint16
+	if err := binary.Read(buf, binary.BigEndian, &nbDisks); err != nil {
+		return fmt.Errorf("failed to decode the disks count: %w", err)
+	}
+
+	if nbDisks > 0 {
+		a.disks = make([]Disk, nbDisks)
+		for i := range a.disks {
+			err = a.disks[i].readBinary(buf)
+			if err != nil {
+				return fmt.Errorf("failed to decode the disk %d: %w", i, err)
+			}
+		}
+	}
+
+	// The stats saved before the CPU collection stop after the disks.
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	hasCPU, err := buf.ReadByte()
+	if err != nil || hasCPU == 0 {
+		return err
+	}
+
+	a.cpu = &CPU{}
+	for _, field := range []any{&a.cpu.busy, &a.cpu.total} {
+		if err := binary.Read(buf, binary.BigEndian, field); err != nil {
+			return fmt.Errorf("failed to decode the cpu: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// CPU holds the CPU time counters of all the cores since the boot, in clock
+// ticks. A single stats doesn't give the usage, see Stats.CPUPercent.
+type CPU struct {
+	busy  uint64
+	total uint64
+}
+
+func (c CPU) Busy() uint64 {
+	return c.busy
+}
+
+func (c CPU) Total() uint64 {
+	return c.total
+}
+
+// memoryBinarySize is the size of an encoded Memory: 9 uint64.
+const memoryBinarySize = 9 * 8
+
 // Memory holds information on system memory usage
 type Memory struct {
 	totalMem     datasize.ByteSize
@@ -200,3 +401,155 @@ func (c Memory) TotalSwap() datasize.ByteSize {
 func (c Memory) UsedSwap() datasize.ByteSize {
 	return c.totalSwap - c.freeSwap
 }
+
+// Disk holds information on a mounted filesystem usage.
+type Disk struct {
+	mountPoint string
+	total      datasize.ByteSize
+	available  datasize.ByteSize
+}
+
+func (d Disk) MountPoint() string {
+	return d.mountPoint
+}
+
+func (d Disk) Total() datasize.ByteSize {
+	return d.total
+}
+
+// Available is the space available for the unprivileged users.
+func (d Disk) Available() datasize.ByteSize {
+	return d.available
+}
+
+func (d Disk) Used() datasize.ByteSize {
+	return d.total - d.available
+}
+
+func (d Disk) PercentageUsed() int {
+	if d.total == 0 {
+		return 0
+	}
+
+	return int(math.Round(float64(d.Used()) / float64(d.total) * 100))
+}
+
+func (d Disk) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"mountPoint": d.mountPoint,
+		"total":      math.Round(d.total.GBytes()*100) / 100,
+		"available":  math.Round(d.available.GBytes()*100) / 100,
+	})
+}
+
+func (d Disk) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	binary.Write(buf, binary.BigEndian, uint16(len(d.mountPoint)))
+	buf.WriteString(d.mountPoint)
+	binary.Write(buf, binary.BigEndian, d.total)
+	binary.Write(buf, binary.BigEndian, d.available)
+
+	return buf.Bytes(), nil
+}
+
+func (d *Disk) readBinary(buf *bytes.Reader) error {
+	var nameLen uint16
+	if err := binary.Read(buf, binary.BigEndian, &nameLen); err != nil {
+		return err
+	}
+
+	name := make([]byte, nameLen)
+	if _, err := io.ReadFull(buf, name); err != nil {
+		return err
+	}
+	d.mountPoint = string(name)
+
+	if err := binary.Read(buf, binary.BigEndian, &d.total); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &d.available); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CGroup holds the resources accounting of a cgroup v2: a slice, a service or
+// a scope (containers, user sessions). The CPU and IO values are counters
+// since the cgroup creation.
+type CGroup struct {
+	time     time.Time
+	path     string
+	memory   datasize.ByteSize
+	anon     datasize.ByteSize
+	file     datasize.ByteSize
+	cpuUsage time.Duration
+	ioRead   datasize.ByteSize
+	ioWrite  datasize.ByteSize
+	pids     uint64
+}
+
+func (c CGroup) Time() time.Time {
+	return c.time
+}
+
+// Path is relative to the cgroup root, for example
+// "system.slice/nginx.service".
+func (c CGroup) Path() string {
+	return c.path
+}
+
+// Name is the last element of the path, for example "nginx.service".
+func (c CGroup) Name() string {
+	return path.Base(c.path)
+}
+
+// Kind returns "slice", "service" or "scope".
+func (c CGroup) Kind() string {
+	return strings.TrimPrefix(path.Ext(c.path), ".")
+}
+
+// Memory is the total memory used by the cgroup, page cache included.
+func (c CGroup) Memory() datasize.ByteSize {
+	return c.memory
+}
+
+// Anon is the memory used by the processes: heap, stacks, etc.
+func (c CGroup) Anon() datasize.ByteSize {
+	return c.anon
+}
+
+// File is the page cache charged to the cgroup.
+func (c CGroup) File() datasize.ByteSize {
+	return c.file
+}
+
+func (c CGroup) CPUUsage() time.Duration {
+	return c.cpuUsage
+}
+
+func (c CGroup) IORead() datasize.ByteSize {
+	return c.ioRead
+}
+
+func (c CGroup) IOWrite() datasize.ByteSize {
+	return c.ioWrite
+}
+
+func (c CGroup) Pids() uint64 {
+	return c.pids
+}
+
+func (c CGroup) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"path":     c.path,
+		"memory":   math.Round(c.memory.GBytes()*100) / 100,
+		"anon":     math.Round(c.anon.GBytes()*100) / 100,
+		"file":     math.Round(c.file.GBytes()*100) / 100,
+		"cpuUsage": c.cpuUsage.Seconds(),
+		"ioRead":   math.Round(c.ioRead.GBytes()*100) / 100,
+		"ioWrite":  math.Round(c.ioWrite.GBytes()*100) / 100,
+		"pids":     c.pids,
+	})
+}
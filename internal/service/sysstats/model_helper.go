@@ -19,6 +19,7 @@ func NewFakeStats(t testing.TB) *FakeStatsBuilder {
 
 	totalMem := datasize.ByteSize(gofakeit.Number(int(datasize.GB), 20*int(datasize.GB)))
 	totalSwap := datasize.ByteSize(gofakeit.Number(int(datasize.GB), 20*int(datasize.GB)))
+	totalDisk := datasize.ByteSize(gofakeit.Number(10*int(datasize.GB), 500*int(datasize.GB)))
 
 	return &FakeStatsBuilder{
 		t: t,
@@ -35,6 +36,15 @@ func NewFakeStats(t testing.TB) *FakeStatsBuilder {
 				totalSwap:    totalSwap,
 				freeSwap:     datasize.ByteSize(gofakeit.Number(0, int(totalSwap))),
 			},
+			cpu: &CPU{
+				busy:  uint64(gofakeit.Number(1000, 100000)),
+				total: uint64(gofakeit.Number(200000, 1000000)),
+			},
+			disks: []Disk{{
+				mountPoint: "/",
+				total:      totalDisk,
+				available:  datasize.ByteSize(gofakeit.Number(0, int(totalDisk))),
+			}},
 		},
 	}
 }
@@ -45,6 +55,51 @@ func (b *FakeStatsBuilder) WithTime(t time.Time) *FakeStatsBuilder {
 	return b
 }
 
+func (b *FakeStatsBuilder) WithMemory(total, available datasize.ByteSize) *FakeStatsBuilder {
+	b.stats.memory.totalMem = total
+	b.stats.memory.availableMem = available
+
+	return b
+}
+
+// WithCPU sets the CPU counters, nil removes them.
+func (b *FakeStatsBuilder) WithCPU(cpu *CPU) *FakeStatsBuilder {
+	b.stats.cpu = cpu
+
+	return b
+}
+
+// NewFakeCPU returns a CPU, used with WithCPU.
+func NewFakeCPU(busy, total uint64) *CPU {
+	return &CPU{busy: busy, total: total}
+}
+
+func (b *FakeStatsBuilder) WithDisks(disks ...Disk) *FakeStatsBuilder {
+	b.stats.disks = disks
+
+	return b
+}
+
+// NewFakeDisk returns a Disk, used with WithDisks.
+func NewFakeDisk(mountPoint string, total, available datasize.ByteSize) Disk {
+	return Disk{mountPoint: mountPoint, total: total, available: available}
+}
+
+// NewFakeCGroup returns a CGroup recorded at the given time.
+func NewFakeCGroup(at time.Time, path string, memory datasize.ByteSize) CGroup {
+	return CGroup{
+		time:     at.UTC().Truncate(time.Second),
+		path:     path,
+		memory:   memory,
+		anon:     memory / 4 * 3,
+		file:     memory / 4,
+		cpuUsage: time.Duration(gofakeit.Number(1, 3600)) * time.Second,
+		ioRead:   datasize.ByteSize(gofakeit.Number(0, int(datasize.GB))),
+		ioWrite:  datasize.ByteSize(gofakeit.Number(0, int(datasize.GB))),
+		pids:     uint64(gofakeit.Number(1, 100)),
+	}
+}
+
 func (b *FakeStatsBuilder) Build() *Stats {
 	return b.stats
 }
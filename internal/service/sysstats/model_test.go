@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Peltoche/zapette/internal/tools/datasize"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -30,6 +31,65 @@ func TestSystats(t *testing.T) {
 
 			assert.EqualValues(t, stats, res)
 		})
+
+		t.Run("UnmarshalBinary without disks", func(t *testing.T) {
+			// The stats saved before the disks collection.
+			res := &Stats{}
+
+			err := res.UnmarshalBinary(buf[:8+memoryBinarySize])
+			require.NoError(t, err)
+
+			assert.EqualValues(t, stats.memory, res.memory)
+			assert.Empty(t, res.Disks())
+		})
+
+		t.Run("UnmarshalBinary without cpu", func(t *testing.T) {
+			// The stats saved before the CPU collection.
+			withoutCPU := NewFakeStats(t).WithCPU(nil).Build()
+			rawStats, err := withoutCPU.MarshalBinary()
+			require.NoError(t, err)
+
+			res := &Stats{}
+			err = res.UnmarshalBinary(rawStats[:len(rawStats)-1])
+			require.NoError(t, err)
+
+			assert.EqualValues(t, withoutCPU, res)
+		})
+
+		t.Run("UnmarshalBinary with a truncated input", func(t *testing.T) {
+			res := &Stats{}
+
+			require.Error(t, res.UnmarshalBinary(buf[:4]))
+			require.Error(t, res.UnmarshalBinary(buf[:20]))
+		})
+	})
+
+	t.Run("CGroup", func(t *testing.T) {
+		cgroup := NewFakeCGroup(time.Now(), "system.slice/docker-1234.scope", datasize.GB)
+
+		assert.Equal(t, "docker-1234.scope", cgroup.Name())
+		assert.Equal(t, "scope", cgroup.Kind())
+	})
+
+	t.Run("CPUPercent", func(t *testing.T) {
+		prev := NewFakeStats(t).WithCPU(NewFakeCPU(1000, 10000)).Build()
+		stats := NewFakeStats(t).WithCPU(NewFakeCPU(1500, 12000)).Build()
+
+		res := stats.CPUPercent(prev)
+		require.NotNil(t, res)
+		assert.InDelta(t, 25.0, *res, 0.001)
+
+		// After a reboot the counters restart from zero.
+		assert.Nil(t, prev.CPUPercent(stats))
+		assert.Nil(t, stats.CPUPercent(nil))
+		assert.Nil(t, stats.CPUPercent(NewFakeStats(t).WithCPU(nil).Build()))
+	})
+
+	t.Run("Disk", func(t *testing.T) {
+		disk := NewFakeDisk("/", 100*datasize.GB, 25*datasize.GB)
+
+		assert.Equal(t, 75*datasize.GB, disk.Used())
+		assert.Equal(t, 75, disk.PercentageUsed())
 	})
 
 	t.Run("MarshalJSON success", func(t *testing.T) {
@@ -50,7 +110,12 @@ func TestSystats(t *testing.T) {
 				"freeSwap": %.2f,
 				"shmem": %.2f,
 				"sReclaimable": %.2f
-			}
+			},
+			"disks": [{
+				"mountPoint": "/",
+				"total": %.2f,
+				"available": %.2f
+			}]
 		}`,
 			stats.time.Format(time.RFC3339),
 			stats.memory.totalMem.GBytes(),
@@ -62,6 +127,56 @@ func TestSystats(t *testing.T) {
 			stats.memory.freeSwap.GBytes(),
 			stats.memory.shmem.GBytes(),
 			stats.memory.sReclaimable.GBytes(),
+			stats.disks[0].total.GBytes(),
+			stats.disks[0].available.GBytes(),
 		), string(buf))
 	})
 }
+
+func TestGraph(t *testing.T) {
+	t.Run("NewGraph picks the finest tick span", func(t *testing.T) {
+		for _, test := range []struct {
+			span     time.Duration
+			interval time.Duration
+			tick     time.Duration
+		}{
+			{span: 5 * time.Minute, interval: 5 * time.Second, tick: 5 * time.Second},
+			{span: time.Hour, interval: 5 * time.Second, tick: 10 * time.Second},
+			{span: 24 * time.Hour, interval: 5 * time.Second, tick: 5 * time.Minute},
+			{span: 7 * 24 * time.Hour, interval: 5 * time.Second, tick: 30 * time.Minute},
+			{span: 1000 * 24 * time.Hour, interval: 5 * time.Second, tick: 24 * time.Hour},
+			{span: 5 * time.Minute, interval: time.Second, tick: time.Second},
+			{span: 5 * time.Minute, interval: time.Minute, tick: time.Minute},
+			{span: 5 * time.Minute, interval: 7 * time.Second, tick: 10 * time.Second},
+		} {
+			graph := NewGraph(test.span, test.interval)
+
+			assert.Equal(t, test.tick, graph.TickSpan(), test.span.String()+" every "+test.interval.String())
+			assert.True(t, graph.IsLive())
+		}
+	})
+
+	t.Run("NewHistoryGraph", func(t *testing.T) {
+		end := time.Now()
+		start := end.Add(-90 * time.Minute)
+
+		graph := NewHistoryGraph(start, end, 5*time.Second)
+
+		assert.False(t, graph.IsLive())
+		assert.Equal(t, 90*time.Minute, graph.Span())
+		assert.Equal(t, 15*time.Second, graph.TickSpan())
+		assert.Equal(t, 360, graph.Ticks())
+
+		resStart, resEnd := graph.Range(time.Now().Add(time.Hour))
+		assert.Equal(t, start, resStart)
+		assert.Equal(t, end, resEnd)
+	})
+
+	t.Run("Ticks with a partial tick", func(t *testing.T) {
+		end := time.Now()
+
+		graph := NewHistoryGraph(end.Add(-62*time.Second), end, 5*time.Second)
+
+		assert.Equal(t, 13, graph.Ticks())
+	})
+}
@@ -1,9 +1,14 @@
 package sysstats
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
 	"slices"
 	"strconv"
 	"strings"
@@ -13,10 +18,21 @@ import (
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/clock"
 	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/errs"
 	"github.com/spf13/afero"
 )
 
-const filePath = "/proc/meminfo"
+const (
+	filePath   = "/proc/meminfo"
+	cpuPath    = "/proc/stat"
+	mountsPath = "/proc/mounts"
+	cgroupRoot = "/sys/fs/cgroup"
+
+	// maxCGroups is the number of services and scopes recorded at each
+	// collection, the ones using the most memory. All the slices are
+	// recorded.
+	maxCGroups = 20
+)
 
 var (
 	ErrInvalidFieldFormat = errors.New("invalid field format")
@@ -31,13 +47,21 @@ func InvalidFieldFormat(key, expected, val string) error {
 type storage interface {
 	GetLatest(ctx context.Context) (*Stats, error)
 	Save(ctx context.Context, ns Namespace, stats *Stats) error
+	SaveAll(ctx context.Context, ns Namespace, stats []Stats) (int, error)
 	GetRange(ctx context.Context, ns Namespace, start time.Time, end time.Time) ([]Stats, error)
+	GetLatestByTick(ctx context.Context, ns Namespace, start, end time.Time, tick time.Duration) ([]Stats, error)
+	DeleteBefore(ctx context.Context, before time.Time) error
+
+	SaveCGroups(ctx context.Context, cgroups []CGroup) error
+	GetLatestCGroups(ctx context.Context) ([]CGroup, error)
 }
 
 type service struct {
 	fs          afero.Fs
 	storage     storage
 	clock       clock.Clock
+	statfs      statfsFunc
+	log         *slog.Logger
 	watchers    []chan struct{}
 	watcherLock *sync.Mutex
 }
@@ -47,6 +71,8 @@ func newService(storage storage, fs afero.Fs, tools tools.Tools) *service {
 		storage:     storage,
 		fs:          fs,
 		clock:       tools.Clock(),
+		statfs:      statfs,
+		log:         tools.Logger().With(slog.String("source", "sysstats")),
 		watchers:    []chan struct{}{},
 		watcherLock: new(sync.Mutex),
 	}
@@ -98,27 +124,68 @@ func (s *service) Watch(ctx context.Context) chan struct{} {
 	return c
 }
 
+// GetStatsForGraph returns a stats for each tick of the graph, the latest
+// one recorded during the tick. The ticks without any stats are empty.
 func (s *service) GetStatsForGraph(ctx context.Context, graph *Graph) ([]Stats, error) {
-	now := s.clock.Now()
-	start := now.Add(-graph.graphSpan)
+	start, end := graph.Range(s.clock.Now())
 
-	stats, err := s.storage.GetRange(ctx, graph.namespace, start, now)
+	stats, err := s.storage.GetLatestByTick(ctx, graph.namespace, start, end, graph.tickSpan)
 	if err != nil {
 		return nil, err
 	}
 
-	res := make([]Stats, graph.graphSpan/graph.tickSpan)
+	res := make([]Stats, graph.Ticks())
 
 	for _, stat := range stats {
-		relativeStart := stat.Time().Sub(start)
-		res[relativeStart/graph.tickSpan] = stat
+		// The range excludes start and includes end, so the stats at end
+		// belong to the last tick.
+		idx := int((stat.Time().Sub(start) - 1) / graph.tickSpan)
+		res[min(max(idx, 0), len(res)-1)] = stat
+	}
+
+	return res, nil
+}
+
+// GetRange returns all the stats recorded between start and end.
+func (s *service) GetRange(ctx context.Context, start, end time.Time) ([]Stats, error) {
+	return s.storage.GetRange(ctx, MinGraph, start, end)
+}
+
+// Import saves the stats read from a file written by Encode. The stats
+// already recorded at the same time are kept. It returns the number of
+// imported stats.
+func (s *service) Import(ctx context.Context, r io.Reader, format Format) (int, error) {
+	stats, err := Decode(r, format)
+	if err != nil {
+		return 0, errs.Validation(err)
+	}
+
+	res, err := s.storage.SaveAll(ctx, MinGraph, stats)
+	if err != nil {
+		return 0, errs.Internal(fmt.Errorf("failed to save the stats: %w", err))
 	}
 
 	return res, nil
 }
 
 func (s *service) GetLatest(ctx context.Context) (*Stats, error) {
-	return s.storage.GetLatest(ctx)
+	res, err := s.storage.GetLatest(ctx)
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	return res, err
+}
+
+// GetLatestCGroups returns the cgroups of the latest collection, sorted by
+// path. It's empty if the host doesn't use the cgroup v2 hierarchy.
+func (s *service) GetLatestCGroups(ctx context.Context) ([]CGroup, error) {
+	res, err := s.storage.GetLatestCGroups(ctx)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
 }
 
 func (s *service) fetchAndRegister(ctx context.Context) (*Stats, error) {
@@ -135,6 +202,38 @@ func (s *service) fetchAndRegister(ctx context.Context) (*Stats, error) {
 	return stats, nil
 }
 
+// fetchAndRegisterCGroups saves the cgroups. They are collected separately
+// from the stats, at their own interval.
+func (s *service) fetchAndRegisterCGroups(ctx context.Context) error {
+	// The cgroups are optional, a failure is only reported.
+	cgroups, err := s.fetchCGroups(s.clock.Now().Truncate(time.Second))
+	if err != nil {
+		s.log.Warn("failed to fetch the cgroups", slog.String("error", err.Error()))
+		return nil
+	}
+
+	err = s.storage.SaveCGroups(ctx, cgroups)
+	if err != nil {
+		return fmt.Errorf("failed to save the cgroups: %w", err)
+	}
+
+	return nil
+}
+
+// deleteBefore removes the stats older than the retention.
+func (s *service) deleteBefore(ctx context.Context, before time.Time) error {
+	err := s.storage.DeleteBefore(ctx, before)
+	if err != nil {
+		return errs.Internal(fmt.Errorf("failed to DeleteBefore: %w", err))
+	}
+
+	return nil
+}
+
+func (s *service) Collect(ctx context.Context) (*Stats, error) {
+	return s.fetch(ctx)
+}
+
 func (s *service) fetch(_ context.Context) (*Stats, error) {
 	now := s.clock.Now().Truncate(time.Second)
 
@@ -208,14 +307,321 @@ func (s *service) fetch(_ context.Context) (*Stats, error) {
 		}
 	}
 
+	disks, err := s.fetchDisks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the disks: %w", err)
+	}
+
+	cpu, err := s.fetchCPU()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the cpu: %w", err)
+	}
+
 	stats := Stats{
 		time:   now,
 		memory: &mem,
+		cpu:    cpu,
+		disks:  disks,
 	}
 
 	return &stats, nil
 }
 
+// fetchDisks returns the usage of the filesystems backed by a block device.
+// The bind mounts and the loop devices (snaps, images) are skipped.
+func (s *service) fetchDisks() ([]Disk, error) {
+	content, err := afero.ReadFile(s.fs, mountsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	devices := map[string]struct{}{}
+	disks := []Disk{}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		device := fields[0]
+		if !strings.HasPrefix(device, "/dev/") || strings.HasPrefix(device, "/dev/loop") {
+			continue
+		}
+
+		if _, ok := devices[device]; ok {
+			continue
+		}
+		devices[device] = struct{}{}
+
+		// The spaces are escaped in /proc/mounts.
+		mountPoint := strings.ReplaceAll(fields[1], "\\040", " ")
+
+		// A single unreachable mount, a stale NFS share for example, must not
+		// prevent the other stats to be collected.
+		total, available, err := s.statfs(mountPoint)
+		if err != nil {
+			s.log.Warn("failed to statfs a mount point, skip it",
+				slog.String("mount", mountPoint),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		disks = append(disks, Disk{
+			mountPoint: mountPoint,
+			total:      total,
+			available:  available,
+		})
+	}
+
+	if len(disks) == 0 {
+		return nil, nil
+	}
+
+	slices.SortFunc(disks, func(a, b Disk) int { return strings.Compare(a.mountPoint, b.mountPoint) })
+
+	return disks, nil
+}
+
+// fetchCPU returns the counters of the "cpu" line of /proc/stat, the sum of
+// all the cores. The iowait time is counted as idle.
+func (s *service) fetchCPU() (*CPU, error) {
+	content, err := afero.ReadFile(s.fs, cpuPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	line, _, _ := strings.Cut(string(content), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return nil, fmt.Errorf("%s: %w: %q", cpuPath, ErrInvalidLineFormat, line)
+	}
+
+	res := CPU{}
+
+	// user nice system idle iowait irq softirq steal. The guest times are
+	// already counted in user and nice.
+	for i, field := range fields[1:min(len(fields), 9)] {
+		val, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, InvalidFieldFormat(cpuPath, "uint64", field)
+		}
+
+		res.total += val
+		if i != 3 && i != 4 {
+			res.busy += val
+		}
+	}
+
+	return &res, nil
+}
+
+// fetchCGroups returns the accounting of the slices and of the services and
+// scopes using the most memory, see maxCGroups. Only the cgroup v2 unified
+// hierarchy is supported, nothing is returned for the v1 or the hybrid
+// hierarchies.
+func (s *service) fetchCGroups(now time.Time) ([]CGroup, error) {
+	_, err := s.fs.Stat(path.Join(cgroupRoot, "cgroup.controllers"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	cgroups := []CGroup{}
+
+	err = s.walkCGroups("", now, &cgroups)
+	if err != nil {
+		return nil, err
+	}
+
+	units := []CGroup{}
+	cgroups = slices.DeleteFunc(cgroups, func(c CGroup) bool {
+		if c.Kind() == "slice" {
+			return false
+		}
+
+		units = append(units, c)
+		return true
+	})
+
+	slices.SortFunc(units, func(a, b CGroup) int { return cmp.Compare(b.memory, a.memory) })
+	cgroups = append(cgroups, units[:min(len(units), maxCGroups)]...)
+
+	if len(cgroups) == 0 {
+		return nil, nil
+	}
+
+	slices.SortFunc(cgroups, func(a, b CGroup) int { return strings.Compare(a.path, b.path) })
+
+	return cgroups, nil
+}
+
+// walkCGroups only goes down the slices. The services and the scopes can
+// have sub-groups (delegation, nested containers) but they are accounted in
+// their parent.
+//
+// The cgroups come and go during the walk, a scope can be removed between
+// the listing and the read. The cgroups failing to be read are skipped.
+func (s *service) walkCGroups(dir string, now time.Time, res *[]CGroup) error {
+	entries, err := afero.ReadDir(s.fs, path.Join(cgroupRoot, dir))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() {
+			continue
+		}
+
+		ext := path.Ext(name)
+		if ext != ".slice" && ext != ".service" && ext != ".scope" {
+			continue
+		}
+
+		cgroup, err := s.readCGroup(path.Join(dir, name))
+		if err != nil {
+			s.log.Debug("failed to read a cgroup, skip it",
+				slog.String("cgroup", path.Join(dir, name)),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		cgroup.time = now
+		*res = append(*res, *cgroup)
+
+		if ext == ".slice" {
+			err = s.walkCGroups(path.Join(dir, name), now, res)
+			if err != nil {
+				s.log.Debug("failed to walk a cgroup, skip it",
+					slog.String("cgroup", path.Join(dir, name)),
+					slog.String("error", err.Error()))
+			}
+		}
+	}
+
+	return nil
+}
+
+// readCGroup reads the accounting files of a cgroup. The files of the
+// disabled controllers are missing and their values stay at zero.
+func (s *service) readCGroup(cgroupPath string) (*CGroup, error) {
+	res := CGroup{path: cgroupPath}
+
+	read := func(file string) (string, error) {
+		content, err := afero.ReadFile(s.fs, path.Join(cgroupRoot, cgroupPath, file))
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return strings.TrimSpace(string(content)), err
+	}
+
+	for _, file := range []struct {
+		name  string
+		parse func(string) error
+	}{
+		{name: "memory.current", parse: func(c string) error {
+			v, err := parseCGroupUint(c)
+			res.memory = datasize.ByteSize(v)
+			return err
+		}},
+		{name: "pids.current", parse: func(c string) error {
+			v, err := parseCGroupUint(c)
+			res.pids = v
+			return err
+		}},
+		{name: "memory.stat", parse: func(c string) error {
+			keys := parseCGroupKeys(c)
+			res.anon = datasize.ByteSize(keys["anon"])
+			res.file = datasize.ByteSize(keys["file"])
+			return nil
+		}},
+		{name: "cpu.stat", parse: func(c string) error {
+			res.cpuUsage = time.Duration(parseCGroupKeys(c)["usage_usec"]) * time.Microsecond
+			return nil
+		}},
+		{name: "io.stat", parse: func(c string) error {
+			// One line per device: "8:0 rbytes=1 wbytes=2 rios=3 ...".
+			for _, line := range strings.Split(c, "\n") {
+				for _, field := range strings.Fields(line) {
+					key, rawValue, _ := strings.Cut(field, "=")
+					value, _ := strconv.ParseUint(rawValue, 10, 64)
+
+					switch key {
+					case "rbytes":
+						res.ioRead += datasize.ByteSize(value)
+					case "wbytes":
+						res.ioWrite += datasize.ByteSize(value)
+					}
+				}
+			}
+			return nil
+		}},
+	} {
+		content, err := read(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if content == "" {
+			continue
+		}
+
+		err = file.parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.name, err)
+		}
+	}
+
+	return &res, nil
+}
+
+// parseCGroupUint parses a single value file. "max" is returned as 0.
+func parseCGroupUint(content string) (uint64, error) {
+	if content == "max" {
+		return 0, nil
+	}
+
+	res, err := strconv.ParseUint(content, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: expected an uint64, have %q", ErrInvalidFieldFormat, content)
+	}
+
+	return res, nil
+}
+
+// parseCGroupKeys parses the "key value" lines of a flat keyed file. The
+// invalid lines are skipped.
+func parseCGroupKeys(content string) map[string]uint64 {
+	res := map[string]uint64{}
+
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err == nil {
+			res[fields[0]] = v
+		}
+	}
+
+	return res
+}
+
 func parseBytesValue(fields []string) (datasize.ByteSize, error) {
 	if len(fields) != 3 {
 		return 0, ErrInvalidLineFormat
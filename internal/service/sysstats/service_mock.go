@@ -4,8 +4,11 @@ package sysstats
 
 import (
 	context "context"
+	io "io"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockService is an autogenerated mock type for the Service type
@@ -43,6 +46,66 @@ func (_m *MockService) GetLatest(ctx context.Context) (*Stats, error) {
 	return r0, r1
 }
 
+// GetLatestCGroups provides a mock function with given fields: ctx
+func (_m *MockService) GetLatestCGroups(ctx context.Context) ([]CGroup, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestCGroups")
+	}
+
+	var r0 []CGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]CGroup, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []CGroup); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]CGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRange provides a mock function with given fields: ctx, start, end
+func (_m *MockService) GetRange(ctx context.Context, start time.Time, end time.Time) ([]Stats, error) {
+	ret := _m.Called(ctx, start, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRange")
+	}
+
+	var r0 []Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]Stats, error)); ok {
+		return rf(ctx, start, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []Stats); ok {
+		r0 = rf(ctx, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Stats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStatsForGraph provides a mock function with given fields: ctx, graph
 func (_m *MockService) GetStatsForGraph(ctx context.Context, graph *Graph) ([]Stats, error) {
 	ret := _m.Called(ctx, graph)
@@ -73,6 +136,34 @@ func (_m *MockService) GetStatsForGraph(ctx context.Context, graph *Graph) ([]St
 	return r0, r1
 }
 
+// Import provides a mock function with given fields: ctx, r, format
+func (_m *MockService) Import(ctx context.Context, r io.Reader, format Format) (int, error) {
+	ret := _m.Called(ctx, r, format)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, Format) (int, error)); ok {
+		return rf(ctx, r, format)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, Format) int); ok {
+		r0 = rf(ctx, r, format)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, Format) error); ok {
+		r1 = rf(ctx, r, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Watch provides a mock function with given fields: ctx
 func (_m *MockService) Watch(ctx context.Context) chan struct{} {
 	ret := _m.Called(ctx)
@@ -93,6 +184,24 @@ func (_m *MockService) Watch(ctx context.Context) chan struct{} {
 	return r0
 }
 
+// deleteBefore provides a mock function with given fields: ctx, before
+func (_m *MockService) deleteBefore(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for deleteBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // fetchAndRegister provides a mock function with given fields: ctx
 func (_m *MockService) fetchAndRegister(ctx context.Context) (*Stats, error) {
 	ret := _m.Called(ctx)
@@ -123,6 +232,24 @@ func (_m *MockService) fetchAndRegister(ctx context.Context) (*Stats, error) {
 	return r0, r1
 }
 
+// fetchAndRegisterCGroups provides a mock function with given fields: ctx
+func (_m *MockService) fetchAndRegisterCGroups(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for fetchAndRegisterCGroups")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMockService creates a new instance of MockService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockService(t interface {
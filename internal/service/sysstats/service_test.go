@@ -1,15 +1,22 @@
 package sysstats
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/errs"
 	"github.com/Peltoche/zapette/internal/tools/startutils"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,3 +56,246 @@ func TestFetchMemInfos(t *testing.T) {
 		assert.Equal(t, "2.9 GB", res.memory.UsedMemory().HumanReadable())
 	})
 }
+
+func TestFetchDisks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		toolsMock := tools.NewMock(t)
+		afs := afero.NewMemMapFs()
+		storageMock := newMockStorage(t)
+		startutils.LoadFileinFS(t, afs, "./testdata/mounts.txt", "/proc/mounts")
+
+		svc := newService(storageMock, afs, toolsMock)
+
+		statfsCalls := []string{}
+		svc.statfs = func(path string) (datasize.ByteSize, datasize.ByteSize, error) {
+			statfsCalls = append(statfsCalls, path)
+			return 100 * datasize.GB, 40 * datasize.GB, nil
+		}
+
+		res, err := svc.fetchDisks()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/", "/boot/efi", "/mnt/backup disk"}, statfsCalls)
+		assert.Equal(t, []Disk{
+			{mountPoint: "/", total: 100 * datasize.GB, available: 40 * datasize.GB},
+			{mountPoint: "/boot/efi", total: 100 * datasize.GB, available: 40 * datasize.GB},
+			{mountPoint: "/mnt/backup disk", total: 100 * datasize.GB, available: 40 * datasize.GB},
+		}, res)
+	})
+
+	t.Run("With a failing mount point", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		startutils.LoadFileinFS(t, afs, "./testdata/mounts.txt", "/proc/mounts")
+
+		svc := newService(newMockStorage(t), afs, tools.NewMock(t))
+
+		svc.statfs = func(path string) (datasize.ByteSize, datasize.ByteSize, error) {
+			if path == "/boot/efi" {
+				return 0, 0, errors.New("some-error")
+			}
+
+			return 100 * datasize.GB, 40 * datasize.GB, nil
+		}
+
+		res, err := svc.fetchDisks()
+		require.NoError(t, err)
+		assert.Equal(t, []Disk{
+			{mountPoint: "/", total: 100 * datasize.GB, available: 40 * datasize.GB},
+			{mountPoint: "/mnt/backup disk", total: 100 * datasize.GB, available: 40 * datasize.GB},
+		}, res)
+	})
+
+	t.Run("Without /proc/mounts", func(t *testing.T) {
+		svc := newService(newMockStorage(t), afero.NewMemMapFs(), tools.NewMock(t))
+
+		res, err := svc.fetchDisks()
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestFetchCPU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		startutils.LoadFileinFS(t, afs, "./testdata/stat.txt", "/proc/stat")
+
+		svc := newService(newMockStorage(t), afs, tools.NewMock(t))
+
+		res, err := svc.fetchCPU()
+		require.NoError(t, err)
+		assert.Equal(t, &CPU{busy: 13532763, total: 60377929}, res)
+	})
+
+	t.Run("Without /proc/stat", func(t *testing.T) {
+		svc := newService(newMockStorage(t), afero.NewMemMapFs(), tools.NewMock(t))
+
+		res, err := svc.fetchCPU()
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestFetchCGroups(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		files := map[string]string{
+			"/sys/fs/cgroup/cgroup.controllers":                                     "cpu io memory pids\n",
+			"/sys/fs/cgroup/memory.current":                                         "999\n",
+			"/sys/fs/cgroup/system.slice/memory.current":                            "3000\n",
+			"/sys/fs/cgroup/system.slice/pids.current":                              "12\n",
+			"/sys/fs/cgroup/system.slice/nginx.service/memory.current":              "2048\n",
+			"/sys/fs/cgroup/system.slice/nginx.service/memory.stat":                 "anon 1024\nfile 512\nkernel 8\n",
+			"/sys/fs/cgroup/system.slice/nginx.service/cpu.stat":                    "usage_usec 1500000\nuser_usec 1000000\n",
+			"/sys/fs/cgroup/system.slice/nginx.service/io.stat":                     "8:0 rbytes=100 wbytes=200 rios=1 wios=2\n8:16 rbytes=10 wbytes=20 rios=1 wios=2\n",
+			"/sys/fs/cgroup/system.slice/nginx.service/pids.current":                "4\n",
+			"/sys/fs/cgroup/system.slice/nginx.service/sub/memory.current":          "1\n",
+			"/sys/fs/cgroup/system.slice/docker-42.scope/memory.current":            "4096\n",
+			"/sys/fs/cgroup/system.slice/docker-42.scope/nested.slice/pids.current": "1\n",
+			"/sys/fs/cgroup/init.scope/memory.current":                              "max\n",
+			"/sys/fs/cgroup/not-a-unit/memory.current":                              "1\n",
+			"/sys/fs/cgroup/broken.service/memory.current":                          "invalid\n",
+		}
+		for name, content := range files {
+			require.NoError(t, afero.WriteFile(afs, name, []byte(content), 0o644))
+		}
+
+		svc := newService(newMockStorage(t), afs, tools.NewMock(t))
+
+		now := time.Now().Truncate(time.Second)
+		res, err := svc.fetchCGroups(now)
+		require.NoError(t, err)
+		assert.Equal(t, []CGroup{
+			{time: now, path: "init.scope"},
+			{time: now, path: "system.slice", memory: 3000, pids: 12},
+			{time: now, path: "system.slice/docker-42.scope", memory: 4096},
+			{
+				time:     now,
+				path:     "system.slice/nginx.service",
+				memory:   2048,
+				anon:     1024,
+				file:     512,
+				cpuUsage: 1500 * time.Millisecond,
+				ioRead:   110,
+				ioWrite:  220,
+				pids:     4,
+			},
+		}, res)
+	})
+
+	t.Run("Keeps the services using the most memory", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(afs, "/sys/fs/cgroup/cgroup.controllers", []byte("memory\n"), 0o644))
+		require.NoError(t, afero.WriteFile(afs, "/sys/fs/cgroup/system.slice/memory.current", []byte("1\n"), 0o644))
+		for i := range maxCGroups + 5 {
+			name := fmt.Sprintf("/sys/fs/cgroup/system.slice/unit-%02d.service/memory.current", i)
+			require.NoError(t, afero.WriteFile(afs, name, []byte(strconv.Itoa(i)+"\n"), 0o644))
+		}
+
+		svc := newService(newMockStorage(t), afs, tools.NewMock(t))
+
+		res, err := svc.fetchCGroups(time.Now())
+		require.NoError(t, err)
+		require.Len(t, res, maxCGroups+1)
+		assert.Equal(t, "system.slice", res[0].Path())
+		assert.Equal(t, "system.slice/unit-05.service", res[1].Path())
+	})
+
+	t.Run("With a cgroup v1 hierarchy", func(t *testing.T) {
+		afs := afero.NewMemMapFs()
+		require.NoError(t, afs.MkdirAll("/sys/fs/cgroup/memory/system.slice", 0o755))
+
+		svc := newService(newMockStorage(t), afs, tools.NewMock(t))
+
+		res, err := svc.fetchCGroups(time.Now())
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestGetLatest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Without any stats", func(t *testing.T) {
+		toolsMock := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+
+		storageMock.On("GetLatest", mock.Anything).Return(nil, errNotFound).Once()
+
+		svc := newService(storageMock, afero.NewMemMapFs(), toolsMock)
+
+		res, err := svc.GetLatest(context.Background())
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+		require.ErrorIs(t, err, errNotFound)
+	})
+}
+
+func TestGetStatsForGraph(t *testing.T) {
+	t.Parallel()
+
+	t.Run("With a history graph", func(t *testing.T) {
+		toolsMock := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+
+		end := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+		start := end.Add(-time.Hour)
+		graph := NewHistoryGraph(start, end, 5*time.Second)
+
+		// The latest stats of the first tick.
+		second := NewFakeStats(t).WithTime(start.Add(8 * time.Second)).Build()
+		last := NewFakeStats(t).WithTime(end).Build()
+
+		toolsMock.ClockMock.On("Now").Return(end.Add(time.Hour)).Once()
+		storageMock.On("GetLatestByTick", mock.Anything, MinGraph, start, end, 10*time.Second).
+			Return([]Stats{*second, *last}, nil).Once()
+
+		svc := newService(storageMock, afero.NewMemMapFs(), toolsMock)
+
+		res, err := svc.GetStatsForGraph(context.Background(), &graph)
+		require.NoError(t, err)
+		require.Len(t, res, 360)
+		assert.Equal(t, *second, res[0])
+		assert.True(t, res[1].IsEmpty())
+		assert.Equal(t, *last, res[359])
+	})
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		toolsMock := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+
+		stats := NewFakeStats(t).WithTime(time.Now().UTC()).Build()
+
+		buf := bytes.NewBuffer(nil)
+		err := Encode(buf, JSON, []Stats{*stats})
+		require.NoError(t, err)
+
+		storageMock.On("SaveAll", mock.Anything, MinGraph, []Stats{*stats}).Return(1, nil).Once()
+
+		svc := newService(storageMock, afero.NewMemMapFs(), toolsMock)
+
+		res, err := svc.Import(context.Background(), buf, JSON)
+		require.NoError(t, err)
+		assert.Equal(t, 1, res)
+	})
+
+	t.Run("With an invalid file", func(t *testing.T) {
+		toolsMock := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+
+		svc := newService(storageMock, afero.NewMemMapFs(), toolsMock)
+
+		res, err := svc.Import(context.Background(), strings.NewReader("not a csv"), CSV)
+		assert.Zero(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+		require.ErrorIs(t, err, ErrInvalidFile)
+	})
+}
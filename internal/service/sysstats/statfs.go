@@ -0,0 +1,25 @@
+package sysstats
+
+import (
+	"syscall"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+)
+
+// statfsFunc returns the total and the available size of the filesystem
+// mounted at path.
+type statfsFunc func(path string) (total datasize.ByteSize, available datasize.ByteSize, err error)
+
+func statfs(path string) (datasize.ByteSize, datasize.ByteSize, error) {
+	var st syscall.Statfs_t
+
+	err := syscall.Statfs(path, &st)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	//nolint:unconvert // The field types depend on the platform.
+	blockSize := uint64(st.Bsize)
+
+	return datasize.ByteSize(uint64(st.Blocks) * blockSize), datasize.ByteSize(uint64(st.Bavail) * blockSize), nil
+}
@@ -10,11 +10,17 @@ import (
 	sq "github.com/Masterminds/squirrel"
 )
 
-const tableName = "sysstats"
+const (
+	tableName        = "sysstats"
+	cgroupsTableName = "sysstats_cgroups"
+)
 
 var errNotFound = errors.New("not found")
 
-var allFields = []string{"time", "namespace", "content"}
+var (
+	allFields       = []string{"time", "namespace", "content"}
+	allCGroupFields = []string{"time", "path", "memory", "anon", "file", "cpu_usage", "io_read", "io_write", "pids"}
+)
 
 // sqlStorage use to save/retrieve Users
 type sqlStorage struct {
@@ -47,6 +53,48 @@ func (s *sqlStorage) Save(ctx context.Context, ns Namespace, stats *Stats) error
 	return nil
 }
 
+// SaveAll saves the given stats, in a single transaction. The stats already
+// recorded for the same second are kept. It returns the number of saved
+// stats.
+func (s *sqlStorage) SaveAll(ctx context.Context, ns Namespace, stats []Stats) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start a transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	saved := 0
+
+	for _, stat := range stats {
+		rawStats, _ := stat.MarshalBinary()
+
+		res, err := sq.
+			Insert(tableName).
+			Columns(allFields...).
+			Values(stat.time.Unix(), ns, rawStats).
+			Suffix("ON CONFLICT DO NOTHING").
+			RunWith(tx).
+			ExecContext(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("sql error: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the affected rows: %w", err)
+		}
+
+		saved += int(affected)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return saved, nil
+}
+
 func (s *sqlStorage) GetRange(ctx context.Context, ns Namespace, start time.Time, end time.Time) ([]Stats, error) {
 	rows, err := sq.
 		Select(allFields...).
@@ -62,6 +110,127 @@ func (s *sqlStorage) GetRange(ctx context.Context, ns Namespace, start time.Time
 	return s.scanRows(rows)
 }
 
+// GetLatestByTick returns the latest stats of each tick between start and
+// end, the ticks starting at start. The other stats are skipped by the
+// database without being loaded.
+func (s *sqlStorage) GetLatestByTick(ctx context.Context, ns Namespace, start, end time.Time, tick time.Duration) ([]Stats, error) {
+	inRange := sq.And{sq.Eq{"namespace": ns}, sq.Gt{"time": start.Unix()}, sq.LtOrEq{"time": end.Unix()}}
+
+	// The range excludes start so the first tick ends at start+tick
+	// included.
+	latestTimes := sq.
+		Select("MAX(time)").
+		From(tableName).
+		Where(inRange).
+		GroupBy(fmt.Sprintf("(time - %d - 1) / %d", start.Unix(), int64(max(tick/time.Second, 1))))
+
+	rows, err := sq.
+		Select(allFields...).
+		From(tableName).
+		Where(sq.And{inRange, sq.Expr("time IN (?)", latestTimes)}).
+		OrderBy("time ASC").
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the db: %w", err)
+	}
+
+	return s.scanRows(rows)
+}
+
+// DeleteBefore deletes the stats and the cgroups older than before, in all
+// the namespaces.
+func (s *sqlStorage) DeleteBefore(ctx context.Context, before time.Time) error {
+	for _, table := range []string{tableName, cgroupsTableName} {
+		_, err := sq.
+			Delete(table).
+			Where(sq.Lt{"time": before.Unix()}).
+			RunWith(s.db).
+			ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("sql error on %q: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// SaveCGroups saves the cgroups collected at the same time, in a single
+// query.
+func (s *sqlStorage) SaveCGroups(ctx context.Context, cgroups []CGroup) error {
+	if len(cgroups) == 0 {
+		return nil
+	}
+
+	query := sq.Insert(cgroupsTableName).Columns(allCGroupFields...)
+	for _, c := range cgroups {
+		query = query.Values(
+			c.time.Unix(),
+			c.path,
+			c.memory,
+			c.anon,
+			c.file,
+			c.cpuUsage,
+			c.ioRead,
+			c.ioWrite,
+			c.pids,
+		)
+	}
+
+	_, err := query.RunWith(s.db).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sql error: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestCGroups returns the cgroups of the latest collection, sorted by
+// path.
+func (s *sqlStorage) GetLatestCGroups(ctx context.Context) ([]CGroup, error) {
+	rows, err := sq.
+		Select(allCGroupFields...).
+		From(cgroupsTableName).
+		Where(sq.Expr("time = (SELECT MAX(time) FROM " + cgroupsTableName + ")")).
+		OrderBy("path ASC").
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the db: %w", err)
+	}
+	defer rows.Close()
+
+	res := []CGroup{}
+	for rows.Next() {
+		var c CGroup
+		var unixTime int64
+
+		err = rows.Scan(
+			&unixTime,
+			&c.path,
+			&c.memory,
+			&c.anon,
+			&c.file,
+			&c.cpuUsage,
+			&c.ioRead,
+			&c.ioWrite,
+			&c.pids,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan the result: %w", err)
+		}
+
+		c.time = time.Unix(unixTime, 0).UTC()
+		res = append(res, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	return res, nil
+}
+
 func (s *sqlStorage) GetLatest(ctx context.Context) (*Stats, error) {
 	rawContent := []byte{}
 	var unixTime int64
@@ -14,6 +14,24 @@ type mockStorage struct {
 	mock.Mock
 }
 
+// DeleteBefore provides a mock function with given fields: ctx, before
+func (_m *mockStorage) DeleteBefore(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetLatest provides a mock function with given fields: ctx
 func (_m *mockStorage) GetLatest(ctx context.Context) (*Stats, error) {
 	ret := _m.Called(ctx)
@@ -44,6 +62,66 @@ func (_m *mockStorage) GetLatest(ctx context.Context) (*Stats, error) {
 	return r0, r1
 }
 
+// GetLatestByTick provides a mock function with given fields: ctx, ns, start, end, tick
+func (_m *mockStorage) GetLatestByTick(ctx context.Context, ns Namespace, start time.Time, end time.Time, tick time.Duration) ([]Stats, error) {
+	ret := _m.Called(ctx, ns, start, end, tick)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestByTick")
+	}
+
+	var r0 []Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, Namespace, time.Time, time.Time, time.Duration) ([]Stats, error)); ok {
+		return rf(ctx, ns, start, end, tick)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, Namespace, time.Time, time.Time, time.Duration) []Stats); ok {
+		r0 = rf(ctx, ns, start, end, tick)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Stats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, Namespace, time.Time, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, ns, start, end, tick)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestCGroups provides a mock function with given fields: ctx
+func (_m *mockStorage) GetLatestCGroups(ctx context.Context) ([]CGroup, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestCGroups")
+	}
+
+	var r0 []CGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]CGroup, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []CGroup); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]CGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRange provides a mock function with given fields: ctx, ns, start, end
 func (_m *mockStorage) GetRange(ctx context.Context, ns Namespace, start time.Time, end time.Time) ([]Stats, error) {
 	ret := _m.Called(ctx, ns, start, end)
@@ -92,6 +170,52 @@ func (_m *mockStorage) Save(ctx context.Context, ns Namespace, stats *Stats) err
 	return r0
 }
 
+// SaveAll provides a mock function with given fields: ctx, ns, stats
+func (_m *mockStorage) SaveAll(ctx context.Context, ns Namespace, stats []Stats) (int, error) {
+	ret := _m.Called(ctx, ns, stats)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAll")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, Namespace, []Stats) (int, error)); ok {
+		return rf(ctx, ns, stats)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, Namespace, []Stats) int); ok {
+		r0 = rf(ctx, ns, stats)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, Namespace, []Stats) error); ok {
+		r1 = rf(ctx, ns, stats)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveCGroups provides a mock function with given fields: ctx, cgroups
+func (_m *mockStorage) SaveCGroups(ctx context.Context, cgroups []CGroup) error {
+	ret := _m.Called(ctx, cgroups)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveCGroups")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []CGroup) error); ok {
+		r0 = rf(ctx, cgroups)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // newMockStorage creates a new instance of mockStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockStorage(t interface {
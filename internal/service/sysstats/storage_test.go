@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
 	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -69,4 +70,67 @@ func TestSystatSqlStorage(t *testing.T) {
 
 		assert.EqualValues(t, []Stats{*stats, *stats2}, res)
 	})
+
+	t.Run("SaveAll keeps the existing stats", func(t *testing.T) {
+		// Same time as stats2, the saved one is kept.
+		duplicate := NewFakeStats(t).WithTime(time2).Build()
+		stats3 := NewFakeStats(t).WithTime(time2.Add(time.Hour)).Build()
+
+		saved, err := store.SaveAll(ctx, MinGraph, []Stats{*duplicate, *stats3})
+		require.NoError(t, err)
+		assert.Equal(t, 1, saved)
+
+		res, err := store.GetRange(ctx, MinGraph, time1, time2.Add(time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, []Stats{*stats2, *stats3}, res)
+	})
+
+	t.Run("GetLatestByTick success", func(t *testing.T) {
+		start := time2.Add(-time.Hour)
+
+		// The tick (start+1h, start+2h] contains the 2 first seconds.
+		first := NewFakeStats(t).WithTime(start.Add(time.Hour + time.Second)).Build()
+		second := NewFakeStats(t).WithTime(start.Add(time.Hour + 2*time.Second)).Build()
+		_, err := store.SaveAll(ctx, Unknown, []Stats{*first, *second})
+		require.NoError(t, err)
+
+		res, err := store.GetLatestByTick(ctx, Unknown, start.Add(time.Hour), start.Add(3*time.Hour), time.Hour)
+		require.NoError(t, err)
+
+		assert.EqualValues(t, []Stats{*second}, res)
+	})
+
+	cgroups := []CGroup{
+		NewFakeCGroup(time2, "system.slice", 2*datasize.GB),
+		NewFakeCGroup(time2, "system.slice/nginx.service", 500*datasize.MB),
+	}
+
+	t.Run("SaveCGroups success", func(t *testing.T) {
+		err := store.SaveCGroups(ctx, []CGroup{NewFakeCGroup(time1, "system.slice", datasize.GB)})
+		require.NoError(t, err)
+
+		err = store.SaveCGroups(ctx, cgroups)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetLatestCGroups success", func(t *testing.T) {
+		res, err := store.GetLatestCGroups(ctx)
+		require.NoError(t, err)
+
+		assert.EqualValues(t, cgroups, res)
+	})
+
+	t.Run("DeleteBefore success", func(t *testing.T) {
+		err := store.DeleteBefore(ctx, time2)
+		require.NoError(t, err)
+
+		res, err := store.GetRange(ctx, MinGraph, time1.Add(-time.Second), time2.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.EqualValues(t, *stats2, res[0])
+
+		resCGroups, err := store.GetLatestCGroups(ctx)
+		require.NoError(t, err)
+		assert.EqualValues(t, cgroups, resCGroups)
+	})
 }
@@ -0,0 +1,158 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	sddbus "github.com/coreos/go-systemd/dbus"
+)
+
+// dbusBus talks to systemd through the D-Bus system bus. A new connection is
+// opened for each call so a restart of the bus is transparent.
+type dbusBus struct{}
+
+func newDBusBus() *dbusBus {
+	return &dbusBus{}
+}
+
+func (b *dbusBus) ListUnits(ctx context.Context) ([]Unit, error) {
+	conn, err := b.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	statuses, err := conn.ListUnitsByPatterns(nil, []string{"*" + serviceSuffix})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the units: %w", err)
+	}
+
+	res := make([]Unit, 0, len(statuses))
+	for _, status := range statuses {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		unit := Unit{
+			name:        status.Name,
+			description: status.Description,
+			loadState:   status.LoadState,
+			activeState: status.ActiveState,
+			subState:    status.SubState,
+		}
+
+		err = b.fillDetails(conn, &unit)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, unit)
+	}
+
+	return res, nil
+}
+
+func (b *dbusBus) GetUnit(_ context.Context, name string) (*Unit, error) {
+	conn, err := b.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	props, err := conn.GetUnitProperties(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the unit properties: %w", err)
+	}
+
+	loadState, _ := props["LoadState"].(string)
+	if loadState == "not-found" {
+		return nil, errNotFound
+	}
+
+	unit := Unit{name: name, loadState: loadState}
+	unit.description, _ = props["Description"].(string)
+	unit.activeState, _ = props["ActiveState"].(string)
+	unit.subState, _ = props["SubState"].(string)
+
+	err = b.fillDetails(conn, &unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unit, nil
+}
+
+func (b *dbusBus) RunJob(ctx context.Context, name string, action Action) (string, error) {
+	conn, err := b.connect()
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	resCh := make(chan string, 1)
+
+	switch action {
+	case StartAction:
+		_, err = conn.StartUnit(name, "replace", resCh)
+	case StopAction:
+		_, err = conn.StopUnit(name, "replace", resCh)
+	case RestartAction:
+		_, err = conn.RestartUnit(name, "replace", resCh)
+	default:
+		return "", fmt.Errorf("unknown action %q", action)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create the job: %w", err)
+	}
+
+	select {
+	case res := <-resCh:
+		return res, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (b *dbusBus) connect() (*sddbus.Conn, error) {
+	conn, err := sddbus.NewSystemConnection()
+	if err != nil {
+		return nil, errors.Join(ErrUnavailable, err)
+	}
+
+	return conn, nil
+}
+
+// fillDetails fetches the state change time and the cgroup accounting of the
+// unit.
+func (b *dbusBus) fillDetails(conn *sddbus.Conn, unit *Unit) error {
+	prop, err := conn.GetUnitProperty(unit.name, "StateChangeTimestamp")
+	if err != nil {
+		return fmt.Errorf("failed to get the state change of %q: %w", unit.name, err)
+	}
+
+	if usec, ok := prop.Value.Value().(uint64); ok && usec > 0 {
+		unit.since = time.UnixMicro(int64(usec))
+	}
+
+	props, err := conn.GetUnitTypeProperties(unit.name, "Service")
+	if err != nil {
+		return fmt.Errorf("failed to get the service properties of %q: %w", unit.name, err)
+	}
+
+	// systemd uses the max uint64 for the unset values.
+	if memory, ok := props["MemoryCurrent"].(uint64); ok && memory != math.MaxUint64 {
+		unit.memory = ptr.To(datasize.ByteSize(memory))
+	}
+
+	if cpu, ok := props["CPUUsageNSec"].(uint64); ok && cpu != math.MaxUint64 {
+		usage := time.Duration(cpu)
+		unit.cpuUsage = &usage
+	}
+
+	return nil
+}
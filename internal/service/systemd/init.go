@@ -0,0 +1,18 @@
+package systemd
+
+import (
+	"context"
+)
+
+type Service interface {
+	// GetUnits returns the loaded service units, sorted by name.
+	GetUnits(ctx context.Context) ([]Unit, error)
+	GetUnit(ctx context.Context, name string) (*Unit, error)
+	// Control starts, stops or restarts a service and waits for the job to
+	// complete.
+	Control(ctx context.Context, cmd *ControlCmd) error
+}
+
+func Init() Service {
+	return newService(newDBusBus())
+}
@@ -0,0 +1,78 @@
+package systemd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	v "github.com/go-ozzo/ozzo-validation"
+)
+
+type Action string
+
+const (
+	StartAction   Action = "start"
+	StopAction    Action = "stop"
+	RestartAction Action = "restart"
+)
+
+var AllActions = []Action{StartAction, StopAction, RestartAction}
+
+// Unit is the state of a systemd unit.
+type Unit struct {
+	since       time.Time
+	memory      *datasize.ByteSize
+	cpuUsage    *time.Duration
+	name        string
+	description string
+	loadState   string
+	activeState string
+	subState    string
+}
+
+func (u Unit) Name() string        { return u.name }
+func (u Unit) Description() string { return u.description }
+func (u Unit) LoadState() string   { return u.loadState }
+
+// ActiveState is the high level state: "active", "inactive", "failed",
+// "activating", "deactivating" or "reloading".
+func (u Unit) ActiveState() string { return u.activeState }
+
+// SubState is the low level state, specific to the unit type. For example
+// "running" or "exited" for a service.
+func (u Unit) SubState() string { return u.subState }
+
+// Since returns the time of the last state change. It's zero if unknown.
+func (u Unit) Since() time.Time { return u.since }
+
+// Memory returns the memory used by the unit cgroup. It's nil if the memory
+// accounting is disabled or the unit isn't running.
+func (u Unit) Memory() *datasize.ByteSize { return u.memory }
+
+// CPUUsage returns the CPU time consumed by the unit cgroup. It's nil if the
+// CPU accounting is disabled or the unit isn't running.
+func (u Unit) CPUUsage() *time.Duration { return u.cpuUsage }
+
+func (u Unit) IsActive() bool { return u.activeState == "active" }
+func (u Unit) IsFailed() bool { return u.activeState == "failed" }
+
+type ControlCmd struct {
+	Name   string
+	Action Action
+}
+
+func (t ControlCmd) Validate() error {
+	return v.ValidateStruct(&t,
+		v.Field(&t.Name, v.Required, v.By(isServiceName)),
+		v.Field(&t.Action, v.Required, v.In(StartAction, StopAction, RestartAction)),
+	)
+}
+
+func isServiceName(value any) error {
+	s, _ := value.(string)
+	if !strings.HasSuffix(s, serviceSuffix) || strings.ContainsAny(s, "/ ") {
+		return errNotAService
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package systemd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type FakeUnitBuilder struct {
+	t    testing.TB
+	unit *Unit
+}
+
+func NewFakeUnit(t testing.TB) *FakeUnitBuilder {
+	t.Helper()
+
+	since := gofakeit.DateRange(time.Now().Add(-time.Hour*1000), time.Now())
+	memory := datasize.ByteSize(gofakeit.Number(int(datasize.MB), int(datasize.GB)))
+	cpu := time.Duration(gofakeit.Number(1, 3600)) * time.Second
+
+	return &FakeUnitBuilder{
+		t: t,
+		unit: &Unit{
+			name:        strings.ToLower(gofakeit.AppName()) + serviceSuffix,
+			description: gofakeit.Sentence(3),
+			loadState:   "loaded",
+			activeState: "active",
+			subState:    "running",
+			since:       since.UTC(),
+			memory:      &memory,
+			cpuUsage:    &cpu,
+		},
+	}
+}
+
+func (f *FakeUnitBuilder) WithName(name string) *FakeUnitBuilder {
+	f.unit.name = name
+
+	return f
+}
+
+// WithState sets the active and sub states. The accounting is removed for
+// the not running units.
+func (f *FakeUnitBuilder) WithState(activeState, subState string) *FakeUnitBuilder {
+	f.unit.activeState = activeState
+	f.unit.subState = subState
+
+	if activeState != "active" {
+		f.unit.memory = nil
+		f.unit.cpuUsage = nil
+	}
+
+	return f
+}
+
+func (f *FakeUnitBuilder) Build() *Unit {
+	return f.unit
+}
@@ -0,0 +1,104 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+)
+
+const serviceSuffix = ".service"
+
+var (
+	// ErrUnavailable is returned when systemd can't be reached, for example
+	// inside a container.
+	ErrUnavailable = errors.New("systemd is not available")
+	ErrJobFailed   = errors.New("the job failed")
+
+	errNotAService = errors.New("must be a service unit name")
+	errNotFound    = errors.New("not found")
+)
+
+// bus is a small abstraction over the systemd D-Bus API.
+type bus interface {
+	ListUnits(ctx context.Context) ([]Unit, error)
+	GetUnit(ctx context.Context, name string) (*Unit, error)
+	// RunJob runs the action on the unit and returns the job result: "done"
+	// on success.
+	RunJob(ctx context.Context, name string, action Action) (string, error)
+}
+
+type service struct {
+	bus bus
+}
+
+func newService(bus bus) *service {
+	return &service{bus: bus}
+}
+
+func (s *service) GetUnits(ctx context.Context) ([]Unit, error) {
+	units, err := s.bus.ListUnits(ctx)
+	if err != nil {
+		return nil, s.wrapErr(fmt.Errorf("failed to ListUnits: %w", err))
+	}
+
+	res := []Unit{}
+	for _, unit := range units {
+		if strings.HasSuffix(unit.name, serviceSuffix) {
+			res = append(res, unit)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+
+	return res, nil
+}
+
+func (s *service) GetUnit(ctx context.Context, name string) (*Unit, error) {
+	err := isServiceName(name)
+	if err != nil {
+		return nil, errs.Validation(err)
+	}
+
+	res, err := s.bus.GetUnit(ctx, name)
+	if err != nil {
+		return nil, s.wrapErr(fmt.Errorf("failed to GetUnit: %w", err))
+	}
+
+	return res, nil
+}
+
+func (s *service) Control(ctx context.Context, cmd *ControlCmd) error {
+	err := cmd.Validate()
+	if err != nil {
+		return errs.Validation(err)
+	}
+
+	// Check that the unit exists before running a job on it.
+	_, err = s.bus.GetUnit(ctx, cmd.Name)
+	if err != nil {
+		return s.wrapErr(fmt.Errorf("failed to GetUnit: %w", err))
+	}
+
+	result, err := s.bus.RunJob(ctx, cmd.Name, cmd.Action)
+	if err != nil {
+		return s.wrapErr(fmt.Errorf("failed to %s %q: %w", cmd.Action, cmd.Name, err))
+	}
+
+	if result != "done" {
+		return errs.Internal(fmt.Errorf("%w: %s %q: %s", ErrJobFailed, cmd.Action, cmd.Name, result))
+	}
+
+	return nil
+}
+
+func (s *service) wrapErr(err error) error {
+	if errors.Is(err, errNotFound) {
+		return errs.NotFound(err)
+	}
+
+	return errs.Internal(err)
+}
@@ -0,0 +1,120 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetUnits success", func(t *testing.T) {
+		t.Parallel()
+		busMock := newMockBus(t)
+		svc := newService(busMock)
+
+		// Data
+		nginx := NewFakeUnit(t).WithName("nginx.service").Build()
+		cron := NewFakeUnit(t).WithName("cron.service").WithState("failed", "failed").Build()
+		timer := NewFakeUnit(t).WithName("backup.timer").Build()
+
+		// Mocks
+		busMock.On("ListUnits", mock.Anything).Return([]Unit{*nginx, *timer, *cron}, nil).Once()
+
+		// Run
+		res, err := svc.GetUnits(ctx)
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, []Unit{*cron, *nginx}, res)
+		assert.True(t, res[0].IsFailed())
+		assert.True(t, res[1].IsActive())
+	})
+
+	t.Run("GetUnits with systemd unavailable", func(t *testing.T) {
+		t.Parallel()
+		busMock := newMockBus(t)
+		svc := newService(busMock)
+
+		busMock.On("ListUnits", mock.Anything).Return(nil, ErrUnavailable).Once()
+
+		res, err := svc.GetUnits(ctx)
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrUnavailable)
+		require.ErrorIs(t, err, errs.ErrInternal)
+	})
+
+	t.Run("GetUnit not found", func(t *testing.T) {
+		t.Parallel()
+		busMock := newMockBus(t)
+		svc := newService(busMock)
+
+		busMock.On("GetUnit", mock.Anything, "unknown.service").Return(nil, errNotFound).Once()
+
+		res, err := svc.GetUnit(ctx, "unknown.service")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("GetUnit with an invalid name", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockBus(t))
+
+		res, err := svc.GetUnit(ctx, "../foo")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("Control success", func(t *testing.T) {
+		t.Parallel()
+		busMock := newMockBus(t)
+		svc := newService(busMock)
+
+		// Data
+		unit := NewFakeUnit(t).Build()
+
+		// Mocks
+		busMock.On("GetUnit", mock.Anything, unit.Name()).Return(unit, nil).Once()
+		busMock.On("RunJob", mock.Anything, unit.Name(), RestartAction).Return("done", nil).Once()
+
+		// Run
+		err := svc.Control(ctx, &ControlCmd{Name: unit.Name(), Action: RestartAction})
+
+		// Asserts
+		require.NoError(t, err)
+	})
+
+	t.Run("Control with a failed job", func(t *testing.T) {
+		t.Parallel()
+		busMock := newMockBus(t)
+		svc := newService(busMock)
+
+		// Data
+		unit := NewFakeUnit(t).Build()
+
+		// Mocks
+		busMock.On("GetUnit", mock.Anything, unit.Name()).Return(unit, nil).Once()
+		busMock.On("RunJob", mock.Anything, unit.Name(), StartAction).Return("failed", nil).Once()
+
+		// Run
+		err := svc.Control(ctx, &ControlCmd{Name: unit.Name(), Action: StartAction})
+
+		// Asserts
+		require.ErrorIs(t, err, ErrJobFailed)
+		require.EqualError(t, err, fmt.Sprintf("internal: the job failed: start %q: failed", unit.Name()))
+	})
+
+	t.Run("Control with an unknown action", func(t *testing.T) {
+		t.Parallel()
+		svc := newService(newMockBus(t))
+
+		err := svc.Control(ctx, &ControlCmd{Name: "nginx.service", Action: Action("kill")})
+		require.ErrorIs(t, err, errs.ErrValidation)
+	})
+}
@@ -14,6 +14,7 @@ type Service interface {
 	Create(ctx context.Context, user *CreateCmd) (*User, error)
 	Bootstrap(ctx context.Context, cmd *BootstrapCmd) (*User, error)
 	GetByID(ctx context.Context, userID uuid.UUID) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
 	Authenticate(ctx context.Context, username string, password secret.Text) (*User, error)
 	GetAll(ctx context.Context, paginateCmd *sqlstorage.PaginateCmd) ([]User, error)
 	AddToDeletion(ctx context.Context, userID uuid.UUID) error
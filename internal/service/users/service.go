@@ -202,6 +202,19 @@ func (s *service) GetByID(ctx context.Context, userID uuid.UUID) (*User, error)
 	return res, nil
 }
 
+func (s *service) GetByUsername(ctx context.Context, username string) (*User, error) {
+	res, err := s.storage.GetByUsername(ctx, username)
+	if errors.Is(err, errNotFound) {
+		return nil, errs.NotFound(err)
+	}
+
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+
+	return res, nil
+}
+
 func (s *service) GetAll(ctx context.Context, paginateCmd *sqlstorage.PaginateCmd) ([]User, error) {
 	res, err := s.storage.GetAll(ctx, paginateCmd)
 	if err != nil {
@@ -216,6 +216,36 @@ func (_m *MockService) GetByID(ctx context.Context, userID uuid.UUID) (*User, er
 	return r0, r1
 }
 
+// GetByUsername provides a mock function with given fields: ctx, username
+func (_m *MockService) GetByUsername(ctx context.Context, username string) (*User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUsername")
+	}
+
+	var r0 *User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HardDelete provides a mock function with given fields: ctx, userID
 func (_m *MockService) HardDelete(ctx context.Context, userID uuid.UUID) error {
 	ret := _m.Called(ctx, userID)
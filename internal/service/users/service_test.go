@@ -216,6 +216,43 @@ func Test_Users_Service(t *testing.T) {
 		assert.Equal(t, user, res)
 	})
 
+	t.Run("GetByUsername success", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		store := newMockStorage(t)
+		service := newService(tools, store)
+
+		// Data
+		user := NewFakeUser(t).Build()
+
+		// Mocks
+		store.On("GetByUsername", ctx, user.Username()).Return(user, nil).Once()
+
+		// Run
+		res, err := service.GetByUsername(ctx, user.Username())
+
+		// Asserts
+		require.NoError(t, err)
+		assert.Equal(t, user, res)
+	})
+
+	t.Run("GetByUsername with an unknown username", func(t *testing.T) {
+		t.Parallel()
+		tools := tools.NewMock(t)
+		store := newMockStorage(t)
+		service := newService(tools, store)
+
+		// Mocks
+		store.On("GetByUsername", ctx, "unknown").Return(nil, errNotFound).Once()
+
+		// Run
+		res, err := service.GetByUsername(ctx, "unknown")
+
+		// Asserts
+		require.ErrorIs(t, err, errs.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
 	t.Run("GetAll success", func(t *testing.T) {
 		t.Parallel()
 		tools := tools.NewMock(t)
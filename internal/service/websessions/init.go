@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/secret"
 	"github.com/Peltoche/zapette/internal/tools/sqlstorage"
@@ -15,6 +16,7 @@ import (
 var (
 	ErrMissingSessionToken = errors.New("missing session token")
 	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionExpired      = errors.New("session expired")
 )
 
 type Service interface {
@@ -27,8 +29,8 @@ type Service interface {
 	DeleteAll(ctx context.Context, userID uuid.UUID) error
 }
 
-func Init(tools tools.Tools, db *sql.DB) Service {
+func Init(tools tools.Tools, db *sql.DB, config config.Service) Service {
 	storage := newSQLStorage(db)
 
-	return newService(storage, tools)
+	return newService(storage, config, tools)
 }
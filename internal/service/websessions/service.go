@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/clock"
 	"github.com/Peltoche/zapette/internal/tools/errs"
@@ -28,14 +29,16 @@ type storage interface {
 type service struct {
 	clock   clock.Clock
 	storage storage
+	config  config.Service
 	uuid    uuid.Service
 }
 
-func newService(storage storage, tools tools.Tools) *service {
+func newService(storage storage, config config.Service, tools tools.Tools) *service {
 	return &service{
 		clock:   tools.Clock(),
 		uuid:    tools.UUID(),
 		storage: storage,
+		config:  config,
 	}
 }
 
@@ -100,7 +103,19 @@ func (s *service) GetByToken(ctx context.Context, token secret.Text) (*Session,
 		return nil, errs.Internal(err)
 	}
 
-	// TODO: Handle session expiration
+	lifetime, err := s.config.GetDuration(ctx, config.SessionLifetime)
+	if err != nil {
+		return nil, errs.Internal(fmt.Errorf("failed to get the session lifetime: %w", err))
+	}
+
+	if s.clock.Now().After(session.CreatedAt().Add(lifetime)) {
+		err = s.storage.RemoveByToken(ctx, token)
+		if err != nil {
+			return nil, errs.Internal(fmt.Errorf("failed to RemoveByToken: %w", err))
+		}
+
+		return nil, errs.NotFound(ErrSessionExpired)
+	}
 
 	return session, nil
 }
@@ -112,7 +127,7 @@ func (s *service) GetFromReq(r *http.Request) (*Session, error) {
 	}
 
 	session, err := s.GetByToken(r.Context(), secret.NewText(c.Value))
-	if errors.Is(err, errNotFound) {
+	if errors.Is(err, errNotFound) || errors.Is(err, ErrSessionExpired) {
 		return nil, errs.BadRequest(ErrSessionNotFound, "session not found")
 	}
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/service/users"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/errs"
@@ -29,7 +30,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		now := time.Now().UTC()
@@ -65,7 +67,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 
@@ -89,7 +92,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		now := time.Now().UTC()
@@ -126,7 +130,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -148,7 +153,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -158,6 +164,9 @@ func Test_WebSessions_Service(t *testing.T) {
 		// Mocks
 		storageMock.On("GetByToken", mock.Anything, secret.NewText(rawToken)).Return(session, nil).Once()
 
+		configMock.On("GetDuration", mock.Anything, config.SessionLifetime).Return(24*time.Hour, nil).Once()
+		tools.ClockMock.On("Now").Return(session.CreatedAt().Add(time.Hour)).Once()
+
 		// Run
 		res, err := service.GetByToken(ctx, secret.NewText(rawToken))
 
@@ -166,12 +175,41 @@ func Test_WebSessions_Service(t *testing.T) {
 		assert.EqualValues(t, session, res)
 	})
 
+	t.Run("GetByToken with an expired session", func(t *testing.T) {
+		t.Parallel()
+
+		tools := tools.NewMock(t)
+		storageMock := newMockStorage(t)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
+
+		// Data
+		user := users.NewFakeUser(t).Build()
+		rawToken := "some-token"
+		session := NewFakeSession(t).WithToken(rawToken).CreatedBy(user).Build()
+
+		// Mocks
+		storageMock.On("GetByToken", mock.Anything, secret.NewText(rawToken)).Return(session, nil).Once()
+		configMock.On("GetDuration", mock.Anything, config.SessionLifetime).Return(24*time.Hour, nil).Once()
+		tools.ClockMock.On("Now").Return(session.CreatedAt().Add(25 * time.Hour)).Once()
+		storageMock.On("RemoveByToken", mock.Anything, secret.NewText(rawToken)).Return(nil).Once()
+
+		// Run
+		res, err := service.GetByToken(ctx, secret.NewText(rawToken))
+
+		// Asserts
+		require.ErrorIs(t, err, ErrSessionExpired)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
 	t.Run("GetFromReq success", func(t *testing.T) {
 		t.Parallel()
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -187,6 +225,9 @@ func Test_WebSessions_Service(t *testing.T) {
 		// Mocks
 		storageMock.On("GetByToken", mock.Anything, secret.NewText(rawToken)).Return(session, nil).Once()
 
+		configMock.On("GetDuration", mock.Anything, config.SessionLifetime).Return(24*time.Hour, nil).Once()
+		tools.ClockMock.On("Now").Return(session.CreatedAt().Add(time.Hour)).Once()
+
 		// Run
 		res, err := service.GetFromReq(req)
 
@@ -200,7 +241,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		req, _ := http.NewRequest(http.MethodGet, "/foo", nil) // No cookie
@@ -218,7 +260,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		rawToken := "some-token"
@@ -245,7 +288,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		rawToken := "some-token"
@@ -272,7 +316,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		rawToken := "some-token"
@@ -304,7 +349,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		w := httptest.NewRecorder()
 
@@ -326,7 +372,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		rawToken := "some-token"
@@ -353,7 +400,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -379,7 +427,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		session := NewFakeSession(t).Build()
@@ -400,7 +449,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -425,7 +475,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -452,7 +503,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -476,7 +528,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -497,7 +550,8 @@ func Test_WebSessions_Service(t *testing.T) {
 
 		tools := tools.NewMock(t)
 		storageMock := newMockStorage(t)
-		service := newService(storageMock, tools)
+		configMock := config.NewMockService(t)
+		service := newService(storageMock, configMock, tools)
 
 		// Data
 		user := users.NewFakeUser(t).Build()
@@ -0,0 +1,95 @@
+// Package anomaly flags the points deviating from a rolling baseline.
+package anomaly
+
+import (
+	"math"
+)
+
+// Detector computes, for each point, the mean and the standard deviation of
+// the Window previous points and flags the point as an anomaly if its z-score
+// is above Threshold.
+type Detector struct {
+	// Window is the number of previous points used for the baseline.
+	Window int
+	// MinPoints is the minimal number of points required in the window to
+	// have a baseline.
+	MinPoints int
+	// Threshold is the z-score above which a point is an anomaly.
+	Threshold float64
+	// MinStdDev avoids flagging tiny variations of a flat series.
+	MinStdDev float64
+}
+
+// Score is the result of the detection for a single point.
+type Score struct {
+	Mean   float64
+	StdDev float64
+	// Z is the number of standard deviations between the point and the mean.
+	Z           float64
+	HasBaseline bool
+	IsAnomaly   bool
+}
+
+// Lower returns the lower bound of the normal range.
+func (s Score) Lower(threshold float64) float64 {
+	return s.Mean - threshold*s.StdDev
+}
+
+// Upper returns the upper bound of the normal range.
+func (s Score) Upper(threshold float64) float64 {
+	return s.Mean + threshold*s.StdDev
+}
+
+// Detect returns a score for each value. The missing values must be set to
+// NaN, they are skipped and their score is empty.
+//
+// The anomalies are clamped to the normal range before entering the baseline:
+// a spike barely moves it while a lasting level shift is absorbed after a while.
+func (d Detector) Detect(values []float64) []Score {
+	res := make([]Score, len(values))
+
+	window := make([]float64, 0, d.Window)
+	var sum, sumSq float64
+
+	for i, value := range values {
+		if math.IsNaN(value) {
+			continue
+		}
+
+		if len(window) >= max(d.MinPoints, 2) {
+			n := float64(len(window))
+			mean := sum / n
+			stdDev := math.Sqrt(math.Max(sumSq/n-mean*mean, 0))
+			stdDev = math.Max(stdDev, d.MinStdDev)
+
+			z := 0.0
+			if stdDev > 0 {
+				z = (value - mean) / stdDev
+			}
+
+			res[i] = Score{
+				Mean:        mean,
+				StdDev:      stdDev,
+				Z:           z,
+				HasBaseline: true,
+				IsAnomaly:   math.Abs(z) > d.Threshold,
+			}
+		}
+
+		if res[i].IsAnomaly {
+			value = math.Min(math.Max(value, res[i].Lower(d.Threshold)), res[i].Upper(d.Threshold))
+		}
+
+		if len(window) == d.Window {
+			sum -= window[0]
+			sumSq -= window[0] * window[0]
+			window = window[1:]
+		}
+
+		window = append(window, value)
+		sum += value
+		sumSq += value * value
+	}
+
+	return res
+}
@@ -0,0 +1,68 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetector(t *testing.T) {
+	detector := Detector{Window: 10, MinPoints: 5, Threshold: 3, MinStdDev: 0.5}
+
+	t.Run("flags a spike", func(t *testing.T) {
+		values := []float64{50, 51, 49, 50, 51, 49, 50, 51, 49, 50, 80, 50}
+
+		res := detector.Detect(values)
+		require.Len(t, res, len(values))
+
+		for i := 0; i < 5; i++ {
+			assert.False(t, res[i].HasBaseline, "point %d", i)
+		}
+
+		assert.True(t, res[5].HasBaseline)
+		assert.False(t, res[9].IsAnomaly)
+		assert.True(t, res[10].IsAnomaly)
+		assert.Greater(t, res[10].Z, 3.0)
+		assert.InDelta(t, 50, res[10].Mean, 0.5)
+
+		// The spike is clamped before entering the baseline.
+		assert.False(t, res[11].IsAnomaly)
+		assert.Less(t, res[11].Mean, res[10].Upper(3))
+	})
+
+	t.Run("uses MinStdDev for a flat series", func(t *testing.T) {
+		values := []float64{50, 50, 50, 50, 50, 50, 51}
+
+		res := detector.Detect(values)
+		assert.Equal(t, 0.5, res[6].StdDev)
+		assert.False(t, res[6].IsAnomaly)
+		assert.Equal(t, 48.5, res[6].Lower(3))
+		assert.Equal(t, 51.5, res[6].Upper(3))
+	})
+
+	t.Run("skips the missing values", func(t *testing.T) {
+		values := []float64{50, 50, math.NaN(), 50, 50, 50, 50}
+
+		res := detector.Detect(values)
+		assert.Equal(t, Score{}, res[2])
+		assert.False(t, res[5].HasBaseline)
+		assert.True(t, res[6].HasBaseline)
+	})
+
+	t.Run("absorbs a level shift", func(t *testing.T) {
+		values := []float64{}
+		for i := 0; i < 20; i++ {
+			values = append(values, 10)
+		}
+		for i := 0; i < 40; i++ {
+			values = append(values, 20+float64(i%2))
+		}
+
+		res := detector.Detect(values)
+		assert.True(t, res[20].IsAnomaly)
+		assert.False(t, res[59].IsAnomaly)
+		assert.InDelta(t, 20.5, res[59].Mean, 0.1)
+	})
+}
@@ -0,0 +1,160 @@
+// Package forecast extrapolates the trend of a series of points.
+package forecast
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var ErrNotEnoughPoints = errors.New("not enough points")
+
+// Point is a value measured at a given time.
+type Point struct {
+	At    time.Time
+	Value float64
+}
+
+// Trend is the estimated value at a given time with its slope.
+type Trend struct {
+	At time.Time
+	// Level is the estimated value at At.
+	Level float64
+	// Slope is the value variation per second.
+	Slope float64
+}
+
+// ValueAt extrapolates the value at t.
+func (t Trend) ValueAt(at time.Time) float64 {
+	return t.Level + t.Slope*at.Sub(t.At).Seconds()
+}
+
+// TimeToReach returns the duration after At required to reach the target. It
+// returns false if the target is never reached with the current trend.
+func (t Trend) TimeToReach(target float64) (time.Duration, bool) {
+	if t.Level >= target {
+		return 0, true
+	}
+
+	if t.Slope <= 0 {
+		return 0, false
+	}
+
+	secs := (target - t.Level) / t.Slope
+	if secs > float64(math.MaxInt64/int64(time.Second)) {
+		return 0, false
+	}
+
+	return time.Duration(secs * float64(time.Second)), true
+}
+
+// Linear fits a line with the least squares method. The points must be sorted
+// by time.
+func Linear(points []Point) (*Trend, error) {
+	if len(points) < 2 {
+		return nil, ErrNotEnoughPoints
+	}
+
+	origin := points[0].At
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.At.Sub(origin).Seconds()
+		sumX += x
+		sumY += p.Value
+		sumXY += x * p.Value
+		sumXX += x * x
+	}
+
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return nil, ErrNotEnoughPoints
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+
+	last := points[len(points)-1].At
+
+	return &Trend{
+		At:    last,
+		Level: intercept + slope*last.Sub(origin).Seconds(),
+		Slope: slope,
+	}, nil
+}
+
+// Holt applies a double exponential smoothing (Holt's linear trend method).
+// Alpha is the level smoothing factor and beta the trend smoothing factor,
+// both between 0 and 1. The points must be sorted by time and evenly spaced,
+// see [Resample].
+func Holt(points []Point, alpha, beta float64) (*Trend, error) {
+	if len(points) < 2 {
+		return nil, ErrNotEnoughPoints
+	}
+
+	step := points[1].At.Sub(points[0].At).Seconds()
+	if step <= 0 {
+		return nil, ErrNotEnoughPoints
+	}
+
+	level := points[0].Value
+	trend := points[1].Value - points[0].Value
+
+	for _, p := range points[1:] {
+		prevLevel := level
+		level = alpha*p.Value + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+	}
+
+	return &Trend{
+		At:    points[len(points)-1].At,
+		Level: level,
+		Slope: trend / step,
+	}, nil
+}
+
+// Resample averages the points by buckets of the given duration. The
+// empty buckets, a downtime for example, are filled with a linear
+// interpolation so the result is evenly spaced as required by [Holt]. The
+// points must be sorted by time.
+func Resample(points []Point, bucket time.Duration) []Point {
+	res := []Point{}
+
+	add := func(p Point) {
+		if len(res) > 0 {
+			prev := res[len(res)-1]
+			gap := p.At.Sub(prev.At).Seconds()
+
+			for at := prev.At.Add(bucket); at.Before(p.At); at = at.Add(bucket) {
+				ratio := at.Sub(prev.At).Seconds() / gap
+				res = append(res, Point{At: at, Value: prev.Value + (p.Value-prev.Value)*ratio})
+			}
+		}
+
+		res = append(res, p)
+	}
+
+	var current time.Time
+	var sum float64
+	var count int
+
+	for _, p := range points {
+		start := p.At.Truncate(bucket)
+
+		if count > 0 && !start.Equal(current) {
+			add(Point{At: current, Value: sum / float64(count)})
+			sum, count = 0, 0
+		}
+
+		current = start
+		sum += p.Value
+		count++
+	}
+
+	if count > 0 {
+		add(Point{At: current, Value: sum / float64(count)})
+	}
+
+	return res
+}
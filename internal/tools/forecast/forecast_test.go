@@ -0,0 +1,129 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func linePoints(start time.Time, n int, step time.Duration, origin, slope float64) []Point {
+	res := make([]Point, n)
+	for i := range res {
+		res[i] = Point{
+			At:    start.Add(time.Duration(i) * step),
+			Value: origin + slope*float64(i)*step.Seconds(),
+		}
+	}
+
+	return res
+}
+
+func TestLinear(t *testing.T) {
+	start := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		points := linePoints(start, 60, time.Minute, 10, 0.5)
+
+		res, err := Linear(points)
+		require.NoError(t, err)
+		assert.Equal(t, points[59].At, res.At)
+		assert.InDelta(t, 0.5, res.Slope, 1e-9)
+		assert.InDelta(t, points[59].Value, res.Level, 1e-6)
+
+		eta, ok := res.TimeToReach(res.Level + 30)
+		require.True(t, ok)
+		assert.Equal(t, time.Minute, eta)
+	})
+
+	t.Run("with a decreasing trend", func(t *testing.T) {
+		res, err := Linear(linePoints(start, 10, time.Minute, 10, -0.5))
+		require.NoError(t, err)
+
+		_, ok := res.TimeToReach(100)
+		assert.False(t, ok)
+	})
+
+	t.Run("with not enough points", func(t *testing.T) {
+		res, err := Linear([]Point{{At: start, Value: 1}})
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrNotEnoughPoints)
+	})
+
+	t.Run("with points at the same time", func(t *testing.T) {
+		res, err := Linear([]Point{{At: start, Value: 1}, {At: start, Value: 2}})
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrNotEnoughPoints)
+	})
+}
+
+func TestHolt(t *testing.T) {
+	start := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("follows a linear trend", func(t *testing.T) {
+		points := linePoints(start, 100, time.Minute, 10, 2)
+
+		res, err := Holt(points, 0.5, 0.3)
+		require.NoError(t, err)
+		assert.InDelta(t, 2, res.Slope, 1e-6)
+		assert.InDelta(t, points[99].Value, res.Level, 1e-3)
+	})
+
+	t.Run("follows a change of trend", func(t *testing.T) {
+		points := linePoints(start, 50, time.Minute, 10, 0)
+		points = append(points, linePoints(start.Add(50*time.Minute), 50, time.Minute, 10, 1)...)
+
+		holt, err := Holt(points, 0.5, 0.3)
+		require.NoError(t, err)
+		linear, err := Linear(points)
+		require.NoError(t, err)
+
+		// The linear regression averages the whole history while Holt
+		// focuses on the recent points.
+		assert.InDelta(t, 1, holt.Slope, 0.01)
+		assert.Less(t, linear.Slope, holt.Slope)
+	})
+
+	t.Run("with a gap at the start of the series", func(t *testing.T) {
+		// A single point followed by a 30 minutes downtime.
+		points := []Point{{At: start, Value: 10}}
+		points = append(points, linePoints(start.Add(30*time.Minute), 60, time.Minute, 10+0.01*30*60, 0.01)...)
+
+		res, err := Holt(Resample(points, time.Minute), 0.5, 0.3)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.01, res.Slope, 1e-6)
+	})
+}
+
+func TestResample(t *testing.T) {
+	start := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	res := Resample([]Point{
+		{At: start, Value: 1},
+		{At: start.Add(30 * time.Second), Value: 3},
+		{At: start.Add(3 * time.Minute), Value: 5},
+	}, time.Minute)
+
+	// The empty buckets are interpolated.
+	assert.Equal(t, []Point{
+		{At: start, Value: 2},
+		{At: start.Add(time.Minute), Value: 3},
+		{At: start.Add(2 * time.Minute), Value: 4},
+		{At: start.Add(3 * time.Minute), Value: 5},
+	}, res)
+}
+
+func TestTrend_TimeToReach(t *testing.T) {
+	trend := Trend{At: time.Now(), Level: 50, Slope: 1}
+
+	eta, ok := trend.TimeToReach(40)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), eta)
+
+	eta, ok = trend.TimeToReach(110)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, eta)
+
+	assert.InDelta(t, 60, trend.ValueAt(trend.At.Add(10*time.Second)), 1e-9)
+}
@@ -0,0 +1,27 @@
+package scheduler
+
+import "time"
+
+// JobStatus is the state of a job at a given time.
+type JobStatus struct {
+	name         string
+	schedule     string
+	running      bool
+	next         time.Time
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastError    string
+}
+
+func (s JobStatus) Name() string                { return s.name }
+func (s JobStatus) Schedule() string            { return s.schedule }
+func (s JobStatus) IsRunning() bool             { return s.running }
+func (s JobStatus) NextRun() time.Time          { return s.next }
+func (s JobStatus) LastRun() time.Time          { return s.lastRun }
+func (s JobStatus) LastDuration() time.Duration { return s.lastDuration }
+
+// LastError is the error of the last run, empty if it succeeded.
+func (s JobStatus) LastError() string { return s.lastError }
+
+// HasRun returns false until the end of the first run.
+func (s JobStatus) HasRun() bool { return !s.lastRun.IsZero() }
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type FakeJobStatusBuilder struct {
+	t      testing.TB
+	status *JobStatus
+}
+
+func NewFakeJobStatus(t testing.TB) *FakeJobStatusBuilder {
+	t.Helper()
+
+	return &FakeJobStatusBuilder{
+		t: t,
+		status: &JobStatus{
+			name:         gofakeit.Username(),
+			schedule:     Every(time.Minute).String(),
+			running:      false,
+			next:         time.Now().Add(time.Minute).UTC(),
+			lastRun:      time.Time{},
+			lastDuration: 0,
+			lastError:    "",
+		},
+	}
+}
+
+func (f *FakeJobStatusBuilder) WithLastRun(at time.Time, duration time.Duration, err error) *FakeJobStatusBuilder {
+	f.status.lastRun = at
+	f.status.lastDuration = duration
+
+	if err != nil {
+		f.status.lastError = err.Error()
+	}
+
+	return f
+}
+
+func (f *FakeJobStatusBuilder) Running() *FakeJobStatusBuilder {
+	f.status.running = true
+
+	return f
+}
+
+func (f *FakeJobStatusBuilder) Build() *JobStatus {
+	return f.status
+}
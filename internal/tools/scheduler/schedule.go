@@ -0,0 +1,246 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCronLookup is the period searched for the next run of a cron
+// expression. It covers the leap years.
+const maxCronLookup = 5 * 366 * 24 * time.Hour
+
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// Schedule gives the run times of a job.
+type Schedule interface {
+	// Next returns the first run time strictly after t, or the zero time if
+	// there is none.
+	Next(t time.Time) time.Time
+	String() string
+}
+
+type every struct {
+	interval time.Duration
+}
+
+// Every runs a job at each multiple of the interval, based on the computer
+// clock: with a 5m interval the job runs at hh:00, hh:05, etc.
+func Every(interval time.Duration) Schedule {
+	return &every{interval: interval}
+}
+
+func (e *every) Next(t time.Time) time.Time {
+	if e.interval <= 0 {
+		return time.Time{}
+	}
+
+	return t.Truncate(e.interval).Add(e.interval)
+}
+
+func (e *every) String() string {
+	return "every " + e.interval.String()
+}
+
+// cronField is a set of values, one bit per value.
+type cronField uint64
+
+func (f cronField) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+type cron struct {
+	expr   string
+	minute cronField
+	hour   cronField
+	dom    cronField
+	month  cronField
+	dow    cronField
+	// anyDom and anyDow are set for a "*" day field. If both day fields are
+	// restricted, a day matching any of them is a match.
+	anyDom bool
+	anyDow bool
+	loc    *time.Location
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard cron expression with five fields: minute,
+// hour, day of month, month and day of week. Each field accepts "*", values,
+// ranges ("1-5"), steps ("*/15", "0-30/10") and lists of them ("1,15").
+// The descriptors "@hourly", "@daily", "@weekly", "@monthly" and "@yearly"
+// are accepted too. The times are evaluated in the local timezone.
+func ParseCron(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+
+	if len(fields) == 1 {
+		desc, ok := cronDescriptors[fields[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown descriptor %q", ErrInvalidCron, fields[0])
+		}
+
+		fields = strings.Fields(desc)
+	}
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidCron, len(fields))
+	}
+
+	res := cron{
+		expr:   strings.Join(strings.Fields(expr), " "),
+		anyDom: fields[2] == "*",
+		anyDow: fields[4] == "*",
+		loc:    time.Local,
+	}
+
+	var err error
+	for _, f := range []struct {
+		name      string
+		raw       string
+		low, high int
+		res       *cronField
+	}{
+		{"minute", fields[0], 0, 59, &res.minute},
+		{"hour", fields[1], 0, 23, &res.hour},
+		{"day of month", fields[2], 1, 31, &res.dom},
+		{"month", fields[3], 1, 12, &res.month},
+		{"day of week", fields[4], 0, 7, &res.dow},
+	} {
+		*f.res, err = parseCronField(f.raw, f.low, f.high)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCron, f.name, err)
+		}
+	}
+
+	// Sunday is either 0 or 7.
+	if res.dow.has(7) {
+		res.dow |= 1
+	}
+
+	return &res, nil
+}
+
+// MustParseCron is like ParseCron but panics for an invalid expression. It's
+// made for the expressions hardcoded in the jobs.
+func MustParseCron(expr string) Schedule {
+	res, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return res
+}
+
+func parseCronField(raw string, low, high int) (cronField, error) {
+	var res cronField
+
+	for _, part := range strings.Split(raw, ",") {
+		rangePart, rawStep, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(rawStep)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", rawStep)
+			}
+		}
+
+		start, end := low, high
+		if rangePart != "*" {
+			rawStart, rawEnd, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			start, err = parseCronValue(rawStart, low, high)
+			if err != nil {
+				return 0, err
+			}
+
+			end = start
+			if isRange {
+				end, err = parseCronValue(rawEnd, low, high)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/10" is "5-high/10".
+				end = high
+			}
+
+			if end < start {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			res |= 1 << uint(v)
+		}
+	}
+
+	return res, nil
+}
+
+func parseCronValue(raw string, low, high int) (int, error) {
+	res, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", raw)
+	}
+
+	if res < low || res > high {
+		return 0, fmt.Errorf("%d out of range [%d-%d]", res, low, high)
+	}
+
+	return res, nil
+}
+
+func (c *cron) Next(t time.Time) time.Time {
+	t = t.In(c.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxCronLookup)
+
+	for t.Before(limit) {
+		switch {
+		case !c.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+		case !c.hour.has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.loc)
+		case !c.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c *cron) matchDay(t time.Time) bool {
+	dom := c.dom.has(t.Day())
+	dow := c.dow.has(int(t.Weekday()))
+
+	switch {
+	case c.anyDom && c.anyDow:
+		return true
+	case c.anyDom:
+		return dow
+	case c.anyDow:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func (c *cron) String() string {
+	return c.expr
+}
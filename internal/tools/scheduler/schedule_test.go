@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvery(t *testing.T) {
+	t.Parallel()
+
+	schedule := Every(5 * time.Minute)
+
+	now := time.Date(2024, time.June, 7, 10, 7, 3, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, time.June, 7, 10, 10, 0, 0, time.UTC), schedule.Next(now))
+	assert.Equal(t, time.Date(2024, time.June, 7, 10, 15, 0, 0, time.UTC), schedule.Next(schedule.Next(now)))
+	assert.Equal(t, "every 5m0s", schedule.String())
+	assert.True(t, Every(0).Next(now).IsZero())
+}
+
+func TestParseCron(t *testing.T) {
+	t.Parallel()
+
+	// Friday
+	now := time.Date(2024, time.June, 7, 10, 7, 3, 0, time.Local)
+
+	tests := []struct {
+		Expr     string
+		Expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.June, 7, 10, 8, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2024, time.June, 7, 10, 15, 0, 0, time.Local)},
+		{"5,50 * * * *", time.Date(2024, time.June, 7, 10, 50, 0, 0, time.Local)},
+		{"0-30/10 11 * * *", time.Date(2024, time.June, 7, 11, 0, 0, 0, time.Local)},
+		{"0 9 * * 1-5", time.Date(2024, time.June, 10, 9, 0, 0, 0, time.Local)},
+		{"30 2 * * 7", time.Date(2024, time.June, 9, 2, 30, 0, 0, time.Local)},
+		{"0 0 1 */3 *", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.Local)},
+		{"0 0 13 * 5", time.Date(2024, time.June, 13, 0, 0, 0, 0, time.Local)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.Local)},
+		{"@hourly", time.Date(2024, time.June, 7, 11, 0, 0, 0, time.Local)},
+		{"@daily", time.Date(2024, time.June, 8, 0, 0, 0, 0, time.Local)},
+		{"@weekly", time.Date(2024, time.June, 9, 0, 0, 0, 0, time.Local)},
+		{"@monthly", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.Local)},
+		{"@yearly", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Expr, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := ParseCron(test.Expr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.Expected, schedule.Next(now))
+		})
+	}
+
+	t.Run("never", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := ParseCron("0 0 30 2 *")
+		require.NoError(t, err)
+
+		assert.True(t, schedule.Next(now).IsZero())
+	})
+
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"30-10 * * * *",
+		"a * * * *",
+		"@never",
+	} {
+		t.Run("invalid "+expr, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := ParseCron(expr)
+			require.ErrorIs(t, err, ErrInvalidCron)
+			assert.Nil(t, schedule)
+		})
+	}
+}
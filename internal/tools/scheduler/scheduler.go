@@ -0,0 +1,350 @@
+// Package scheduler runs the background jobs of the server.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"go.uber.org/fx"
+)
+
+var (
+	ErrUnknownJob   = errors.New("unknown job")
+	ErrDuplicateJob = errors.New("a job with the same name already exists")
+	ErrJobRunning   = errors.New("the job is already running")
+	ErrStopped      = errors.New("the scheduler is stopped")
+	ErrNoRun        = errors.New("the schedule never runs the job")
+)
+
+// Runner is the task of a job.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// Job is a named task run by the [Scheduler] according to its schedule.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Jitter delays each scheduled run by a random duration up to Jitter, in
+	// order to spread the jobs sharing the same schedule.
+	Jitter time.Duration
+	// Timeout cancels the context of a run taking longer than that. Zero
+	// means no timeout.
+	Timeout time.Duration
+	Runner  Runner
+}
+
+type job struct {
+	Job
+
+	running      bool
+	next         time.Time
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastErr      error
+}
+
+// Scheduler runs the jobs in the background.
+//
+// A job is never run twice at the same time: a run scheduled while the
+// previous one is still running is skipped. The failures and the panics are
+// logged and kept until the next run.
+type Scheduler struct {
+	clock clock.Clock
+	log   *slog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// wg tracks the job loops and the runs.
+	wg *sync.WaitGroup
+
+	lock    *sync.Mutex
+	jobs    []*job
+	started bool
+	stopped bool
+}
+
+func New(tools tools.Tools) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		clock:   tools.Clock(),
+		log:     tools.Logger().With(slog.String("source", "scheduler")),
+		ctx:     ctx,
+		cancel:  cancel,
+		wg:      new(sync.WaitGroup),
+		lock:    new(sync.Mutex),
+		jobs:    []*job{},
+		started: false,
+		stopped: false,
+	}
+}
+
+// Add registers the jobs. They are scheduled right away if the scheduler
+// is already started.
+func (s *Scheduler) Add(jobs ...Job) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, j := range jobs {
+		switch {
+		case j.Name == "":
+			return errors.New("missing job name")
+		case j.Schedule == nil:
+			return fmt.Errorf("job %q: missing schedule", j.Name)
+		case j.Runner == nil:
+			return fmt.Errorf("job %q: missing runner", j.Name)
+		case j.Jitter < 0 || j.Timeout < 0:
+			return fmt.Errorf("job %q: negative jitter or timeout", j.Name)
+		case j.Schedule.Next(s.clock.Now()).IsZero():
+			return fmt.Errorf("job %q: %w", j.Name, ErrNoRun)
+		case s.getJob(j.Name) != nil:
+			return fmt.Errorf("job %q: %w", j.Name, ErrDuplicateJob)
+		}
+
+		newJob := &job{Job: j}
+		s.jobs = append(s.jobs, newJob)
+
+		if s.started && !s.stopped {
+			s.wg.Add(1)
+			go s.loop(newJob)
+		}
+	}
+
+	return nil
+}
+
+// Start schedules the jobs.
+func (s *Scheduler) Start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.started || s.stopped {
+		return
+	}
+
+	s.started = true
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+}
+
+// Stop cancels the running jobs and waits for them until the context is
+// canceled. The scheduler can't be restarted.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.lock.Lock()
+	s.stopped = true
+	s.lock.Unlock()
+
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("some jobs are still running: %w", ctx.Err())
+	}
+}
+
+// Jobs returns the state of the jobs, in the order they were added.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res := make([]JobStatus, len(s.jobs))
+	for i, j := range s.jobs {
+		res[i] = j.status()
+	}
+
+	return res
+}
+
+// Trigger starts a run of the job now, out of its schedule.
+func (s *Scheduler) Trigger(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	j := s.getJob(name)
+	if j == nil {
+		return errs.NotFound(ErrUnknownJob)
+	}
+
+	if s.stopped {
+		return errs.BadRequest(ErrStopped)
+	}
+
+	if !s.startRun(j) {
+		return errs.BadRequest(ErrJobRunning)
+	}
+
+	s.log.Info("job triggered manually", slog.String("job", name))
+
+	return nil
+}
+
+func (s *Scheduler) FXRegister(lc fx.Lifecycle) {
+	if lc != nil {
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				s.Start()
+				return nil
+			},
+			OnStop: s.Stop,
+		})
+	}
+}
+
+func (s *Scheduler) getJob(name string) *job {
+	idx := slices.IndexFunc(s.jobs, func(j *job) bool { return j.Name == name })
+	if idx < 0 {
+		return nil
+	}
+
+	return s.jobs[idx]
+}
+
+// loop runs the job at each scheduled time until the scheduler is stopped.
+func (s *Scheduler) loop(j *job) {
+	defer s.wg.Done()
+
+	var prev time.Time
+
+	for {
+		// The timers don't follow the computer clock. Starting from the
+		// previous run avoids to run the job twice if the clock is late.
+		now := s.clock.Now()
+		from := now
+		if from.Before(prev) {
+			from = prev
+		}
+
+		next := j.Schedule.Next(from)
+		if next.IsZero() {
+			s.log.Warn("job not scheduled anymore", slog.String("job", j.Name))
+			return
+		}
+
+		prev = next
+
+		if j.Jitter > 0 {
+			next = next.Add(rand.N(j.Jitter))
+		}
+
+		s.lock.Lock()
+		j.next = next
+		s.lock.Unlock()
+
+		timer := time.NewTimer(next.Sub(now))
+
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		s.lock.Lock()
+		started := s.startRun(j)
+		s.lock.Unlock()
+
+		if !started {
+			s.log.Warn("job skipped: the previous run is still running", slog.String("job", j.Name))
+		}
+	}
+}
+
+// startRun runs the job in the background unless it's already running. The
+// lock must be held.
+func (s *Scheduler) startRun(j *job) bool {
+	if j.running || s.stopped {
+		return false
+	}
+
+	j.running = true
+	s.wg.Add(1)
+
+	go s.run(j)
+
+	return true
+}
+
+func (s *Scheduler) run(j *job) {
+	defer s.wg.Done()
+
+	ctx := s.ctx
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+
+	start := s.clock.Now()
+	err := s.safeRun(ctx, j)
+	duration := s.clock.Now().Sub(start)
+
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timeout after %s: %w", j.Timeout, err)
+	}
+
+	s.lock.Lock()
+	j.running = false
+	j.lastRun = start
+	j.lastDuration = duration
+	j.lastErr = err
+	s.lock.Unlock()
+
+	if err != nil && s.ctx.Err() == nil {
+		s.log.Error("job failed",
+			slog.String("job", j.Name),
+			slog.Duration("duration", duration),
+			slog.String("error", err.Error()))
+	}
+}
+
+// safeRun turns a panic of the job into an error.
+func (s *Scheduler) safeRun(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("job panic", slog.String("job", j.Name), slog.String("stack", string(debug.Stack())))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return j.Runner.Run(ctx)
+}
+
+func (j *job) status() JobStatus {
+	res := JobStatus{
+		name:         j.Name,
+		schedule:     j.Schedule.String(),
+		running:      j.running,
+		next:         j.next,
+		lastRun:      j.lastRun,
+		lastDuration: j.lastDuration.Round(time.Millisecond),
+		lastError:    "",
+	}
+
+	if j.lastErr != nil {
+		res.lastError = j.lastErr.Error()
+	}
+
+	return res
+}
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScheduler(t *testing.T) *Scheduler {
+	t.Helper()
+
+	tools := tools.NewMock(t)
+	tools.ClockMock.On("Now").Return(time.Now).Maybe()
+
+	s := New(tools)
+	t.Cleanup(func() {
+		require.NoError(t, s.Stop(context.Background()))
+	})
+
+	return s
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("run a job at its schedule", func(t *testing.T) {
+		t.Parallel()
+		s := newTestScheduler(t)
+		runner := NewMockRunner(t)
+
+		runs := make(chan struct{}, 10)
+		runner.On("Run", mock.Anything).Run(func(mock.Arguments) { runs <- struct{}{} }).Return(nil)
+
+		require.NoError(t, s.Add(Job{Name: "foo", Schedule: Every(10 * time.Millisecond), Runner: runner}))
+		s.Start()
+
+		for range 2 {
+			select {
+			case <-runs:
+			case <-time.After(time.Second):
+				t.Fatal("the job didn't run")
+			}
+		}
+
+		require.NoError(t, s.Stop(context.Background()))
+
+		res := s.Jobs()
+		require.Len(t, res, 1)
+		assert.Equal(t, "foo", res[0].Name())
+		assert.Equal(t, "every 10ms", res[0].Schedule())
+		assert.True(t, res[0].HasRun())
+		assert.Empty(t, res[0].LastError())
+	})
+
+	t.Run("Stop cancels the running jobs", func(t *testing.T) {
+		t.Parallel()
+		s := newTestScheduler(t)
+		runner := NewMockRunner(t)
+
+		started := make(chan struct{})
+		runner.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+			close(started)
+			<-args.Get(0).(context.Context).Done()
+		}).Return(context.Canceled).Once()
+
+		require.NoError(t, s.Add(Job{Name: "foo", Schedule: Every(time.Hour), Runner: runner}))
+		s.Start()
+		require.NoError(t, s.Trigger("foo"))
+		<-started
+
+		start := time.Now()
+		err := s.Stop(context.Background())
+
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), start, 100*time.Millisecond)
+		require.ErrorIs(t, s.Trigger("foo"), ErrStopped)
+	})
+
+	t.Run("a job doesn't overlap itself", func(t *testing.T) {
+		t.Parallel()
+		s := newTestScheduler(t)
+		runner := NewMockRunner(t)
+
+		release := make(chan struct{})
+		runner.On("Run", mock.Anything).Run(func(mock.Arguments) { <-release }).Return(nil).Once()
+
+		require.NoError(t, s.Add(Job{Name: "foo", Schedule: Every(time.Hour), Runner: runner}))
+
+		require.NoError(t, s.Trigger("foo"))
+		err := s.Trigger("foo")
+		require.ErrorIs(t, err, ErrJobRunning)
+		require.ErrorIs(t, err, errs.ErrBadRequest)
+		assert.True(t, s.Jobs()[0].IsRunning())
+
+		close(release)
+		require.Eventually(t, func() bool { return s.Jobs()[0].HasRun() }, time.Second, time.Millisecond)
+		assert.False(t, s.Jobs()[0].IsRunning())
+	})
+
+	t.Run("a panic is recorded as an error", func(t *testing.T) {
+		t.Parallel()
+		s := newTestScheduler(t)
+		runner := NewMockRunner(t)
+
+		runner.On("Run", mock.Anything).Run(func(mock.Arguments) { panic("boom") }).Return(nil).Once()
+
+		require.NoError(t, s.Add(Job{Name: "foo", Schedule: Every(time.Hour), Runner: runner}))
+		require.NoError(t, s.Trigger("foo"))
+
+		require.Eventually(t, func() bool { return s.Jobs()[0].HasRun() }, time.Second, time.Millisecond)
+		assert.Equal(t, "panic: boom", s.Jobs()[0].LastError())
+	})
+
+	t.Run("a job is canceled after its timeout", func(t *testing.T) {
+		t.Parallel()
+		s := newTestScheduler(t)
+		runner := NewMockRunner(t)
+
+		runner.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Return(context.DeadlineExceeded).Once()
+
+		require.NoError(t, s.Add(Job{Name: "foo", Schedule: Every(time.Hour), Timeout: 10 * time.Millisecond, Runner: runner}))
+		require.NoError(t, s.Trigger("foo"))
+
+		require.Eventually(t, func() bool { return s.Jobs()[0].HasRun() }, time.Second, time.Millisecond)
+		assert.Equal(t, "timeout after 10ms: context deadline exceeded", s.Jobs()[0].LastError())
+	})
+
+	t.Run("Trigger with an unknown job", func(t *testing.T) {
+		t.Parallel()
+		s := newTestScheduler(t)
+
+		err := s.Trigger("unknown")
+		require.ErrorIs(t, err, ErrUnknownJob)
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("Add with invalid jobs", func(t *testing.T) {
+		t.Parallel()
+		s := newTestScheduler(t)
+		runner := NewMockRunner(t)
+
+		require.NoError(t, s.Add(Job{Name: "foo", Schedule: Every(time.Hour), Runner: runner}))
+
+		require.ErrorIs(t, s.Add(Job{Name: "foo", Schedule: Every(time.Hour), Runner: runner}), ErrDuplicateJob)
+		require.ErrorIs(t, s.Add(Job{Name: "bar", Schedule: MustParseCron("0 0 30 2 *"), Runner: runner}), ErrNoRun)
+		require.Error(t, s.Add(Job{Name: "bar", Schedule: nil, Runner: runner}))
+		require.Error(t, s.Add(Job{Name: "bar", Schedule: Every(time.Hour), Runner: nil}))
+		require.Error(t, s.Add(Job{Name: "", Schedule: Every(time.Hour), Runner: runner}))
+
+		assert.Len(t, s.Jobs(), 1)
+	})
+}
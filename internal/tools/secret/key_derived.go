@@ -0,0 +1,43 @@
+package secret
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// SaltLength is the size of the salt used by DeriveKey.
+const SaltLength = 16
+
+// The argon2id parameters recommended by the RFC 9106 for the memory
+// constrained environments.
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+)
+
+// NewSalt generates a random salt for DeriveKey.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltLength)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate randomness: %w", err)
+	}
+
+	return salt, nil
+}
+
+// DeriveKey derives a Key from a passphrase with argon2id. The same
+// passphrase and salt always give the same key.
+func DeriveKey(passphrase Text, salt []byte) (*Key, error) {
+	if len(salt) != SaltLength {
+		return nil, fmt.Errorf("invalid salt size: expected %d have %d", SaltLength, len(salt))
+	}
+
+	raw := argon2.IDKey([]byte(passphrase.Raw()), salt, argonTime, argonMemory, argonThreads, KeyLength)
+
+	return KeyFromRaw(raw)
+}
@@ -0,0 +1,52 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveKey(t *testing.T) {
+	salt, err := NewSalt()
+	require.NoError(t, err)
+
+	t.Run("same passphrase and salt give the same key", func(t *testing.T) {
+		k1, err := DeriveKey(NewText("some passphrase"), salt)
+		require.NoError(t, err)
+
+		k2, err := DeriveKey(NewText("some passphrase"), salt)
+		require.NoError(t, err)
+
+		assert.True(t, k1.Equals(k2))
+	})
+
+	t.Run("another passphrase gives another key", func(t *testing.T) {
+		k1, err := DeriveKey(NewText("some passphrase"), salt)
+		require.NoError(t, err)
+
+		k2, err := DeriveKey(NewText("another passphrase"), salt)
+		require.NoError(t, err)
+
+		assert.False(t, k1.Equals(k2))
+	})
+
+	t.Run("another salt gives another key", func(t *testing.T) {
+		otherSalt, err := NewSalt()
+		require.NoError(t, err)
+
+		k1, err := DeriveKey(NewText("some passphrase"), salt)
+		require.NoError(t, err)
+
+		k2, err := DeriveKey(NewText("some passphrase"), otherSalt)
+		require.NoError(t, err)
+
+		assert.False(t, k1.Equals(k2))
+	})
+
+	t.Run("with an invalid salt", func(t *testing.T) {
+		res, err := DeriveKey(NewText("some passphrase"), []byte("too short"))
+		assert.Nil(t, res)
+		require.Error(t, err)
+	})
+}
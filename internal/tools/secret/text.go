@@ -5,7 +5,9 @@
 package secret
 
 import (
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -87,6 +89,15 @@ func (s *Text) Equals(s2 Text) bool {
 	return s.v == s2.v
 }
 
+// Hash returns the hex encoded SHA-256 of the secret value. It's used to save
+// the random tokens looked up by equality, unlike the passwords they don't
+// need a slow hash.
+func (s Text) Hash() string {
+	sum := sha256.Sum256([]byte(s.v))
+
+	return hex.EncodeToString(sum[:])
+}
+
 func (s Text) Value() (driver.Value, error) {
 	return s.v, nil
 }
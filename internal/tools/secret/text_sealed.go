@@ -0,0 +1,107 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+var ErrInvalidSealedText = errors.New("invalid sealed text")
+
+// SealedText is a Text encrypted with a Key.
+//
+// Contrary to the SealedKey, its size depends on the size of the encrypted text.
+type SealedText struct {
+	v []byte
+}
+
+func SealedTextFromBase64(str string) (*SealedText, error) {
+	raw, err := base64.RawStdEncoding.Strict().DecodeString(str)
+	if err != nil {
+		return nil, fmt.Errorf("decoding error: %w", err)
+	}
+
+	if len(raw) < nonceLength+secretbox.Overhead {
+		return nil, ErrInvalidSealedText
+	}
+
+	return &SealedText{v: raw}, nil
+}
+
+func SealText(encryptionKey *Key, input Text) (*SealedText, error) {
+	var nonce [nonceLength]byte
+	_, err := rand.Read(nonce[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate random numbers: %w", err)
+	}
+
+	encrypted := secretbox.Seal(nonce[:], []byte(input.Raw()), &nonce, &encryptionKey.v)
+
+	return &SealedText{v: encrypted}, nil
+}
+
+func (t *SealedText) Open(encryptionKey *Key) (Text, error) {
+	if len(t.v) < nonceLength+secretbox.Overhead {
+		return Empty, ErrInvalidSealedText
+	}
+
+	var decryptNonce [nonceLength]byte
+	copy(decryptNonce[:], t.v[:nonceLength])
+
+	decrypted, ok := secretbox.Open(nil, t.v[nonceLength:], &decryptNonce, &encryptionKey.v)
+	if !ok {
+		return Empty, errors.New("failed to open the sealed text")
+	}
+
+	return NewText(string(decrypted)), nil
+}
+
+// String implements the fmt.Stringer interface and returns only the redact hint. This prevents the
+// secret value from being printed to std*, logs etc.
+func (t *SealedText) String() string {
+	return RedactText
+}
+
+func (t *SealedText) Base64() string {
+	return base64.RawStdEncoding.Strict().EncodeToString(t.v)
+}
+
+func (t *SealedText) Raw() []byte {
+	return bytes.Clone(t.v)
+}
+
+// Equals checks whether t2 has same encrypted content or not.
+func (t *SealedText) Equals(t2 *SealedText) bool {
+	return bytes.Equal(t.v, t2.v)
+}
+
+// MarshalJSON allows SealedText to be serialized into a JSON string. Only the redact hint is part of the
+// the JSON string.
+func (t SealedText) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%s"`, RedactText)), nil
+}
+
+func (t SealedText) Value() (driver.Value, error) {
+	return t.v, nil
+}
+
+func (t *SealedText) Scan(src any) error {
+	v, ok := src.([]byte)
+	if !ok {
+		return errors.New("expected a []byte")
+	}
+
+	t.v = bytes.Clone(v)
+
+	return nil
+}
+
+func (t SealedText) LogValue() slog.Value {
+	return slog.StringValue(RedactText)
+}
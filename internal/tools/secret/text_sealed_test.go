@@ -0,0 +1,74 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealedText(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	var st *SealedText
+
+	t.Run("SealText", func(t *testing.T) {
+		var err error
+
+		st, err = SealText(key, NewText("some-secret"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, st)
+		assert.NotContains(t, string(st.Raw()), "some-secret")
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		res, err := st.Open(key)
+		require.NoError(t, err)
+		assert.Equal(t, "some-secret", res.Raw())
+	})
+
+	t.Run("Open with an invalid key", func(t *testing.T) {
+		invalidKey, err := NewKey()
+		require.NoError(t, err)
+
+		res, err := st.Open(invalidKey)
+		require.Error(t, err)
+		assert.Equal(t, Empty, res)
+	})
+
+	t.Run("MarshalJSON", func(t *testing.T) {
+		res, err := st.MarshalJSON()
+		require.NoError(t, err)
+
+		assert.Equal(t, `"*****"`, string(res))
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, `*****`, st.String())
+	})
+
+	t.Run("Base64", func(t *testing.T) {
+		res, err := SealedTextFromBase64(st.Base64())
+		require.NoError(t, err)
+
+		assert.True(t, st.Equals(res))
+	})
+
+	t.Run("SealedTextFromBase64 with a too short input", func(t *testing.T) {
+		res, err := SealedTextFromBase64("Zm9v")
+		assert.Nil(t, res)
+		require.ErrorIs(t, err, ErrInvalidSealedText)
+	})
+
+	t.Run("Value/Scan", func(t *testing.T) {
+		val, err := st.Value()
+		require.NoError(t, err)
+
+		var res SealedText
+		err = res.Scan(val)
+		require.NoError(t, err)
+
+		assert.True(t, st.Equals(&res))
+	})
+}
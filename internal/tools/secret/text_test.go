@@ -37,6 +37,10 @@ func TestText(t *testing.T) {
 		assert.Equal(t, "hello", s1.Raw())
 	})
 
+	t.Run("Hash", func(t *testing.T) {
+		assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", s1.Hash())
+	})
+
 	t.Run("Equals", func(t *testing.T) {
 		s2 := NewText("hello")
 
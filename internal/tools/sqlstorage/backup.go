@@ -0,0 +1,110 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// Backup writes a copy of the database into path with "VACUUM INTO". The
+// copy is done inside a read transaction so it's consistent even if the
+// database is written at the same time. The file must not exist or be empty.
+func Backup(ctx context.Context, db *sql.DB, path string) error {
+	if DialectOf(db) != SQLite {
+		return ErrSQLiteOnly
+	}
+
+	_, err := db.ExecContext(ctx, "VACUUM INTO ?", path)
+	if err != nil {
+		return fmt.Errorf("failed to vacuum into %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// OpenReadOnly opens a database file without modifying it, in order to
+// inspect a backup.
+func OpenReadOnly(path string) (*sql.DB, error) {
+	_, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("mode", "ro")
+	params.Add("_foreign_keys", "true")
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Replace swaps the database at dbPath with the file at newPath. The
+// current database is kept next to it with the given suffix, along with its
+// WAL files. The server must be stopped.
+func Replace(fs afero.Fs, dbPath, newPath, oldSuffix string) error {
+	tmpPath := dbPath + ".tmp"
+
+	err := copyFile(fs, newPath, tmpPath)
+	if err != nil {
+		_ = fs.Remove(tmpPath)
+		return fmt.Errorf("failed to copy %q: %w", newPath, err)
+	}
+
+	for _, ext := range []string{"", "-wal", "-shm"} {
+		err = fs.Rename(dbPath+ext, dbPath+oldSuffix+ext)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			_ = fs.Remove(tmpPath)
+			return fmt.Errorf("failed to move %q: %w", dbPath+ext, err)
+		}
+	}
+
+	err = fs.Rename(tmpPath, dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to move %q: %w", tmpPath, err)
+	}
+
+	return nil
+}
+
+func copyFile(fs afero.Fs, src, dst string) error {
+	in, err := fs.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := fs.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+
+	err = out.Sync()
+	if err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,97 @@
+package sqlstorage
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Backup and OpenReadOnly success", func(t *testing.T) {
+		db := NewSQLiteTestStorage(t)
+		backupPath := path.Join(t.TempDir(), "backup.sqlite")
+
+		_, err := db.ExecContext(ctx, `CREATE TABLE foo (id INTEGER PRIMARY KEY);
+			INSERT INTO foo (id) VALUES (42);`)
+		require.NoError(t, err)
+
+		err = Backup(ctx, db, backupPath)
+		require.NoError(t, err)
+
+		backup, err := OpenReadOnly(backupPath)
+		require.NoError(t, err)
+		defer backup.Close()
+
+		var res int
+		err = backup.QueryRowContext(ctx, "SELECT id FROM foo").Scan(&res)
+		require.NoError(t, err)
+		assert.Equal(t, 42, res)
+
+		_, err = backup.ExecContext(ctx, "INSERT INTO foo (id) VALUES (43)")
+		require.Error(t, err)
+	})
+
+	t.Run("Backup into an existing file", func(t *testing.T) {
+		db := NewSQLiteTestStorage(t)
+		backupPath := path.Join(t.TempDir(), "backup.sqlite")
+
+		require.NoError(t, Backup(ctx, db, backupPath))
+
+		err := Backup(ctx, db, backupPath)
+		require.Error(t, err)
+	})
+
+	t.Run("OpenReadOnly with a missing file", func(t *testing.T) {
+		backup, err := OpenReadOnly(path.Join(t.TempDir(), "missing.sqlite"))
+		require.Error(t, err)
+		assert.Nil(t, backup)
+	})
+}
+
+func TestReplace(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+
+		require.NoError(t, afero.WriteFile(fs, "/data/db.sqlite", []byte("old"), 0o600))
+		require.NoError(t, afero.WriteFile(fs, "/data/db.sqlite-wal", []byte("old-wal"), 0o600))
+		require.NoError(t, afero.WriteFile(fs, "/backup.sqlite", []byte("new"), 0o600))
+
+		err := Replace(fs, "/data/db.sqlite", "/backup.sqlite", ".old")
+		require.NoError(t, err)
+
+		content, err := afero.ReadFile(fs, "/data/db.sqlite")
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content))
+
+		content, err = afero.ReadFile(fs, "/data/db.sqlite.old")
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+
+		content, err = afero.ReadFile(fs, "/data/db.sqlite.old-wal")
+		require.NoError(t, err)
+		assert.Equal(t, "old-wal", string(content))
+
+		exists, err := afero.Exists(fs, "/data/db.sqlite-wal")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("with a missing file", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+
+		require.NoError(t, afero.WriteFile(fs, "/data/db.sqlite", []byte("old"), 0o600))
+
+		err := Replace(fs, "/data/db.sqlite", "/backup.sqlite", ".old")
+		require.Error(t, err)
+
+		content, err := afero.ReadFile(fs, "/data/db.sqlite")
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+	})
+}
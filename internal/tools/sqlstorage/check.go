@@ -0,0 +1,63 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Check runs the SQLite integrity and foreign key checks. It returns a
+// message per problem found, none if the database is healthy.
+func Check(ctx context.Context, db *sql.DB) ([]string, error) {
+	if DialectOf(db) != SQLite {
+		return nil, ErrSQLiteOnly
+	}
+
+	res := []string{}
+
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run the integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var msg string
+		err = rows.Scan(&msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan the integrity check: %w", err)
+		}
+
+		if msg != "ok" {
+			res = append(res, msg)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to run the integrity check: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run the foreign key check: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+		err = rows.Scan(&table, &rowID, &parent, &fkID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan the foreign key check: %w", err)
+		}
+
+		res = append(res, fmt.Sprintf("%s: row %d references a missing %s", table, rowID.Int64, parent))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to run the foreign key check: %w", err)
+	}
+
+	return res, nil
+}
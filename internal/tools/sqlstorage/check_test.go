@@ -0,0 +1,35 @@
+package sqlstorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		db := NewSQLiteTestStorage(t)
+
+		res, err := Check(ctx, db)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("with a missing foreign key", func(t *testing.T) {
+		db := NewSQLiteTestStorage(t)
+
+		_, err := db.ExecContext(ctx, `PRAGMA foreign_keys = OFF;
+			CREATE TABLE parents (id INTEGER PRIMARY KEY);
+			CREATE TABLE children (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES parents(id));
+			INSERT INTO children (id, parent_id) VALUES (1, 42);`)
+		require.NoError(t, err)
+
+		res, err := Check(ctx, db)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"children: row 1 references a missing parents"}, res)
+	})
+}
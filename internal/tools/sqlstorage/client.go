@@ -9,6 +9,9 @@ import (
 
 type Config struct {
 	Path string `json:"path"`
+	// PostgresURL replaces the SQLite database at Path by a PostgreSQL
+	// database.
+	PostgresURL string `json:"postgresURL"`
 }
 
 // RowScanner is the interface that wraps the Scan method.
@@ -29,7 +32,7 @@ func NewSQliteClient(cfg *Config, hookList *SQLChangeHookList, tools tools.Tools
 
 	dsn := "file:" + cfg.Path + "?" + connectionUrlParams.Encode()
 
-	db := newDBWithHooks(dsn, hookList, tools)
+	db := newDBWithHooks(dsn, hookList)
 
 	db.SetMaxOpenConns(1)
 
@@ -0,0 +1,170 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// NewPostgresClient connects to the PostgreSQL database at cfg.PostgresURL.
+//
+// The queries are written with the "?" placeholders used by SQLite, they are
+// rewritten into the "$1" placeholders expected by PostgreSQL.
+func NewPostgresClient(cfg *Config) (*sql.DB, error) {
+	connector, err := pq.NewConnector(cfg.PostgresURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid postgres url: %w", err)
+	}
+
+	db := sql.OpenDB(&rebindConnector{connector: connector})
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+type rebindConnector struct {
+	connector driver.Connector
+}
+
+func (c *rebindConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pgConn, ok := conn.(postgresConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected postgres connection: %T", conn)
+	}
+
+	return &rebindConn{pgConn}, nil
+}
+
+func (c *rebindConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// postgresConn lists the interfaces implemented by the lib/pq connections.
+// They must be kept by the wrapper, database/sql checks them.
+type postgresConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+type rebindConn struct {
+	postgresConn
+}
+
+func (c *rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.postgresConn.Prepare(rebind(query))
+}
+
+func (c *rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.postgresConn.PrepareContext(ctx, rebind(query))
+}
+
+func (c *rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.postgresConn.ExecContext(ctx, rebind(query), args)
+}
+
+func (c *rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.postgresConn.QueryContext(ctx, rebind(query), args)
+}
+
+// rebind replaces the "?" placeholders by "$1", "$2", etc. The question
+// marks inside the strings, the quoted identifiers and the comments are
+// kept.
+func rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var res strings.Builder
+	res.Grow(len(query) + 8)
+
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case c == '?':
+			n++
+			res.WriteByte('$')
+			res.WriteString(strconv.Itoa(n))
+			continue
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				res.WriteString(query[i:])
+				return res.String()
+			}
+
+			res.WriteString(query[i : i+end+2])
+			i += end + 1
+			continue
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				res.WriteString(query[i:])
+				return res.String()
+			}
+
+			res.WriteString(query[i : i+end])
+			i += end - 1
+			continue
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := blockCommentLen(query[i:])
+			if end < 0 {
+				res.WriteString(query[i:])
+				return res.String()
+			}
+
+			res.WriteString(query[i : i+end])
+			i += end - 1
+			continue
+		}
+
+		res.WriteByte(c)
+	}
+
+	return res.String()
+}
+
+// blockCommentLen returns the length of the "/* */" comment starting query,
+// or -1 if it's unterminated. Like PostgreSQL, the comments can be nested.
+func blockCommentLen(query string) int {
+	depth := 0
+	for i := 0; i < len(query)-1; i++ {
+		switch query[i : i+2] {
+		case "/*":
+			depth++
+			i++
+		case "*/":
+			depth--
+			i++
+
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,56 @@
+package sqlstorage
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebind(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"without placeholder", "SELECT 1", "SELECT 1"},
+		{"with placeholders", "SELECT a FROM t WHERE a = ? AND b > ?", "SELECT a FROM t WHERE a = $1 AND b > $2"},
+		{"inside a string", "SELECT '?' FROM t WHERE a = ?", "SELECT '?' FROM t WHERE a = $1"},
+		{"inside an escaped string", "SELECT 'it''s ?' FROM t WHERE a = ?", "SELECT 'it''s ?' FROM t WHERE a = $1"},
+		{"inside an identifier", `SELECT "a?" FROM t WHERE a = ?`, `SELECT "a?" FROM t WHERE a = $1`},
+		{"inside a comment", "SELECT a -- why?\nFROM t WHERE a = ?", "SELECT a -- why?\nFROM t WHERE a = $1"},
+		{"inside a block comment", "SELECT a /* why? */ FROM t WHERE a = ?", "SELECT a /* why? */ FROM t WHERE a = $1"},
+		{"inside a nested block comment", "SELECT ? /* a /* b? */ c? */ FROM t WHERE a = ?", "SELECT $1 /* a /* b? */ c? */ FROM t WHERE a = $2"},
+		{"unterminated block comment", "SELECT ? /* a?", "SELECT $1 /* a?"},
+		{"unterminated string", "SELECT ? 'a?", "SELECT $1 'a?"},
+		{"dollar placeholders", "SELECT a FROM t WHERE a = $1", "SELECT a FROM t WHERE a = $1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, rebind(tc.query))
+		})
+	}
+}
+
+func TestDialectOf(t *testing.T) {
+	t.Run("with SQLite", func(t *testing.T) {
+		db := NewSQLiteTestStorage(t)
+
+		assert.Equal(t, SQLite, DialectOf(db))
+	})
+
+	t.Run("with PostgreSQL", func(t *testing.T) {
+		postgresURL := os.Getenv(TestPostgresEnv)
+		if postgresURL == "" {
+			t.Skip("$" + TestPostgresEnv + " not set")
+		}
+
+		db := newPostgresTestStorage(t, postgresURL)
+
+		assert.Equal(t, Postgres, DialectOf(db))
+
+		_, err := Check(context.Background(), db)
+		require.ErrorIs(t, err, ErrSQLiteOnly)
+	})
+}
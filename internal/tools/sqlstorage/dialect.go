@@ -0,0 +1,34 @@
+package sqlstorage
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrSQLiteOnly is returned by the features working on the SQLite file
+// itself, like the backups, with another database.
+var ErrSQLiteOnly = errors.New("only available with SQLite")
+
+// Dialect is the database engine behind a *sql.DB.
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// DialectOf returns the engine of the database. The queries are written for
+// both engines, only the features tied to an engine need it.
+func DialectOf(db *sql.DB) Dialect {
+	switch db.Driver().(type) {
+	case *pq.Driver:
+		return Postgres
+	case *sqlite3.SQLiteDriver:
+		return SQLite
+	default:
+		return ""
+	}
+}
@@ -4,13 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
-	"log/slog"
 
-	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/mattn/go-sqlite3"
 )
 
-func newDBWithHooks(dsn string, hookList *SQLChangeHookList, tools tools.Tools) *sql.DB {
+func newDBWithHooks(dsn string, hookList *SQLChangeHookList) *sql.DB {
 	driver := sqlite3.SQLiteDriver{
 		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
 			if hookList == nil {
@@ -18,17 +16,7 @@ func newDBWithHooks(dsn string, hookList *SQLChangeHookList, tools tools.Tools)
 			}
 
 			conn.RegisterUpdateHook(func(op int, dbName string, tableName string, rowID int64) {
-				ctx := context.Background()
-
-				for _, hook := range hookList.GetHooks() {
-					err := hook.RunSQLHook(ctx, tableName)
-					if err != nil {
-						tools.Logger().Error("RunHook error", slog.String("hook", hook.SQLHookName()), slog.String("error", err.Error()))
-						return
-					}
-
-					tools.Logger().Debug("RunHook success", slog.String("hook", hook.SQLHookName()), slog.String("table", tableName))
-				}
+				hookList.run(context.Background(), tableName)
 			})
 
 			return nil
@@ -35,3 +35,16 @@ func (l *SQLChangeHookList) AddHooks(hooks ...SQLChangeHook) {
 		l.logger.Debug("register SQL hook", slog.String("hook", hook.SQLHookName()))
 	}
 }
+
+// run calls the hooks after a change of the table.
+func (l *SQLChangeHookList) run(ctx context.Context, table string) {
+	for _, hook := range l.GetHooks() {
+		err := hook.RunSQLHook(ctx, table)
+		if err != nil {
+			l.logger.Error("RunHook error", slog.String("hook", hook.SQLHookName()), slog.String("error", err.Error()))
+			return
+		}
+
+		l.logger.Debug("RunHook success", slog.String("hook", hook.SQLHookName()), slog.String("table", table))
+	}
+}
@@ -1,6 +1,7 @@
 package sqlstorage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -13,12 +14,44 @@ type Result struct {
 	DB *sql.DB
 }
 
-func Init(cfg Config, hookList *SQLChangeHookList, tools tools.Tools) (Result, error) {
-	db, err := NewSQliteClient(&cfg, hookList, tools)
+// Init opens the SQLite database, or the PostgreSQL one if
+// cfg.PostgresURL is set.
+func Init(cfg Config, hookList *SQLChangeHookList, lc fx.Lifecycle, tools tools.Tools) (Result, error) {
+	if cfg.PostgresURL == "" {
+		// The lock prevents "db restore" from replacing the database while
+		// it's used.
+		if cfg.Path != ":memory:" {
+			lock, err := LockShared(cfg.Path)
+			if err != nil {
+				return Result{}, fmt.Errorf("sqlite error: %w", err)
+			}
+
+			lc.Append(fx.Hook{
+				OnStop: func(context.Context) error { return lock.Close() },
+			})
+		}
+
+		db, err := NewSQliteClient(&cfg, hookList, tools)
+		if err != nil {
+			return Result{}, fmt.Errorf("sqlite error: %w", err)
+		}
+
+		return Result{
+			DB: db,
+		}, nil
+	}
+
+	db, err := NewPostgresClient(&cfg)
 	if err != nil {
-		return Result{}, fmt.Errorf("sqlite error: %w", err)
+		return Result{}, fmt.Errorf("postgres error: %w", err)
 	}
 
+	listener := newPostgresListener(&cfg, hookList, tools.Logger())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error { return listener.Start() },
+		OnStop:  func(context.Context) error { return listener.Stop() },
+	})
+
 	return Result{
 		DB: db,
 	}, nil
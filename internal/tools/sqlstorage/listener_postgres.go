@@ -0,0 +1,83 @@
+package sqlstorage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// changesChannel is notified by the triggers of all the tables with the
+// name of the modified table, see the postgres migrations.
+const changesChannel = "zapette_changes"
+
+// postgresListener runs the hooks on the change notifications, like the
+// SQLite update hook.
+//
+// Contrary to SQLite, the notifications are sent after the commit and the
+// changes made by the other servers sharing the database are notified too.
+type postgresListener struct {
+	listener *pq.Listener
+	hookList *SQLChangeHookList
+	log      *slog.Logger
+	done     chan struct{}
+}
+
+func newPostgresListener(cfg *Config, hookList *SQLChangeHookList, log *slog.Logger) *postgresListener {
+	l := &postgresListener{
+		hookList: hookList,
+		log:      log,
+		done:     make(chan struct{}),
+	}
+
+	l.listener = pq.NewListener(cfg.PostgresURL, time.Second, time.Minute, l.onEvent)
+
+	return l
+}
+
+func (l *postgresListener) Start() error {
+	err := l.listener.Listen(changesChannel)
+	if err != nil {
+		return fmt.Errorf("failed to listen %q: %w", changesChannel, err)
+	}
+
+	go l.loop()
+
+	return nil
+}
+
+func (l *postgresListener) Stop() error {
+	err := l.listener.Close()
+	<-l.done
+
+	return err
+}
+
+func (l *postgresListener) loop() {
+	defer close(l.done)
+
+	ctx := context.Background()
+
+	for n := range l.listener.NotificationChannel() {
+		// A nil notification is sent after a reconnection, some changes
+		// may have been missed.
+		if n == nil {
+			continue
+		}
+
+		l.hookList.run(ctx, n.Extra)
+	}
+}
+
+func (l *postgresListener) onEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		l.log.Warn("postgres listener disconnected", slog.String("error", err.Error()))
+	case pq.ListenerEventReconnected:
+		l.log.Info("postgres listener reconnected")
+	case pq.ListenerEventConnectionAttemptFailed:
+		l.log.Warn("postgres listener failed to reconnect", slog.String("error", err.Error()))
+	}
+}
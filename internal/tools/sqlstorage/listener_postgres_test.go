@@ -0,0 +1,62 @@
+package sqlstorage
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/migrations"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/stretchr/testify/require"
+)
+
+type tableHook chan string
+
+func (h tableHook) SQLHookName() string { return "test" }
+
+func (h tableHook) RunSQLHook(_ context.Context, table string) error {
+	h <- table
+	return nil
+}
+
+func TestPostgresListener(t *testing.T) {
+	postgresURL := os.Getenv(TestPostgresEnv)
+	if postgresURL == "" {
+		t.Skip("$" + TestPostgresEnv + " not set")
+	}
+
+	cfg := Config{PostgresURL: newPostgresTestSchema(t, postgresURL)}
+	tools := tools.NewToolboxForTest(t)
+
+	db, err := NewPostgresClient(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	err = migrations.Run(db, nil)
+	require.NoError(t, err)
+
+	hook := make(tableHook, 10)
+	hookList := NewSQLChangeHookList(tools)
+	hookList.AddHooks(hook)
+
+	listener := newPostgresListener(&cfg, hookList, tools.Logger())
+	require.NoError(t, listener.Start())
+
+	_, err = db.Exec("INSERT INTO config (key, value) VALUES (?, ?)", "foo", "bar")
+	require.NoError(t, err)
+
+	// The channel is shared by all the schemas, the other tests running on
+	// the same database notify their changes too.
+	timeout := time.After(5 * time.Second)
+	for received := false; !received; {
+		select {
+		case table := <-hook:
+			received = table == "config"
+		case <-timeout:
+			t.Fatal("no notification received for the config table")
+		}
+	}
+
+	require.NoError(t, listener.Stop())
+}
@@ -0,0 +1,59 @@
+package sqlstorage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ErrDatabaseInUse is returned by [LockExclusive] while an other process,
+// like the server, uses the database.
+var ErrDatabaseInUse = errors.New("the database is used by an other process")
+
+// lockSuffix is appended to the database path to get the lock file. The
+// database file itself isn't used in order to not interfere with the SQLite
+// locks.
+const lockSuffix = ".lock"
+
+// Lock is an advisory lock on a SQLite database. It's released with Close,
+// or when the process exits.
+type Lock struct {
+	file *os.File
+}
+
+// LockShared takes a shared lock on the database at dbPath. It's held by
+// all the processes using the database and blocks [LockExclusive].
+func LockShared(dbPath string) (*Lock, error) {
+	return lock(dbPath, syscall.LOCK_SH)
+}
+
+// LockExclusive takes an exclusive lock on the database at dbPath, in order
+// to replace it. It fails with ErrDatabaseInUse if it's already locked.
+func LockExclusive(dbPath string) (*Lock, error) {
+	return lock(dbPath, syscall.LOCK_EX)
+}
+
+func lock(dbPath string, how int) (*Lock, error) {
+	file, err := os.OpenFile(dbPath+lockSuffix, os.O_RDONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the lock file: %w", err)
+	}
+
+	err = syscall.Flock(int(file.Fd()), how|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		file.Close()
+		return nil, ErrDatabaseInUse
+	}
+
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %q: %w", file.Name(), err)
+	}
+
+	return &Lock{file: file}, nil
+}
+
+func (l *Lock) Close() error {
+	return l.file.Close()
+}
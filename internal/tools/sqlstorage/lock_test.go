@@ -0,0 +1,52 @@
+package sqlstorage
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	t.Run("LockShared twice", func(t *testing.T) {
+		dbPath := path.Join(t.TempDir(), "db.sqlite")
+
+		lock1, err := LockShared(dbPath)
+		require.NoError(t, err)
+		defer lock1.Close()
+
+		lock2, err := LockShared(dbPath)
+		require.NoError(t, err)
+		defer lock2.Close()
+	})
+
+	t.Run("LockExclusive with a shared lock", func(t *testing.T) {
+		dbPath := path.Join(t.TempDir(), "db.sqlite")
+
+		shared, err := LockShared(dbPath)
+		require.NoError(t, err)
+
+		res, err := LockExclusive(dbPath)
+		require.ErrorIs(t, err, ErrDatabaseInUse)
+		assert.Nil(t, res)
+
+		require.NoError(t, shared.Close())
+
+		res, err = LockExclusive(dbPath)
+		require.NoError(t, err)
+		defer res.Close()
+	})
+
+	t.Run("LockShared with an exclusive lock", func(t *testing.T) {
+		dbPath := path.Join(t.TempDir(), "db.sqlite")
+
+		exclusive, err := LockExclusive(dbPath)
+		require.NoError(t, err)
+		defer exclusive.Close()
+
+		res, err := LockShared(dbPath)
+		require.ErrorIs(t, err, ErrDatabaseInUse)
+		assert.Nil(t, res)
+	})
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func TestTestStorage(t *testing.T) {
-	client := NewTestStorage(t)
+	client := NewSQLiteTestStorage(t)
 
 	row := client.QueryRow(`SELECT COUNT(*) FROM sqlite_schema 
   where type='table' AND name NOT LIKE 'sqlite_%'`)
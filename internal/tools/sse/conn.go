@@ -0,0 +1,134 @@
+package sse
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RetryDelay is the delay advised to the clients before reconnecting to a
+// stream closed by the server.
+const RetryDelay = 5 * time.Second
+
+// Connections tracks the open streams of a handler in order to close them
+// during the server shutdown.
+type Connections struct {
+	lock     *sync.Mutex
+	conns    []*Conn
+	isClosed bool
+}
+
+func NewConnections() *Connections {
+	return &Connections{
+		lock:     new(sync.Mutex),
+		conns:    []*Conn{},
+		isClosed: false,
+	}
+}
+
+// Open registers a new stream for the given request. It returns false if the
+// connections are already closed.
+func (c *Connections) Open(r *http.Request) (*Conn, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.isClosed {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	conn := &Conn{
+		conns:        c,
+		ctx:          ctx,
+		cancel:       cancel,
+		isServerStop: false,
+	}
+
+	c.conns = append(c.conns, conn)
+
+	return conn, true
+}
+
+// CloseAll asks to all the open streams to stop. It can be called several
+// times.
+func (c *Connections) CloseAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.isClosed = true
+
+	for _, conn := range c.conns {
+		conn.isServerStop = true
+		conn.cancel()
+	}
+}
+
+// Len returns the number of open streams.
+func (c *Connections) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.conns)
+}
+
+func (c *Connections) remove(conn *Conn) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conns = slices.DeleteFunc(c.conns, func(o *Conn) bool { return o == conn })
+}
+
+// Conn is an open stream.
+type Conn struct {
+	conns  *Connections
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// isServerStop is guarded by conns.lock.
+	isServerStop bool
+}
+
+// Context returns a context canceled when the client disconnects or when the
+// connections are closed. Everything feeding the stream must use it.
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// Done is a shortcut for Context().Done().
+func (c *Conn) Done() <-chan struct{} {
+	return c.ctx.Done()
+}
+
+// Close unregisters the stream. If the stream have been stopped by the
+// server, the client is asked to reconnect after RetryDelay.
+func (c *Conn) Close(w http.ResponseWriter) {
+	c.cancel()
+	c.conns.remove(c)
+
+	c.conns.lock.Lock()
+	isServerStop := c.isServerStop
+	c.conns.lock.Unlock()
+
+	if isServerStop {
+		fmt.Fprintf(w, "retry: %d\n\n", RetryDelay.Milliseconds())
+		w.(http.Flusher).Flush()
+	}
+}
+
+// WriteHeaders writes the headers of an event stream.
+func WriteHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+}
+
+// WriteUnavailable answers to a stream opened during the server shutdown.
+func WriteUnavailable(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", fmt.Sprint(int(RetryDelay.Seconds())))
+	http.Error(w, "the server is shutting down", http.StatusServiceUnavailable)
+}
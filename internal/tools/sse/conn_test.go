@@ -0,0 +1,81 @@
+package sse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnections(t *testing.T) {
+	t.Run("Open and Close", func(t *testing.T) {
+		conns := NewConnections()
+
+		r := httptest.NewRequest(http.MethodGet, "/sse", nil)
+		w := httptest.NewRecorder()
+
+		conn, ok := conns.Open(r)
+		require.True(t, ok)
+		assert.Equal(t, 1, conns.Len())
+		assert.NoError(t, conn.Context().Err())
+
+		conn.Close(w)
+
+		assert.Equal(t, 0, conns.Len())
+		assert.Error(t, conn.Context().Err())
+
+		// The client left by itself, it's not asked to reconnect.
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("CloseAll stops all the streams", func(t *testing.T) {
+		conns := NewConnections()
+
+		r := httptest.NewRequest(http.MethodGet, "/sse", nil)
+		w := httptest.NewRecorder()
+
+		conn1, ok := conns.Open(r)
+		require.True(t, ok)
+		conn2, ok := conns.Open(r)
+		require.True(t, ok)
+
+		conns.CloseAll()
+
+		assert.Error(t, conn1.Context().Err())
+		assert.Error(t, conn2.Context().Err())
+
+		conn1.Close(w)
+		assert.Equal(t, "retry: 5000\n\n", w.Body.String())
+		assert.Equal(t, 1, conns.Len())
+
+		// It can be called several times.
+		conns.CloseAll()
+	})
+
+	t.Run("Open after CloseAll", func(t *testing.T) {
+		conns := NewConnections()
+		conns.CloseAll()
+
+		conn, ok := conns.Open(httptest.NewRequest(http.MethodGet, "/sse", nil))
+		assert.False(t, ok)
+		assert.Nil(t, conn)
+	})
+
+	t.Run("the client disconnection cancels the context", func(t *testing.T) {
+		conns := NewConnections()
+
+		r := httptest.NewRequest(http.MethodGet, "/sse", nil)
+		ctx, cancel := context.WithCancel(r.Context())
+		r = r.WithContext(ctx)
+
+		conn, ok := conns.Open(r)
+		require.True(t, ok)
+
+		cancel()
+
+		<-conn.Done()
+	})
+}
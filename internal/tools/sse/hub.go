@@ -0,0 +1,310 @@
+package sse
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+const (
+	// KeepAliveInterval is the interval between two comments sent on an idle
+	// stream. It prevents the proxies from closing the connection.
+	KeepAliveInterval = 15 * time.Second
+
+	// BufferSize is the number of events kept for a subscriber not reading
+	// its stream. A subscriber exceeding it is resynced with a snapshot.
+	BufferSize = 16
+
+	// HistorySize is the number of events kept in order to resume the streams
+	// from their Last-Event-ID.
+	HistorySize = 64
+)
+
+// Event is an event ready to be sent to the clients.
+type Event struct {
+	ID   string
+	Name string
+	Data []byte
+}
+
+// Producer computes the events published by a [Hub].
+type Producer interface {
+	// Watch returns a channel notified each time some new data is available.
+	// It must be closed once the context is canceled.
+	Watch(ctx context.Context) chan struct{}
+
+	// Next returns the snapshot containing the whole state and the deltas
+	// since the previous call. It's called once per notification whatever
+	// the number of subscribers.
+	Next(ctx context.Context) (*Event, []Event, error)
+}
+
+type subscriber struct {
+	ch chan Event
+}
+
+// Hub computes the events of a [Producer] once and fans them out to all the
+// connected clients.
+//
+// The producer is only run while there is at least one subscriber. A new
+// subscriber receives the events following its Last-Event-ID if they are
+// still in the history, else the latest snapshot, then the deltas.
+type Hub struct {
+	producer  Producer
+	uuid      uuid.Service
+	log       *slog.Logger
+	keepAlive time.Duration
+
+	// runLock ensures that the producer is never called concurrently, even
+	// if a stopping run overlaps with a new one.
+	runLock *sync.Mutex
+
+	lock        *sync.Mutex
+	subscribers []*subscriber
+	snapshot    *Event
+	cancel      context.CancelFunc
+
+	// The event ids are prefixed by an epoch, changed at each run, in order
+	// to never resume a stream with the events of an other run.
+	epoch   string
+	lastID  int
+	history []Event
+}
+
+func NewHub(name string, tools tools.Tools, producer Producer) *Hub {
+	return &Hub{
+		producer:    producer,
+		uuid:        tools.UUID(),
+		log:         tools.Logger().With(slog.String("sse-hub", name)),
+		keepAlive:   KeepAliveInterval,
+		runLock:     new(sync.Mutex),
+		lock:        new(sync.Mutex),
+		subscribers: []*subscriber{},
+		snapshot:    nil,
+		cancel:      nil,
+		epoch:       "",
+		lastID:      0,
+		history:     []Event{},
+	}
+}
+
+// Stream sends the events to the client until it disconnects or the
+// connections are closed. In the latter case the pending events are sent
+// before returning.
+func (h *Hub) Stream(w http.ResponseWriter, r *http.Request, conns *Connections) {
+	conn, ok := conns.Open(r)
+	if !ok {
+		WriteUnavailable(w)
+		return
+	}
+	defer conn.Close(w)
+
+	WriteHeaders(w)
+
+	sub := h.subscribe(r.Header.Get("Last-Event-ID"))
+	defer h.unsubscribe(sub)
+
+	ticker := time.NewTicker(h.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		var err error
+
+		select {
+		case event := <-sub.ch:
+			err = writeEvent(w, &event)
+		case <-ticker.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case <-conn.Done():
+			if r.Context().Err() == nil {
+				h.drain(w, sub)
+			}
+			return
+		}
+
+		if err != nil {
+			return
+		}
+
+		w.(http.Flusher).Flush()
+	}
+}
+
+// drain sends the events already published to the subscriber.
+func (h *Hub) drain(w http.ResponseWriter, sub *subscriber) {
+	for {
+		select {
+		case event := <-sub.ch:
+			if writeEvent(w, &event) != nil {
+				return
+			}
+		default:
+			w.(http.Flusher).Flush()
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event *Event) error {
+	if event.ID != "" {
+		_, err := fmt.Fprintf(w, "id: %s\n", event.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Name, event.Data)
+
+	return err
+}
+
+func (h *Hub) subscribe(lastEventID string) *subscriber {
+	sub := &subscriber{ch: make(chan Event, BufferSize)}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.snapshot != nil && lastEventID != h.snapshot.ID {
+		missed := h.eventsAfter(lastEventID)
+		if missed == nil || len(missed) > BufferSize {
+			missed = []Event{*h.snapshot}
+		}
+
+		for _, event := range missed {
+			sub.ch <- event
+		}
+	}
+
+	h.subscribers = append(h.subscribers, sub)
+
+	if h.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.cancel = cancel
+		h.epoch = string(h.uuid.New())
+		h.lastID = 0
+		h.history = []Event{}
+
+		go h.run(ctx)
+	}
+
+	return sub
+}
+
+// eventsAfter returns the events following the given id or nil if it's not
+// in the history.
+func (h *Hub) eventsAfter(id string) []Event {
+	for i, event := range h.history {
+		if event.ID == id {
+			return h.history[i+1:]
+		}
+	}
+
+	return nil
+}
+
+func (h *Hub) unsubscribe(sub *subscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.subscribers = slices.DeleteFunc(h.subscribers, func(s *subscriber) bool {
+		return s == sub
+	})
+
+	if len(h.subscribers) == 0 && h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+		h.snapshot = nil
+	}
+}
+
+func (h *Hub) run(ctx context.Context) {
+	h.runLock.Lock()
+	defer h.runLock.Unlock()
+
+	eventCh := h.producer.Watch(ctx)
+
+	// The deltas of the first call are relative to a previous run: only the
+	// snapshot is relevant.
+	isFirst := true
+
+	for {
+		snapshot, deltas, err := h.producer.Next(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			h.log.Error("failed to compute the events", slog.String("error", err.Error()))
+		case isFirst:
+			h.publish(snapshot, []Event{*snapshot})
+			isFirst = false
+		default:
+			h.publish(snapshot, deltas)
+		}
+
+		select {
+		case _, ok := <-eventCh:
+			if !ok {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (h *Hub) publish(snapshot *Event, events []Event) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for i := range events {
+		h.lastID++
+		events[i].ID = fmt.Sprintf("%s-%d", h.epoch, h.lastID)
+	}
+
+	// The snapshot is the state once all the events are applied.
+	h.snapshot = &Event{
+		ID:   fmt.Sprintf("%s-%d", h.epoch, h.lastID),
+		Name: snapshot.Name,
+		Data: snapshot.Data,
+	}
+
+	h.history = append(h.history, events...)
+	if len(h.history) > HistorySize {
+		h.history = slices.Clone(h.history[len(h.history)-HistorySize:])
+	}
+
+	for _, sub := range h.subscribers {
+		for _, event := range events {
+			select {
+			case sub.ch <- event:
+				continue
+			default:
+			}
+
+			// The subscriber is too slow: the pending events are replaced by
+			// the snapshot which contains all of them.
+			h.log.Debug("resync a slow subscriber")
+			drain(sub.ch)
+			sub.ch <- *h.snapshot
+
+			break
+		}
+	}
+}
+
+func drain(ch chan Event) {
+	for {
+		select {
+		case <-ch:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func readLine(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+
+	return strings.TrimSuffix(line, "\n")
+}
+
+func TestHub(t *testing.T) {
+	t.Run("Stream success", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		producerMock := NewMockProducer(t)
+		hub := NewHub("test", tools, producerMock)
+		conns := NewConnections()
+
+		notifyCh := make(chan struct{})
+
+		// Data
+		snapshot := &Event{Name: "Snapshot", Data: []byte(`{"full":true}`)}
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-epoch")).Once()
+		producerMock.On("Watch", mock.Anything).Return(notifyCh).Once()
+		producerMock.On("Next", mock.Anything).Return(snapshot, []Event{{Name: "Delta", Data: []byte(`"old"`)}}, nil).Once()
+		producerMock.On("Next", mock.Anything).Return(snapshot, []Event{{Name: "Delta", Data: []byte(`"new"`)}}, nil).Once()
+
+		// Run
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hub.Stream(w, r, conns)
+		}))
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		// Asserts
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		reader := bufio.NewReader(res.Body)
+
+		// The deltas of the first call are replaced by the snapshot.
+		assert.Equal(t, "id: some-epoch-1", readLine(t, reader))
+		assert.Equal(t, "event: Snapshot", readLine(t, reader))
+		assert.Equal(t, `data: {"full":true}`, readLine(t, reader))
+		assert.Empty(t, readLine(t, reader))
+
+		notifyCh <- struct{}{}
+
+		assert.Equal(t, "id: some-epoch-2", readLine(t, reader))
+		assert.Equal(t, "event: Delta", readLine(t, reader))
+		assert.Equal(t, `data: "new"`, readLine(t, reader))
+		assert.Empty(t, readLine(t, reader))
+	})
+
+	t.Run("Stream sends keep-alive comments", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		producerMock := NewMockProducer(t)
+		hub := NewHub("test", tools, producerMock)
+		hub.keepAlive = 10 * time.Millisecond
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-epoch")).Once()
+		producerMock.On("Watch", mock.Anything).Return(make(chan struct{})).Once()
+		producerMock.On("Next", mock.Anything).Return(&Event{Name: "Snapshot", Data: []byte(`{}`)}, []Event{}, nil).Once()
+
+		// Run
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hub.Stream(w, r, NewConnections())
+		}))
+		defer srv.Close()
+
+		res, err := srv.Client().Get(srv.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		// Asserts
+		reader := bufio.NewReader(res.Body)
+		assert.Equal(t, "id: some-epoch-1", readLine(t, reader))
+		assert.Equal(t, "event: Snapshot", readLine(t, reader))
+		assert.Equal(t, "data: {}", readLine(t, reader))
+		assert.Empty(t, readLine(t, reader))
+		assert.Equal(t, ": keep-alive", readLine(t, reader))
+	})
+
+	t.Run("Stream drains the pending events when the connections are closed", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		producerMock := NewMockProducer(t)
+		hub := NewHub("test", tools, producerMock)
+		conns := NewConnections()
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-epoch")).Once()
+		producerMock.On("Watch", mock.Anything).Return(make(chan struct{})).Once()
+		producerMock.On("Next", mock.Anything).Return(&Event{Name: "Snapshot", Data: []byte(`{}`)}, []Event{}, nil).Once()
+
+		// Run
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hub.Stream(w, r, conns)
+		}))
+		defer srv.Close()
+
+		res, err := srv.Client().Get(srv.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		reader := bufio.NewReader(res.Body)
+		assert.Equal(t, "id: some-epoch-1", readLine(t, reader))
+		assert.Equal(t, "event: Snapshot", readLine(t, reader))
+		assert.Equal(t, "data: {}", readLine(t, reader))
+		assert.Empty(t, readLine(t, reader))
+
+		conns.CloseAll()
+
+		// Asserts
+		assert.Equal(t, "retry: 5000", readLine(t, reader))
+		assert.Empty(t, readLine(t, reader))
+
+		_, err = reader.ReadString('\n')
+		require.Error(t, err)
+	})
+
+	t.Run("Stream with the connections already closed", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		hub := NewHub("test", tools, NewMockProducer(t))
+		conns := NewConnections()
+		conns.CloseAll()
+
+		// Run
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/sse", nil)
+		hub.Stream(w, r, conns)
+
+		// Asserts
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "5", w.Header().Get("Retry-After"))
+		assert.Empty(t, hub.subscribers)
+	})
+
+	t.Run("the payloads are computed once for all the subscribers", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		producerMock := NewMockProducer(t)
+		hub := NewHub("test", tools, producerMock)
+
+		notifyCh := make(chan struct{})
+
+		// Data
+		snapshot := &Event{Name: "Snapshot", Data: []byte(`{}`)}
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-epoch")).Once()
+		producerMock.On("Watch", mock.Anything).Return(notifyCh).Once()
+		producerMock.On("Next", mock.Anything).Return(snapshot, []Event{}, nil).Once()
+		producerMock.On("Next", mock.Anything).Return(snapshot, []Event{{Name: "Delta", Data: []byte(`{}`)}}, nil).Once()
+
+		// Run
+		sub1 := hub.subscribe("")
+		assert.Equal(t, Event{ID: "some-epoch-1", Name: "Snapshot", Data: []byte(`{}`)}, <-sub1.ch)
+
+		// The second subscriber receives the current snapshot on subscription.
+		sub2 := hub.subscribe("")
+		assert.Equal(t, Event{ID: "some-epoch-1", Name: "Snapshot", Data: []byte(`{}`)}, <-sub2.ch)
+
+		notifyCh <- struct{}{}
+
+		// Asserts
+		delta := Event{ID: "some-epoch-2", Name: "Delta", Data: []byte(`{}`)}
+		assert.Equal(t, delta, <-sub1.ch)
+		assert.Equal(t, delta, <-sub2.ch)
+
+		hub.unsubscribe(sub1)
+		hub.unsubscribe(sub2)
+		assert.Nil(t, hub.snapshot)
+	})
+
+	t.Run("subscribe resumes from the Last-Event-ID", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		hub := NewHub("test", tools, NewMockProducer(t))
+
+		// Data
+		hub.epoch = "some-epoch"
+		hub.cancel = func() {}
+		snapshot := &Event{Name: "Snapshot", Data: []byte(`{}`)}
+		hub.publish(snapshot, []Event{{Name: "Delta", Data: []byte(`1`)}})
+		hub.publish(snapshot, []Event{{Name: "Delta", Data: []byte(`2`)}})
+		hub.publish(snapshot, []Event{{Name: "Delta", Data: []byte(`3`)}})
+
+		// Run
+		sub := hub.subscribe("some-epoch-1")
+
+		// Asserts
+		require.Len(t, sub.ch, 2)
+		assert.Equal(t, Event{ID: "some-epoch-2", Name: "Delta", Data: []byte(`2`)}, <-sub.ch)
+		assert.Equal(t, Event{ID: "some-epoch-3", Name: "Delta", Data: []byte(`3`)}, <-sub.ch)
+	})
+
+	t.Run("subscribe with an up to date Last-Event-ID", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		hub := NewHub("test", tools, NewMockProducer(t))
+
+		// Data
+		hub.epoch = "some-epoch"
+		hub.cancel = func() {}
+		hub.publish(&Event{Name: "Snapshot", Data: []byte(`{}`)}, []Event{{Name: "Delta", Data: []byte(`1`)}})
+
+		// Run
+		sub := hub.subscribe("some-epoch-1")
+
+		// Asserts
+		assert.Empty(t, sub.ch)
+	})
+
+	t.Run("subscribe with an unknown Last-Event-ID", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		hub := NewHub("test", tools, NewMockProducer(t))
+
+		// Data
+		hub.epoch = "some-epoch"
+		hub.cancel = func() {}
+		hub.publish(&Event{Name: "Snapshot", Data: []byte(`{}`)}, []Event{{Name: "Delta", Data: []byte(`1`)}})
+
+		// Run
+		sub := hub.subscribe("an-other-epoch-1")
+
+		// Asserts
+		require.Len(t, sub.ch, 1)
+		assert.Equal(t, Event{ID: "some-epoch-1", Name: "Snapshot", Data: []byte(`{}`)}, <-sub.ch)
+	})
+
+	t.Run("a slow subscriber is resynced with the snapshot", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		hub := NewHub("test", tools, NewMockProducer(t))
+
+		// Data
+		sub := &subscriber{ch: make(chan Event, BufferSize)}
+		hub.subscribers = append(hub.subscribers, sub)
+		snapshot := &Event{Name: "Snapshot", Data: []byte(`{}`)}
+
+		// Run
+		for i := 0; i <= BufferSize; i++ {
+			hub.publish(snapshot, []Event{{Name: "Delta", Data: []byte(`{}`)}})
+		}
+
+		// Asserts
+		require.Len(t, sub.ch, 1)
+		assert.Equal(t, "Snapshot", (<-sub.ch).Name)
+	})
+
+	t.Run("the history is bounded", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		hub := NewHub("test", tools, NewMockProducer(t))
+
+		// Run
+		for i := 0; i < HistorySize+10; i++ {
+			hub.publish(&Event{Name: "Snapshot", Data: []byte(`{}`)}, []Event{{Name: "Delta", Data: []byte(`{}`)}})
+		}
+
+		// Asserts
+		assert.Len(t, hub.history, HistorySize)
+		assert.Equal(t, "-11", hub.history[0].ID)
+	})
+
+	t.Run("the producer is stopped without subscriber", func(t *testing.T) {
+		tools := tools.NewMock(t)
+		producerMock := NewMockProducer(t)
+		hub := NewHub("test", tools, producerMock)
+
+		stoppedCh := make(chan struct{})
+
+		// Mocks
+		tools.UUIDMock.On("New").Return(uuid.UUID("some-epoch")).Once()
+		producerMock.On("Watch", mock.Anything).Return(make(chan struct{})).Run(func(args mock.Arguments) {
+			go func(ctx context.Context) {
+				<-ctx.Done()
+				close(stoppedCh)
+			}(args.Get(0).(context.Context))
+		}).Once()
+		producerMock.On("Next", mock.Anything).Return(&Event{Name: "Snapshot", Data: []byte(`{}`)}, []Event{}, nil).Once()
+
+		// Run
+		sub := hub.subscribe("")
+		<-sub.ch
+		hub.unsubscribe(sub)
+
+		// Asserts
+		select {
+		case <-stoppedCh:
+		case <-time.After(time.Second):
+			t.Fatal("the producer is still running")
+		}
+	})
+}
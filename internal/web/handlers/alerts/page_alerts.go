@@ -0,0 +1,285 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/anomalies"
+	"github.com/Peltoche/zapette/internal/service/silences"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/alerts"
+	"github.com/go-chi/chi/v5"
+)
+
+var allWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+type AlertsPage struct {
+	html      html.Writer
+	auth      *auth.Authenticator
+	alerts    alerts.Service
+	silences  silences.Service
+	anomalies anomalies.Service
+	users     users.Service
+	clock     clock.Clock
+}
+
+func NewAlertsPage(
+	html html.Writer,
+	tools tools.Tools,
+	auth *auth.Authenticator,
+	alerts alerts.Service,
+	silences silences.Service,
+	anomalies anomalies.Service,
+	users users.Service,
+) *AlertsPage {
+	return &AlertsPage{
+		html:      html,
+		auth:      auth,
+		alerts:    alerts,
+		silences:  silences,
+		anomalies: anomalies,
+		users:     users,
+		clock:     tools.Clock(),
+	}
+}
+
+func (h *AlertsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/alerts", h.printPage)
+	r.Post("/web/alerts/silences", h.createSilence)
+	r.Post("/web/alerts/silences/{id}/expire", h.expireSilence)
+	r.Post("/web/alerts/windows", h.createWindow)
+	r.Post("/web/alerts/windows/{id}/delete", h.deleteWindow)
+	r.Post("/web/alerts/anomalies", h.setAnomalyAlerts)
+}
+
+func (h *AlertsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, user, http.StatusOK, "")
+}
+
+func (h *AlertsPage) createSilence(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	matchers, err := silences.ParseMatchers(r.FormValue("matchers"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	duration, err := time.ParseDuration(r.FormValue("duration"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, fmt.Sprintf("invalid duration: %s", err))
+		return
+	}
+
+	now := h.clock.Now()
+
+	_, err = h.silences.CreateSilence(r.Context(), &silences.CreateSilenceCmd{
+		CreatedBy: user,
+		Matchers:  matchers,
+		StartsAt:  now,
+		EndsAt:    now.Add(duration),
+		Comment:   r.FormValue("comment"),
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to create the silence: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/alerts", http.StatusFound)
+}
+
+func (h *AlertsPage) expireSilence(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.silences.ExpireSilence(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to expire the silence: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/alerts", http.StatusFound)
+}
+
+func (h *AlertsPage) createWindow(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	matchers, err := silences.ParseMatchers(r.FormValue("matchers"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	start, err := time.Parse("15:04", r.FormValue("start"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, "invalid start: expected a HH:MM format")
+		return
+	}
+
+	duration, err := time.ParseDuration(r.FormValue("duration"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, fmt.Sprintf("invalid duration: %s", err))
+		return
+	}
+
+	weekdays := []time.Weekday{}
+	for _, raw := range r.Form["weekdays"] {
+		day, err := strconv.Atoi(raw)
+		if err != nil {
+			h.renderPage(w, r, user, http.StatusUnprocessableEntity, fmt.Sprintf("invalid weekday: %q", raw))
+			return
+		}
+
+		weekdays = append(weekdays, time.Weekday(day))
+	}
+
+	_, err = h.silences.CreateMaintenanceWindow(r.Context(), &silences.CreateMaintenanceWindowCmd{
+		CreatedBy: user,
+		Name:      r.FormValue("name"),
+		Matchers:  matchers,
+		Weekdays:  weekdays,
+		Start:     time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		Duration:  duration,
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to create the maintenance window: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/alerts", http.StatusFound)
+}
+
+func (h *AlertsPage) deleteWindow(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.silences.DeleteMaintenanceWindow(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to delete the maintenance window: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/alerts", http.StatusFound)
+}
+
+func (h *AlertsPage) setAnomalyAlerts(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.anomalies.SetAlertingEnabled(r.Context(), r.FormValue("enabled") == "true")
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to save the anomaly alerts setting: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/alerts", http.StatusFound)
+}
+
+func (h *AlertsPage) renderPage(w http.ResponseWriter, r *http.Request, user *users.User, status int, formErr string) {
+	ctx := r.Context()
+
+	firing, err := h.alerts.GetFiring(ctx)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the firing alerts: %w", err))
+		return
+	}
+
+	silenceList, err := h.silences.GetSilences(ctx)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the silences: %w", err))
+		return
+	}
+
+	windows, err := h.silences.GetMaintenanceWindows(ctx)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the maintenance windows: %w", err))
+		return
+	}
+
+	anomalyAlerts, err := h.anomalies.IsAlertingEnabled(ctx)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the anomaly alerts setting: %w", err))
+		return
+	}
+
+	creators := []uuid.UUID{}
+	for _, s := range silenceList {
+		creators = append(creators, s.CreatedBy())
+	}
+	for _, w := range windows {
+		creators = append(creators, w.CreatedBy())
+	}
+
+	usernames := map[uuid.UUID]string{}
+	for _, id := range creators {
+		if _, ok := usernames[id]; ok {
+			continue
+		}
+
+		creator, err := h.users.GetByID(ctx, id)
+		if err != nil || creator == nil {
+			// The creator have been deleted.
+			usernames[id] = "unknown"
+			continue
+		}
+
+		usernames[id] = creator.Username()
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.AlertsPageTmpl{
+		Now:           h.clock.Now(),
+		Usernames:     usernames,
+		Alerts:        firing,
+		Silences:      silenceList,
+		Windows:       windows,
+		Weekdays:      allWeekdays,
+		Error:         formErr,
+		Matchers:      r.URL.Query().Get("matchers"),
+		AnomalyAlerts: anomalyAlerts,
+		IsAdmin:       user.IsAdmin(),
+	})
+}
@@ -0,0 +1,125 @@
+package backups
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/Peltoche/zapette/internal/service/backups"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/backups"
+	"github.com/go-chi/chi/v5"
+)
+
+type BackupsPage struct {
+	html    html.Writer
+	auth    *auth.Authenticator
+	backups backups.Service
+	clock   clock.Clock
+	logger  *slog.Logger
+}
+
+func NewBackupsPage(
+	html html.Writer,
+	tools tools.Tools,
+	auth *auth.Authenticator,
+	backups backups.Service,
+) *BackupsPage {
+	return &BackupsPage{
+		html:    html,
+		auth:    auth,
+		backups: backups,
+		clock:   tools.Clock(),
+		logger:  tools.Logger().With(slog.String("source", "backups-page")),
+	}
+}
+
+func (h *BackupsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/backups", h.printPage)
+	r.Get("/web/backups/download", h.downloadSnapshot)
+	r.Get("/web/backups/{name}", h.downloadBackup)
+}
+
+func (h *BackupsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	res, err := h.backups.GetAll(r.Context())
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the backups: %w", err))
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, http.StatusOK, &tmpl.BackupsPageTmpl{
+		Backups: res,
+	})
+}
+
+// downloadSnapshot sends a new backup of the database, it isn't saved in
+// the backups folder.
+func (h *BackupsPage) downloadSnapshot(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	snapshot, err := h.backups.Snapshot(r.Context())
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to create the backup: %w", err))
+		return
+	}
+	defer snapshot.Close()
+
+	writeAttachmentHeaders(w, backups.FileName(h.clock.Now()))
+
+	_, err = io.Copy(w, snapshot)
+	if err != nil {
+		h.logger.Error("failed to send the backup", slog.String("error", err.Error()))
+	}
+}
+
+func (h *BackupsPage) downloadBackup(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+
+	file, err := h.backups.Open(r.Context(), name)
+	if errors.Is(err, errs.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to open the backup: %w", err))
+		return
+	}
+	defer file.Close()
+
+	writeAttachmentHeaders(w, name)
+
+	_, err = io.Copy(w, file)
+	if err != nil {
+		h.logger.Error("failed to send the backup", slog.String("error", err.Error()))
+	}
+}
+
+func writeAttachmentHeaders(w http.ResponseWriter, fileName string) {
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+}
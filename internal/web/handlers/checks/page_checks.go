@@ -0,0 +1,161 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/checks"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/checks"
+	"github.com/go-chi/chi/v5"
+)
+
+// summarySpan is the period summarized for each check.
+const summarySpan = 24 * time.Hour
+
+type ChecksPage struct {
+	html   html.Writer
+	auth   *auth.Authenticator
+	checks checks.Service
+	clock  clock.Clock
+}
+
+func NewChecksPage(
+	html html.Writer,
+	tools tools.Tools,
+	auth *auth.Authenticator,
+	checks checks.Service,
+) *ChecksPage {
+	return &ChecksPage{
+		html:   html,
+		auth:   auth,
+		checks: checks,
+		clock:  tools.Clock(),
+	}
+}
+
+func (h *ChecksPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/checks", h.printPage)
+	r.Post("/web/checks", h.createCheck)
+	r.Post("/web/checks/{id}/delete", h.deleteCheck)
+}
+
+func (h *ChecksPage) printPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, user, http.StatusOK, "")
+}
+
+func (h *ChecksPage) createCheck(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	interval, err := time.ParseDuration(r.FormValue("interval"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, fmt.Sprintf("invalid interval: %s", err))
+		return
+	}
+
+	timeout, err := time.ParseDuration(r.FormValue("timeout"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, fmt.Sprintf("invalid timeout: %s", err))
+		return
+	}
+
+	kind := checks.Kind(r.FormValue("kind"))
+
+	settings := checks.Settings{}
+	switch kind {
+	case checks.HTTP:
+		settings.ExpectedStatus, _ = strconv.Atoi(r.FormValue("expected_status"))
+		settings.BodyRegex = r.FormValue("body_regex")
+	case checks.DNS:
+		settings.Resolver = r.FormValue("resolver")
+	}
+
+	_, err = h.checks.Create(r.Context(), &checks.CreateCmd{
+		CreatedBy: user,
+		Name:      r.FormValue("name"),
+		Kind:      kind,
+		Target:    r.FormValue("target"),
+		Settings:  settings,
+		Interval:  interval,
+		Timeout:   timeout,
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to create the check: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/checks", http.StatusFound)
+}
+
+func (h *ChecksPage) deleteCheck(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.checks.Delete(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to delete the check: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/checks", http.StatusFound)
+}
+
+func (h *ChecksPage) renderPage(w http.ResponseWriter, r *http.Request, user *users.User, status int, formErr string) {
+	ctx := r.Context()
+
+	checkList, err := h.checks.GetAll(ctx, nil)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the checks: %w", err))
+		return
+	}
+
+	since := h.clock.Now().Add(-summarySpan)
+
+	summaries := make(map[uuid.UUID]checks.Summary, len(checkList))
+	for i := range checkList {
+		probes, err := h.checks.GetProbes(ctx, &checkList[i], since)
+		if err != nil {
+			h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the probes: %w", err))
+			return
+		}
+
+		summaries[checkList[i].ID()] = checks.Summarize(probes)
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.ChecksPageTmpl{
+		Checks:    checkList,
+		Summaries: summaries,
+		Kinds:     checks.AllKinds,
+		Error:     formErr,
+		IsAdmin:   user.IsAdmin(),
+	})
+}
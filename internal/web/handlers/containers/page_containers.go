@@ -0,0 +1,222 @@
+package containers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/containers"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/sse"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/containers"
+	"github.com/go-chi/chi/v5"
+)
+
+// logsSize is the number of log lines displayed before the live tail.
+const logsSize = 200
+
+type ContainersPage struct {
+	html       html.Writer
+	auth       *auth.Authenticator
+	containers containers.Service
+	clock      clock.Clock
+	logger     *slog.Logger
+	conns      *sse.Connections
+}
+
+func NewContainersPage(
+	html html.Writer,
+	tools tools.Tools,
+	auth *auth.Authenticator,
+	containers containers.Service,
+) *ContainersPage {
+	return &ContainersPage{
+		html:       html,
+		auth:       auth,
+		containers: containers,
+		clock:      tools.Clock(),
+		logger:     tools.Logger().With(slog.String("source", "containers-logs-sse")),
+		conns:      sse.NewConnections(),
+	}
+}
+
+func (h *ContainersPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/containers", h.printPage)
+	r.Get("/web/containers/{id}", h.printContainerPage)
+	r.Get("/web/containers/{id}/logs/sse", h.logsSSE)
+	r.Post("/web/containers/{id}/{action}", h.control)
+}
+
+func (h *ContainersPage) printPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	list, err := h.containers.GetAll(r.Context())
+	if err != nil && !errors.Is(err, containers.ErrUnavailable) {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the containers: %w", err))
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, http.StatusOK, &tmpl.ContainersPageTmpl{
+		Containers:  list,
+		Unavailable: errors.Is(err, containers.ErrUnavailable),
+		Now:         h.clock.Now(),
+		IsAdmin:     user.IsAdmin(),
+	})
+}
+
+func (h *ContainersPage) printContainerPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderContainerPage(w, r, user, http.StatusOK, "")
+}
+
+func (h *ContainersPage) control(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+
+	err := h.containers.Control(r.Context(), &containers.ControlCmd{
+		ID:     id,
+		Action: containers.Action(chi.URLParam(r, "action")),
+	})
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		http.Redirect(w, r, "/web/containers", http.StatusFound)
+		return
+	case err != nil:
+		// The engine error messages are meaningful: "container already
+		// stopped", "port is already allocated", etc.
+		h.renderContainerPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, "/web/containers/"+id, http.StatusFound)
+}
+
+func (h *ContainersPage) renderContainerPage(w http.ResponseWriter, r *http.Request, user *users.User, status int, formErr string) {
+	container, err := h.containers.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, errs.ErrNotFound) || errors.Is(err, errs.ErrValidation) {
+		http.Redirect(w, r, "/web/containers", http.StatusFound)
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the container: %w", err))
+		return
+	}
+
+	// Like the host logs, the container logs can contain secrets.
+	var logs []containers.LogLine
+	if user.IsAdmin() {
+		logs, err = h.containers.GetLogs(r.Context(), container, logsSize)
+		if err != nil {
+			h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the logs: %w", err))
+			return
+		}
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.ContainerPageTmpl{
+		Container: container,
+		Logs:      logs,
+		Now:       h.clock.Now(),
+		Error:     formErr,
+		IsAdmin:   user.IsAdmin(),
+	})
+}
+
+func (h *ContainersPage) logsSSE(w http.ResponseWriter, r *http.Request) {
+	type logLine struct {
+		Time    string `json:"time"`
+		Stream  string `json:"stream"`
+		Message string `json:"message"`
+	}
+
+	ctx := r.Context()
+
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	container, err := h.containers.GetByID(ctx, chi.URLParam(r, "id"))
+	if errors.Is(err, errs.ErrNotFound) || errors.Is(err, errs.ErrValidation) {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		h.logger.Error("failed to get the container", slog.String("error", err.Error()))
+		http.Error(w, "failed to get the container", http.StatusInternalServerError)
+		return
+	}
+
+	conn, ok := h.conns.Open(r)
+	if !ok {
+		sse.WriteUnavailable(w)
+		return
+	}
+	defer conn.Close(w)
+
+	lineCh, err := h.containers.TailLogs(conn.Context(), container)
+	if err != nil {
+		h.logger.Error("failed to tail the logs", slog.String("error", err.Error()))
+		http.Error(w, "failed to tail the logs", http.StatusInternalServerError)
+		return
+	}
+
+	sse.WriteHeaders(w)
+
+	for {
+		var line containers.LogLine
+		var ok bool
+
+		select {
+		case line, ok = <-lineCh:
+			if !ok {
+				return
+			}
+		case <-conn.Done():
+			return
+		}
+
+		rawData, err := json.Marshal(&logLine{
+			Time:    line.At().Local().Format(time.DateTime),
+			Stream:  line.Stream(),
+			Message: line.Message(),
+		})
+		if err != nil {
+			h.logger.Error("failed to marshal the log line", slog.String("error", err.Error()))
+			continue
+		}
+
+		fmt.Fprintf(w, "event: LogLine\ndata: %s\n\n", rawData)
+		w.(http.Flusher).Flush()
+	}
+}
+
+func (h *ContainersPage) CloseOpenConnections() {
+	h.logger.Info("close open connections")
+	h.conns.CloseAll()
+}
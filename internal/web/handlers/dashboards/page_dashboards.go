@@ -0,0 +1,373 @@
+package dashboards
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/dashboards"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/dashboards"
+	"github.com/go-chi/chi/v5"
+)
+
+type DashboardsPage struct {
+	html       html.Writer
+	auth       *auth.Authenticator
+	dashboards dashboards.Service
+}
+
+func NewDashboardsPage(
+	html html.Writer,
+	auth *auth.Authenticator,
+	dashboards dashboards.Service,
+) *DashboardsPage {
+	return &DashboardsPage{
+		html:       html,
+		auth:       auth,
+		dashboards: dashboards,
+	}
+}
+
+func (h *DashboardsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/dashboards", h.printPage)
+	r.Post("/web/dashboards", h.createDashboard)
+	r.Get("/web/dashboards/shared/{token}", h.printSharedPage)
+	r.Get("/web/dashboards/{id}", h.printDashboardPage)
+	r.Post("/web/dashboards/{id}/panels", h.addPanel)
+	r.Post("/web/dashboards/{id}/panels/{panelID}/move", h.movePanel)
+	r.Post("/web/dashboards/{id}/panels/{panelID}/delete", h.deletePanel)
+	r.Post("/web/dashboards/{id}/share", h.share)
+	r.Post("/web/dashboards/{id}/unshare", h.unshare)
+	r.Post("/web/dashboards/{id}/delete", h.deleteDashboard)
+}
+
+func (h *DashboardsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, user, http.StatusOK, "")
+}
+
+func (h *DashboardsPage) createDashboard(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, err := h.dashboards.Create(r.Context(), &dashboards.CreateCmd{
+		Owner: user,
+		Name:  r.FormValue("name"),
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to create the dashboard: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/dashboards/"+string(dashboard.ID()), http.StatusFound)
+}
+
+func (h *DashboardsPage) printDashboardPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, abort := h.getOwnedDashboard(w, r, user)
+	if abort {
+		return
+	}
+
+	h.renderDashboardPage(w, r, dashboard, http.StatusOK, "")
+}
+
+func (h *DashboardsPage) printSharedPage(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, err := h.dashboards.GetByShareToken(r.Context(), secret.NewText(chi.URLParam(r, "token")))
+	if errors.Is(err, errs.ErrNotFound) {
+		http.Redirect(w, r, "/web/dashboards", http.StatusFound)
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the dashboard: %w", err))
+		return
+	}
+
+	panels, err := h.getPanels(r, dashboard)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, err)
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, http.StatusOK, &tmpl.DashboardPageTmpl{
+		Dashboard: dashboard,
+		Panels:    panels,
+		Series:    []dashboards.Series{},
+		ShareURL:  "",
+		ReadOnly:  true,
+		Error:     "",
+	})
+}
+
+func (h *DashboardsPage) addPanel(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, abort := h.getOwnedDashboard(w, r, user)
+	if abort {
+		return
+	}
+
+	// The invalid numbers are left to zero and rejected by the validation.
+	span, _ := strconv.Atoi(r.FormValue("span"))
+	width, _ := strconv.Atoi(r.FormValue("width"))
+
+	_, err := h.dashboards.AddPanel(r.Context(), &dashboards.AddPanelCmd{
+		Dashboard: dashboard,
+		Title:     r.FormValue("title"),
+		Kind:      dashboards.PanelKind(r.FormValue("kind")),
+		Series:    r.FormValue("series"),
+		Span:      time.Duration(span) * time.Second,
+		Width:     width,
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderDashboardPage(w, r, dashboard, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to add the panel: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/dashboards/"+string(dashboard.ID()), http.StatusFound)
+}
+
+func (h *DashboardsPage) movePanel(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, abort := h.getOwnedDashboard(w, r, user)
+	if abort {
+		return
+	}
+
+	offset, _ := strconv.Atoi(r.FormValue("offset"))
+
+	_, err := h.dashboards.MovePanel(r.Context(), &dashboards.MovePanelCmd{
+		Dashboard: dashboard,
+		PanelID:   uuid.UUID(chi.URLParam(r, "panelID")),
+		Offset:    offset,
+	})
+	if errors.Is(err, errs.ErrValidation) || errors.Is(err, errs.ErrNotFound) {
+		h.renderDashboardPage(w, r, dashboard, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to move the panel: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/dashboards/"+string(dashboard.ID()), http.StatusFound)
+}
+
+func (h *DashboardsPage) deletePanel(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, abort := h.getOwnedDashboard(w, r, user)
+	if abort {
+		return
+	}
+
+	_, err := h.dashboards.DeletePanel(r.Context(), dashboard, uuid.UUID(chi.URLParam(r, "panelID")))
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to delete the panel: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/dashboards/"+string(dashboard.ID()), http.StatusFound)
+}
+
+func (h *DashboardsPage) share(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, abort := h.getOwnedDashboard(w, r, user)
+	if abort {
+		return
+	}
+
+	dashboard, err := h.dashboards.Share(r.Context(), dashboard)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to share the dashboard: %w", err))
+		return
+	}
+
+	// The page is rendered right away, the share link can't be read anymore
+	// after it.
+	h.renderDashboardPage(w, r, dashboard, http.StatusOK, "")
+}
+
+func (h *DashboardsPage) unshare(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, abort := h.getOwnedDashboard(w, r, user)
+	if abort {
+		return
+	}
+
+	_, err := h.dashboards.Unshare(r.Context(), dashboard)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to unshare the dashboard: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/dashboards/"+string(dashboard.ID()), http.StatusFound)
+}
+
+func (h *DashboardsPage) deleteDashboard(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	dashboard, abort := h.getOwnedDashboard(w, r, user)
+	if abort {
+		return
+	}
+
+	err := h.dashboards.Delete(r.Context(), dashboard)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to delete the dashboard: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/dashboards", http.StatusFound)
+}
+
+// getOwnedDashboard returns the dashboard from the url. The users other than
+// the owner are redirected to the dashboards list.
+func (h *DashboardsPage) getOwnedDashboard(w http.ResponseWriter, r *http.Request, user *users.User) (*dashboards.Dashboard, bool) {
+	dashboard, err := h.dashboards.GetByID(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if errors.Is(err, errs.ErrNotFound) || (err == nil && !dashboard.IsOwnedBy(user)) {
+		http.Redirect(w, r, "/web/dashboards", http.StatusFound)
+		return nil, true
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the dashboard: %w", err))
+		return nil, true
+	}
+
+	return dashboard, false
+}
+
+func (h *DashboardsPage) renderPage(w http.ResponseWriter, r *http.Request, user *users.User, status int, formErr string) {
+	res, err := h.dashboards.GetAllForUser(r.Context(), user)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the dashboards: %w", err))
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.DashboardsPageTmpl{
+		Dashboards: res,
+		Error:      formErr,
+	})
+}
+
+func (h *DashboardsPage) renderDashboardPage(w http.ResponseWriter, r *http.Request, dashboard *dashboards.Dashboard, status int, formErr string) {
+	panels, err := h.getPanels(r, dashboard)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, err)
+		return
+	}
+
+	series, err := h.dashboards.GetAllSeries(r.Context())
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the series: %w", err))
+		return
+	}
+
+	shareURL := ""
+	if token := dashboard.ShareToken(); token != nil {
+		shareURL = baseURL(r) + "/web/dashboards/shared/" + token.Raw()
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.DashboardPageTmpl{
+		Dashboard: dashboard,
+		Panels:    panels,
+		Series:    series,
+		ShareURL:  shareURL,
+		ReadOnly:  false,
+		Error:     formErr,
+	})
+}
+
+// getPanels returns the panels with their data. The data of a series not
+// available anymore, like the series of a deleted host, are left to nil.
+func (h *DashboardsPage) getPanels(r *http.Request, dashboard *dashboards.Dashboard) ([]tmpl.PanelView, error) {
+	panels := dashboard.Panels()
+	res := make([]tmpl.PanelView, len(panels))
+
+	for i, panel := range panels {
+		data, err := h.dashboards.GetPanelData(r.Context(), &panel)
+		if err != nil && !errors.Is(err, errs.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get the data of the panel %q: %w", panel.ID(), err)
+		}
+
+		res[i] = tmpl.PanelView{
+			Panel:   panel,
+			Data:    data,
+			IsFirst: i == 0,
+			IsLast:  i == len(panels)-1,
+		}
+	}
+
+	return res, nil
+}
+
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host
+}
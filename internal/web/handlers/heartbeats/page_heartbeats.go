@@ -0,0 +1,176 @@
+package heartbeats
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/heartbeats"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/heartbeats"
+	"github.com/go-chi/chi/v5"
+)
+
+// historySize is the number of pings displayed in the history.
+const historySize = 50
+
+type HeartbeatsPage struct {
+	html       html.Writer
+	auth       *auth.Authenticator
+	heartbeats heartbeats.Service
+}
+
+func NewHeartbeatsPage(
+	html html.Writer,
+	auth *auth.Authenticator,
+	heartbeats heartbeats.Service,
+) *HeartbeatsPage {
+	return &HeartbeatsPage{
+		html:       html,
+		auth:       auth,
+		heartbeats: heartbeats,
+	}
+}
+
+func (h *HeartbeatsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/heartbeats", h.printPage)
+	r.Post("/web/heartbeats", h.createHeartbeat)
+	r.Get("/web/heartbeats/{id}", h.printHeartbeatPage)
+	r.Post("/web/heartbeats/{id}/delete", h.deleteHeartbeat)
+}
+
+func (h *HeartbeatsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, user, http.StatusOK, "")
+}
+
+func (h *HeartbeatsPage) createHeartbeat(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	period, err := time.ParseDuration(r.FormValue("period"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, fmt.Sprintf("invalid period: %s", err))
+		return
+	}
+
+	grace, err := time.ParseDuration(r.FormValue("grace"))
+	if err != nil {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, fmt.Sprintf("invalid grace time: %s", err))
+		return
+	}
+
+	heartbeat, err := h.heartbeats.Create(r.Context(), &heartbeats.CreateCmd{
+		CreatedBy: user,
+		Name:      r.FormValue("name"),
+		Period:    period,
+		Grace:     grace,
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to create the heartbeat: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/heartbeats/"+string(heartbeat.ID()), http.StatusFound)
+}
+
+func (h *HeartbeatsPage) printHeartbeatPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	heartbeat, err := h.heartbeats.GetByID(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if errors.Is(err, errs.ErrNotFound) {
+		http.Redirect(w, r, "/web/heartbeats", http.StatusFound)
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the heartbeat: %w", err))
+		return
+	}
+
+	pings, err := h.heartbeats.GetPings(r.Context(), heartbeat, historySize)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the pings: %w", err))
+		return
+	}
+
+	var lastRun *heartbeats.Ping
+	for i := range pings {
+		if pings[i].Kind() != heartbeats.Start {
+			lastRun = &pings[i]
+			break
+		}
+	}
+
+	h.html.WriteHTMLTemplate(w, r, http.StatusOK, &tmpl.HeartbeatPageTmpl{
+		Heartbeat: heartbeat,
+		Pings:     pings,
+		LastRun:   lastRun,
+		BaseURL:   baseURL(r),
+		IsAdmin:   user.IsAdmin(),
+	})
+}
+
+func (h *HeartbeatsPage) deleteHeartbeat(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.heartbeats.Delete(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to delete the heartbeat: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/heartbeats", http.StatusFound)
+}
+
+func (h *HeartbeatsPage) renderPage(w http.ResponseWriter, r *http.Request, user *users.User, status int, formErr string) {
+	list, err := h.heartbeats.GetAll(r.Context(), nil)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the heartbeats: %w", err))
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.HeartbeatsPageTmpl{
+		Heartbeats: list,
+		BaseURL:    baseURL(r),
+		Error:      formErr,
+		IsAdmin:    user.IsAdmin(),
+	})
+}
+
+// baseURL returns the url used by the client to reach the server.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,415 @@
+package hosts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/sse"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/hosts"
+	"github.com/go-chi/chi/v5"
+)
+
+// refreshInterval is the max interval between two checks of the tiles.
+const refreshInterval = 5 * time.Second
+
+type HostsPage struct {
+	html   html.Writer
+	auth   *auth.Authenticator
+	hosts  hosts.Service
+	clock  clock.Clock
+	hub    *sse.Hub
+	logger *slog.Logger
+	conns  *sse.Connections
+}
+
+func NewHostsPage(
+	html html.Writer,
+	tools tools.Tools,
+	auth *auth.Authenticator,
+	hosts hosts.Service,
+) *HostsPage {
+	return &HostsPage{
+		html:   html,
+		auth:   auth,
+		hosts:  hosts,
+		clock:  tools.Clock(),
+		hub:    sse.NewHub("hosts", tools, &fleetProducer{hosts: hosts, clock: tools.Clock(), prev: nil}),
+		logger: tools.Logger().With(slog.String("source", "hosts-sse")),
+		conns:  sse.NewConnections(),
+	}
+}
+
+func (h *HostsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/hosts", h.printPage)
+	r.Post("/web/hosts", h.createHost)
+	r.Get("/web/hosts/sse", h.sse)
+	r.Get("/web/hosts/{id}", h.printHostPage)
+	r.Post("/web/hosts/{id}/tags", h.updateTags)
+	r.Post("/web/hosts/{id}/token", h.regenerateToken)
+	r.Post("/web/hosts/{id}/delete", h.deleteHost)
+}
+
+func (h *HostsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, user, http.StatusOK, "")
+}
+
+func (h *HostsPage) createHost(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	host, err := h.hosts.Create(r.Context(), &hosts.CreateCmd{
+		CreatedBy: user,
+		Name:      r.FormValue("name"),
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to create the host: %w", err))
+		return
+	}
+
+	// The page is rendered right away, the token can't be read anymore
+	// after it.
+	h.writeHostPage(w, r, user, host, http.StatusOK, "")
+}
+
+func (h *HostsPage) printHostPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderHostPage(w, r, user, http.StatusOK, "")
+}
+
+func (h *HostsPage) updateTags(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	host, err := h.hosts.GetByID(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if errors.Is(err, errs.ErrNotFound) {
+		http.Redirect(w, r, "/web/hosts", http.StatusFound)
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the host: %w", err))
+		return
+	}
+
+	_, err = h.hosts.UpdateTags(r.Context(), &hosts.UpdateTagsCmd{
+		Host: host,
+		Tags: strings.Split(r.FormValue("tags"), ","),
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderHostPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to update the tags: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/hosts/"+string(host.ID()), http.StatusFound)
+}
+
+func (h *HostsPage) regenerateToken(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	host, err := h.hosts.GetByID(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if errors.Is(err, errs.ErrNotFound) {
+		http.Redirect(w, r, "/web/hosts", http.StatusFound)
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the host: %w", err))
+		return
+	}
+
+	host, err = h.hosts.RegenerateToken(r.Context(), host)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to regenerate the token: %w", err))
+		return
+	}
+
+	h.writeHostPage(w, r, user, host, http.StatusOK, "")
+}
+
+func (h *HostsPage) deleteHost(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.hosts.Delete(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to delete the host: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/hosts", http.StatusFound)
+}
+
+func (h *HostsPage) sse(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.hub.Stream(w, r, h.conns)
+}
+
+func (h *HostsPage) CloseOpenConnections() {
+	h.logger.Info("close open connections")
+	h.conns.CloseAll()
+}
+
+func (h *HostsPage) renderPage(w http.ResponseWriter, r *http.Request, user *users.User, status int, formErr string) {
+	summaries, err := h.hosts.GetSummaries(r.Context())
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the hosts: %w", err))
+		return
+	}
+
+	tag := r.URL.Query().Get("tag")
+	sortKey := r.URL.Query().Get("sort")
+	if !slices.Contains(tmpl.SortKeys, sortKey) {
+		sortKey = tmpl.SortKeys[0]
+	}
+
+	now := h.clock.Now()
+	tags := []string{}
+	tiles := []tmpl.Tile{}
+
+	for _, summary := range summaries {
+		host := summary.Host()
+
+		for _, t := range host.Tags() {
+			if !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+
+		if tag == "" || host.HasTag(tag) {
+			tiles = append(tiles, tmpl.NewTile(&summary, now))
+		}
+	}
+
+	sort.Strings(tags)
+	sortTiles(tiles, sortKey)
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.HostsPageTmpl{
+		Tiles:   tiles,
+		Tags:    tags,
+		Tag:     tag,
+		Sort:    sortKey,
+		Error:   formErr,
+		IsAdmin: user.IsAdmin(),
+	})
+}
+
+func (h *HostsPage) renderHostPage(w http.ResponseWriter, r *http.Request, user *users.User, status int, formErr string) {
+	host, err := h.hosts.GetByID(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if errors.Is(err, errs.ErrNotFound) {
+		http.Redirect(w, r, "/web/hosts", http.StatusFound)
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the host: %w", err))
+		return
+	}
+
+	h.writeHostPage(w, r, user, host, status, formErr)
+}
+
+func (h *HostsPage) writeHostPage(w http.ResponseWriter, r *http.Request, user *users.User, host *hosts.Host, status int, formErr string) {
+	stats, err := h.hosts.GetLatestStats(r.Context(), host)
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the stats: %w", err))
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.HostPageTmpl{
+		Host:    host,
+		Stats:   stats,
+		BaseURL: baseURL(r),
+		Error:   formErr,
+		IsAdmin: user.IsAdmin(),
+	})
+}
+
+// sortTiles sorts by name or by the given value, the highest first. The
+// unknown values are at the end.
+func sortTiles(tiles []tmpl.Tile, key string) {
+	value := func(t tmpl.Tile) *int {
+		switch key {
+		case "cpu":
+			return t.CPU
+		case "memory":
+			return t.Memory
+		case "disk":
+			return t.Disk
+		case "alerts":
+			return &t.Alerts
+		default:
+			return nil
+		}
+	}
+
+	sort.SliceStable(tiles, func(i, j int) bool {
+		a, b := tiles[i], tiles[j]
+
+		if key == "last-seen" && !a.LastSeenAt().Equal(b.LastSeenAt()) {
+			return a.LastSeenAt().After(b.LastSeenAt())
+		}
+
+		va, vb := value(a), value(b)
+		switch {
+		case va != nil && vb != nil && *va != *vb:
+			return *va > *vb
+		case va != nil && vb == nil:
+			return true
+		case va == nil && vb != nil:
+			return false
+		}
+
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+// fleetProducer sends the tiles which changed since the previous check.
+type fleetProducer struct {
+	hosts hosts.Service
+	clock clock.Clock
+	// prev contains the last payload of each tile, by host id.
+	prev map[string][]byte
+}
+
+// Watch notifies on each host change and at least every refreshInterval,
+// so the hosts are seen stale even without any push.
+func (p *fleetProducer) Watch(ctx context.Context) chan struct{} {
+	eventCh := p.hosts.Watch(ctx)
+	c := make(chan struct{}, 1)
+
+	go func() {
+		defer close(c)
+
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case _, ok := <-eventCh:
+				if !ok {
+					return
+				}
+			case <-ticker.C:
+			}
+
+			select {
+			case c <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return c
+}
+
+func (p *fleetProducer) Next(ctx context.Context) (*sse.Event, []sse.Event, error) {
+	summaries, err := p.hosts.GetSummaries(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the summaries: %w", err)
+	}
+
+	now := p.clock.Now()
+	tiles := make([]tmpl.Tile, len(summaries))
+	current := make(map[string][]byte, len(summaries))
+	deltas := []sse.Event{}
+
+	for i, summary := range summaries {
+		tiles[i] = tmpl.NewTile(&summary, now)
+
+		rawTile, err := json.Marshal(tiles[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal the tile: %w", err)
+		}
+
+		current[tiles[i].ID] = rawTile
+
+		prevTile, ok := p.prev[tiles[i].ID]
+		if ok && !bytes.Equal(prevTile, rawTile) {
+			deltas = append(deltas, sse.Event{Name: "HostTile", Data: rawTile})
+		}
+	}
+
+	rawTiles, err := json.Marshal(tiles)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal the tiles: %w", err)
+	}
+
+	isFleetChanged := len(p.prev) != len(current)
+	for id := range current {
+		if _, ok := p.prev[id]; !ok {
+			isFleetChanged = true
+		}
+	}
+
+	p.prev = current
+
+	if isFleetChanged {
+		deltas = []sse.Event{{Name: "FleetChanged", Data: []byte("{}")}}
+	}
+
+	return &sse.Event{Name: "HostTiles", Data: rawTiles}, deltas, nil
+}
+
+// baseURL returns the url used by the client to reach the server.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/jobs"
+	"github.com/go-chi/chi/v5"
+)
+
+type JobsPage struct {
+	html      html.Writer
+	auth      *auth.Authenticator
+	scheduler *scheduler.Scheduler
+}
+
+func NewJobsPage(
+	html html.Writer,
+	auth *auth.Authenticator,
+	scheduler *scheduler.Scheduler,
+) *JobsPage {
+	return &JobsPage{
+		html:      html,
+		auth:      auth,
+		scheduler: scheduler,
+	}
+}
+
+func (h *JobsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/jobs", h.printPage)
+	r.Post("/web/jobs/{name}/run", h.runJob)
+}
+
+func (h *JobsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, http.StatusOK, &tmpl.JobsPageTmpl{
+		Jobs:  h.scheduler.Jobs(),
+		Error: "",
+	})
+}
+
+func (h *JobsPage) runJob(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.scheduler.Trigger(chi.URLParam(r, "name"))
+	if errors.Is(err, scheduler.ErrJobRunning) {
+		h.html.WriteHTMLTemplate(w, r, http.StatusConflict, &tmpl.JobsPageTmpl{
+			Jobs:  h.scheduler.Jobs(),
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to trigger the job: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/jobs", http.StatusFound)
+}
@@ -0,0 +1,228 @@
+package logs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/logs"
+	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/sse"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/logs"
+	"github.com/go-chi/chi/v5"
+)
+
+// pageLimit is the number of entries displayed before the live tail.
+const pageLimit = 500
+
+// dateTimeLocal is the format of the "datetime-local" inputs.
+const dateTimeLocal = "2006-01-02T15:04"
+
+type LogsPage struct {
+	html   html.Writer
+	auth   *auth.Authenticator
+	logs   logs.Service
+	logger *slog.Logger
+	conns  *sse.Connections
+}
+
+func NewLogsPage(
+	html html.Writer,
+	tools tools.Tools,
+	auth *auth.Authenticator,
+	logs logs.Service,
+) *LogsPage {
+	return &LogsPage{
+		html:   html,
+		auth:   auth,
+		logs:   logs,
+		logger: tools.Logger().With(slog.String("source", "logs-sse")),
+		conns:  sse.NewConnections(),
+	}
+}
+
+func (h *LogsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/logs", h.printPage)
+	r.Get("/web/logs/sse", h.sse)
+}
+
+func (h *LogsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	form := tmpl.FilterForm{
+		Unit:     r.URL.Query().Get("unit"),
+		Priority: r.URL.Query().Get("priority"),
+		Since:    r.URL.Query().Get("since"),
+		Until:    r.URL.Query().Get("until"),
+		Search:   r.URL.Query().Get("q"),
+	}
+
+	page := &tmpl.LogsPageTmpl{
+		Entries:    []logs.Entry{},
+		Priorities: logs.AllPriorities,
+		Form:       form,
+		Source:     h.logs.SourceName(),
+	}
+
+	filter, err := parseFilter(&form)
+	if err != nil {
+		page.Error = err.Error()
+		h.html.WriteHTMLTemplate(w, r, http.StatusUnprocessableEntity, page)
+		return
+	}
+
+	page.Entries, err = h.logs.Search(r.Context(), filter, pageLimit)
+	if errors.Is(err, errs.ErrValidation) {
+		page.Entries = []logs.Entry{}
+		page.Error = err.Error()
+		h.html.WriteHTMLTemplate(w, r, http.StatusUnprocessableEntity, page)
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to search the logs: %w", err))
+		return
+	}
+
+	// There is nothing to tail for a time range in the past.
+	if filter.Until.IsZero() {
+		page.TailURL = "/web/logs/sse?" + url.Values{
+			"unit":     {form.Unit},
+			"priority": {form.Priority},
+			"q":        {form.Search},
+		}.Encode()
+	}
+
+	h.html.WriteHTMLTemplate(w, r, http.StatusOK, page)
+}
+
+func (h *LogsPage) sse(w http.ResponseWriter, r *http.Request) {
+	type logEntry struct {
+		Time         string `json:"time"`
+		Unit         string `json:"unit"`
+		PriorityName string `json:"priorityName"`
+		Message      string `json:"message"`
+		Priority     int    `json:"priority"`
+	}
+
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	filter, err := parseFilter(&tmpl.FilterForm{
+		Unit:     r.URL.Query().Get("unit"),
+		Priority: r.URL.Query().Get("priority"),
+		Search:   r.URL.Query().Get("q"),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	conn, ok := h.conns.Open(r)
+	if !ok {
+		sse.WriteUnavailable(w)
+		return
+	}
+	defer conn.Close(w)
+
+	entryCh, err := h.logs.Tail(conn.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to tail the logs", slog.String("error", err.Error()))
+		http.Error(w, "failed to tail the logs", http.StatusInternalServerError)
+		return
+	}
+
+	sse.WriteHeaders(w)
+
+	for {
+		var entry logs.Entry
+		var ok bool
+
+		select {
+		case entry, ok = <-entryCh:
+			if !ok {
+				return
+			}
+		case <-conn.Done():
+			return
+		}
+
+		unit := entry.Unit()
+		if unit == "" {
+			unit = entry.Ident()
+		}
+
+		rawData, err := json.Marshal(&logEntry{
+			Time:         entry.At().Local().Format(time.DateTime),
+			Unit:         unit,
+			PriorityName: entry.Priority().String(),
+			Message:      entry.Message(),
+			Priority:     int(entry.Priority()),
+		})
+		if err != nil {
+			h.logger.Error("failed to marshal the log entry", slog.String("error", err.Error()))
+			continue
+		}
+
+		fmt.Fprintf(w, "event: LogEntry\ndata: %s\n\n", rawData)
+		w.(http.Flusher).Flush()
+	}
+}
+
+func (h *LogsPage) CloseOpenConnections() {
+	h.logger.Info("close open connections")
+	h.conns.CloseAll()
+}
+
+func parseFilter(form *tmpl.FilterForm) (*logs.Filter, error) {
+	res := logs.Filter{
+		Unit:   form.Unit,
+		Search: form.Search,
+	}
+
+	if form.Priority != "" {
+		priority, err := logs.ParsePriority(form.Priority)
+		if err != nil {
+			return nil, fmt.Errorf("priority: %w", err)
+		}
+		res.Priority = &priority
+	}
+
+	for _, field := range []struct {
+		dst  *time.Time
+		name string
+		raw  string
+	}{
+		{name: "from", raw: form.Since, dst: &res.Since},
+		{name: "to", raw: form.Until, dst: &res.Until},
+	} {
+		if field.raw == "" {
+			continue
+		}
+
+		t, err := time.ParseInLocation(dateTimeLocal, field.raw, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid date", field.name)
+		}
+		*field.dst = t
+	}
+
+	return &res, nil
+}
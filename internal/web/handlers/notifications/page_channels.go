@@ -0,0 +1,159 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Peltoche/zapette/internal/service/notifications"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/notifications"
+	"github.com/go-chi/chi/v5"
+)
+
+type ChannelsPage struct {
+	html          html.Writer
+	auth          *auth.Authenticator
+	notifications notifications.Service
+}
+
+func NewChannelsPage(
+	html html.Writer,
+	auth *auth.Authenticator,
+	notifications notifications.Service,
+) *ChannelsPage {
+	return &ChannelsPage{
+		html:          html,
+		auth:          auth,
+		notifications: notifications,
+	}
+}
+
+func (h *ChannelsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/notifications", h.printPage)
+	r.Post("/web/notifications", h.createChannel)
+	r.Post("/web/notifications/{id}/delete", h.deleteChannel)
+	r.Post("/web/notifications/{id}/test", h.sendTest)
+}
+
+func (h *ChannelsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, http.StatusOK, "")
+}
+
+func (h *ChannelsPage) createChannel(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	port, _ := strconv.Atoi(r.FormValue("port"))
+	priority, _ := strconv.Atoi(r.FormValue("priority"))
+
+	var to []string
+	for _, addr := range strings.Split(r.FormValue("to"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+
+	kind := notifications.Kind(r.FormValue("kind"))
+
+	settings := notifications.Settings{}
+	switch kind {
+	case notifications.SMTP:
+		settings.Host = r.FormValue("host")
+		settings.Port = port
+		settings.Username = r.FormValue("username")
+		settings.From = r.FormValue("from")
+		settings.To = to
+	default:
+		settings.URL = r.FormValue("url")
+		settings.Priority = priority
+	}
+
+	_, err := h.notifications.Create(r.Context(), &notifications.CreateCmd{
+		CreatedBy: user,
+		Name:      r.FormValue("name"),
+		Kind:      kind,
+		Secret:    secret.NewText(r.FormValue("secret")),
+		Settings:  settings,
+	})
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to create the channel: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/notifications", http.StatusFound)
+}
+
+func (h *ChannelsPage) deleteChannel(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.notifications.Delete(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to delete the channel: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/notifications", http.StatusFound)
+}
+
+func (h *ChannelsPage) sendTest(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	channel, err := h.notifications.GetByID(r.Context(), uuid.UUID(chi.URLParam(r, "id")))
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the channel: %w", err))
+		return
+	}
+
+	res := tmpl.TestResultTmpl{Channel: channel}
+
+	err = h.notifications.SendTest(r.Context(), channel)
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	h.html.WriteHTMLTemplate(w, r, http.StatusOK, &res)
+}
+
+func (h *ChannelsPage) renderPage(w http.ResponseWriter, r *http.Request, status int, formErr string) {
+	channels, err := h.notifications.GetAll(r.Context(), nil)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the channels: %w", err))
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.ChannelsPageTmpl{
+		Channels: channels,
+		Kinds:    notifications.AllKinds,
+		Error:    formErr,
+	})
+}
@@ -1,15 +1,19 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
 
+	"github.com/Peltoche/zapette/internal/service/forecasts"
 	"github.com/Peltoche/zapette/internal/service/sysinfos"
 	"github.com/Peltoche/zapette/internal/service/sysstats"
 	"github.com/Peltoche/zapette/internal/tools"
 	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/sse"
 	"github.com/Peltoche/zapette/internal/web/handlers/auth"
 	"github.com/Peltoche/zapette/internal/web/html"
 	"github.com/Peltoche/zapette/internal/web/html/templates/server"
@@ -17,12 +21,14 @@ import (
 )
 
 type DetailsPage struct {
-	html     html.Writer
-	auth     *auth.Authenticator
-	sysstats sysstats.Service
-	sysinfos sysinfos.Service
-	logger   *slog.Logger
-	closeCh  chan struct{}
+	html      html.Writer
+	auth      *auth.Authenticator
+	sysstats  sysstats.Service
+	sysinfos  sysinfos.Service
+	forecasts forecasts.Service
+	hub       *sse.Hub
+	logger    *slog.Logger
+	conns     *sse.Connections
 }
 
 func NewDetailsPage(
@@ -31,14 +37,17 @@ func NewDetailsPage(
 	auth *auth.Authenticator,
 	sysinfos sysinfos.Service,
 	sysstats sysstats.Service,
+	forecasts forecasts.Service,
 ) *DetailsPage {
 	return &DetailsPage{
-		html:     html,
-		sysstats: sysstats,
-		sysinfos: sysinfos,
-		auth:     auth,
-		logger:   tools.Logger().With(slog.String("source", "server-details-sse")),
-		closeCh:  make(chan struct{}, 1),
+		html:      html,
+		sysstats:  sysstats,
+		sysinfos:  sysinfos,
+		forecasts: forecasts,
+		auth:      auth,
+		hub:       sse.NewHub("server-details", tools, &latestStatProducer{sysstats: sysstats, prev: nil}),
+		logger:    tools.Logger().With(slog.String("source", "server-details-sse")),
+		conns:     sse.NewConnections(),
 	}
 }
 
@@ -63,63 +72,79 @@ func (h *DetailsPage) printServerPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	forecastList, err := h.forecasts.GetAll(r.Context())
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the forecasts: %w", err))
+		return
+	}
+
+	cgroups, err := h.sysstats.GetLatestCGroups(r.Context())
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the cgroups: %w", err))
+		return
+	}
+
 	h.html.WriteHTMLTemplate(w, r, http.StatusOK, &server.DetailsPageTmpl{
-		Stats:    latest,
-		SysInfos: h.sysinfos.GetInfos(r.Context()),
+		Stats:     latest,
+		SysInfos:  h.sysinfos.GetInfos(r.Context()),
+		Forecasts: forecastList,
+		CGroups:   cgroups,
 	})
 }
 
 func (h *DetailsPage) sse(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.hub.Stream(w, r, h.conns)
+}
+
+func (h *DetailsPage) CloseOpenConnections() {
+	h.logger.Info("close open connections")
+	h.conns.CloseAll()
+}
+
+// latestStatProducer sends the latest stat only when the displayed values
+// change.
+type latestStatProducer struct {
+	sysstats sysstats.Service
+	prev     []byte
+}
+
+func (p *latestStatProducer) Watch(ctx context.Context) chan struct{} {
+	return p.sysstats.Watch(ctx)
+}
+
+func (p *latestStatProducer) Next(ctx context.Context) (*sse.Event, []sse.Event, error) {
 	type refreshPage struct {
 		PercentageUsedMemory      int    `json:"percentageUsedMemory"`
 		PercentageAvailableMemory int    `json:"percentageAvailableMemory"`
 		TotalMemory               string `json:"totalMemory"`
 	}
 
-	ctx := r.Context()
+	latest, err := p.sysstats.GetLatest(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the latest stat: %w", err)
+	}
 
-	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
-	if abort {
-		return
+	rawData, err := json.Marshal(&refreshPage{
+		PercentageUsedMemory:      latest.Memory().PercentageUsedMemory(),
+		PercentageAvailableMemory: latest.Memory().PercentageAvailableMemory(),
+		TotalMemory:               latest.Memory().TotalMemory().HR(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal the latest stat: %w", err)
 	}
 
-	// Set headers for SSE
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-
-	eventCh := h.sysstats.Watch(ctx)
-
-	for {
-		select {
-		case <-eventCh:
-			// continue
-		case <-h.closeCh:
-			return
-		}
-
-		latest, err := h.sysstats.GetLatest(ctx)
-		if err != nil {
-			h.logger.Error("failed to get the latest stat", slog.String("error", err.Error()))
-			return
-		}
-
-		rawData, err := json.Marshal(&refreshPage{
-			PercentageUsedMemory:      latest.Memory().PercentageUsedMemory(),
-			PercentageAvailableMemory: latest.Memory().PercentageAvailableMemory(),
-			TotalMemory:               latest.Memory().TotalMemory().HR(),
-		})
-		if err != nil {
-			h.logger.Error("failed to marshal the latest stat", slog.String("error", err.Error()))
-			continue
-		}
-
-		fmt.Fprintf(w, "event: LatestStat\ndata: %s\n\n", rawData)
-		w.(http.Flusher).Flush()
+	snapshot := &sse.Event{Name: "LatestStat", Data: rawData}
+
+	if bytes.Equal(p.prev, rawData) {
+		return snapshot, []sse.Event{}, nil
 	}
-}
 
-func (h *DetailsPage) CloseOpenConnections() {
-	h.logger.Info("close open connections")
-	close(h.closeCh)
+	p.prev = rawData
+
+	return snapshot, []sse.Event{*snapshot}, nil
 }
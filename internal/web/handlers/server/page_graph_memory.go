@@ -3,27 +3,65 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
 	"time"
 
+	"github.com/Peltoche/zapette/internal/service/anomalies"
+	"github.com/Peltoche/zapette/internal/service/config"
 	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/service/users"
 	"github.com/Peltoche/zapette/internal/tools"
+	"github.com/Peltoche/zapette/internal/tools/clock"
+	"github.com/Peltoche/zapette/internal/tools/errs"
 	"github.com/Peltoche/zapette/internal/tools/ptr"
 	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/tools/sse"
 	"github.com/Peltoche/zapette/internal/web/handlers/auth"
 	"github.com/Peltoche/zapette/internal/web/html"
 	"github.com/Peltoche/zapette/internal/web/html/templates/server"
 	"github.com/go-chi/chi/v5"
 )
 
+// dateTimeLocal is the format of the "datetime-local" inputs with the
+// seconds. The inputs without the seconds are accepted too.
+const dateTimeLocal = "2006-01-02T15:04:05"
+
+// maxImportSize is the max size of an imported file.
+const maxImportSize = 64 * 1024 * 1024
+
+type graphRange struct {
+	Name string
+	Span time.Duration
+}
+
+// graphRanges are the relative ranges of the graph, ending now and updated
+// live. The first one is the default.
+var graphRanges = []graphRange{
+	{Name: "5m", Span: 5 * time.Minute},
+	{Name: "15m", Span: 15 * time.Minute},
+	{Name: "1h", Span: time.Hour},
+	{Name: "6h", Span: 6 * time.Hour},
+	{Name: "24h", Span: 24 * time.Hour},
+	{Name: "7d", Span: 7 * 24 * time.Hour},
+}
+
 type MemoryGraphPage struct {
-	html     html.Writer
-	auth     *auth.Authenticator
-	sysstats sysstats.Service
-	logger   *slog.Logger
-	closeCh  chan struct{}
+	html      html.Writer
+	auth      *auth.Authenticator
+	sysstats  sysstats.Service
+	anomalies anomalies.Service
+	config    config.Service
+	clock     clock.Clock
+	// hubs contains a hub per relative range, by name.
+	hubs   map[string]*sse.Hub
+	logger *slog.Logger
+	conns  *sse.Connections
 }
 
 func NewMemoryGraphPage(
@@ -31,14 +69,26 @@ func NewMemoryGraphPage(
 	tools tools.Tools,
 	auth *auth.Authenticator,
 	sysstats sysstats.Service,
+	anomalies anomalies.Service,
+	config config.Service,
 ) *MemoryGraphPage {
-	return &MemoryGraphPage{
-		html:     html,
-		sysstats: sysstats,
-		auth:     auth,
-		logger:   tools.Logger().With(slog.String("source", "server-memory-graph-sse")),
-		closeCh:  make(chan struct{}, 1),
+	h := &MemoryGraphPage{
+		html:      html,
+		sysstats:  sysstats,
+		anomalies: anomalies,
+		config:    config,
+		auth:      auth,
+		clock:     tools.Clock(),
+		hubs:      make(map[string]*sse.Hub, len(graphRanges)),
+		logger:    tools.Logger().With(slog.String("source", "server-memory-graph-sse")),
+		conns:     sse.NewConnections(),
 	}
+
+	for _, gr := range graphRanges {
+		h.hubs[gr.Name] = sse.NewHub("server-memory-graph-"+gr.Name, tools, newMemoryGraphProducer(h, gr.Span))
+	}
+
+	return h
 }
 
 func (h *MemoryGraphPage) Register(r chi.Router, mids *router.Middlewares) {
@@ -48,25 +98,166 @@ func (h *MemoryGraphPage) Register(r chi.Router, mids *router.Middlewares) {
 
 	r.Get("/web/server/memory/details", h.printMemoryGraphPage)
 	r.Get("/web/server/memory/details/sse", h.sse)
+	r.Get("/web/server/memory/export", h.export)
+	r.Post("/web/server/memory/import", h.importStats)
 }
 
 func (h *MemoryGraphPage) printMemoryGraphPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, user, r.URL.Query(), http.StatusOK, "", "")
+}
+
+// export downloads the stats of the range asked by the query, all of them
+// and not only the ones displayed on the graph.
+func (h *MemoryGraphPage) export(w http.ResponseWriter, r *http.Request) {
 	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
 	if abort {
 		return
 	}
 
-	stats, err := h.sysstats.GetStatsForGraph(r.Context(), &sysstats.FiveMnGraph)
+	format, err := sysstats.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, "format: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	interval, err := h.config.GetDuration(r.Context(), config.CollectionInterval)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the collection interval: %w", err))
+		return
+	}
+
+	graph, _, err := parseGraph(r.URL.Query(), h.clock.Now(), interval)
 	if err != nil {
-		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the latest 5mn stats: %w", err))
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		return
 	}
 
-	graphData := statsToMemoryGraphData(stats)
+	start, end := graph.Range(h.clock.Now())
 
-	h.html.WriteHTMLTemplate(w, r, http.StatusOK, &server.SysstatsPageTmpl{
-		GraphData: graphData,
-	})
+	stats, err := h.sysstats.GetRange(r.Context(), start, end)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the stats: %w", err))
+		return
+	}
+
+	fileName := fmt.Sprintf("zapette-stats-%s-%s.%s",
+		start.Local().Format("20060102T150405"), end.Local().Format("20060102T150405"), format)
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+
+	err = sysstats.Encode(w, format, stats)
+	if err != nil {
+		h.logger.Error("failed to export the stats", slog.String("error", err.Error()))
+	}
+}
+
+// importStats loads a file written by export. The format is given by the
+// file extension.
+func (h *MemoryGraphPage) importStats(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.renderPage(w, r, user, url.Values{}, http.StatusUnprocessableEntity, "", "file: missing or too large")
+		return
+	}
+	defer file.Close()
+
+	format, err := sysstats.ParseFormat(strings.TrimPrefix(path.Ext(header.Filename), "."))
+	if err != nil {
+		h.renderPage(w, r, user, url.Values{}, http.StatusUnprocessableEntity, "", "file: "+err.Error())
+		return
+	}
+
+	imported, err := h.sysstats.Import(r.Context(), file, format)
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, user, url.Values{}, http.StatusUnprocessableEntity, "", err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to import the stats: %w", err))
+		return
+	}
+
+	h.renderPage(w, r, user, url.Values{}, http.StatusOK, fmt.Sprintf("%d stats imported.", imported), "")
+}
+
+func (h *MemoryGraphPage) renderPage(w http.ResponseWriter, r *http.Request, user *users.User, query url.Values, status int, msg, formErr string) {
+	now := h.clock.Now()
+
+	// The ranges are given in the server time zone, like everywhere else.
+	// The browser time zone can differ.
+	zone, offset := now.Local().Zone()
+
+	page := &server.SysstatsPageTmpl{
+		Ranges:    make([]string, len(graphRanges)),
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+		TimeZone:  zone,
+		UTCOffset: offset,
+		Message:   msg,
+		Error:     formErr,
+		IsAdmin:   user.IsAdmin(),
+	}
+
+	for i, gr := range graphRanges {
+		page.Ranges[i] = gr.Name
+	}
+
+	interval, err := h.config.GetDuration(r.Context(), config.CollectionInterval)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the collection interval: %w", err))
+		return
+	}
+
+	graph, rangeName, parseErr := parseGraph(query, now, interval)
+	if parseErr != nil {
+		// Display the default graph with the error.
+		status = http.StatusUnprocessableEntity
+		page.Error = parseErr.Error()
+		graph, rangeName = sysstats.NewGraph(graphRanges[0].Span, interval), graphRanges[0].Name
+	}
+
+	page.GraphData, err = h.getGraphData(r.Context(), &graph)
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, err)
+		return
+	}
+
+	start, end := graph.Range(now)
+	page.Range = rangeName
+	page.Resolution = graph.TickSpan().String()
+
+	if graph.IsLive() {
+		page.SSEURL = "/web/server/memory/details/sse?range=" + rangeName
+		page.ExportQuery = url.Values{"range": {rangeName}}.Encode()
+	} else {
+		page.ZoomOutURL = zoomOutURL(start, end, now)
+		page.ExportQuery = url.Values{
+			"from": {start.Local().Format(dateTimeLocal)},
+			"to":   {end.Local().Format(dateTimeLocal)},
+		}.Encode()
+	}
+
+	// Keep the invalid values in the form.
+	if parseErr == nil {
+		page.From = start.Local().Format(dateTimeLocal)
+		page.To = end.Local().Format(dateTimeLocal)
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, page)
 }
 
 func (h *MemoryGraphPage) sse(w http.ResponseWriter, r *http.Request) {
@@ -75,56 +266,275 @@ func (h *MemoryGraphPage) sse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set headers for SSE
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
+	hub, ok := h.hubs[r.URL.Query().Get("range")]
+	if !ok {
+		http.Error(w, "range: unknown range", http.StatusUnprocessableEntity)
+		return
+	}
+
+	hub.Stream(w, r, h.conns)
+}
 
-	h.listenSysstatsEvents(r.Context(), w)
+func (h *MemoryGraphPage) CloseOpenConnections() {
+	h.logger.Warn("close open connections")
+	h.conns.CloseAll()
 }
 
-func (h *MemoryGraphPage) listenSysstatsEvents(ctx context.Context, w http.ResponseWriter) {
-	eventCh := h.sysstats.Watch(ctx)
+func (h *MemoryGraphPage) getGraphData(ctx context.Context, graph *sysstats.Graph) (*server.Graph, error) {
+	stats, err := h.sysstats.GetStatsForGraph(ctx, graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the graph stats: %w", err)
+	}
+
+	start, end := graph.Range(h.clock.Now())
 
-	// Send data to the client
-	for {
-		select {
-		case <-eventCh:
-			// continue
-		case <-h.closeCh:
-			return
+	scores, err := h.anomalies.GetMemoryScores(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the memory anomalies: %w", err)
+	}
+
+	labelFormat := time.TimeOnly
+	if graph.Span() > 24*time.Hour {
+		labelFormat = "01-02 15:04"
+	}
+
+	return statsToMemoryGraphData(stats, scores, labelFormat), nil
+}
+
+// parseGraph returns the graph asked by the query: an absolute range with
+// the "from" and "to" parameters or a relative one with "range". The name of
+// the relative range is empty for an absolute range. The graph has no tick
+// shorter than the collection interval.
+func parseGraph(query url.Values, now time.Time, interval time.Duration) (sysstats.Graph, string, error) {
+	rawFrom, rawTo := query.Get("from"), query.Get("to")
+
+	if rawFrom == "" && rawTo == "" {
+		name := query.Get("range")
+		if name == "" {
+			name = graphRanges[0].Name
 		}
 
-		stats, err := h.sysstats.GetStatsForGraph(ctx, &sysstats.FiveMnGraph)
-		if err != nil {
-			h.logger.Error("failed to get the 5mn stats", slog.String("error", err.Error()))
-			return
+		for _, gr := range graphRanges {
+			if gr.Name == name {
+				return sysstats.NewGraph(gr.Span, interval), gr.Name, nil
+			}
 		}
 
-		graphData := statsToMemoryGraphData(stats)
+		return sysstats.Graph{}, "", errors.New("range: unknown range")
+	}
+
+	from, err := parseDateTime(rawFrom)
+	if err != nil {
+		return sysstats.Graph{}, "", fmt.Errorf("from: %w", err)
+	}
+
+	to, err := parseDateTime(rawTo)
+	if err != nil {
+		return sysstats.Graph{}, "", fmt.Errorf("to: %w", err)
+	}
+
+	switch {
+	case to.Sub(from) < sysstats.MinGraphSpan:
+		return sysstats.Graph{}, "", fmt.Errorf("to: must be at least %s after from", sysstats.MinGraphSpan)
+	case to.Sub(from) > sysstats.MaxGraphSpan:
+		return sysstats.Graph{}, "", fmt.Errorf("to: must be at most %d days after from", sysstats.MaxGraphSpan/(24*time.Hour))
+	case from.After(now):
+		return sysstats.Graph{}, "", errors.New("from: must be in the past")
+	}
+
+	return sysstats.NewHistoryGraph(from, to, interval), "", nil
+}
+
+func parseDateTime(raw string) (time.Time, error) {
+	for _, layout := range []string{dateTimeLocal, "2006-01-02T15:04"} {
+		res, err := time.ParseInLocation(layout, raw, time.Local)
+		if err == nil {
+			return res, nil
+		}
+	}
+
+	return time.Time{}, errors.New("invalid date")
+}
+
+// zoomOutURL returns the url of a graph twice larger than the given range
+// and centered on it, without going into the future nor over
+// sysstats.MaxGraphSpan.
+func zoomOutURL(start, end, now time.Time) string {
+	span := end.Sub(start)
+	added := max(min(span, sysstats.MaxGraphSpan-span), 0)
+
+	start = start.Add(-added / 2)
+	end = end.Add(added / 2)
+
+	if end.After(now) {
+		start = start.Add(-end.Sub(now))
+		end = now
+	}
+
+	return "/web/server/memory/details?" + url.Values{
+		"from": {start.Local().Format(dateTimeLocal)},
+		"to":   {end.Local().Format(dateTimeLocal)},
+	}.Encode()
+}
+
+// graphPoints are the points appended to a graph, the Evict oldest ones
+// being removed.
+type graphPoints struct {
+	Evict  int       `json:"evict"`
+	Labels []*string `json:"labels"`
+	Times  []*int64  `json:"times"`
+	// Data contains the new values of each dataset.
+	Data [][]*float64 `json:"data"`
+}
+
+// memoryGraphProducer computes the graph once for all the clients and only
+// sends the new points. The graph is built again once per tick, a refresh
+// during the same tick reuses the previous one. The graph is rebuilt in
+// order to follow the changes of the collection interval.
+type memoryGraphProducer struct {
+	page         *MemoryGraphPage
+	span         time.Duration
+	prev         *server.Graph
+	prevSnapshot *sse.Event
+	builtAt      time.Time
+}
+
+func newMemoryGraphProducer(page *MemoryGraphPage, span time.Duration) *memoryGraphProducer {
+	return &memoryGraphProducer{
+		page:         page,
+		span:         span,
+		prev:         nil,
+		prevSnapshot: nil,
+		builtAt:      time.Time{},
+	}
+}
+
+func (p *memoryGraphProducer) Watch(ctx context.Context) chan struct{} {
+	return p.page.sysstats.Watch(ctx)
+}
+
+func (p *memoryGraphProducer) Next(ctx context.Context) (*sse.Event, []sse.Event, error) {
+	interval, err := p.page.config.GetDuration(ctx, config.CollectionInterval)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the collection interval: %w", err)
+	}
+
+	graph := sysstats.NewGraph(p.span, interval)
+
+	now := p.page.clock.Now()
+	if p.prev != nil && now.Sub(p.builtAt) < graph.TickSpan() {
+		return p.prevSnapshot, []sse.Event{}, nil
+	}
+
+	graphData, err := p.page.getGraphData(ctx, &graph)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the graph data: %w", err)
+	}
+
+	rawGraph, err := json.Marshal(graphData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal the graph data: %w", err)
+	}
+
+	snapshot := &sse.Event{Name: "RefreshGraph", Data: rawGraph}
+
+	prev := p.prev
+	p.prev = graphData
+	p.prevSnapshot = snapshot
+	p.builtAt = now
+
+	if prev == nil {
+		return snapshot, []sse.Event{*snapshot}, nil
+	}
+
+	points, ok := graphDelta(prev, graphData)
+	switch {
+	case !ok:
+		return snapshot, []sse.Event{*snapshot}, nil
+	case points.Evict == 0:
+		return snapshot, []sse.Event{}, nil
+	}
+
+	rawPoints, err := json.Marshal(points)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal the graph points: %w", err)
+	}
+
+	return snapshot, []sse.Event{{Name: "GraphPoints", Data: rawPoints}}, nil
+}
 
-		rawData, err := json.Marshal(graphData)
-		if err != nil {
-			h.logger.Error("failed to marshal the graph data", slog.String("error", err.Error()))
+// graphDelta returns the points to append to prev in order to obtain next.
+// It returns false if next is not a shifted version of prev, in which case
+// the whole graph must be sent.
+func graphDelta(prev, next *server.Graph) (*graphPoints, bool) {
+	size := len(next.Times)
+	if len(prev.Times) != size || len(prev.Data.Datasets) != len(next.Data.Datasets) {
+		return nil, false
+	}
+
+	for shift := 0; shift < size; shift++ {
+		if !isShiftedGraph(prev, next, shift) {
 			continue
 		}
 
-		fmt.Fprintf(w, "event: RefreshGraph\ndata: %s\n\n", rawData)
-		w.(http.Flusher).Flush()
+		points := graphPoints{
+			Evict:  shift,
+			Labels: next.Data.Labels[size-shift:],
+			Times:  next.Times[size-shift:],
+			Data:   make([][]*float64, len(next.Data.Datasets)),
+		}
+
+		for i, dataset := range next.Data.Datasets {
+			points.Data[i] = dataset.Data[size-shift:]
+		}
+
+		return &points, true
 	}
+
+	return nil, false
 }
 
-func (h *MemoryGraphPage) CloseOpenConnections() {
-	h.logger.Warn("close open connections")
-	close(h.closeCh)
+// isShiftedGraph returns true if the first points of next are the points of
+// prev shifted by shift.
+func isShiftedGraph(prev, next *server.Graph, shift int) bool {
+	for i := 0; i+shift < len(prev.Times); i++ {
+		if !equalPtr(prev.Times[i+shift], next.Times[i]) ||
+			!equalPtr(prev.Data.Labels[i+shift], next.Data.Labels[i]) {
+			return false
+		}
+
+		for d := range prev.Data.Datasets {
+			if !equalPtr(prev.Data.Datasets[d].Data[i+shift], next.Data.Datasets[d].Data[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
 }
 
-func statsToMemoryGraphData(stats []sysstats.Stats) *server.Graph {
+func statsToMemoryGraphData(stats []sysstats.Stats, scores []anomalies.ScoredPoint, labelFormat string) *server.Graph {
 	memoryTotal := make([]*float64, len(stats))
 	memoryUsed := make([]*float64, len(stats))
 	swapUsed := make([]*float64, len(stats))
 	cacheBuffer := make([]*float64, len(stats))
+	baseline := make([]*float64, len(stats))
+	anomalyPoints := make([]*float64, len(stats))
 	labels := make([]*string, len(stats))
+	times := make([]*int64, len(stats))
+
+	scoresByTime := make(map[int64]anomalies.ScoredPoint, len(scores))
+	for _, score := range scores {
+		scoresByTime[score.At().Unix()] = score
+	}
 
 	for i, stat := range stats {
 		if stat.IsEmpty() {
@@ -132,11 +542,25 @@ func statsToMemoryGraphData(stats []sysstats.Stats) *server.Graph {
 			continue
 		}
 
-		labels[i] = ptr.To(stat.Time().Format(time.TimeOnly))
+		labels[i] = ptr.To(stat.Time().Local().Format(labelFormat))
+		times[i] = ptr.To(stat.Time().Unix())
 		memoryUsed[i] = ptr.To(stat.Memory().UsedMemory().GBytes())
 		memoryTotal[i] = ptr.To(stat.Memory().TotalMemory().GBytes())
 		swapUsed[i] = ptr.To(stat.Memory().UsedSwap().GBytes())
 		cacheBuffer[i] = ptr.To(stat.Memory().BufCache().GBytes())
+
+		score, ok := scoresByTime[stat.Time().Unix()]
+		if !ok {
+			continue
+		}
+
+		if expected := score.Baseline(); expected != nil {
+			baseline[i] = ptr.To(expected.GBytes())
+		}
+
+		if score.IsAnomaly() {
+			anomalyPoints[i] = memoryUsed[i]
+		}
 	}
 
 	return &server.Graph{
@@ -177,7 +601,26 @@ func statsToMemoryGraphData(stats []sysstats.Stats) *server.Graph {
 					BorderWidth: 2,
 					PointRadius: 0,
 				},
+				{
+					Label:       "Baseline",
+					Data:        baseline,
+					ShowLine:    true,
+					BorderColor: "grey",
+					BorderDash:  []int{5, 5},
+					BorderWidth: 1,
+					PointRadius: 0,
+				},
+				{
+					Label:           "Anomalies",
+					Data:            anomalyPoints,
+					ShowLine:        false,
+					BorderColor:     "red",
+					BackgroundColor: "red",
+					BorderWidth:     1,
+					PointRadius:     4,
+				},
 			},
 		},
+		Times: times,
 	}
 }
@@ -0,0 +1,104 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Peltoche/zapette/internal/service/config"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/settings"
+	"github.com/go-chi/chi/v5"
+)
+
+type SettingsPage struct {
+	html   html.Writer
+	auth   *auth.Authenticator
+	config config.Service
+}
+
+func NewSettingsPage(
+	html html.Writer,
+	auth *auth.Authenticator,
+	config config.Service,
+) *SettingsPage {
+	return &SettingsPage{
+		html:   html,
+		auth:   auth,
+		config: config,
+	}
+}
+
+func (h *SettingsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/settings", h.printPage)
+	r.Post("/web/settings/{key}", h.updateSetting)
+	r.Post("/web/settings/{key}/reset", h.resetSetting)
+}
+
+func (h *SettingsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, http.StatusOK, "", "")
+}
+
+func (h *SettingsPage) updateSetting(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	key := config.ConfigKey(chi.URLParam(r, "key"))
+
+	err := h.config.UpdateSetting(r.Context(), key, strings.TrimSpace(r.FormValue("value")))
+	if errors.Is(err, errs.ErrValidation) {
+		h.renderPage(w, r, http.StatusUnprocessableEntity, key, err.Error())
+		return
+	}
+
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to update the setting: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/settings", http.StatusFound)
+}
+
+func (h *SettingsPage) resetSetting(w http.ResponseWriter, r *http.Request) {
+	_, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.config.ResetSetting(r.Context(), config.ConfigKey(chi.URLParam(r, "key")))
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to reset the setting: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/settings", http.StatusFound)
+}
+
+func (h *SettingsPage) renderPage(w http.ResponseWriter, r *http.Request, status int, errorKey config.ConfigKey, formErr string) {
+	settings, err := h.config.GetSettings(r.Context())
+	if err != nil {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the settings: %w", err))
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.SettingsPageTmpl{
+		Settings: settings,
+		ErrorKey: errorKey,
+		Error:    formErr,
+	})
+}
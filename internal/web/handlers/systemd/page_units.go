@@ -0,0 +1,89 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Peltoche/zapette/internal/service/systemd"
+	"github.com/Peltoche/zapette/internal/service/users"
+	"github.com/Peltoche/zapette/internal/tools/errs"
+	"github.com/Peltoche/zapette/internal/tools/router"
+	"github.com/Peltoche/zapette/internal/web/handlers/auth"
+	"github.com/Peltoche/zapette/internal/web/html"
+	tmpl "github.com/Peltoche/zapette/internal/web/html/templates/systemd"
+	"github.com/go-chi/chi/v5"
+)
+
+type UnitsPage struct {
+	html    html.Writer
+	auth    *auth.Authenticator
+	systemd systemd.Service
+}
+
+func NewUnitsPage(
+	html html.Writer,
+	auth *auth.Authenticator,
+	systemd systemd.Service,
+) *UnitsPage {
+	return &UnitsPage{
+		html:    html,
+		auth:    auth,
+		systemd: systemd,
+	}
+}
+
+func (h *UnitsPage) Register(r chi.Router, mids *router.Middlewares) {
+	if mids != nil {
+		r = r.With(mids.Defaults()...)
+	}
+
+	r.Get("/web/systemd", h.printPage)
+	r.Post("/web/systemd/{name}/{action}", h.control)
+}
+
+func (h *UnitsPage) printPage(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AnyUser)
+	if abort {
+		return
+	}
+
+	h.renderPage(w, r, user, http.StatusOK, "")
+}
+
+func (h *UnitsPage) control(w http.ResponseWriter, r *http.Request) {
+	user, _, abort := h.auth.GetUserAndSession(w, r, auth.AdminOnly)
+	if abort {
+		return
+	}
+
+	err := h.systemd.Control(r.Context(), &systemd.ControlCmd{
+		Name:   chi.URLParam(r, "name"),
+		Action: systemd.Action(chi.URLParam(r, "action")),
+	})
+	switch {
+	case errors.Is(err, errs.ErrValidation), errors.Is(err, errs.ErrNotFound), errors.Is(err, systemd.ErrJobFailed):
+		h.renderPage(w, r, user, http.StatusUnprocessableEntity, err.Error())
+		return
+	case err != nil:
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to control the unit: %w", err))
+		return
+	}
+
+	http.Redirect(w, r, "/web/systemd", http.StatusFound)
+}
+
+func (h *UnitsPage) renderPage(w http.ResponseWriter, r *http.Request, user *users.User, status int, formErr string) {
+	units, err := h.systemd.GetUnits(r.Context())
+	if err != nil && !errors.Is(err, systemd.ErrUnavailable) {
+		h.html.WriteHTMLErrorPage(w, r, fmt.Errorf("failed to get the units: %w", err))
+		return
+	}
+
+	h.html.WriteHTMLTemplate(w, r, status, &tmpl.UnitsPageTmpl{
+		Units:       units,
+		Unavailable: errors.Is(err, systemd.ErrUnavailable),
+		Error:       formErr,
+		IsAdmin:     user.IsAdmin(),
+	})
+}
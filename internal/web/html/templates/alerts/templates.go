@@ -0,0 +1,26 @@
+package alerts
+
+import (
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/silences"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+type AlertsPageTmpl struct {
+	Now       time.Time
+	Usernames map[uuid.UUID]string
+	Alerts    []alerts.Alert
+	Silences  []silences.Silence
+	Windows   []silences.MaintenanceWindow
+	Weekdays  []time.Weekday
+	Error     string
+	// Matchers prefills the silence form, used by the "Silence" button of
+	// the alerts.
+	Matchers      string
+	AnomalyAlerts bool
+	IsAdmin       bool
+}
+
+func (t *AlertsPageTmpl) Template() string { return "alerts/page_alerts" }
@@ -0,0 +1,72 @@
+package alerts
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/alerts"
+	"github.com/Peltoche/zapette/internal/service/silences"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	silence := silences.NewFakeSilence(t).Build()
+	window := silences.NewFakeMaintenanceWindow(t).Build()
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "AlertsPageTmpl",
+			Layout: true,
+			Template: &AlertsPageTmpl{
+				Now: time.Now(),
+				Usernames: map[uuid.UUID]string{
+					silence.CreatedBy(): "some-username",
+				},
+				Alerts:        []alerts.Alert{*alerts.NewFakeAlert(t).Build()},
+				Silences:      []silences.Silence{*silence},
+				Windows:       []silences.MaintenanceWindow{*window},
+				Weekdays:      []time.Weekday{time.Monday, time.Sunday},
+				Error:         "some-error-msg",
+				Matchers:      "alertname=foo",
+				AnomalyAlerts: true,
+				IsAdmin:       true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
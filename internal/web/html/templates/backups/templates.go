@@ -0,0 +1,9 @@
+package backups
+
+import "github.com/Peltoche/zapette/internal/service/backups"
+
+type BackupsPageTmpl struct {
+	Backups []backups.Backup
+}
+
+func (t *BackupsPageTmpl) Template() string { return "backups/page_backups" }
@@ -0,0 +1,65 @@
+package backups
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/service/backups"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "BackupsPageTmpl",
+			Layout: true,
+			Template: &BackupsPageTmpl{
+				Backups: []backups.Backup{
+					*backups.NewFakeBackup(t).Build(),
+					*backups.NewFakeBackup(t).Manual().Build(),
+				},
+			},
+		},
+		{
+			Name:   "BackupsPageTmpl without backups",
+			Layout: true,
+			Template: &BackupsPageTmpl{
+				Backups: []backups.Backup{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package checks
+
+import (
+	"github.com/Peltoche/zapette/internal/service/checks"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+)
+
+type ChecksPageTmpl struct {
+	Checks []checks.Check
+	// Summaries contains the summary of the last 24h for each check.
+	Summaries map[uuid.UUID]checks.Summary
+	Kinds     []checks.Kind
+	Error     string
+	IsAdmin   bool
+}
+
+func (t *ChecksPageTmpl) Template() string { return "checks/page_checks" }
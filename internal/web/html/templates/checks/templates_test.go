@@ -0,0 +1,74 @@
+package checks
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/checks"
+	"github.com/Peltoche/zapette/internal/tools/uuid"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	upCheck := checks.NewFakeCheck(t).Build()
+	downCheck := checks.NewFakeCheck(t).WithKind(checks.TCP, "localhost:5432").Build()
+	pendingCheck := checks.NewFakeCheck(t).Build()
+
+	now := time.Now()
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "ChecksPageTmpl",
+			Layout: true,
+			Template: &ChecksPageTmpl{
+				Checks: []checks.Check{*upCheck, *downCheck, *pendingCheck},
+				Summaries: map[uuid.UUID]checks.Summary{
+					upCheck.ID(): checks.Summarize([]checks.Probe{
+						checks.NewFakeProbe(upCheck, now, true, 12*time.Millisecond),
+					}),
+					downCheck.ID(): checks.Summarize([]checks.Probe{
+						checks.NewFakeProbe(downCheck, now, false, time.Second),
+					}),
+				},
+				Kinds:   checks.AllKinds,
+				Error:   "some-error-msg",
+				IsAdmin: true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package containers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/containers"
+)
+
+type ContainersPageTmpl struct {
+	Now        time.Time
+	Containers []containers.Container
+	// Unavailable is set when no Docker or Podman socket can be reached.
+	Unavailable bool
+	IsAdmin     bool
+}
+
+func (t *ContainersPageTmpl) Template() string { return "containers/page_containers" }
+
+type ContainerPageTmpl struct {
+	Now       time.Time
+	Container *containers.Container
+	// Logs are only set for the admins, the logs can contain secrets.
+	Logs    []containers.LogLine
+	Error   string
+	IsAdmin bool
+}
+
+func (t *ContainerPageTmpl) Template() string { return "containers/page_container" }
+
+// CPU formats the CPU usage, "-" if it's unknown.
+func (t *ContainersPageTmpl) CPU(usage *containers.Usage) string { return formatCPU(usage) }
+
+// CPU formats the CPU usage, "-" if it's unknown.
+func (t *ContainerPageTmpl) CPU(usage *containers.Usage) string { return formatCPU(usage) }
+
+func formatCPU(usage *containers.Usage) string {
+	if usage == nil || usage.CPUPercent() == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.1f%%", *usage.CPUPercent())
+}
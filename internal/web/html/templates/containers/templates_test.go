@@ -0,0 +1,95 @@
+package containers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/containers"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	now := time.Now()
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "ContainersPageTmpl",
+			Layout: true,
+			Template: &ContainersPageTmpl{
+				Now: now,
+				Containers: []containers.Container{
+					*containers.NewFakeContainer(t).WithUsage(12.5, 200*datasize.MB, 2*datasize.GB).Build(),
+					*containers.NewFakeContainer(t).WithState("exited").Build(),
+				},
+				IsAdmin: true,
+			},
+		},
+		{
+			Name:   "ContainersPageTmpl unavailable",
+			Layout: true,
+			Template: &ContainersPageTmpl{
+				Now:         now,
+				Unavailable: true,
+			},
+		},
+		{
+			Name:   "ContainerPageTmpl",
+			Layout: true,
+			Template: &ContainerPageTmpl{
+				Now:       now,
+				Container: containers.NewFakeContainer(t).WithUsage(12.5, 200*datasize.MB, 2*datasize.GB).Build(),
+				Logs: []containers.LogLine{
+					containers.NewFakeLogLine(now, "some log line"),
+				},
+				Error:   "some-error-msg",
+				IsAdmin: true,
+			},
+		},
+		{
+			Name:   "ContainerPageTmpl stopped",
+			Layout: true,
+			Template: &ContainerPageTmpl{
+				Now:       now,
+				Container: containers.NewFakeContainer(t).WithState("exited").Build(),
+				Logs:      nil,
+				IsAdmin:   false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package dashboards
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/dashboards"
+)
+
+type DashboardsPageTmpl struct {
+	Dashboards []dashboards.Dashboard
+	Error      string
+}
+
+func (t *DashboardsPageTmpl) Template() string { return "dashboards/page_dashboards" }
+
+type DashboardPageTmpl struct {
+	Dashboard *dashboards.Dashboard
+	Panels    []PanelView
+	// Series are the series available for a new panel. They are empty on a
+	// read-only dashboard.
+	Series []dashboards.Series
+	// ShareURL is the link giving a read-only access. It's only set right
+	// after the dashboard is shared.
+	ShareURL string
+	// ReadOnly is true when the dashboard is opened from its share link.
+	ReadOnly bool
+	Error    string
+}
+
+func (t *DashboardPageTmpl) Template() string { return "dashboards/page_dashboard" }
+
+// URL is the address used to reload the panels.
+func (t *DashboardPageTmpl) URL() string {
+	if t.ReadOnly {
+		return "/web/dashboards/shared/" + t.Dashboard.ShareToken().Raw()
+	}
+
+	return "/web/dashboards/" + string(t.Dashboard.ID())
+}
+
+func (t *DashboardPageTmpl) Kinds() []dashboards.PanelKind { return dashboards.PanelKinds }
+
+func (t *DashboardPageTmpl) Widths() []int { return dashboards.Widths }
+
+// Option is an entry of a select input.
+type Option struct {
+	Value int
+	Label string
+}
+
+func (t *DashboardPageTmpl) Spans() []Option {
+	res := make([]Option, len(dashboards.Spans))
+	for i, span := range dashboards.Spans {
+		res[i] = Option{Value: int(span.Seconds()), Label: FormatSpan(span)}
+	}
+
+	return res
+}
+
+// Charts are the data of the line panels, drawn by the page script.
+func (t *DashboardPageTmpl) Charts() []Chart {
+	res := []Chart{}
+	for _, panel := range t.Panels {
+		if panel.Panel.Kind() == dashboards.LinePanel && panel.Data != nil {
+			res = append(res, newChart(&panel))
+		}
+	}
+
+	return res
+}
+
+// PanelView is a panel with its data, nil if the series is unavailable.
+type PanelView struct {
+	Panel dashboards.Panel
+	Data  *dashboards.PanelData
+	// IsFirst and IsLast hide the moves going out of the dashboard.
+	IsFirst bool
+	IsLast  bool
+}
+
+func (p PanelView) Span() string { return FormatSpan(p.Panel.Span()) }
+
+// Title is the panel title, or the series label if no title is set.
+func (p PanelView) Title() string {
+	if p.Panel.Title() != "" {
+		return p.Panel.Title()
+	}
+
+	if p.Data != nil {
+		return p.Data.Series().Label()
+	}
+
+	return p.Panel.Series()
+}
+
+func (p PanelView) Latest() string { return p.format(p.Data.Latest()) }
+func (p PanelView) Min() string    { return p.format(p.Data.Min()) }
+func (p PanelView) Max() string    { return p.format(p.Data.Max()) }
+func (p PanelView) Avg() string    { return p.format(p.Data.Avg()) }
+
+// format formats a value of the series with its unit, "-" if unknown.
+func (p PanelView) format(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.1f %s", *value, p.Data.Series().Unit())
+}
+
+type Chart struct {
+	ID     string    `json:"id"`
+	Label  string    `json:"label"`
+	Unit   string    `json:"unit"`
+	Labels []string  `json:"labels"`
+	Values []float64 `json:"values"`
+}
+
+func newChart(p *PanelView) Chart {
+	points := p.Data.Points()
+
+	format := time.TimeOnly
+	if p.Panel.Span() > 24*time.Hour {
+		format = "01-02 15:04"
+	}
+
+	res := Chart{
+		ID:     string(p.Panel.ID()),
+		Label:  p.Data.Series().Label(),
+		Unit:   p.Data.Series().Unit(),
+		Labels: make([]string, len(points)),
+		Values: make([]float64, len(points)),
+	}
+
+	for i, point := range points {
+		res.Labels[i] = point.At.Local().Format(format)
+		res.Values[i] = point.Value
+	}
+
+	return res
+}
+
+// FormatSpan formats a span the short way: "15m", "6h", "7d".
+func FormatSpan(span time.Duration) string {
+	switch {
+	case span >= 48*time.Hour && span%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", span/(24*time.Hour))
+	case span >= time.Hour && span%time.Hour == 0:
+		return fmt.Sprintf("%dh", span/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", span/time.Minute)
+	}
+}
@@ -0,0 +1,138 @@
+package dashboards
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/dashboards"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/tools/secret"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	series := dashboards.NewFakeSeries("local/memory.percent", "Memory", "%")
+	data := dashboards.NewFakePanelData(series, []dashboards.Point{
+		{At: time.Now().Add(-time.Minute), Value: 40},
+		{At: time.Now(), Value: 42},
+	}, ptr.To(42.0), ptr.To(100.0))
+
+	panels := []dashboards.Panel{
+		dashboards.NewFakePanel(dashboards.LinePanel, series.Key()),
+		dashboards.NewFakePanel(dashboards.GaugePanel, series.Key()),
+		dashboards.NewFakePanel(dashboards.StatPanel, series.Key()),
+		dashboards.NewFakePanel(dashboards.TablePanel, series.Key()),
+		dashboards.NewFakePanel(dashboards.LinePanel, "unknown/memory.percent"),
+	}
+
+	dashboard := dashboards.NewFakeDashboard(t).WithPanels(panels...).Build()
+	sharedDashboard := dashboards.NewFakeDashboard(t).WithPanels(panels...).WithShareToken(secret.NewText("some-token")).Build()
+
+	views := []PanelView{
+		{Panel: panels[0], Data: data, IsFirst: true, IsLast: false},
+		{Panel: panels[1], Data: data, IsFirst: false, IsLast: false},
+		{Panel: panels[2], Data: data, IsFirst: false, IsLast: false},
+		{Panel: panels[3], Data: data, IsFirst: false, IsLast: false},
+		{Panel: panels[4], Data: nil, IsFirst: false, IsLast: true},
+	}
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "DashboardsPageTmpl",
+			Layout: true,
+			Template: &DashboardsPageTmpl{
+				Dashboards: []dashboards.Dashboard{*dashboard, *sharedDashboard},
+				Error:      "some-error-msg",
+			},
+		},
+		{
+			Name:   "DashboardPageTmpl",
+			Layout: true,
+			Template: &DashboardPageTmpl{
+				Dashboard: sharedDashboard,
+				Panels:    views,
+				Series:    []dashboards.Series{series},
+				ShareURL:  "https://example.com/web/dashboards/shared/some-token",
+				ReadOnly:  false,
+				Error:     "some-error-msg",
+			},
+		},
+		{
+			Name:   "DashboardPageTmpl read-only",
+			Layout: true,
+			Template: &DashboardPageTmpl{
+				Dashboard: sharedDashboard,
+				Panels:    views,
+				Series:    []dashboards.Series{},
+				ReadOnly:  true,
+			},
+		},
+		{
+			Name:   "DashboardPageTmpl shared before",
+			Layout: true,
+			Template: &DashboardPageTmpl{
+				Dashboard: sharedDashboard,
+				Panels:    views,
+				Series:    []dashboards.Series{series},
+				ShareURL:  "",
+				ReadOnly:  false,
+			},
+		},
+		{
+			Name:   "DashboardPageTmpl without panels",
+			Layout: true,
+			Template: &DashboardPageTmpl{
+				Dashboard: dashboards.NewFakeDashboard(t).Build(),
+				Panels:    []PanelView{},
+				Series:    []dashboards.Series{series},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
+
+func TestFormatSpan(t *testing.T) {
+	for _, span := range dashboards.Spans {
+		t.Run(span.String(), func(t *testing.T) {
+			assert.NotEmpty(t, FormatSpan(span))
+		})
+	}
+
+	assert.Equal(t, "15m", FormatSpan(15*time.Minute))
+	assert.Equal(t, "24h", FormatSpan(24*time.Hour))
+	assert.Equal(t, "7d", FormatSpan(7*24*time.Hour))
+}
@@ -0,0 +1,26 @@
+package heartbeats
+
+import (
+	"github.com/Peltoche/zapette/internal/service/heartbeats"
+)
+
+type HeartbeatsPageTmpl struct {
+	Heartbeats []heartbeats.Heartbeat
+	// BaseURL is the url prefix of the ping endpoints.
+	BaseURL string
+	Error   string
+	IsAdmin bool
+}
+
+func (t *HeartbeatsPageTmpl) Template() string { return "heartbeats/page_heartbeats" }
+
+type HeartbeatPageTmpl struct {
+	Heartbeat *heartbeats.Heartbeat
+	Pings     []heartbeats.Ping
+	// LastRun is the latest ping reporting the end of a run, if any.
+	LastRun *heartbeats.Ping
+	BaseURL string
+	IsAdmin bool
+}
+
+func (t *HeartbeatPageTmpl) Template() string { return "heartbeats/page_heartbeat" }
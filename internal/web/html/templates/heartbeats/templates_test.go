@@ -0,0 +1,92 @@
+package heartbeats
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/heartbeats"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	now := time.Now()
+	upHeartbeat := heartbeats.NewFakeHeartbeat(t).WithLastPing(now).Build()
+	startedHeartbeat := heartbeats.NewFakeHeartbeat(t).WithStart(now).Build()
+	newHeartbeat := heartbeats.NewFakeHeartbeat(t).Build()
+	lastRun := heartbeats.NewFakePing(t, upHeartbeat).
+		WithKind(heartbeats.Fail).
+		WithExitCode(2).
+		WithDuration(time.Minute).
+		Build()
+	start := heartbeats.NewFakePing(t, upHeartbeat).WithKind(heartbeats.Start).Build()
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "HeartbeatsPageTmpl",
+			Layout: true,
+			Template: &HeartbeatsPageTmpl{
+				Heartbeats: []heartbeats.Heartbeat{*upHeartbeat, *startedHeartbeat, *newHeartbeat},
+				BaseURL:    "https://example.com",
+				Error:      "some-error-msg",
+				IsAdmin:    true,
+			},
+		},
+		{
+			Name:   "HeartbeatPageTmpl",
+			Layout: true,
+			Template: &HeartbeatPageTmpl{
+				Heartbeat: upHeartbeat,
+				Pings:     []heartbeats.Ping{*lastRun, *start},
+				LastRun:   lastRun,
+				BaseURL:   "https://example.com",
+				IsAdmin:   true,
+			},
+		},
+		{
+			Name:   "HeartbeatPageTmpl without pings",
+			Layout: true,
+			Template: &HeartbeatPageTmpl{
+				Heartbeat: newHeartbeat,
+				Pings:     []heartbeats.Ping{},
+				LastRun:   nil,
+				BaseURL:   "https://example.com",
+				IsAdmin:   false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
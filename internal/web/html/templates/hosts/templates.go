@@ -0,0 +1,123 @@
+package hosts
+
+import (
+	"math"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+)
+
+// SortKeys are the orders available on the fleet overview.
+var SortKeys = []string{"name", "cpu", "memory", "disk", "alerts", "last-seen"}
+
+type HostsPageTmpl struct {
+	Tiles []Tile
+	// Tags are all the tags used by the hosts.
+	Tags    []string
+	Tag     string
+	Sort    string
+	Error   string
+	IsAdmin bool
+}
+
+func (t *HostsPageTmpl) Template() string { return "hosts/page_hosts" }
+
+func (t *HostsPageTmpl) SortKeys() []string { return SortKeys }
+
+// Tile is the state of a host displayed on the fleet overview. It's also
+// the payload of the live updates.
+type Tile struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Hostname string   `json:"hostname"`
+	Tags     []string `json:"tags"`
+	// Status is "new" for a host never seen, "stale" or "up".
+	Status   string `json:"status"`
+	Uptime   string `json:"uptime"`
+	LastSeen string `json:"lastSeen"`
+	// Memory, CPU and Disk are percentages, nil if unknown.
+	Memory    *int   `json:"memory"`
+	CPU       *int   `json:"cpu"`
+	Disk      *int   `json:"disk"`
+	DiskMount string `json:"diskMount"`
+	Alerts    int    `json:"alerts"`
+
+	lastSeenAt time.Time
+}
+
+func NewTile(summary *hosts.Summary, now time.Time) Tile {
+	host := summary.Host()
+
+	res := Tile{
+		ID:       string(host.ID()),
+		Name:     host.Name(),
+		Hostname: host.Hostname(),
+		Tags:     host.Tags(),
+		Status:   "new",
+		Alerts:   len(summary.Alerts()),
+	}
+
+	if lastSeen := host.LastSeenAt(); lastSeen != nil {
+		res.Status = "up"
+		if host.IsStale(now) {
+			res.Status = "stale"
+		}
+
+		res.lastSeenAt = *lastSeen
+		res.LastSeen = lastSeen.Local().Format(time.DateTime)
+		res.Uptime = host.Uptime().String()
+	}
+
+	if stats := summary.Stats(); stats != nil {
+		res.Memory = ptr.To(stats.Memory().PercentageUsedMemory())
+	}
+
+	if cpu := summary.CPU(); cpu != nil {
+		res.CPU = ptr.To(int(math.Round(*cpu)))
+	}
+
+	if disk := summary.FullestDisk(); disk != nil {
+		res.Disk = ptr.To(disk.PercentageUsed())
+		res.DiskMount = disk.MountPoint()
+	}
+
+	return res
+}
+
+// Gauge is a percentage displayed as a progress bar.
+type Gauge struct {
+	Field string
+	Label string
+	Value *int
+}
+
+func (t Tile) Gauges() []Gauge {
+	diskLabel := "Disk"
+	if t.DiskMount != "" {
+		diskLabel = "Disk " + t.DiskMount
+	}
+
+	return []Gauge{
+		{Field: "cpu", Label: "CPU", Value: t.CPU},
+		{Field: "memory", Label: "Memory", Value: t.Memory},
+		{Field: "disk", Label: diskLabel, Value: t.Disk},
+	}
+}
+
+// LastSeenAt is zero for a host never seen.
+func (t Tile) LastSeenAt() time.Time { return t.lastSeenAt }
+
+type HostPageTmpl struct {
+	Host *hosts.Host
+	// Stats are the latest stats pushed by the agent, nil if the agent
+	// never pushed.
+	Stats *sysstats.Stats
+	// BaseURL is the url used by the agent to reach the server.
+	BaseURL string
+	Error   string
+	IsAdmin bool
+}
+
+func (t *HostPageTmpl) Template() string { return "hosts/page_host" }
@@ -0,0 +1,90 @@
+package hosts
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/hosts"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/ptr"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	seenHost := hosts.NewFakeHost(t).WithLastSeen(time.Now()).WithoutToken().Build()
+	newHost := hosts.NewFakeHost(t).Build()
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "HostsPageTmpl",
+			Layout: true,
+			Template: &HostsPageTmpl{
+				Tiles: []Tile{
+					NewTile(hosts.NewFakeSummary(seenHost, sysstats.NewFakeStats(t).Build(), ptr.To(42.0)), time.Now()),
+					NewTile(hosts.NewFakeSummary(newHost, nil, nil), time.Now()),
+				},
+				Tags:    []string{"prod", "web"},
+				Tag:     "prod",
+				Sort:    "cpu",
+				Error:   "some-error-msg",
+				IsAdmin: true,
+			},
+		},
+		{
+			Name:   "HostPageTmpl",
+			Layout: true,
+			Template: &HostPageTmpl{
+				Host:    seenHost,
+				Stats:   sysstats.NewFakeStats(t).Build(),
+				BaseURL: "https://example.com",
+				Error:   "some-error-msg",
+				IsAdmin: true,
+			},
+		},
+		{
+			Name:   "HostPageTmpl never seen",
+			Layout: true,
+			Template: &HostPageTmpl{
+				Host:    newHost,
+				Stats:   nil,
+				BaseURL: "https://example.com",
+				IsAdmin: false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
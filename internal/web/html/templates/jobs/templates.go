@@ -0,0 +1,10 @@
+package jobs
+
+import "github.com/Peltoche/zapette/internal/tools/scheduler"
+
+type JobsPageTmpl struct {
+	Jobs  []scheduler.JobStatus
+	Error string
+}
+
+func (t *JobsPageTmpl) Template() string { return "jobs/page_jobs" }
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/tools/scheduler"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	now := time.Now()
+
+	jobs := []scheduler.JobStatus{
+		*scheduler.NewFakeJobStatus(t).Build(),
+		*scheduler.NewFakeJobStatus(t).Running().Build(),
+		*scheduler.NewFakeJobStatus(t).WithLastRun(now, time.Second, nil).Build(),
+		*scheduler.NewFakeJobStatus(t).WithLastRun(now, time.Second, errors.New("some-error")).Build(),
+	}
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "JobsPageTmpl",
+			Layout: true,
+			Template: &JobsPageTmpl{
+				Jobs: jobs,
+			},
+		},
+		{
+			Name:   "JobsPageTmpl with an error",
+			Layout: true,
+			Template: &JobsPageTmpl{
+				Jobs:  jobs,
+				Error: "some-error-msg",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
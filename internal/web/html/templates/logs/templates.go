@@ -0,0 +1,28 @@
+package logs
+
+import (
+	"github.com/Peltoche/zapette/internal/service/logs"
+)
+
+// FilterForm contains the raw values of the filter form.
+type FilterForm struct {
+	Unit     string
+	Priority string
+	Since    string
+	Until    string
+	Search   string
+}
+
+type LogsPageTmpl struct {
+	Entries    []logs.Entry
+	Priorities []logs.Priority
+	Form       FilterForm
+	// Source is "journal" or "files".
+	Source string
+	// TailURL is the SSE endpoint streaming the new entries matching the
+	// filter.
+	TailURL string
+	Error   string
+}
+
+func (t *LogsPageTmpl) Template() string { return "logs/page_logs" }
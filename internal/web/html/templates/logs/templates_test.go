@@ -0,0 +1,73 @@
+package logs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Peltoche/zapette/internal/service/logs"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "LogsPageTmpl",
+			Layout: true,
+			Template: &LogsPageTmpl{
+				Entries: []logs.Entry{
+					*logs.NewFakeEntry(t).WithPriority(logs.Error).Build(),
+					*logs.NewFakeEntry(t).WithPriority(logs.Info).Build(),
+				},
+				Priorities: logs.AllPriorities,
+				Form:       FilterForm{Unit: "nginx.service", Priority: "err", Search: "foo"},
+				Source:     "journal",
+				TailURL:    "/web/logs/sse?priority=err&unit=nginx.service",
+			},
+		},
+		{
+			Name:   "LogsPageTmpl without entries",
+			Layout: true,
+			Template: &LogsPageTmpl{
+				Entries:    []logs.Entry{},
+				Priorities: logs.AllPriorities,
+				Form:       FilterForm{Since: "2024-03-10T10:00", Until: "2024-03-10T11:00"},
+				Source:     "files",
+				Error:      "some-error-msg",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
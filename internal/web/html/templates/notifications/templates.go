@@ -0,0 +1,18 @@
+package notifications
+
+import "github.com/Peltoche/zapette/internal/service/notifications"
+
+type ChannelsPageTmpl struct {
+	Channels []notifications.Channel
+	Kinds    []notifications.Kind
+	Error    string
+}
+
+func (t *ChannelsPageTmpl) Template() string { return "notifications/page_channels" }
+
+type TestResultTmpl struct {
+	Channel *notifications.Channel
+	Error   string
+}
+
+func (t *TestResultTmpl) Template() string { return "notifications/test_result" }
@@ -1,31 +1,102 @@
 package server
 
 import (
+	"sort"
+
+	"github.com/Peltoche/zapette/internal/service/forecasts"
 	"github.com/Peltoche/zapette/internal/service/sysinfos"
 	"github.com/Peltoche/zapette/internal/service/sysstats"
 )
 
 type DetailsPageTmpl struct {
-	Stats    *sysstats.Stats
-	SysInfos *sysinfos.Infos
+	Stats     *sysstats.Stats
+	SysInfos  *sysinfos.Infos
+	Forecasts []forecasts.Forecast
+	CGroups   []sysstats.CGroup
 }
 
 func (t *DetailsPageTmpl) Template() string { return "server/page_details" }
 
+// ForecastFor returns the forecast of the memory ("memory") or of the disk
+// mounted at name. It returns nil if the resource is not filling up.
+func (t *DetailsPageTmpl) ForecastFor(name string) *forecasts.Forecast {
+	for i := range t.Forecasts {
+		if t.Forecasts[i].Name() == name && t.Forecasts[i].IsFilling() {
+			return &t.Forecasts[i]
+		}
+	}
+
+	return nil
+}
+
+// topCGroupsLen is the number of cgroups displayed on the details page.
+const topCGroupsLen = 10
+
+// TopCGroups returns the services and scopes using the most memory. The
+// slices are skipped as they aggregate their children.
+func (t *DetailsPageTmpl) TopCGroups() []sysstats.CGroup {
+	res := []sysstats.CGroup{}
+	for _, cgroup := range t.CGroups {
+		if cgroup.Kind() != "slice" {
+			res = append(res, cgroup)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Memory() > res[j].Memory() })
+
+	if len(res) > topCGroupsLen {
+		res = res[:topCGroupsLen]
+	}
+
+	return res
+}
+
 type SysstatsPageTmpl struct {
 	GraphData *Graph
+	// Ranges are the names of the relative ranges, updated live.
+	Ranges []string
+	// Range is the displayed relative range, empty for an absolute range.
+	Range string
+	// From and To are the bounds of the displayed range, in the server time
+	// zone.
+	From string
+	To   string
+	// TimeZone is the name of the server time zone, displayed next to the
+	// range inputs.
+	TimeZone string
+	// UTCOffset is the offset of the server time zone in seconds. The
+	// scripts use it to convert the unix times into the server time zone.
+	UTCOffset int
+	// Resolution is the time span covered by each point.
+	Resolution string
+	// SSEURL is the url of the live updates, empty for a range in the past.
+	SSEURL     string
+	ZoomOutURL string
+	// ExportQuery is the query of the export links, for the displayed range.
+	ExportQuery string
+	Message     string
+	Error       string
+	IsAdmin     bool
 }
 
 func (t *SysstatsPageTmpl) Template() string { return "server/page_graph_memory" }
 
+// ExportURL returns the url downloading the displayed range in the given
+// format.
+func (t *SysstatsPageTmpl) ExportURL(format string) string {
+	return "/web/server/memory/export?format=" + format + "&" + t.ExportQuery
+}
+
 type Dataset struct {
-	Label       string     `json:"label"`
-	Data        []*float64 `json:"data"`
-	ShowLine    bool       `json:"showLine"`
-	BorderColor string     `json:"borderColor"`
-	SteppedLine bool       `json:"steppedLine"`
-	BorderWidth int        `json:"borderWidth"`
-	PointRadius int        `json:"pointRadius"`
+	Label           string     `json:"label"`
+	Data            []*float64 `json:"data"`
+	ShowLine        bool       `json:"showLine"`
+	BorderColor     string     `json:"borderColor"`
+	BackgroundColor string     `json:"backgroundColor,omitempty"`
+	BorderDash      []int      `json:"borderDash,omitempty"`
+	SteppedLine     bool       `json:"steppedLine"`
+	BorderWidth     int        `json:"borderWidth"`
+	PointRadius     int        `json:"pointRadius"`
 }
 
 type Data struct {
@@ -36,4 +107,7 @@ type Data struct {
 type Graph struct {
 	Type string `json:"type"`
 	Data Data   `json:"data"`
+	// Times are the unix timestamps of the labels. They are used to open the
+	// logs at the time of a point.
+	Times []*int64 `json:"times,omitempty"`
 }
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peltoche/zapette/internal/service/forecasts"
+	"github.com/Peltoche/zapette/internal/service/sysinfos"
+	"github.com/Peltoche/zapette/internal/service/sysstats"
+	"github.com/Peltoche/zapette/internal/tools/datasize"
+	"github.com/Peltoche/zapette/internal/web/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates(t *testing.T) {
+	renderer := html.NewRenderer(html.Config{
+		PrettyRender: false,
+		HotReload:    false,
+	})
+
+	tests := []struct {
+		Template html.Templater
+		Name     string
+		Layout   bool
+	}{
+		{
+			Name:   "DetailsPageTmpl",
+			Layout: true,
+			Template: &DetailsPageTmpl{
+				Stats:    sysstats.NewFakeStats(t).Build(),
+				SysInfos: &sysinfos.Infos{},
+				Forecasts: []forecasts.Forecast{
+					*forecasts.NewFakeForecast(t, "memory").WithResource(forecasts.Memory).Build(),
+					*forecasts.NewFakeForecast(t, "/").Build(),
+				},
+				CGroups: []sysstats.CGroup{
+					sysstats.NewFakeCGroup(time.Now(), "system.slice", 2*datasize.GB),
+					sysstats.NewFakeCGroup(time.Now(), "system.slice/nginx.service", 500*datasize.MB),
+				},
+			},
+		},
+		{
+			Name:   "SysstatsPageTmpl live",
+			Layout: true,
+			Template: &SysstatsPageTmpl{
+				GraphData:   &Graph{Type: "line", Data: Data{Labels: []*string{}, Datasets: []Dataset{}}, Times: []*int64{}},
+				Ranges:      []string{"5m", "1h"},
+				Range:       "5m",
+				From:        "2024-03-10T11:55:00",
+				To:          "2024-03-10T12:00:00",
+				TimeZone:    "CET",
+				UTCOffset:   3600,
+				Resolution:  "5s",
+				SSEURL:      "/web/server/memory/details/sse?range=5m",
+				ZoomOutURL:  "",
+				ExportQuery: "range=5m",
+				Message:     "42 stats imported.",
+				Error:       "",
+				IsAdmin:     true,
+			},
+		},
+		{
+			Name:   "SysstatsPageTmpl history",
+			Layout: true,
+			Template: &SysstatsPageTmpl{
+				GraphData:   &Graph{Type: "line", Data: Data{Labels: []*string{}, Datasets: []Dataset{}}, Times: []*int64{}},
+				Ranges:      []string{"5m", "1h"},
+				Range:       "",
+				From:        "2024-03-10T11:00:00",
+				To:          "2024-03-10T12:00:00",
+				TimeZone:    "UTC",
+				UTCOffset:   0,
+				Resolution:  "10s",
+				SSEURL:      "",
+				ZoomOutURL:  "/web/server/memory/details?from=2024-03-10T10%3A30%3A00&to=2024-03-10T12%3A30%3A00",
+				ExportQuery: "from=2024-03-10T11%3A00%3A00&to=2024-03-10T12%3A00%3A00",
+				Error:       "some-error-msg",
+				IsAdmin:     false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+			if !test.Layout {
+				r.Header.Add("HX-Boosted", "true")
+			}
+
+			renderer.WriteHTMLTemplate(w, r, http.StatusOK, test.Template)
+
+			if !assert.Equal(t, http.StatusOK, w.Code) {
+				res := w.Result()
+				res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				t.Log(string(body))
+			}
+		})
+	}
+}
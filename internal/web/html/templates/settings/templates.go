@@ -0,0 +1,12 @@
+package settings
+
+import "github.com/Peltoche/zapette/internal/service/config"
+
+type SettingsPageTmpl struct {
+	Settings []config.SettingValue
+	// Error is the validation error of the setting ErrorKey.
+	ErrorKey config.ConfigKey
+	Error    string
+}
+
+func (t *SettingsPageTmpl) Template() string { return "settings/page_settings" }
@@ -0,0 +1,15 @@
+package systemd
+
+import (
+	"github.com/Peltoche/zapette/internal/service/systemd"
+)
+
+type UnitsPageTmpl struct {
+	Units []systemd.Unit
+	// Unavailable is set when systemd can't be reached.
+	Unavailable bool
+	Error       string
+	IsAdmin     bool
+}
+
+func (t *UnitsPageTmpl) Template() string { return "systemd/page_units" }